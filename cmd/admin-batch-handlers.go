@@ -0,0 +1,151 @@
+/*
+ * MinIO Cloud Storage, (C) 2021 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/minio/minio/cmd/logger"
+	iampolicy "github.com/minio/minio/pkg/iam/policy"
+	"github.com/minio/minio/pkg/madmin"
+)
+
+// StartBatchJobHandler - POST /minio/admin/v3/batch/start-job
+// ----------
+// Starts the batch job described by the YAML or JSON specification in
+// the request body, returns the ID of the job.
+func (a adminAPIHandlers) StartBatchJobHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, w, "StartBatchJob")
+
+	defer logger.AuditLog(ctx, w, r, mustGetClaimsFromToken(r))
+
+	objectAPI, _ := validateAdminReq(ctx, w, r, iampolicy.StartBatchJobAction)
+	if objectAPI == nil {
+		return
+	}
+
+	if r.ContentLength > maxBatchJobSpecSize || r.ContentLength == -1 {
+		writeErrorResponseJSON(ctx, w, errorCodes.ToAPIErr(ErrAdminConfigTooLarge), r.URL)
+		return
+	}
+
+	spec, err := ioutil.ReadAll(io.LimitReader(r.Body, r.ContentLength))
+	if err != nil {
+		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
+		return
+	}
+
+	id, err := globalBatchJobsSys.Start(ctx, objectAPI, spec)
+	if err != nil {
+		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
+		return
+	}
+
+	data, err := json.Marshal(madmin.StartBatchJobResult{ID: id})
+	if err != nil {
+		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
+		return
+	}
+
+	// Write success response.
+	writeSuccessResponseJSON(w, data)
+}
+
+// ListBatchJobsHandler - GET /minio/admin/v3/batch/list-jobs
+// ----------
+// Returns the progress of all the batch jobs, most recent first.
+func (a adminAPIHandlers) ListBatchJobsHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, w, "ListBatchJobs")
+
+	defer logger.AuditLog(ctx, w, r, mustGetClaimsFromToken(r))
+
+	objectAPI, _ := validateAdminReq(ctx, w, r, iampolicy.ListBatchJobsAction)
+	if objectAPI == nil {
+		return
+	}
+
+	jobs, err := globalBatchJobsSys.List(ctx, objectAPI)
+	if err != nil {
+		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
+		return
+	}
+
+	data, err := json.Marshal(jobs)
+	if err != nil {
+		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
+		return
+	}
+
+	// Write success response.
+	writeSuccessResponseJSON(w, data)
+}
+
+// DescribeBatchJobHandler - GET /minio/admin/v3/batch/describe-job?id=<id>
+// ----------
+// Returns the progress of the batch job.
+func (a adminAPIHandlers) DescribeBatchJobHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, w, "DescribeBatchJob")
+
+	defer logger.AuditLog(ctx, w, r, mustGetClaimsFromToken(r))
+
+	objectAPI, _ := validateAdminReq(ctx, w, r, iampolicy.DescribeBatchJobAction)
+	if objectAPI == nil {
+		return
+	}
+
+	status, err := globalBatchJobsSys.Status(ctx, objectAPI, mux.Vars(r)["id"])
+	if err != nil {
+		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
+		return
+	}
+
+	data, err := json.Marshal(status)
+	if err != nil {
+		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
+		return
+	}
+
+	// Write success response.
+	writeSuccessResponseJSON(w, data)
+}
+
+// CancelBatchJobHandler - DELETE /minio/admin/v3/batch/cancel-job?id=<id>
+// ----------
+// Cancels the running batch job, the objects it already processed
+// are left as is.
+func (a adminAPIHandlers) CancelBatchJobHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, w, "CancelBatchJob")
+
+	defer logger.AuditLog(ctx, w, r, mustGetClaimsFromToken(r))
+
+	objectAPI, _ := validateAdminReq(ctx, w, r, iampolicy.CancelBatchJobAction)
+	if objectAPI == nil {
+		return
+	}
+
+	if err := globalBatchJobsSys.Cancel(ctx, objectAPI, mux.Vars(r)["id"]); err != nil {
+		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
+		return
+	}
+
+	// Write success response.
+	writeSuccessResponseHeadersOnly(w)
+}
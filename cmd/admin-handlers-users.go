@@ -1006,3 +1006,86 @@ func (a adminAPIHandlers) SetPolicyForUserOrGroup(w http.ResponseWriter, r *http
 		}
 	}
 }
+
+// SetUserQuota - PUT /minio/admin/v3/set-user-quota?name=<user_or_group>&isGroup=[true|false]
+func (a adminAPIHandlers) SetUserQuota(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, w, "SetUserQuota")
+
+	defer logger.AuditLog(ctx, w, r, mustGetClaimsFromToken(r))
+
+	objectAPI, _ := validateAdminUsersReq(ctx, w, r, iampolicy.SetUserQuotaAdminAction)
+	if objectAPI == nil {
+		return
+	}
+
+	vars := mux.Vars(r)
+	name := vars["name"]
+	isGroup := vars["isGroup"] == "true"
+
+	if !isGroup {
+		ok, err := globalIAMSys.IsTempUser(name)
+		if err != nil && err != errNoSuchUser {
+			writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
+			return
+		}
+		if ok {
+			writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, errIAMActionNotAllowed), r.URL)
+			return
+		}
+	}
+
+	var quota madmin.UserQuota
+	if err := json.NewDecoder(io.LimitReader(r.Body, r.ContentLength)).Decode(&quota); err != nil {
+		writeErrorResponseJSON(ctx, w, errorCodes.ToAPIErrWithErr(ErrAdminConfigBadJSON, err), r.URL)
+		return
+	}
+
+	if err := globalUserQuotaSys.Set(ctx, objectAPI, name, isGroup, quota); err != nil {
+		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
+		return
+	}
+
+	// Write success response.
+	writeSuccessResponseHeadersOnly(w)
+}
+
+// GetUserQuota - GET /minio/admin/v3/get-user-quota?name=<user_or_group>&isGroup=[true|false]
+func (a adminAPIHandlers) GetUserQuota(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, w, "GetUserQuota")
+
+	defer logger.AuditLog(ctx, w, r, mustGetClaimsFromToken(r))
+
+	objectAPI, _ := validateAdminUsersReq(ctx, w, r, iampolicy.GetUserQuotaAdminAction)
+	if objectAPI == nil {
+		return
+	}
+
+	vars := mux.Vars(r)
+	name := vars["name"]
+	isGroup := vars["isGroup"] == "true"
+
+	quota, err := globalUserQuotaSys.Get(objectAPI, name, isGroup)
+	if err != nil {
+		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
+		return
+	}
+	size, lastUpdate, err := globalUserQuotaSys.Usage(objectAPI, name, isGroup)
+	if err != nil {
+		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
+		return
+	}
+
+	body, err := json.Marshal(madmin.UserQuotaUsage{
+		Name:       name,
+		IsGroup:    isGroup,
+		Quota:      quota.Quota,
+		LastUpdate: lastUpdate,
+		Size:       size,
+	})
+	if err != nil {
+		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
+		return
+	}
+
+	writeSuccessResponseJSON(w, body)
+}
@@ -1530,6 +1530,74 @@ func (a adminAPIHandlers) BandwidthMonitorHandler(w http.ResponseWriter, r *http
 	}
 }
 
+// SetBandwidthLimitHandler - PUT /minio/admin/v3/set-bandwidth-limit?name=<bucket_or_user>&isUser=[true|false]
+// ----------
+// Sets the bandwidth limit of the client GET and PUT traffic of a bucket or of a user
+func (a adminAPIHandlers) SetBandwidthLimitHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, w, "SetBandwidthLimit")
+
+	defer logger.AuditLog(ctx, w, r, mustGetClaimsFromToken(r))
+
+	objectAPI, _ := validateAdminReq(ctx, w, r, iampolicy.SetBandwidthLimitAdminAction)
+	if objectAPI == nil {
+		return
+	}
+
+	vars := mux.Vars(r)
+	name := vars["name"]
+	isUser := vars["isUser"] == "true"
+
+	var limit madmin.BandwidthLimit
+	if err := json.NewDecoder(io.LimitReader(r.Body, r.ContentLength)).Decode(&limit); err != nil {
+		writeErrorResponseJSON(ctx, w, errorCodes.ToAPIErrWithErr(ErrAdminConfigBadJSON, err), r.URL)
+		return
+	}
+
+	if !isUser && (limit.Get > 0 || limit.Put > 0) {
+		// Limits of deleted buckets can still be removed.
+		if _, err := objectAPI.GetBucketInfo(ctx, name); err != nil {
+			writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
+			return
+		}
+	}
+
+	if err := globalBandwidthLimitsSys.Set(ctx, objectAPI, name, isUser, limit); err != nil {
+		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
+		return
+	}
+
+	// Write success response.
+	writeSuccessResponseHeadersOnly(w)
+}
+
+// GetBandwidthLimitsHandler - GET /minio/admin/v3/get-bandwidth-limits
+// ----------
+// Get the bandwidth limits of all the buckets and users
+func (a adminAPIHandlers) GetBandwidthLimitsHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, w, "GetBandwidthLimits")
+
+	defer logger.AuditLog(ctx, w, r, mustGetClaimsFromToken(r))
+
+	objectAPI, _ := validateAdminReq(ctx, w, r, iampolicy.GetBandwidthLimitAdminAction)
+	if objectAPI == nil {
+		return
+	}
+
+	limits, err := globalBandwidthLimitsSys.Get(objectAPI)
+	if err != nil {
+		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
+		return
+	}
+
+	body, err := json.Marshal(limits)
+	if err != nil {
+		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
+		return
+	}
+
+	writeSuccessResponseJSON(w, body)
+}
+
 // ServerInfoHandler - GET /minio/admin/v3/info
 // ----------
 // Get server information
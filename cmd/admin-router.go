@@ -166,6 +166,12 @@ func registerAdminRouter(router *mux.Router, enableConfigOps, enableIAMOps bool)
 
 			// Set Group Status
 			adminRouter.Methods(http.MethodPut).Path(adminVersion+"/set-group-status").HandlerFunc(httpTraceHdrs(adminAPI.SetGroupStatus)).Queries("group", "{group:.*}").Queries("status", "{status:.*}")
+
+			// Set user or group quota
+			adminRouter.Methods(http.MethodPut).Path(adminVersion+"/set-user-quota").HandlerFunc(httpTraceHdrs(adminAPI.SetUserQuota)).Queries("name", "{name:.*}", "isGroup", "{isGroup:true|false}")
+
+			// Get user or group quota
+			adminRouter.Methods(http.MethodGet).Path(adminVersion+"/get-user-quota").HandlerFunc(httpTraceHdrs(adminAPI.GetUserQuota)).Queries("name", "{name:.*}", "isGroup", "{isGroup:true|false}")
 		}
 
 		if globalIsDistErasure || globalIsErasure {
@@ -175,6 +181,9 @@ func registerAdminRouter(router *mux.Router, enableConfigOps, enableIAMOps bool)
 			// PutBucketQuotaConfig
 			adminRouter.Methods(http.MethodPut).Path(adminVersion+"/set-bucket-quota").HandlerFunc(
 				httpTraceHdrs(adminAPI.PutBucketQuotaConfigHandler)).Queries("bucket", "{bucket:.*}")
+			// GetBucketQuotaUsage
+			adminRouter.Methods(http.MethodGet).Path(adminVersion+"/get-bucket-quota-usage").HandlerFunc(
+				httpTraceHdrs(adminAPI.GetBucketQuotaUsageHandler)).Queries("bucket", "{bucket:.*}")
 
 			// Bucket replication operations
 			// GetBucketTargetHandler
@@ -186,6 +195,23 @@ func registerAdminRouter(router *mux.Router, enableConfigOps, enableIAMOps bool)
 			// RemoveRemoteTargetHandler
 			adminRouter.Methods(http.MethodDelete).Path(adminVersion+"/remove-remote-target").HandlerFunc(
 				httpTraceHdrs(adminAPI.RemoveRemoteTargetHandler)).Queries("bucket", "{bucket:.*}", "arn", "{arn:.*}")
+			// StartReplicationResyncHandler
+			adminRouter.Methods(http.MethodPost).Path(adminVersion+"/replication/resync").HandlerFunc(
+				httpTraceHdrs(adminAPI.StartReplicationResyncHandler)).Queries("bucket", "{bucket:.*}", "arn", "{arn:.*}")
+			// ReplicationResyncStatusHandler
+			adminRouter.Methods(http.MethodGet).Path(adminVersion+"/replication/resync-status").HandlerFunc(
+				httpTraceHdrs(adminAPI.ReplicationResyncStatusHandler)).Queries("bucket", "{bucket:.*}")
+			// ReplicateBucketMetadataHandler
+			adminRouter.Methods(http.MethodPut).Path(adminVersion+"/replication/bucket-metadata").HandlerFunc(
+				httpTraceHdrs(adminAPI.ReplicateBucketMetadataHandler)).Queries("bucket", "{bucket:.*}")
+		}
+
+		// Batch job operations
+		if !globalIsGateway {
+			adminRouter.Methods(http.MethodPost).Path(adminVersion + "/batch/start-job").HandlerFunc(httpTraceHdrs(adminAPI.StartBatchJobHandler))
+			adminRouter.Methods(http.MethodGet).Path(adminVersion + "/batch/list-jobs").HandlerFunc(httpTraceHdrs(adminAPI.ListBatchJobsHandler))
+			adminRouter.Methods(http.MethodGet).Path(adminVersion+"/batch/describe-job").HandlerFunc(httpTraceHdrs(adminAPI.DescribeBatchJobHandler)).Queries("id", "{id:.*}")
+			adminRouter.Methods(http.MethodDelete).Path(adminVersion+"/batch/cancel-job").HandlerFunc(httpTraceHdrs(adminAPI.CancelBatchJobHandler)).Queries("id", "{id:.*}")
 		}
 
 		if globalIsDistErasure {
@@ -216,6 +242,10 @@ func registerAdminRouter(router *mux.Router, enableConfigOps, enableIAMOps bool)
 				HandlerFunc(httpTraceHdrs(adminAPI.HealthInfoHandler))
 			adminRouter.Methods(http.MethodGet).Path(adminVersion + "/bandwidth").
 				HandlerFunc(httpTraceHdrs(adminAPI.BandwidthMonitorHandler))
+			adminRouter.Methods(http.MethodPut).Path(adminVersion+"/set-bandwidth-limit").
+				HandlerFunc(httpTraceHdrs(adminAPI.SetBandwidthLimitHandler)).Queries("name", "{name:.*}", "isUser", "{isUser:true|false}")
+			adminRouter.Methods(http.MethodGet).Path(adminVersion + "/get-bandwidth-limits").
+				HandlerFunc(httpTraceHdrs(adminAPI.GetBandwidthLimitsHandler))
 		}
 	}
 
@@ -30,6 +30,7 @@ import (
 	"github.com/minio/minio/cmd/crypto"
 	"github.com/minio/minio/cmd/logger"
 	"github.com/minio/minio/pkg/auth"
+	"github.com/minio/minio/pkg/bucket/inventory"
 	"github.com/minio/minio/pkg/bucket/lifecycle"
 	"github.com/minio/minio/pkg/bucket/replication"
 
@@ -100,6 +101,7 @@ const (
 	ErrNoSuchBucketPolicy
 	ErrNoSuchBucketLifecycle
 	ErrNoSuchLifecycleConfiguration
+	ErrNoSuchInventoryConfiguration
 	ErrNoSuchBucketSSEConfig
 	ErrNoSuchCORSConfiguration
 	ErrNoSuchWebsiteConfiguration
@@ -118,6 +120,9 @@ const (
 	ErrReplicationSourceNotVersionedError
 	ErrReplicationNeedsVersioningError
 	ErrReplicationBucketNeedsVersioningError
+	ErrReplicationResyncInProgress
+	ErrReplicationExistingObjectsDisabled
+	ErrBucketMetadataConflict
 	ErrObjectRestoreAlreadyInProgress
 	ErrNoSuchKey
 	ErrNoSuchUpload
@@ -259,8 +264,15 @@ const (
 	ErrObjectTampered
 	// Bucket Quota error codes
 	ErrAdminBucketQuotaExceeded
+	ErrAdminPrefixQuotaExceeded
+	ErrAdminUserQuotaExceeded
 	ErrAdminNoSuchQuotaConfiguration
 
+	// Batch job error codes
+	ErrAdminNoSuchBatchJob
+	ErrAdminBatchJobNotRunning
+	ErrAdminInvalidBatchJob
+
 	ErrHealNotImplemented
 	ErrHealNoSuchProcess
 	ErrHealInvalidClientToken
@@ -545,6 +557,11 @@ var errorCodes = errorCodeMap{
 		Description:    "The lifecycle configuration does not exist",
 		HTTPStatusCode: http.StatusNotFound,
 	},
+	ErrNoSuchInventoryConfiguration: {
+		Code:           "NoSuchConfiguration",
+		Description:    "The specified configuration does not exist.",
+		HTTPStatusCode: http.StatusNotFound,
+	},
 	ErrNoSuchBucketSSEConfig: {
 		Code:           "ServerSideEncryptionConfigurationNotFoundError",
 		Description:    "The server side encryption configuration was not found",
@@ -890,6 +907,21 @@ var errorCodes = errorCodeMap{
 		Description:    "Versioning must be 'Enabled' on the bucket to add a replication target",
 		HTTPStatusCode: http.StatusBadRequest,
 	},
+	ErrReplicationResyncInProgress: {
+		Code:           "XMinioAdminReplicationResyncInProgress",
+		Description:    "A replication resync to this target is already in progress",
+		HTTPStatusCode: http.StatusConflict,
+	},
+	ErrReplicationExistingObjectsDisabled: {
+		Code:           "XMinioAdminReplicationExistingObjectsDisabled",
+		Description:    "No replication rule of the bucket has existing object replication enabled",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrBucketMetadataConflict: {
+		Code:           "XMinioAdminBucketMetadataConflict",
+		Description:    "The bucket configuration was updated after the replicated change",
+		HTTPStatusCode: http.StatusConflict,
+	},
 	ErrNoSuchObjectLockConfiguration: {
 		Code:           "NoSuchObjectLockConfiguration",
 		Description:    "The specified object does not have a ObjectLock configuration",
@@ -1206,11 +1238,36 @@ var errorCodes = errorCodeMap{
 		Description:    "Bucket quota exceeded",
 		HTTPStatusCode: http.StatusBadRequest,
 	},
+	ErrAdminPrefixQuotaExceeded: {
+		Code:           "XMinioAdminPrefixQuotaExceeded",
+		Description:    "Prefix quota exceeded",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrAdminUserQuotaExceeded: {
+		Code:           "XMinioAdminUserQuotaExceeded",
+		Description:    "User quota exceeded",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
 	ErrAdminNoSuchQuotaConfiguration: {
 		Code:           "XMinioAdminNoSuchQuotaConfiguration",
 		Description:    "The quota configuration does not exist",
 		HTTPStatusCode: http.StatusNotFound,
 	},
+	ErrAdminNoSuchBatchJob: {
+		Code:           "XMinioAdminNoSuchBatchJob",
+		Description:    "The specified batch job does not exist",
+		HTTPStatusCode: http.StatusNotFound,
+	},
+	ErrAdminBatchJobNotRunning: {
+		Code:           "XMinioAdminBatchJobNotRunning",
+		Description:    "The specified batch job is not running",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrAdminInvalidBatchJob: {
+		Code:           "XMinioAdminInvalidBatchJob",
+		Description:    "The batch job specification is invalid",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
 	ErrInsecureClientRequest: {
 		Code:           "XMinioInsecureClientRequest",
 		Description:    "Cannot respond to plain-text request from TLS-encrypted server",
@@ -1929,6 +1986,8 @@ func toAPIErrorCode(ctx context.Context, err error) (apiErr APIErrorCode) {
 		apiErr = ErrNoSuchBucketPolicy
 	case BucketLifecycleNotFound:
 		apiErr = ErrNoSuchLifecycleConfiguration
+	case BucketInventoryConfigNotFound:
+		apiErr = ErrNoSuchInventoryConfiguration
 	case BucketSSEConfigNotFound:
 		apiErr = ErrNoSuchBucketSSEConfig
 	case BucketTaggingNotFound:
@@ -1937,6 +1996,12 @@ func toAPIErrorCode(ctx context.Context, err error) (apiErr APIErrorCode) {
 		apiErr = ErrObjectLockConfigurationNotFound
 	case BucketQuotaConfigNotFound:
 		apiErr = ErrAdminNoSuchQuotaConfiguration
+	case BatchJobNotFound:
+		apiErr = ErrAdminNoSuchBatchJob
+	case BatchJobNotRunning:
+		apiErr = ErrAdminBatchJobNotRunning
+	case InvalidBatchJob:
+		apiErr = ErrAdminInvalidBatchJob
 	case BucketReplicationConfigNotFound:
 		apiErr = ErrReplicationConfigurationNotFoundError
 	case BucketRemoteDestinationNotFound:
@@ -1961,8 +2026,18 @@ func toAPIErrorCode(ctx context.Context, err error) (apiErr APIErrorCode) {
 		apiErr = ErrRemoteTargetNotVersionedError
 	case BucketReplicationSourceNotVersioned:
 		apiErr = ErrReplicationSourceNotVersionedError
+	case BucketReplicationResyncInProgress:
+		apiErr = ErrReplicationResyncInProgress
+	case BucketExistingObjectReplicationDisabled:
+		apiErr = ErrReplicationExistingObjectsDisabled
+	case BucketMetadataConflict:
+		apiErr = ErrBucketMetadataConflict
 	case BucketQuotaExceeded:
 		apiErr = ErrAdminBucketQuotaExceeded
+	case PrefixQuotaExceeded:
+		apiErr = ErrAdminPrefixQuotaExceeded
+	case UserQuotaExceeded:
+		apiErr = ErrAdminUserQuotaExceeded
 	case *event.ErrInvalidEventName:
 		apiErr = ErrEventNotification
 	case *event.ErrInvalidARN:
@@ -2073,6 +2148,12 @@ func toAPIError(ctx context.Context, err error) APIError {
 				Description:    e.Error(),
 				HTTPStatusCode: http.StatusBadRequest,
 			}
+		case inventory.Error:
+			apiErr = APIError{
+				Code:           "InvalidArgument",
+				Description:    e.Error(),
+				HTTPStatusCode: http.StatusBadRequest,
+			}
 		case replication.Error:
 			apiErr = APIError{
 				Code:           "MalformedXML",
@@ -766,9 +766,12 @@ func writeSuccessResponseHeadersOnly(w http.ResponseWriter) {
 func writeErrorResponse(ctx context.Context, w http.ResponseWriter, err APIError, reqURL *url.URL, browser bool) {
 	switch err.Code {
 	case "SlowDown", "XMinioServerNotInitialized", "XMinioReadQuorum", "XMinioWriteQuorum":
-		// Set retry-after header to indicate user-agents to retry request after 120secs.
+		// Set retry-after header to indicate user-agents to retry request after 120secs,
+		// unless the caller knows when the request may be retried.
 		// https://developer.mozilla.org/en-US/docs/Web/HTTP/Headers/Retry-After
-		w.Header().Set(xhttp.RetryAfter, "120")
+		if w.Header().Get(xhttp.RetryAfter) == "" {
+			w.Header().Set(xhttp.RetryAfter, "120")
+		}
 	case "InvalidRegion":
 		err.Description = fmt.Sprintf("Region does not match; expecting '%s'.", globalServerRegion)
 	case "AuthorizationHeaderMalformed":
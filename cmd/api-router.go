@@ -204,6 +204,12 @@ func registerAPIRouter(router *mux.Router) {
 		// GetBucketReplicationConfig
 		bucket.Methods(http.MethodGet).HandlerFunc(
 			collectAPIStats("getbucketreplicationconfiguration", maxClients(httpTraceAll(api.GetBucketReplicationConfigHandler)))).Queries("replication", "")
+		// GetBucketInventoryConfiguration
+		bucket.Methods(http.MethodGet).HandlerFunc(
+			collectAPIStats("getbucketinventoryconfiguration", maxClients(httpTraceAll(api.GetBucketInventoryConfigurationHandler)))).Queries("inventory", "", "id", "{id:.*}")
+		// ListBucketInventoryConfigurations
+		bucket.Methods(http.MethodGet).HandlerFunc(
+			collectAPIStats("listbucketinventoryconfigurations", maxClients(httpTraceAll(api.ListBucketInventoryConfigurationsHandler)))).Queries("inventory", "")
 
 		// GetBucketVersioning
 		bucket.Methods(http.MethodGet).HandlerFunc(
@@ -271,6 +277,9 @@ func registerAPIRouter(router *mux.Router) {
 		// PutBucketReplicationConfig
 		bucket.Methods(http.MethodPut).HandlerFunc(
 			collectAPIStats("putbucketreplicationconfiguration", maxClients(httpTraceAll(api.PutBucketReplicationConfigHandler)))).Queries("replication", "")
+		// PutBucketInventoryConfiguration
+		bucket.Methods(http.MethodPut).HandlerFunc(
+			collectAPIStats("putbucketinventoryconfiguration", maxClients(httpTraceAll(api.PutBucketInventoryConfigurationHandler)))).Queries("inventory", "")
 		// GetObjectRetention
 
 		// PutBucketEncryption
@@ -299,6 +308,9 @@ func registerAPIRouter(router *mux.Router) {
 		// HeadBucket
 		bucket.Methods(http.MethodHead).HandlerFunc(
 			collectAPIStats("headbucket", maxClients(httpTraceAll(api.HeadBucketHandler))))
+		// SelectObjectsContent
+		bucket.Methods(http.MethodPost).HandlerFunc(
+			collectAPIStats("selectobjectscontent", maxClients(httpTraceHdrs(api.SelectObjectsContentHandler)))).Queries("select", "").Queries("select-type", "2")
 		// PostPolicy
 		bucket.Methods(http.MethodPost).HeadersRegexp(xhttp.ContentType, "multipart/form-data*").HandlerFunc(
 			collectAPIStats("postpolicybucket", maxClients(httpTraceHdrs(api.PostPolicyBucketHandler))))
@@ -314,6 +326,9 @@ func registerAPIRouter(router *mux.Router) {
 		// DeleteBucketLifecycle
 		bucket.Methods(http.MethodDelete).HandlerFunc(
 			collectAPIStats("deletebucketlifecycle", maxClients(httpTraceAll(api.DeleteBucketLifecycleHandler)))).Queries("lifecycle", "")
+		// DeleteBucketInventoryConfiguration
+		bucket.Methods(http.MethodDelete).HandlerFunc(
+			collectAPIStats("deletebucketinventoryconfiguration", maxClients(httpTraceAll(api.DeleteBucketInventoryConfigurationHandler)))).Queries("inventory", "")
 		// DeleteBucketEncryption
 		bucket.Methods(http.MethodDelete).HandlerFunc(
 			collectAPIStats("deletebucketencryption", maxClients(httpTraceAll(api.DeleteBucketEncryptionHandler)))).Queries("encryption", "")
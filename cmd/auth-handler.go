@@ -30,6 +30,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/minio/minio-go/v7/pkg/tags"
 	xhttp "github.com/minio/minio/cmd/http"
 	xjwt "github.com/minio/minio/cmd/jwt"
 	"github.com/minio/minio/cmd/logger"
@@ -334,17 +335,47 @@ func checkRequestAuthTypeToAccessKey(ctx context.Context, r *http.Request, actio
 		// Populate payload again to handle it in HTTP handler.
 		r.Body = ioutil.NopCloser(bytes.NewReader(payload))
 	}
+
+	// Request tags are evaluated by the s3:RequestObjectTag conditions,
+	// the owner is always allowed.
+	var requestTags map[string]string
+	if !owner {
+		if action == policy.PutObjectTaggingAction {
+			// To extract tags from XML in request body, get copy of request body.
+			payload, err := ioutil.ReadAll(io.LimitReader(r.Body, maxObjectTaggingSize))
+			if err != nil {
+				return accessKey, owner, toAPIErrorCode(ctx, err)
+			}
+
+			// Invalid tags are reported by the HTTP handler.
+			if objTags, err := tags.ParseObjectXML(bytes.NewReader(payload)); err == nil {
+				requestTags = objTags.ToMap()
+			}
+
+			// Populate payload again to handle it in HTTP handler.
+			r.Body = ioutil.NopCloser(io.MultiReader(bytes.NewReader(payload), r.Body))
+		}
+	}
+
 	if cred.AccessKey != "" {
 		logger.GetReqInfo(ctx).AccessKey = cred.AccessKey
 	}
 
 	if action != policy.ListAllMyBucketsAction && cred.AccessKey == "" {
+		conditionValues := getConditionValues(r, locationConstraint, "", nil)
+		if err := setExistingObjectTagConditionValues(ctx, r, action, bucketName, objectName, cred, owner, nil, conditionValues); err != nil {
+			return cred.AccessKey, owner, toAPIErrorCode(ctx, err)
+		}
+		if requestTags != nil {
+			setRequestObjectTagValues(conditionValues, requestTags)
+		}
+
 		// Anonymous checks are not meant for ListBuckets action
 		if globalPolicySys.IsAllowed(policy.Args{
 			AccountName:     cred.AccessKey,
 			Action:          action,
 			BucketName:      bucketName,
-			ConditionValues: getConditionValues(r, locationConstraint, "", nil),
+			ConditionValues: conditionValues,
 			IsOwner:         false,
 			ObjectName:      objectName,
 		}) {
@@ -359,7 +390,7 @@ func checkRequestAuthTypeToAccessKey(ctx context.Context, r *http.Request, actio
 				AccountName:     cred.AccessKey,
 				Action:          policy.ListBucketAction,
 				BucketName:      bucketName,
-				ConditionValues: getConditionValues(r, locationConstraint, "", nil),
+				ConditionValues: conditionValues,
 				IsOwner:         false,
 				ObjectName:      objectName,
 			}) {
@@ -371,11 +402,19 @@ func checkRequestAuthTypeToAccessKey(ctx context.Context, r *http.Request, actio
 		return cred.AccessKey, owner, ErrAccessDenied
 	}
 
+	conditionValues := getConditionValues(r, "", cred.AccessKey, claims)
+	if err := setExistingObjectTagConditionValues(ctx, r, action, bucketName, objectName, cred, owner, claims, conditionValues); err != nil {
+		return cred.AccessKey, owner, toAPIErrorCode(ctx, err)
+	}
+	if requestTags != nil {
+		setRequestObjectTagValues(conditionValues, requestTags)
+	}
+
 	if globalIAMSys.IsAllowed(iampolicy.Args{
 		AccountName:     cred.AccessKey,
 		Action:          iampolicy.Action(action),
 		BucketName:      bucketName,
-		ConditionValues: getConditionValues(r, "", cred.AccessKey, claims),
+		ConditionValues: conditionValues,
 		ObjectName:      objectName,
 		IsOwner:         owner,
 		Claims:          claims,
@@ -391,7 +430,7 @@ func checkRequestAuthTypeToAccessKey(ctx context.Context, r *http.Request, actio
 			AccountName:     cred.AccessKey,
 			Action:          iampolicy.ListBucketAction,
 			BucketName:      bucketName,
-			ConditionValues: getConditionValues(r, "", cred.AccessKey, claims),
+			ConditionValues: conditionValues,
 			ObjectName:      objectName,
 			IsOwner:         owner,
 			Claims:          claims,
@@ -649,11 +688,15 @@ func isPutActionAllowed(ctx context.Context, atype authType, bucketName, objectN
 	}
 
 	if cred.AccessKey == "" {
+		conditionValues := getConditionValues(r, "", "", nil)
+		if err := setExistingObjectTagConditionValues(ctx, r, policy.Action(action), bucketName, objectName, cred, owner, nil, conditionValues); err != nil {
+			return toAPIErrorCode(ctx, err)
+		}
 		if globalPolicySys.IsAllowed(policy.Args{
 			AccountName:     cred.AccessKey,
 			Action:          policy.Action(action),
 			BucketName:      bucketName,
-			ConditionValues: getConditionValues(r, "", "", nil),
+			ConditionValues: conditionValues,
 			IsOwner:         false,
 			ObjectName:      objectName,
 		}) {
@@ -662,11 +705,15 @@ func isPutActionAllowed(ctx context.Context, atype authType, bucketName, objectN
 		return ErrAccessDenied
 	}
 
+	conditionValues := getConditionValues(r, "", cred.AccessKey, claims)
+	if err := setExistingObjectTagConditionValues(ctx, r, policy.Action(action), bucketName, objectName, cred, owner, claims, conditionValues); err != nil {
+		return toAPIErrorCode(ctx, err)
+	}
 	if globalIAMSys.IsAllowed(iampolicy.Args{
 		AccountName:     cred.AccessKey,
 		Action:          action,
 		BucketName:      bucketName,
-		ConditionValues: getConditionValues(r, "", cred.AccessKey, claims),
+		ConditionValues: conditionValues,
 		ObjectName:      objectName,
 		IsOwner:         owner,
 		Claims:          claims,
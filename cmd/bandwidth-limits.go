@@ -0,0 +1,208 @@
+/*
+ * MinIO Cloud Storage, (C) 2021 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/minio/minio/cmd/logger"
+	"github.com/minio/minio/pkg/auth"
+	"github.com/minio/minio/pkg/bucket/bandwidth"
+	"github.com/minio/minio/pkg/madmin"
+)
+
+const (
+	// Bucket and user bandwidth limits are saved in a single file.
+	bandwidthLimitsConfigFile = minioConfigPrefix + SlashSeparator + "bandwidth-limits.json"
+
+	bandwidthLimitsConfigVersion = 1
+
+	// TTL of the limits loaded from the backend, limits set on
+	// another node are enforced on this node after at most this long.
+	bandwidthLimitsConfigCacheTTL = 10 * time.Second
+)
+
+// bandwidthLimitsConfig holds the bandwidth limits of the client
+// traffic of the buckets and users.
+type bandwidthLimitsConfig struct {
+	Version int `json:"version"`
+	madmin.BandwidthLimits
+}
+
+// BandwidthLimitsSys throttles the client GET and PUT traffic of each
+// bucket and user to their bandwidth limits.
+type BandwidthLimitsSys struct {
+	mu          sync.Mutex // serializes updates of the limits.
+	configCache timedValue
+}
+
+// NewBandwidthLimitsSys returns initialized BandwidthLimitsSys
+func NewBandwidthLimitsSys() *BandwidthLimitsSys {
+	return &BandwidthLimitsSys{}
+}
+
+func loadBandwidthLimitsConfig(ctx context.Context, objAPI ObjectLayer) (cfg bandwidthLimitsConfig, err error) {
+	data, err := readConfig(ctx, objAPI, bandwidthLimitsConfigFile)
+	if err != nil {
+		if errors.Is(err, errConfigNotFound) {
+			return bandwidthLimitsConfig{Version: bandwidthLimitsConfigVersion}, nil
+		}
+		return cfg, err
+	}
+	if err = json.Unmarshal(data, &cfg); err != nil {
+		return cfg, err
+	}
+	return cfg, nil
+}
+
+// Get returns the bandwidth limits of the buckets and users.
+func (sys *BandwidthLimitsSys) Get(objAPI ObjectLayer) (madmin.BandwidthLimits, error) {
+	sys.configCache.Once.Do(func() {
+		sys.configCache.TTL = bandwidthLimitsConfigCacheTTL
+		sys.configCache.Update = func() (interface{}, error) {
+			ctx, done := context.WithTimeout(context.Background(), 5*time.Second)
+			defer done()
+			return loadBandwidthLimitsConfig(ctx, objAPI)
+		}
+	})
+	v, err := sys.configCache.Get()
+	if err != nil {
+		return madmin.BandwidthLimits{}, err
+	}
+	return v.(bandwidthLimitsConfig).BandwidthLimits, nil
+}
+
+// Set sets the bandwidth limit of the bucket or user, an empty limit removes it.
+func (sys *BandwidthLimitsSys) Set(ctx context.Context, objAPI ObjectLayer, name string, isUser bool, limit madmin.BandwidthLimit) error {
+	sys.mu.Lock()
+	defer sys.mu.Unlock()
+
+	cfg, err := loadBandwidthLimitsConfig(ctx, objAPI)
+	if err != nil {
+		return err
+	}
+	limits := cfg.Buckets
+	if isUser {
+		limits = cfg.Users
+	}
+	// Copy the limits, the cached config must not be modified.
+	updated := make(map[string]madmin.BandwidthLimit, len(limits)+1)
+	for k, v := range limits {
+		updated[k] = v
+	}
+	if limit.Get > 0 || limit.Put > 0 {
+		updated[name] = limit
+	} else {
+		delete(updated, name)
+	}
+	if isUser {
+		cfg.Users = updated
+	} else {
+		cfg.Buckets = updated
+	}
+	cfg.Version = bandwidthLimitsConfigVersion
+
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		return err
+	}
+	if err = saveConfig(ctx, objAPI, bandwidthLimitsConfigFile, data); err != nil {
+		return err
+	}
+	sys.configCache.update(cfg)
+	return nil
+}
+
+// clientLimit returns the limit of this node of a cluster wide limit,
+// which is shared equally by all the nodes.
+func clientLimit(clusterBandwidth uint64) bandwidth.ClientLimit {
+	if clusterBandwidth == 0 {
+		return bandwidth.ClientLimit{}
+	}
+	peers, _ := globalEndpoints.peers()
+	totalNodesCount := len(peers)
+	if totalNodesCount == 0 {
+		totalNodesCount = 1 // For standalone erasure coding
+	}
+	limit := int64(clusterBandwidth) / int64(totalNodesCount)
+	if limit == 0 {
+		limit = 1
+	}
+	return bandwidth.ClientLimit{
+		BytesPerSecond:   limit,
+		ClusterBandwidth: int64(clusterBandwidth),
+	}
+}
+
+// clientTraffic returns the client traffic of the request on the bucket
+// with the credentials, ok is false if the traffic is not monitored.
+func (sys *BandwidthLimitsSys) clientTraffic(ctx context.Context, bucket string, cred auth.Credentials, put bool) (traffic bandwidth.ClientTraffic, ok bool) {
+	objAPI := newObjectLayerFn()
+	if globalIsGateway || objAPI == nil || globalBucketMonitor == nil {
+		return traffic, false
+	}
+
+	limits, err := sys.Get(objAPI)
+	if err != nil {
+		// Do not fail the request, the traffic is not throttled.
+		logger.LogIf(ctx, err)
+	}
+
+	traffic = bandwidth.ClientTraffic{
+		Bucket: bucket,
+		User:   objectOwner(cred),
+		Put:    put,
+	}
+	limitOf := func(l madmin.BandwidthLimit) uint64 {
+		if put {
+			return l.Put
+		}
+		return l.Get
+	}
+	traffic.BucketLimit = clientLimit(limitOf(limits.Buckets[bucket]))
+	if traffic.User != "" {
+		traffic.UserLimit = clientLimit(limitOf(limits.Users[traffic.User]))
+	}
+	return traffic, true
+}
+
+// newClientBandwidthReader returns a reader of the data uploaded to the
+// bucket with the credentials, throttled by the bucket and user limits.
+func newClientBandwidthReader(ctx context.Context, bucket string, cred auth.Credentials, reader io.Reader) io.Reader {
+	traffic, ok := globalBandwidthLimitsSys.clientTraffic(ctx, bucket, cred, true)
+	if !ok {
+		return reader
+	}
+	// The throttles are shared by all requests, they must outlive this one.
+	return bandwidth.NewClientReader(GlobalContext, globalBucketMonitor, traffic, reader)
+}
+
+// newClientBandwidthWriter returns a writer of the data downloaded from
+// the bucket with the credentials, throttled by the bucket and user limits.
+func newClientBandwidthWriter(ctx context.Context, bucket string, cred auth.Credentials, writer io.Writer) io.Writer {
+	traffic, ok := globalBandwidthLimitsSys.clientTraffic(ctx, bucket, cred, false)
+	if !ok {
+		return writer
+	}
+	// The throttles are shared by all requests, they must outlive this one.
+	return bandwidth.NewClientWriter(GlobalContext, globalBucketMonitor, traffic, writer)
+}
@@ -0,0 +1,77 @@
+/*
+ * MinIO Cloud Storage, (C) 2021 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/minio/minio/pkg/auth"
+	"github.com/minio/minio/pkg/madmin"
+)
+
+func TestBandwidthLimits(t *testing.T) {
+	objAPI, fsDir, err := prepareFS()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(fsDir)
+	newAllSubsystems()
+	setObjectLayer(objAPI)
+	defer setObjectLayer(nil)
+
+	ctx := context.Background()
+	sys := globalBandwidthLimitsSys
+	if err = sys.Set(ctx, objAPI, "bucket", false, madmin.BandwidthLimit{Get: 1000}); err != nil {
+		t.Fatal(err)
+	}
+	if err = sys.Set(ctx, objAPI, "alice", true, madmin.BandwidthLimit{Put: 2000}); err != nil {
+		t.Fatal(err)
+	}
+	if err = sys.Set(ctx, objAPI, "bob", true, madmin.BandwidthLimit{Put: 2000}); err != nil {
+		t.Fatal(err)
+	}
+	if err = sys.Set(ctx, objAPI, "bob", true, madmin.BandwidthLimit{}); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := loadBandwidthLimitsConfig(ctx, objAPI)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cfg.Buckets) != 1 || cfg.Buckets["bucket"].Get != 1000 {
+		t.Fatalf("unexpected bucket limits %v", cfg.Buckets)
+	}
+	if len(cfg.Users) != 1 || cfg.Users["alice"].Put != 2000 {
+		t.Fatalf("unexpected user limits %v", cfg.Users)
+	}
+
+	// Service accounts are limited by the limits of their parent user.
+	cred := auth.Credentials{AccessKey: "svc", ParentUser: "alice"}
+	traffic, ok := sys.clientTraffic(ctx, "bucket", cred, true)
+	if !ok {
+		t.Fatal("expected the client traffic to be monitored")
+	}
+	if traffic.User != "alice" || traffic.BucketLimit.BytesPerSecond != 0 || traffic.UserLimit.BytesPerSecond != 2000 {
+		t.Fatalf("unexpected PUT traffic %+v", traffic)
+	}
+	traffic, _ = sys.clientTraffic(ctx, "bucket", cred, false)
+	if traffic.BucketLimit.BytesPerSecond != 1000 || traffic.UserLimit.BytesPerSecond != 0 {
+		t.Fatalf("unexpected GET traffic %+v", traffic)
+	}
+}
@@ -0,0 +1,858 @@
+/*
+ * MinIO Cloud Storage, (C) 2021 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	humanize "github.com/dustin/go-humanize"
+	miniogo "github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/encrypt"
+	"github.com/minio/minio-go/v7/pkg/tags"
+	"github.com/minio/minio/cmd/crypto"
+	xhttp "github.com/minio/minio/cmd/http"
+	"github.com/minio/minio/cmd/logger"
+	objectlock "github.com/minio/minio/pkg/bucket/object/lock"
+	"github.com/minio/minio/pkg/bucket/replication"
+	"github.com/minio/minio/pkg/event"
+	"github.com/minio/minio/pkg/hash"
+	"github.com/minio/minio/pkg/madmin"
+	yaml "gopkg.in/yaml.v2"
+)
+
+const (
+	// Batch jobs are saved in one file per job, with their progress.
+	batchJobsPrefix = minioConfigPrefix + SlashSeparator + "batch-jobs"
+
+	// Maximum size of the specification of a batch job.
+	maxBatchJobSpecSize = 1 << 20
+
+	// Interval at which the progress of running jobs is saved.
+	batchJobSaveInterval = 10 * time.Second
+
+	// A job not updated for this long is assumed to have been
+	// interrupted by a restart of the node running it.
+	batchJobStaleAfter = 5 * time.Minute
+)
+
+var (
+	errBatchJobSSEC          = errors.New("objects encrypted with SSE-C cannot be processed by batch jobs")
+	errBatchJobNotReplicated = errors.New("object does not match any replication rule")
+	errBatchJobObjectLocked  = errors.New("object under retention or legal hold cannot be rewritten")
+)
+
+// batchJobFilters select the objects processed by a batch job, all
+// the filters which are set must match.
+type batchJobFilters struct {
+	// Objects last modified more than OlderThan ago,
+	// or less than NewerThan ago, e.g 720h.
+	OlderThan time.Duration `yaml:"olderThan"`
+	NewerThan time.Duration `yaml:"newerThan"`
+	// Objects of at least MinSize and at most MaxSize, e.g 10MiB.
+	MinSize string `yaml:"minSize"`
+	MaxSize string `yaml:"maxSize"`
+	// Objects with all these tags.
+	Tags map[string]string `yaml:"tags"`
+}
+
+// batchJobAction is the action applied to the matching objects.
+type batchJobAction struct {
+	Type madmin.BatchJobType `yaml:"type"`
+	// Destination of copy, either a bucket of this deployment or
+	// the ARN of a remote target. Prefix is prepended to the names
+	// of the copies.
+	Bucket string `yaml:"bucket"`
+	ARN    string `yaml:"arn"`
+	Prefix string `yaml:"prefix"`
+	// Tags added by tag to the tags of the objects.
+	Tags map[string]string `yaml:"tags"`
+}
+
+// batchJobRequest is the specification of a batch job, in YAML or JSON.
+type batchJobRequest struct {
+	Bucket  string          `yaml:"bucket"`
+	Prefix  string          `yaml:"prefix"`
+	Filters batchJobFilters `yaml:"filters"`
+	Action  batchJobAction  `yaml:"action"`
+
+	minSize, maxSize uint64
+}
+
+// parseBatchJobRequest parses and validates the specification of a batch job.
+func parseBatchJobRequest(data []byte) (*batchJobRequest, error) {
+	var req batchJobRequest
+	if err := yaml.UnmarshalStrict(data, &req); err != nil {
+		return nil, InvalidBatchJob{Reason: err.Error()}
+	}
+	if req.Bucket == "" {
+		return nil, InvalidBatchJob{Reason: "bucket is required"}
+	}
+
+	var err error
+	if req.Filters.MinSize != "" {
+		if req.minSize, err = humanize.ParseBytes(req.Filters.MinSize); err != nil {
+			return nil, InvalidBatchJob{Reason: fmt.Sprintf("invalid minSize: %v", err)}
+		}
+	}
+	if req.Filters.MaxSize != "" {
+		if req.maxSize, err = humanize.ParseBytes(req.Filters.MaxSize); err != nil {
+			return nil, InvalidBatchJob{Reason: fmt.Sprintf("invalid maxSize: %v", err)}
+		}
+	}
+	if len(req.Filters.Tags) > 0 {
+		if _, err = tags.NewTags(req.Filters.Tags, true); err != nil {
+			return nil, InvalidBatchJob{Reason: fmt.Sprintf("invalid tags filter: %v", err)}
+		}
+	}
+
+	action := req.Action
+	switch action.Type {
+	case madmin.BatchJobCopy:
+		if (action.Bucket == "") == (action.ARN == "") {
+			return nil, InvalidBatchJob{Reason: "copy requires either a destination bucket or arn"}
+		}
+		if action.ARN != "" {
+			if _, err = madmin.ParseARN(action.ARN); err != nil {
+				return nil, InvalidBatchJob{Reason: fmt.Sprintf("invalid arn: %v", err)}
+			}
+		}
+		// Copies within the bucket must not be listed by the job.
+		if action.Bucket == req.Bucket && (action.Prefix == "" || strings.HasPrefix(action.Prefix, req.Prefix)) {
+			return nil, InvalidBatchJob{Reason: "copies within the bucket require a destination prefix outside of the prefix of the job"}
+		}
+	case madmin.BatchJobTag:
+		if len(action.Tags) == 0 {
+			return nil, InvalidBatchJob{Reason: "tag requires tags"}
+		}
+		if _, err = tags.NewTags(action.Tags, true); err != nil {
+			return nil, InvalidBatchJob{Reason: fmt.Sprintf("invalid tags: %v", err)}
+		}
+	case madmin.BatchJobDelete, madmin.BatchJobReencrypt, madmin.BatchJobReplicate:
+	default:
+		return nil, InvalidBatchJob{Reason: fmt.Sprintf("unsupported action '%s'", action.Type)}
+	}
+	return &req, nil
+}
+
+// validate checks the buckets, remote targets and features required
+// by the job are available.
+func (req *batchJobRequest) validate(ctx context.Context, objAPI ObjectLayer) error {
+	if _, err := objAPI.GetBucketInfo(ctx, req.Bucket); err != nil {
+		return err
+	}
+	switch req.Action.Type {
+	case madmin.BatchJobCopy:
+		if req.Action.ARN != "" {
+			if globalBucketTargetSys.GetRemoteTargetClient(ctx, req.Action.ARN) == nil {
+				return BucketRemoteTargetNotFound{Bucket: req.Bucket}
+			}
+			return nil
+		}
+		if _, err := objAPI.GetBucketInfo(ctx, req.Action.Bucket); err != nil {
+			return err
+		}
+	case madmin.BatchJobTag:
+		if !objAPI.IsTaggingSupported() {
+			return NotImplemented{}
+		}
+	case madmin.BatchJobReencrypt:
+		if GlobalKMS == nil {
+			return errKMSNotConfigured
+		}
+	case madmin.BatchJobReplicate:
+		if !globalIsErasure {
+			return NotImplemented{}
+		}
+		if _, err := getReplicationConfig(ctx, req.Bucket); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// matches returns true if the object matches the filters of the job.
+func (req *batchJobRequest) matches(oi ObjectInfo, now time.Time) bool {
+	f := req.Filters
+	if f.OlderThan > 0 && now.Sub(oi.ModTime) < f.OlderThan {
+		return false
+	}
+	if f.NewerThan > 0 && now.Sub(oi.ModTime) >= f.NewerThan {
+		return false
+	}
+	if req.minSize > 0 || req.maxSize > 0 {
+		size, err := oi.GetActualSize()
+		if err != nil {
+			return false
+		}
+		if uint64(size) < req.minSize || (req.maxSize > 0 && uint64(size) > req.maxSize) {
+			return false
+		}
+	}
+	if len(f.Tags) > 0 {
+		t, err := tags.ParseObjectTags(oi.UserTags)
+		if err != nil {
+			return false
+		}
+		objTags := t.ToMap()
+		for k, v := range f.Tags {
+			if objTags[k] != v {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// apply applies the action of the job to the object, returns the size
+// of the object.
+func (req *batchJobRequest) apply(ctx context.Context, objAPI ObjectLayer, oi ObjectInfo) (int64, error) {
+	size, err := oi.GetActualSize()
+	if err != nil {
+		return 0, err
+	}
+	switch req.Action.Type {
+	case madmin.BatchJobCopy:
+		if req.Action.ARN != "" {
+			err = batchJobCopyObjectRemote(ctx, objAPI, oi, req.Action.ARN, req.Action.Prefix+oi.Name)
+			break
+		}
+		// Copies are encrypted like the object, or as required by the destination bucket.
+		_, sseErr := globalBucketSSEConfigSys.Get(req.Action.Bucket)
+		sseS3 := crypto.S3.IsEncrypted(oi.UserDefined) || globalAutoEncryption || sseErr == nil
+		err = batchJobCopyObject(ctx, objAPI, oi, req.Action.Bucket, req.Action.Prefix+oi.Name, sseS3)
+	case madmin.BatchJobReencrypt:
+		err = batchJobCopyObject(ctx, objAPI, oi, oi.Bucket, oi.Name, true)
+	case madmin.BatchJobTag:
+		err = batchJobTagObject(ctx, objAPI, oi, req.Action.Tags)
+	case madmin.BatchJobDelete:
+		err = batchJobDeleteObject(ctx, objAPI, oi)
+	case madmin.BatchJobReplicate:
+		err = batchJobReplicateObject(ctx, oi)
+	}
+	return size, err
+}
+
+// batchJobObjectMetadata returns the metadata of the object to be
+// written with its copy, the object lock metadata is kept only if
+// the object is rewritten in place.
+func batchJobObjectMetadata(oi ObjectInfo, inPlace bool) map[string]string {
+	metadata := cloneMSS(oi.UserDefined)
+	crypto.RemoveInternalEntries(metadata)
+	for k := range metadata {
+		if k != objectOwnerKey && strings.HasPrefix(strings.ToLower(k), ReservedMetadataPrefixLower) {
+			delete(metadata, k)
+		}
+	}
+	metadata = filterReplicationStatusMetadata(metadata)
+	if !inPlace {
+		metadata = objectlock.FilterObjectLockMetadata(metadata, true, true)
+	}
+	if oi.UserTags != "" {
+		metadata[xhttp.AmzObjectTagging] = oi.UserTags
+	}
+	return metadata
+}
+
+// batchJobCopyObject copies the object to the destination, encrypted
+// with SSE-S3 if sseS3 is set. An object copied onto itself keeps its
+// version ID in versioned buckets, locked versions are not rewritten.
+func batchJobCopyObject(ctx context.Context, objAPI ObjectLayer, oi ObjectInfo, dstBucket, dstObject string, sseS3 bool) error {
+	if crypto.SSEC.IsEncrypted(oi.UserDefined) {
+		return errBatchJobSSEC
+	}
+
+	// Like CopyObject, an object copied onto itself is read without lock.
+	inPlace := oi.Bucket == dstBucket && oi.Name == dstObject
+	lock := readLock
+	if inPlace {
+		lock = noLock
+	}
+	gr, err := objAPI.GetObjectNInfo(ctx, oi.Bucket, oi.Name, nil, http.Header{}, lock, ObjectOptions{
+		VersionID: oi.VersionID,
+	})
+	if err != nil {
+		return err
+	}
+	defer gr.Close()
+	srcInfo := gr.ObjInfo
+
+	// Rewriting a locked version in place would replace its data,
+	// like deleting it.
+	if inPlace && enforceRetentionForDeletion(ctx, srcInfo) {
+		return errBatchJobObjectLocked
+	}
+
+	actualSize, err := srcInfo.GetActualSize()
+	if err != nil {
+		return err
+	}
+	metadata := batchJobObjectMetadata(srcInfo, inPlace)
+
+	hashReader, err := hash.NewReader(gr, actualSize, "", "", actualSize, globalCLIContext.StrictS3Compat)
+	if err != nil {
+		return err
+	}
+	pReader := NewPutObjReader(hashReader)
+	if sseS3 {
+		reader, objEncKey, err := newEncryptReader(hashReader, nil, dstBucket, dstObject, metadata, true)
+		if err != nil {
+			return err
+		}
+		info := ObjectInfo{Size: actualSize}
+		encReader, err := hash.NewReader(reader, info.EncryptedSize(), "", "", actualSize, globalCLIContext.StrictS3Compat)
+		if err != nil {
+			return err
+		}
+		if pReader, err = pReader.WithEncryption(encReader, &objEncKey); err != nil {
+			return err
+		}
+	}
+
+	opts := ObjectOptions{
+		UserDefined:      metadata,
+		Versioned:        globalBucketVersioningSys.PrefixEnabled(dstBucket, dstObject),
+		VersionSuspended: globalBucketVersioningSys.PrefixSuspended(dstBucket, dstObject),
+	}
+	if inPlace && opts.Versioned && srcInfo.VersionID != "" && srcInfo.VersionID != nullVersionID {
+		opts.VersionID = srcInfo.VersionID
+		opts.MTime = srcInfo.ModTime
+	}
+
+	replicate, sync := mustReplicater(ctx, dstBucket, dstObject, metadata, "")
+	if replicate {
+		metadata[xhttp.AmzBucketReplicationStatus] = replication.Pending.String()
+	}
+
+	objInfo, err := objAPI.PutObject(ctx, dstBucket, dstObject, pReader, opts)
+	if err != nil {
+		return err
+	}
+	invalidateCachedObject(dstBucket, dstObject)
+	if replicate {
+		scheduleReplication(ctx, objInfo.Clone(), objAPI, sync)
+	}
+
+	sendEvent(eventArgs{
+		EventName:  event.ObjectCreatedCopy,
+		BucketName: dstBucket,
+		Object:     objInfo,
+		Host:       "Internal: [Batch-Job]",
+	})
+	return nil
+}
+
+// batchJobCopyObjectRemote copies the object to the bucket of the
+// remote target with the ARN.
+func batchJobCopyObjectRemote(ctx context.Context, objAPI ObjectLayer, oi ObjectInfo, arn, dstObject string) error {
+	if crypto.SSEC.IsEncrypted(oi.UserDefined) {
+		return errBatchJobSSEC
+	}
+	tgt := globalBucketTargetSys.GetRemoteTargetClient(ctx, arn)
+	if tgt == nil {
+		return BucketRemoteTargetNotFound{Bucket: oi.Bucket}
+	}
+	tgtArn, err := madmin.ParseARN(arn)
+	if err != nil {
+		return err
+	}
+
+	gr, err := objAPI.GetObjectNInfo(ctx, oi.Bucket, oi.Name, nil, http.Header{}, readLock, ObjectOptions{
+		VersionID: oi.VersionID,
+	})
+	if err != nil {
+		return err
+	}
+	defer gr.Close()
+	srcInfo := gr.ObjInfo
+
+	actualSize, err := srcInfo.GetActualSize()
+	if err != nil {
+		return err
+	}
+
+	putOpts := miniogo.PutObjectOptions{
+		UserMetadata:    make(map[string]string),
+		ContentType:     srcInfo.ContentType,
+		ContentEncoding: srcInfo.ContentEncoding,
+		StorageClass:    srcInfo.StorageClass,
+	}
+	for k, v := range srcInfo.UserDefined {
+		if strings.HasPrefix(strings.ToLower(k), "x-amz-meta-") {
+			putOpts.UserMetadata[k] = v
+		}
+	}
+	if srcInfo.UserTags != "" {
+		t, err := tags.ParseObjectTags(srcInfo.UserTags)
+		if err != nil {
+			return err
+		}
+		putOpts.UserTags = t.ToMap()
+	}
+	if crypto.S3.IsEncrypted(srcInfo.UserDefined) {
+		putOpts.ServerSideEncryption = encrypt.NewSSE()
+	}
+
+	_, err = tgt.PutObject(ctx, tgtArn.Bucket, dstObject, gr, actualSize, putOpts)
+	return err
+}
+
+// batchJobTagObject adds the tags to the tags of the object.
+func batchJobTagObject(ctx context.Context, objAPI ObjectLayer, oi ObjectInfo, newTags map[string]string) error {
+	t, err := tags.ParseObjectTags(oi.UserTags)
+	if err != nil {
+		return err
+	}
+	objTags := t.ToMap()
+	for k, v := range newTags {
+		objTags[k] = v
+	}
+	if t, err = tags.NewTags(objTags, true); err != nil {
+		return err
+	}
+	tagsStr := t.String()
+
+	opts := ObjectOptions{VersionID: oi.VersionID}
+	replicate, sync := mustReplicater(ctx, oi.Bucket, oi.Name, map[string]string{xhttp.AmzObjectTagging: tagsStr}, "")
+	if replicate {
+		opts.UserDefined = map[string]string{
+			xhttp.AmzBucketReplicationStatus: replication.Pending.String(),
+		}
+	}
+
+	objInfo, err := objAPI.PutObjectTags(ctx, oi.Bucket, oi.Name, tagsStr, opts)
+	if err != nil {
+		return err
+	}
+	if replicate {
+		scheduleReplication(ctx, objInfo.Clone(), objAPI, sync)
+	}
+
+	sendEvent(eventArgs{
+		EventName:  event.ObjectCreatedPutTagging,
+		BucketName: oi.Bucket,
+		Object:     objInfo,
+		Host:       "Internal: [Batch-Job]",
+	})
+	return nil
+}
+
+// batchJobDeleteObject deletes the object, a delete marker is created
+// in versioned buckets.
+func batchJobDeleteObject(ctx context.Context, objAPI ObjectLayer, oi ObjectInfo) error {
+	opts := ObjectOptions{
+		Versioned:        globalBucketVersioningSys.PrefixEnabled(oi.Bucket, oi.Name),
+		VersionSuspended: globalBucketVersioningSys.PrefixSuspended(oi.Bucket, oi.Name),
+	}
+	_, replicateDel, replicateSync := checkReplicateDelete(ctx, oi.Bucket, ObjectToDelete{ObjectName: oi.Name}, oi, nil)
+	if replicateDel {
+		opts.DeleteMarkerReplicationStatus = string(replication.Pending)
+	}
+
+	objInfo, err := objAPI.DeleteObject(ctx, oi.Bucket, oi.Name, opts)
+	if err != nil {
+		return err
+	}
+	invalidateCachedObject(oi.Bucket, oi.Name)
+
+	eventName := event.ObjectRemovedDelete
+	if objInfo.DeleteMarker {
+		eventName = event.ObjectRemovedDeleteMarkerCreated
+	}
+	sendEvent(eventArgs{
+		EventName:  eventName,
+		BucketName: oi.Bucket,
+		Object:     objInfo,
+		Host:       "Internal: [Batch-Job]",
+	})
+
+	if replicateDel && objInfo.DeleteMarker {
+		scheduleReplicationDelete(ctx, DeletedObjectVersionInfo{
+			DeletedObject: DeletedObject{
+				ObjectName:                    oi.Name,
+				DeleteMarkerVersionID:         objInfo.VersionID,
+				DeleteMarkerReplicationStatus: string(objInfo.ReplicationStatus),
+				DeleteMarkerMTime:             DeleteMarkerMTime{objInfo.ModTime},
+				DeleteMarker:                  true,
+			},
+			Bucket: oi.Bucket,
+		}, objAPI, replicateSync)
+	}
+	return nil
+}
+
+// batchJobReplicateObject queues the object for replication to the
+// targets of the replication rules it matches.
+func batchJobReplicateObject(ctx context.Context, oi ObjectInfo) error {
+	metadata := cloneMSS(oi.UserDefined)
+	if oi.UserTags != "" {
+		metadata[xhttp.AmzObjectTagging] = oi.UserTags
+	}
+	replicate, _ := mustReplicater(ctx, oi.Bucket, oi.Name, metadata, oi.ReplicationStatus.String())
+	if !replicate {
+		return errBatchJobNotReplicated
+	}
+	globalReplicationState.queueReplicaTask(oi)
+	return nil
+}
+
+// batchJob is a batch job, its specification is saved with its progress.
+type batchJob struct {
+	Spec   string                `json:"spec"`
+	Status madmin.BatchJobStatus `json:"status"`
+}
+
+func batchJobFile(id string) string {
+	return pathJoin(batchJobsPrefix, id+".json")
+}
+
+func loadBatchJob(ctx context.Context, objAPI ObjectLayer, id string) (job batchJob, err error) {
+	data, err := readConfig(ctx, objAPI, batchJobFile(id))
+	if err != nil {
+		if errors.Is(err, errConfigNotFound) {
+			return job, BatchJobNotFound{ID: id}
+		}
+		return job, err
+	}
+	if err = json.Unmarshal(data, &job); err != nil {
+		return job, err
+	}
+	return job, nil
+}
+
+func listBatchJobIDs(ctx context.Context, objAPI ObjectLayer) ([]string, error) {
+	var ids []string
+	marker := ""
+	for {
+		res, err := objAPI.ListObjects(ctx, minioMetaBucket, batchJobsPrefix+SlashSeparator, marker, "", maxObjectList)
+		if err != nil {
+			return nil, err
+		}
+		for _, obj := range res.Objects {
+			ids = append(ids, strings.TrimSuffix(path.Base(obj.Name), ".json"))
+		}
+		if !res.IsTruncated {
+			return ids, nil
+		}
+		marker = res.NextMarker
+	}
+}
+
+// batchJobRun tracks a batch job running on this node.
+type batchJobRun struct {
+	mu     sync.Mutex
+	job    batchJob
+	req    *batchJobRequest
+	cancel context.CancelFunc
+}
+
+func (jr *batchJobRun) update(fn func(s *madmin.BatchJobStatus)) {
+	jr.mu.Lock()
+	defer jr.mu.Unlock()
+	fn(&jr.job.Status)
+	jr.job.Status.LastUpdate = UTCNow()
+}
+
+func (jr *batchJobRun) status() madmin.BatchJobStatus {
+	jr.mu.Lock()
+	defer jr.mu.Unlock()
+	return jr.job.Status
+}
+
+func (jr *batchJobRun) save(ctx context.Context, objAPI ObjectLayer) error {
+	jr.mu.Lock()
+	data, err := json.Marshal(jr.job)
+	id := jr.job.Status.ID
+	jr.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	return saveConfig(ctx, objAPI, batchJobFile(id), data)
+}
+
+// BatchJobsSys runs the batch jobs started on this node, jobs are
+// resumed from their last saved progress when the node restarts.
+type BatchJobsSys struct {
+	mu   sync.Mutex
+	jobs map[string]*batchJobRun
+}
+
+// NewBatchJobsSys returns initialized BatchJobsSys
+func NewBatchJobsSys() *BatchJobsSys {
+	return &BatchJobsSys{
+		jobs: make(map[string]*batchJobRun),
+	}
+}
+
+// Init resumes the jobs of this node interrupted by its restart.
+func (sys *BatchJobsSys) Init(ctx context.Context, objAPI ObjectLayer) {
+	go func() {
+		ids, err := listBatchJobIDs(ctx, objAPI)
+		if err != nil {
+			logger.LogIf(ctx, fmt.Errorf("Unable to list batch jobs: %w", err))
+			return
+		}
+		node := GetLocalPeer(globalEndpoints)
+		for _, id := range ids {
+			job, err := loadBatchJob(ctx, objAPI, id)
+			if err != nil {
+				logger.LogIf(ctx, err)
+				continue
+			}
+			if job.Status.Status != madmin.BatchJobRunning || job.Status.Node != node {
+				continue
+			}
+			req, err := parseBatchJobRequest([]byte(job.Spec))
+			if err != nil {
+				logger.LogIf(ctx, err)
+				continue
+			}
+			sys.start(objAPI, &batchJobRun{job: job, req: req})
+		}
+	}()
+}
+
+// Start validates the specification of a batch job and runs it in the
+// background, returns the ID of the job.
+func (sys *BatchJobsSys) Start(ctx context.Context, objAPI ObjectLayer, spec []byte) (string, error) {
+	req, err := parseBatchJobRequest(spec)
+	if err != nil {
+		return "", err
+	}
+	if err = req.validate(ctx, objAPI); err != nil {
+		return "", err
+	}
+
+	now := UTCNow()
+	jr := &batchJobRun{
+		job: batchJob{
+			Spec: string(spec),
+			Status: madmin.BatchJobStatus{
+				ID:         mustGetUUID(),
+				Type:       req.Action.Type,
+				Status:     madmin.BatchJobRunning,
+				Bucket:     req.Bucket,
+				Prefix:     req.Prefix,
+				Node:       GetLocalPeer(globalEndpoints),
+				StartTime:  now,
+				LastUpdate: now,
+			},
+		},
+		req: req,
+	}
+	if err = jr.save(ctx, objAPI); err != nil {
+		return "", err
+	}
+	sys.start(objAPI, jr)
+	return jr.job.Status.ID, nil
+}
+
+func (sys *BatchJobsSys) start(objAPI ObjectLayer, jr *batchJobRun) {
+	ctx, cancel := context.WithCancel(GlobalContext)
+	jr.cancel = cancel
+
+	sys.mu.Lock()
+	sys.jobs[jr.job.Status.ID] = jr
+	sys.mu.Unlock()
+
+	go sys.run(ctx, objAPI, jr)
+}
+
+// Status returns the progress of the job.
+func (sys *BatchJobsSys) Status(ctx context.Context, objAPI ObjectLayer, id string) (madmin.BatchJobStatus, error) {
+	sys.mu.Lock()
+	jr, ok := sys.jobs[id]
+	sys.mu.Unlock()
+	if ok {
+		return jr.status(), nil
+	}
+
+	job, err := loadBatchJob(ctx, objAPI, id)
+	if err != nil {
+		return madmin.BatchJobStatus{}, err
+	}
+	status := job.Status
+	if status.Status == madmin.BatchJobRunning && UTCNow().Sub(status.LastUpdate) > batchJobStaleAfter {
+		status.Status = madmin.BatchJobFailed
+		status.Error = "job was interrupted"
+	}
+	return status, nil
+}
+
+// List returns the progress of all the jobs, most recent first.
+func (sys *BatchJobsSys) List(ctx context.Context, objAPI ObjectLayer) ([]madmin.BatchJobStatus, error) {
+	ids, err := listBatchJobIDs(ctx, objAPI)
+	if err != nil {
+		return nil, err
+	}
+	jobs := make([]madmin.BatchJobStatus, 0, len(ids))
+	for _, id := range ids {
+		status, err := sys.Status(ctx, objAPI, id)
+		if err != nil {
+			var notFound BatchJobNotFound
+			if errors.As(err, &notFound) {
+				// Removed since it was listed.
+				continue
+			}
+			return nil, err
+		}
+		jobs = append(jobs, status)
+	}
+	sort.Slice(jobs, func(i, j int) bool {
+		return jobs[i].StartTime.After(jobs[j].StartTime)
+	})
+	return jobs, nil
+}
+
+// Cancel stops the job, a job running on another node stops when it
+// next saves its progress.
+func (sys *BatchJobsSys) Cancel(ctx context.Context, objAPI ObjectLayer, id string) error {
+	sys.mu.Lock()
+	jr, ok := sys.jobs[id]
+	sys.mu.Unlock()
+	if ok {
+		jr.cancel()
+		return nil
+	}
+
+	job, err := loadBatchJob(ctx, objAPI, id)
+	if err != nil {
+		return err
+	}
+	if job.Status.Status != madmin.BatchJobRunning {
+		return BatchJobNotRunning{ID: id}
+	}
+	job.Status.Status = madmin.BatchJobCanceled
+	job.Status.LastUpdate = UTCNow()
+	data, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+	return saveConfig(ctx, objAPI, batchJobFile(id), data)
+}
+
+// run lists the objects of the job after its last processed object
+// and applies its action to the matching ones.
+func (sys *BatchJobsSys) run(ctx context.Context, objAPI ObjectLayer, jr *batchJobRun) {
+	id := jr.job.Status.ID
+	defer func() {
+		jr.cancel()
+		sys.mu.Lock()
+		delete(sys.jobs, id)
+		sys.mu.Unlock()
+	}()
+
+	req := jr.req
+	marker := jr.status().Marker
+	lastSave := UTCNow()
+	err := func() error {
+		for {
+			result, err := objAPI.ListObjects(ctx, req.Bucket, req.Prefix, marker, "", maxObjectList)
+			if err != nil {
+				return err
+			}
+			for _, oi := range result.Objects {
+				if ctx.Err() != nil {
+					return ctx.Err()
+				}
+				sys.process(ctx, objAPI, jr, oi)
+				if ctx.Err() != nil {
+					// The object is processed again when the job resumes.
+					return ctx.Err()
+				}
+				marker = oi.Name
+				jr.update(func(s *madmin.BatchJobStatus) {
+					s.Marker = marker
+				})
+				if UTCNow().Sub(lastSave) > batchJobSaveInterval {
+					sys.checkpoint(ctx, objAPI, jr)
+					lastSave = UTCNow()
+				}
+			}
+			if !result.IsTruncated {
+				return nil
+			}
+			if result.NextMarker != "" {
+				marker = result.NextMarker
+			}
+		}
+	}()
+
+	if GlobalContext.Err() != nil {
+		// The server is stopping, the job is resumed from its
+		// last saved progress when it restarts.
+		return
+	}
+	jr.update(func(s *madmin.BatchJobStatus) {
+		switch {
+		case ctx.Err() != nil:
+			s.Status = madmin.BatchJobCanceled
+		case err != nil:
+			s.Status = madmin.BatchJobFailed
+			s.Error = err.Error()
+		default:
+			s.Status = madmin.BatchJobCompleted
+		}
+	})
+	logger.LogIf(GlobalContext, jr.save(GlobalContext, objAPI))
+}
+
+func (sys *BatchJobsSys) process(ctx context.Context, objAPI ObjectLayer, jr *batchJobRun, oi ObjectInfo) {
+	jr.update(func(s *madmin.BatchJobStatus) {
+		s.Scanned++
+	})
+	if !jr.req.matches(oi, UTCNow()) {
+		return
+	}
+	jr.update(func(s *madmin.BatchJobStatus) {
+		s.Matched++
+	})
+
+	size, err := jr.req.apply(ctx, objAPI, oi)
+	if err != nil {
+		if ctx.Err() != nil {
+			return
+		}
+		jr.update(func(s *madmin.BatchJobStatus) {
+			s.Failed++
+			s.Error = fmt.Sprintf("Unable to process %s: %v", oi.Name, err)
+		})
+		return
+	}
+	jr.update(func(s *madmin.BatchJobStatus) {
+		s.Succeeded++
+		s.SucceededSize += size
+	})
+}
+
+// checkpoint saves the progress of the job, unless it was canceled
+// through another node.
+func (sys *BatchJobsSys) checkpoint(ctx context.Context, objAPI ObjectLayer, jr *batchJobRun) {
+	if job, err := loadBatchJob(ctx, objAPI, jr.job.Status.ID); err == nil && job.Status.Status == madmin.BatchJobCanceled {
+		jr.cancel()
+		return
+	}
+	logger.LogIf(ctx, jr.save(ctx, objAPI))
+}
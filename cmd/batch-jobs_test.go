@@ -0,0 +1,239 @@
+/*
+ * MinIO Cloud Storage, (C) 2021 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	xhttp "github.com/minio/minio/cmd/http"
+	"github.com/minio/minio/pkg/madmin"
+)
+
+func TestParseBatchJobRequest(t *testing.T) {
+	testCases := []struct {
+		spec        string
+		expectedErr bool
+	}{
+		// Valid specifications
+		{"bucket: src\nprefix: logs/\naction:\n  type: delete\n", false},
+		{"bucket: src\nfilters:\n  olderThan: 720h\n  minSize: 1MiB\n  tags:\n    project: x\naction:\n  type: copy\n  bucket: dst\n", false},
+		{"bucket: src\nprefix: logs/\naction:\n  type: copy\n  bucket: src\n  prefix: archive/\n", false},
+		{"bucket: src\naction:\n  type: copy\n  arn: arn:minio:replication::id:dst\n", false},
+		{"bucket: src\naction:\n  type: tag\n  tags:\n    archived: \"true\"\n", false},
+		{`{"bucket": "src", "action": {"type": "reencrypt"}}`, false},
+		{`{"bucket": "src", "action": {"type": "replicate"}}`, false},
+		// Missing bucket
+		{"action:\n  type: delete\n", true},
+		// Unknown field
+		{"bucket: src\nrecursive: true\naction:\n  type: delete\n", true},
+		// Unsupported action
+		{"bucket: src\naction:\n  type: move\n", true},
+		// Invalid duration
+		{"bucket: src\nfilters:\n  olderThan: 30d\naction:\n  type: delete\n", true},
+		// Invalid size
+		{"bucket: src\nfilters:\n  maxSize: lots\naction:\n  type: delete\n", true},
+		// Copy without destination
+		{"bucket: src\naction:\n  type: copy\n", true},
+		// Copy with both destinations
+		{"bucket: src\naction:\n  type: copy\n  bucket: dst\n  arn: arn:minio:replication::id:dst\n", true},
+		// Copy onto the objects of the job
+		{"bucket: src\naction:\n  type: copy\n  bucket: src\n", true},
+		{"bucket: src\nprefix: logs/\naction:\n  type: copy\n  bucket: src\n  prefix: logs/copy/\n", true},
+		// Tag without tags
+		{"bucket: src\naction:\n  type: tag\n", true},
+	}
+
+	for i, testCase := range testCases {
+		_, err := parseBatchJobRequest([]byte(testCase.spec))
+		if testCase.expectedErr && err == nil {
+			t.Errorf("Test %d: expected an error, got none", i+1)
+		}
+		if !testCase.expectedErr && err != nil {
+			t.Errorf("Test %d: unexpected error: %v", i+1, err)
+		}
+		if err != nil {
+			var invalid InvalidBatchJob
+			if !errors.As(err, &invalid) {
+				t.Errorf("Test %d: expected an InvalidBatchJob error, got %T", i+1, err)
+			}
+		}
+	}
+}
+
+func TestBatchJobRequestMatches(t *testing.T) {
+	req, err := parseBatchJobRequest([]byte("bucket: src\nfilters:\n  olderThan: 24h\n  minSize: 1KiB\n  maxSize: 1MiB\n  tags:\n    project: x\naction:\n  type: delete\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+	testCases := []struct {
+		oi      ObjectInfo
+		matches bool
+	}{
+		{ObjectInfo{ModTime: now.Add(-48 * time.Hour), Size: 4096, UserTags: "project=x&team=y"}, true},
+		// Too recent
+		{ObjectInfo{ModTime: now.Add(-time.Hour), Size: 4096, UserTags: "project=x"}, false},
+		// Too small
+		{ObjectInfo{ModTime: now.Add(-48 * time.Hour), Size: 10, UserTags: "project=x"}, false},
+		// Too large
+		{ObjectInfo{ModTime: now.Add(-48 * time.Hour), Size: 1 << 30, UserTags: "project=x"}, false},
+		// Missing tag
+		{ObjectInfo{ModTime: now.Add(-48 * time.Hour), Size: 4096, UserTags: "project=z"}, false},
+	}
+	for i, testCase := range testCases {
+		if got := req.matches(testCase.oi, now); got != testCase.matches {
+			t.Errorf("Test %d: expected %v, got %v", i+1, testCase.matches, got)
+		}
+	}
+}
+
+func TestBatchJobs(t *testing.T) {
+	ExecObjectLayerTest(t, testBatchJobs)
+}
+
+func waitBatchJob(ctx context.Context, obj ObjectLayer, id string) (madmin.BatchJobStatus, error) {
+	for i := 0; i < 100; i++ {
+		status, err := globalBatchJobsSys.Status(ctx, obj, id)
+		if err != nil || status.Status != madmin.BatchJobRunning {
+			return status, err
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	return madmin.BatchJobStatus{}, fmt.Errorf("job %s did not complete", id)
+}
+
+func testBatchJobs(obj ObjectLayer, instanceType string, t TestErrHandler) {
+	ctx := context.Background()
+	bucket, dstBucket := "batch-src", "batch-dst"
+	for _, b := range []string{bucket, dstBucket} {
+		if err := obj.MakeBucketWithLocation(ctx, b, BucketOptions{}); err != nil {
+			t.Fatalf("%s: %v", instanceType, err)
+		}
+	}
+	objects := map[string]string{"logs/a": "aaaa", "logs/b": "bbbbbbbb", "data/c": "cc"}
+	for object, data := range objects {
+		if _, err := obj.PutObject(ctx, bucket, object, mustGetPutObjReader(t, bytes.NewReader([]byte(data)),
+			int64(len(data)), "", ""), ObjectOptions{}); err != nil {
+			t.Fatalf("%s: %v", instanceType, err)
+		}
+	}
+
+	// Invalid jobs are rejected.
+	if _, err := globalBatchJobsSys.Start(ctx, obj, []byte("bucket: missing-bucket\naction:\n  type: delete\n")); err == nil {
+		t.Fatalf("%s: expected an error for a missing bucket", instanceType)
+	}
+
+	// Copy the objects larger than 3 bytes under logs/.
+	id, err := globalBatchJobsSys.Start(ctx, obj, []byte(fmt.Sprintf(
+		"bucket: %s\nprefix: logs/\nfilters:\n  minSize: 5B\naction:\n  type: copy\n  bucket: %s\n  prefix: copy/\n", bucket, dstBucket)))
+	if err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+	status, err := waitBatchJob(ctx, obj, id)
+	if err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+	if status.Status != madmin.BatchJobCompleted || status.Scanned != 2 || status.Matched != 1 || status.Succeeded != 1 || status.SucceededSize != 8 {
+		t.Fatalf("%s: unexpected status of the copy job %+v", instanceType, status)
+	}
+	var buf bytes.Buffer
+	if err = obj.GetObject(ctx, dstBucket, "copy/logs/b", 0, -1, &buf, "", ObjectOptions{}); err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+	if buf.String() != objects["logs/b"] {
+		t.Fatalf("%s: expected the copy to contain %q, got %q", instanceType, objects["logs/b"], buf.String())
+	}
+
+	// Tag all the objects, then delete the tagged ones under data/.
+	id, err = globalBatchJobsSys.Start(ctx, obj, []byte(fmt.Sprintf("bucket: %s\naction:\n  type: tag\n  tags:\n    expire: \"yes\"\n", bucket)))
+	if err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+	if status, err = waitBatchJob(ctx, obj, id); err != nil || status.Succeeded != 3 {
+		t.Fatalf("%s: unexpected status of the tag job %+v: %v", instanceType, status, err)
+	}
+	id, err = globalBatchJobsSys.Start(ctx, obj, []byte(fmt.Sprintf("bucket: %s\nprefix: data/\nfilters:\n  tags:\n    expire: \"yes\"\naction:\n  type: delete\n", bucket)))
+	if err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+	if status, err = waitBatchJob(ctx, obj, id); err != nil || status.Succeeded != 1 {
+		t.Fatalf("%s: unexpected status of the delete job %+v: %v", instanceType, status, err)
+	}
+	if _, err = obj.GetObjectInfo(ctx, bucket, "data/c", ObjectOptions{}); !isErrObjectNotFound(err) {
+		t.Fatalf("%s: expected data/c to be deleted, got %v", instanceType, err)
+	}
+
+	jobs, err := globalBatchJobsSys.List(ctx, obj)
+	if err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+	if len(jobs) != 3 || jobs[0].ID != id {
+		t.Fatalf("%s: expected 3 jobs, most recent first, got %+v", instanceType, jobs)
+	}
+
+	var notRunning BatchJobNotRunning
+	if err = globalBatchJobsSys.Cancel(ctx, obj, id); !errors.As(err, &notRunning) {
+		t.Fatalf("%s: expected %T, got %v", instanceType, notRunning, err)
+	}
+	var notFound BatchJobNotFound
+	if _, err = globalBatchJobsSys.Status(ctx, obj, "missing"); !errors.As(err, &notFound) {
+		t.Fatalf("%s: expected %T, got %v", instanceType, notFound, err)
+	}
+}
+
+func TestBatchJobCopyObjectLocked(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	obj, fsDirs, err := prepareErasure(ctx, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeRoots(fsDirs)
+
+	const bucket = "bucket"
+	if err = obj.MakeBucketWithLocation(ctx, bucket, BucketOptions{LockEnabled: true, VersioningEnabled: true}); err != nil {
+		t.Fatal(err)
+	}
+	retainUntil := UTCNow().Add(24 * time.Hour).Format(time.RFC3339)
+	for object, metadata := range map[string]map[string]string{
+		"legal-hold": {xhttp.AmzObjectLockLegalHold: "ON"},
+		"retention":  {xhttp.AmzObjectLockMode: "COMPLIANCE", xhttp.AmzObjectLockRetainUntilDate: retainUntil},
+	} {
+		oi, err := obj.PutObject(ctx, bucket, object, mustGetPutObjReader(t, bytes.NewReader([]byte("data")), 4, "", ""),
+			ObjectOptions{Versioned: true, UserDefined: metadata})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = batchJobCopyObject(ctx, obj, oi, bucket, object, true); err != errBatchJobObjectLocked {
+			t.Fatalf("%s: expected the locked version not to be rewritten, got %v", object, err)
+		}
+		info, err := obj.GetObjectInfo(ctx, bucket, object, ObjectOptions{VersionID: oi.VersionID})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.ETag != oi.ETag || !info.ModTime.Equal(oi.ModTime) {
+			t.Fatalf("%s: expected the locked version to be unchanged", object)
+		}
+	}
+}
@@ -547,6 +547,12 @@ func (api objectAPIHandlers) DeleteMultipleObjectsHandler(w http.ResponseWriter,
 	dObjects, errs := deleteObjectsFn(ctx, bucket, deleteList, ObjectOptions{
 		Versioned:        globalBucketVersioningSys.Enabled(bucket),
 		VersionSuspended: globalBucketVersioningSys.Suspended(bucket),
+		PrefixEnabledFn: func(prefix string) bool {
+			return globalBucketVersioningSys.PrefixEnabled(bucket, prefix)
+		},
+		PrefixSuspendedFn: func(prefix string) bool {
+			return globalBucketVersioningSys.PrefixSuspended(bucket, prefix)
+		},
 	})
 	deletedObjects := make([]DeletedObject, len(deleteObjects.Objects))
 	for i := range errs {
@@ -1167,7 +1173,7 @@ func (api objectAPIHandlers) PutBucketObjectLockConfigHandler(w http.ResponseWri
 		writeErrorResponse(ctx, w, errorCodes.ToAPIErr(ErrServerNotInitialized), r.URL, guessIsBrowserReq(r))
 		return
 	}
-	if !globalIsErasure {
+	if globalIsGateway {
 		writeErrorResponseJSON(ctx, w, errorCodes.ToAPIErr(ErrNotImplemented), r.URL)
 		return
 	}
@@ -1386,7 +1392,7 @@ func (api objectAPIHandlers) PutBucketReplicationConfigHandler(w http.ResponseWr
 		return
 	}
 
-	if versioned := globalBucketVersioningSys.Enabled(bucket); !versioned {
+	if vc, err := globalBucketVersioningSys.Get(bucket); err != nil || !vc.Enabled() || vc.PrefixesExcluded() {
 		writeErrorResponse(ctx, w, errorCodes.ToAPIErr(ErrReplicationNeedsVersioningError), r.URL, guessIsBrowserReq(r))
 		return
 	}
@@ -0,0 +1,308 @@
+/*
+ * MinIO Cloud Storage, (C) 2021 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/xml"
+	"io"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/minio/minio/cmd/logger"
+	"github.com/minio/minio/pkg/bucket/inventory"
+	"github.com/minio/minio/pkg/bucket/policy"
+	iampolicy "github.com/minio/minio/pkg/iam/policy"
+)
+
+const (
+	// Inventory configurations file.
+	bucketInventoryConfig = "inventory.xml"
+
+	// Maximum number of inventory configurations returned by a
+	// ListBucketInventoryConfigurations call.
+	maxInventoryConfigsList = 100
+)
+
+// ListInventoryConfigurationsResult - result of ListBucketInventoryConfigurations.
+type ListInventoryConfigurationsResult struct {
+	XMLName               xml.Name           `xml:"http://s3.amazonaws.com/doc/2006-03-01/ ListInventoryConfigurationsResult"`
+	ContinuationToken     string             `xml:"ContinuationToken,omitempty"`
+	Configs               []inventory.Config `xml:"InventoryConfiguration"`
+	IsTruncated           bool               `xml:"IsTruncated"`
+	NextContinuationToken string             `xml:"NextContinuationToken,omitempty"`
+}
+
+// PutBucketInventoryConfigurationHandler - This HTTP handler stores given bucket inventory configuration as per
+// https://docs.aws.amazon.com/AmazonS3/latest/API/API_PutBucketInventoryConfiguration.html
+func (api objectAPIHandlers) PutBucketInventoryConfigurationHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, w, "PutBucketInventoryConfiguration")
+
+	defer logger.AuditLog(ctx, w, r, mustGetClaimsFromToken(r))
+
+	objAPI := api.ObjectAPI()
+	if objAPI == nil {
+		writeErrorResponse(ctx, w, errorCodes.ToAPIErr(ErrServerNotInitialized), r.URL, guessIsBrowserReq(r))
+		return
+	}
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+
+	if s3Error := checkRequestAuthType(ctx, r, policy.PutBucketInventoryAction, bucket, ""); s3Error != ErrNone {
+		writeErrorResponse(ctx, w, errorCodes.ToAPIErr(s3Error), r.URL, guessIsBrowserReq(r))
+		return
+	}
+
+	// Check if bucket exists.
+	if _, err := objAPI.GetBucketInfo(ctx, bucket); err != nil {
+		writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL, guessIsBrowserReq(r))
+		return
+	}
+
+	config, err := inventory.ParseConfig(io.LimitReader(r.Body, r.ContentLength))
+	if err != nil {
+		writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL, guessIsBrowserReq(r))
+		return
+	}
+
+	if err = config.ValidateID(r.URL.Query().Get("id")); err != nil {
+		writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL, guessIsBrowserReq(r))
+		return
+	}
+
+	if err = config.Validate(); err != nil {
+		writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL, guessIsBrowserReq(r))
+		return
+	}
+
+	// The reports are written with the permissions of the
+	// requester, who must be allowed to write to the destination.
+	destBucket := config.Destination.S3BucketDestination.DestinationBucket()
+	if _, err = objAPI.GetBucketInfo(ctx, destBucket); err != nil {
+		writeErrorResponse(ctx, w, toAPIError(ctx, inventory.Errorf("Destination bucket %s is not accessible: %v", destBucket, err)), r.URL, guessIsBrowserReq(r))
+		return
+	}
+	if s3Error := isPutActionAllowed(ctx, getRequestAuthType(r), destBucket, inventoryReportPrefix(bucket, *config), r, iampolicy.PutObjectAction); s3Error != ErrNone {
+		writeErrorResponse(ctx, w, errorCodes.ToAPIErr(s3Error), r.URL, guessIsBrowserReq(r))
+		return
+	}
+	if config.Destination.S3BucketDestination.IsEncrypted() && GlobalKMS == nil {
+		writeErrorResponse(ctx, w, errorCodes.ToAPIErr(ErrKMSNotConfigured), r.URL, guessIsBrowserReq(r))
+		return
+	}
+
+	configs, err := globalBucketMetadataSys.GetInventoryConfig(bucket)
+	if err != nil {
+		writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL, guessIsBrowserReq(r))
+		return
+	}
+
+	updated, err := configs.Set(*config)
+	if err != nil {
+		writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL, guessIsBrowserReq(r))
+		return
+	}
+
+	configData, err := xml.Marshal(updated)
+	if err != nil {
+		writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL, guessIsBrowserReq(r))
+		return
+	}
+
+	if err = globalBucketMetadataSys.Update(bucket, bucketInventoryConfig, configData); err != nil {
+		writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL, guessIsBrowserReq(r))
+		return
+	}
+
+	// Success.
+	writeSuccessResponseHeadersOnly(w)
+}
+
+// GetBucketInventoryConfigurationHandler - This HTTP handler returns the bucket inventory configuration with the id.
+func (api objectAPIHandlers) GetBucketInventoryConfigurationHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, w, "GetBucketInventoryConfiguration")
+
+	defer logger.AuditLog(ctx, w, r, mustGetClaimsFromToken(r))
+
+	objAPI := api.ObjectAPI()
+	if objAPI == nil {
+		writeErrorResponse(ctx, w, errorCodes.ToAPIErr(ErrServerNotInitialized), r.URL, guessIsBrowserReq(r))
+		return
+	}
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+	id := r.URL.Query().Get("id")
+
+	if s3Error := checkRequestAuthType(ctx, r, policy.GetBucketInventoryAction, bucket, ""); s3Error != ErrNone {
+		writeErrorResponse(ctx, w, errorCodes.ToAPIErr(s3Error), r.URL, guessIsBrowserReq(r))
+		return
+	}
+
+	// Check if bucket exists.
+	if _, err := objAPI.GetBucketInfo(ctx, bucket); err != nil {
+		writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL, guessIsBrowserReq(r))
+		return
+	}
+
+	configs, err := globalBucketMetadataSys.GetInventoryConfig(bucket)
+	if err != nil {
+		writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL, guessIsBrowserReq(r))
+		return
+	}
+
+	config, ok := configs.Get(id)
+	if !ok {
+		writeErrorResponse(ctx, w, toAPIError(ctx, BucketInventoryConfigNotFound{Bucket: bucket, ID: id}), r.URL, guessIsBrowserReq(r))
+		return
+	}
+	config.XMLNS = "http://s3.amazonaws.com/doc/2006-03-01/"
+
+	configData, err := xml.Marshal(config)
+	if err != nil {
+		writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL, guessIsBrowserReq(r))
+		return
+	}
+
+	// Write inventory configuration to client.
+	writeSuccessResponseXML(w, configData)
+}
+
+// ListBucketInventoryConfigurationsHandler - This HTTP handler returns the bucket inventory configurations,
+// at most 100 at a time.
+func (api objectAPIHandlers) ListBucketInventoryConfigurationsHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, w, "ListBucketInventoryConfigurations")
+
+	defer logger.AuditLog(ctx, w, r, mustGetClaimsFromToken(r))
+
+	objAPI := api.ObjectAPI()
+	if objAPI == nil {
+		writeErrorResponse(ctx, w, errorCodes.ToAPIErr(ErrServerNotInitialized), r.URL, guessIsBrowserReq(r))
+		return
+	}
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+
+	if s3Error := checkRequestAuthType(ctx, r, policy.GetBucketInventoryAction, bucket, ""); s3Error != ErrNone {
+		writeErrorResponse(ctx, w, errorCodes.ToAPIErr(s3Error), r.URL, guessIsBrowserReq(r))
+		return
+	}
+
+	// Check if bucket exists.
+	if _, err := objAPI.GetBucketInfo(ctx, bucket); err != nil {
+		writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL, guessIsBrowserReq(r))
+		return
+	}
+
+	configs, err := globalBucketMetadataSys.GetInventoryConfig(bucket)
+	if err != nil {
+		writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL, guessIsBrowserReq(r))
+		return
+	}
+
+	// The continuation token is the id of the first configuration to list.
+	token := r.URL.Query().Get("continuation-token")
+	start := 0
+	if token != "" {
+		start = -1
+		for i, config := range configs.Configs {
+			if config.ID == token {
+				start = i
+				break
+			}
+		}
+		if start < 0 {
+			writeErrorResponse(ctx, w, errorCodes.ToAPIErr(ErrIncorrectContinuationToken), r.URL, guessIsBrowserReq(r))
+			return
+		}
+	}
+
+	result := ListInventoryConfigurationsResult{
+		ContinuationToken: token,
+	}
+	end := start + maxInventoryConfigsList
+	if end < len(configs.Configs) {
+		result.IsTruncated = true
+		result.NextContinuationToken = configs.Configs[end].ID
+	} else {
+		end = len(configs.Configs)
+	}
+	result.Configs = configs.Configs[start:end]
+
+	// Write inventory configurations to client.
+	writeSuccessResponseXML(w, encodeResponse(result))
+}
+
+// DeleteBucketInventoryConfigurationHandler - This HTTP handler removes the bucket inventory configuration with the id.
+func (api objectAPIHandlers) DeleteBucketInventoryConfigurationHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, w, "DeleteBucketInventoryConfiguration")
+
+	defer logger.AuditLog(ctx, w, r, mustGetClaimsFromToken(r))
+
+	objAPI := api.ObjectAPI()
+	if objAPI == nil {
+		writeErrorResponse(ctx, w, errorCodes.ToAPIErr(ErrServerNotInitialized), r.URL, guessIsBrowserReq(r))
+		return
+	}
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+	id := r.URL.Query().Get("id")
+
+	if s3Error := checkRequestAuthType(ctx, r, policy.PutBucketInventoryAction, bucket, ""); s3Error != ErrNone {
+		writeErrorResponse(ctx, w, errorCodes.ToAPIErr(s3Error), r.URL, guessIsBrowserReq(r))
+		return
+	}
+
+	// Check if bucket exists.
+	if _, err := objAPI.GetBucketInfo(ctx, bucket); err != nil {
+		writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL, guessIsBrowserReq(r))
+		return
+	}
+
+	configs, err := globalBucketMetadataSys.GetInventoryConfig(bucket)
+	if err != nil {
+		writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL, guessIsBrowserReq(r))
+		return
+	}
+
+	updated, ok := configs.Delete(id)
+	if !ok {
+		writeErrorResponse(ctx, w, toAPIError(ctx, BucketInventoryConfigNotFound{Bucket: bucket, ID: id}), r.URL, guessIsBrowserReq(r))
+		return
+	}
+
+	var configData []byte
+	if len(updated.Configs) > 0 {
+		if configData, err = xml.Marshal(updated); err != nil {
+			writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL, guessIsBrowserReq(r))
+			return
+		}
+	}
+
+	if err = globalBucketMetadataSys.Update(bucket, bucketInventoryConfig, configData); err != nil {
+		writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL, guessIsBrowserReq(r))
+		return
+	}
+
+	// Remove the schedule of the reports of the deleted configuration.
+	logger.LogIf(ctx, deleteInventoryState(ctx, objAPI, bucket, id))
+
+	// Success.
+	writeSuccessNoContent(w)
+}
@@ -0,0 +1,215 @@
+/*
+ * MinIO Cloud Storage, (C) 2021 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/minio/minio/pkg/auth"
+	"github.com/minio/minio/pkg/bucket/inventory"
+)
+
+func inventoryConfigXML(id, destBucket string) string {
+	return fmt.Sprintf(`<InventoryConfiguration xmlns="http://s3.amazonaws.com/doc/2006-03-01/">
+<Id>%s</Id><IsEnabled>true</IsEnabled>
+<Destination><S3BucketDestination><Bucket>arn:aws:s3:::%s</Bucket><Format>CSV</Format><Prefix>reports</Prefix></S3BucketDestination></Destination>
+<Schedule><Frequency>Daily</Frequency></Schedule>
+<IncludedObjectVersions>Current</IncludedObjectVersions>
+<OptionalFields><Field>Size</Field><Field>ETag</Field></OptionalFields>
+</InventoryConfiguration>`, id, destBucket)
+}
+
+// Test S3 Bucket inventory APIs
+func TestBucketInventoryHandlers(t *testing.T) {
+	ExecObjectLayerAPITest(t, testBucketInventoryHandlers, []string{"GetBucketInventoryConfiguration",
+		"ListBucketInventoryConfigurations", "PutBucketInventoryConfiguration", "DeleteBucketInventoryConfiguration"})
+}
+
+func testBucketInventoryHandlers(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials auth.Credentials, t *testing.T) {
+	testCases := []struct {
+		method             string
+		id                 string
+		body               string
+		expectedRespStatus int
+		expectedErrCode    string
+		expectedIDs        []string
+	}{
+		// Get before any configuration is set
+		{method: http.MethodGet, id: "report", expectedRespStatus: http.StatusNotFound, expectedErrCode: "NoSuchConfiguration"},
+		// List before any configuration is set
+		{method: http.MethodGet, expectedRespStatus: http.StatusOK},
+		// Put a valid configuration
+		{method: http.MethodPut, id: "report", body: inventoryConfigXML("report", bucketName), expectedRespStatus: http.StatusOK},
+		// Put a configuration with an id not matching the request
+		{method: http.MethodPut, id: "other", body: inventoryConfigXML("report", bucketName), expectedRespStatus: http.StatusBadRequest, expectedErrCode: "InvalidArgument"},
+		// Put a configuration writing to a missing bucket
+		{method: http.MethodPut, id: "other", body: inventoryConfigXML("other", "missing-bucket"), expectedRespStatus: http.StatusBadRequest, expectedErrCode: "InvalidArgument"},
+		// Put a configuration with encrypted reports, KMS is not configured
+		{method: http.MethodPut, id: "other", body: strings.Replace(inventoryConfigXML("other", bucketName),
+			"</Prefix>", "</Prefix><Encryption><SSE-S3/></Encryption>", 1), expectedRespStatus: http.StatusBadRequest, expectedErrCode: "InvalidArgument"},
+		// Put a second valid configuration
+		{method: http.MethodPut, id: "other", body: inventoryConfigXML("other", bucketName), expectedRespStatus: http.StatusOK},
+		// Get a configuration
+		{method: http.MethodGet, id: "report", expectedRespStatus: http.StatusOK, expectedIDs: []string{"report"}},
+		// List the configurations
+		{method: http.MethodGet, expectedRespStatus: http.StatusOK, expectedIDs: []string{"report", "other"}},
+		// Delete a configuration
+		{method: http.MethodDelete, id: "report", expectedRespStatus: http.StatusNoContent},
+		// Delete a missing configuration
+		{method: http.MethodDelete, id: "report", expectedRespStatus: http.StatusNotFound, expectedErrCode: "NoSuchConfiguration"},
+		// List the remaining configurations
+		{method: http.MethodGet, expectedRespStatus: http.StatusOK, expectedIDs: []string{"other"}},
+	}
+
+	for i, testCase := range testCases {
+		rec := httptest.NewRecorder()
+		req, err := newTestSignedRequestV4(testCase.method, getBucketInventoryURL("", bucketName, testCase.id),
+			int64(len(testCase.body)), strings.NewReader(testCase.body), credentials.AccessKey, credentials.SecretKey, nil)
+		if err != nil {
+			t.Fatalf("Test %d: %s: Failed to create HTTP request: <ERROR> %v", i+1, instanceType, err)
+		}
+		apiRouter.ServeHTTP(rec, req)
+		if rec.Code != testCase.expectedRespStatus {
+			t.Fatalf("Test %d: %s: Expected the response status to be `%d`, but instead found `%d`: %s",
+				i+1, instanceType, testCase.expectedRespStatus, rec.Code, rec.Body.String())
+		}
+
+		if testCase.expectedErrCode != "" {
+			errorResponse := APIErrorResponse{}
+			if err = xml.Unmarshal(rec.Body.Bytes(), &errorResponse); err != nil {
+				t.Fatalf("Test %d: %s: Failed to unmarshal error response: <ERROR> %v", i+1, instanceType, err)
+			}
+			if errorResponse.Code != testCase.expectedErrCode {
+				t.Fatalf("Test %d: %s: Expected error code `%s`, but instead found `%s`",
+					i+1, instanceType, testCase.expectedErrCode, errorResponse.Code)
+			}
+			continue
+		}
+
+		if testCase.method != http.MethodGet {
+			continue
+		}
+		var ids []string
+		if testCase.id != "" {
+			config, err := inventory.ParseConfig(rec.Body)
+			if err != nil {
+				t.Fatalf("Test %d: %s: Failed to parse configuration: <ERROR> %v", i+1, instanceType, err)
+			}
+			if err = config.Validate(); err != nil {
+				t.Fatalf("Test %d: %s: Invalid configuration returned: <ERROR> %v", i+1, instanceType, err)
+			}
+			ids = append(ids, config.ID)
+		} else {
+			var result ListInventoryConfigurationsResult
+			if err = xml.Unmarshal(rec.Body.Bytes(), &result); err != nil {
+				t.Fatalf("Test %d: %s: Failed to unmarshal list response: <ERROR> %v", i+1, instanceType, err)
+			}
+			for _, config := range result.Configs {
+				ids = append(ids, config.ID)
+			}
+		}
+		if fmt.Sprint(ids) != fmt.Sprint(testCase.expectedIDs) {
+			t.Fatalf("Test %d: %s: Expected configurations %v, but instead found %v", i+1, instanceType, testCase.expectedIDs, ids)
+		}
+	}
+}
+
+// Test the generation of the inventory reports.
+func TestGenerateInventoryReport(t *testing.T) {
+	ExecObjectLayerTest(t, testGenerateInventoryReport)
+}
+
+func testGenerateInventoryReport(obj ObjectLayer, instanceType string, t TestErrHandler) {
+	ctx := context.Background()
+	bucket, destBucket := "source-bucket", "dest-bucket"
+	for _, b := range []string{bucket, destBucket} {
+		if err := obj.MakeBucketWithLocation(ctx, b, BucketOptions{}); err != nil {
+			t.Fatalf("%s: %v", instanceType, err)
+		}
+	}
+	objects := map[string]string{"a.txt": "hello", "b.txt": "hello world", "logs/c.txt": "c"}
+	for object, data := range objects {
+		if _, err := obj.PutObject(ctx, bucket, object, mustGetPutObjReader(t, bytes.NewReader([]byte(data)),
+			int64(len(data)), "", ""), ObjectOptions{}); err != nil {
+			t.Fatalf("%s: %v", instanceType, err)
+		}
+	}
+
+	config, err := inventory.ParseConfig(strings.NewReader(inventoryConfigXML("report", destBucket)))
+	if err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+	if prefix := inventoryReportPrefix(bucket, *config); prefix != "reports/"+bucket+"/report/" {
+		t.Fatalf("%s: unexpected report prefix %s", instanceType, prefix)
+	}
+	if err = generateInventoryReport(ctx, obj, bucket, *config, UTCNow()); err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+
+	result, err := obj.ListObjects(ctx, destBucket, "reports/"+bucket+"/report/data/", "", "", maxObjectList)
+	if err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+	if len(result.Objects) != 1 {
+		t.Fatalf("%s: expected one data file, found %d", instanceType, len(result.Objects))
+	}
+	var buf bytes.Buffer
+	if err = obj.GetObject(ctx, destBucket, result.Objects[0].Name, 0, -1, &buf, "", ObjectOptions{}); err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+	gzr, err := gzip.NewReader(&buf)
+	if err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+	records, err := csv.NewReader(gzr).ReadAll()
+	if err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+	if len(records) != len(objects) {
+		t.Fatalf("%s: expected %d records, found %d", instanceType, len(objects), len(records))
+	}
+	for _, record := range records {
+		// Bucket, Key, Size, ETag
+		if len(record) != 4 || record[0] != bucket || fmt.Sprint(len(objects[record[1]])) != record[2] {
+			t.Fatalf("%s: unexpected record %v", instanceType, record)
+		}
+	}
+
+	result, err = obj.ListObjects(ctx, destBucket, "reports/"+bucket+"/report/", "", "", maxObjectList)
+	if err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+	var manifests int
+	for _, objInfo := range result.Objects {
+		if strings.HasSuffix(objInfo.Name, "/manifest.json") || strings.HasSuffix(objInfo.Name, "/manifest.checksum") {
+			manifests++
+		}
+	}
+	if manifests != 2 {
+		t.Fatalf("%s: expected a manifest and its checksum, found %d files", instanceType, manifests)
+	}
+}
@@ -0,0 +1,477 @@
+/*
+ * MinIO Cloud Storage, (C) 2021 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/md5"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/minio/minio/cmd/crypto"
+	xhttp "github.com/minio/minio/cmd/http"
+	"github.com/minio/minio/cmd/logger"
+	"github.com/minio/minio/pkg/bucket/inventory"
+	objectlock "github.com/minio/minio/pkg/bucket/object/lock"
+	"github.com/minio/minio/pkg/hash"
+	xioutil "github.com/minio/minio/pkg/ioutil"
+	"github.com/minio/minio/pkg/s3select/parquet"
+)
+
+const (
+	// The time of the last report of each inventory configuration is
+	// saved under the metadata prefix of the bucket, in one file per id.
+	inventoryStateDir = "inventory"
+
+	// Version of the manifests of the reports, as per AWS S3 specification.
+	inventoryManifestVersion = "2016-11-30"
+
+	// Number of records of the Parquet reports buffered in memory.
+	inventoryParquetRowGroupCount = 10000
+)
+
+// Set while the inventory reports are being generated.
+var inventoryJobsRunning int32
+
+func inventoryStateFile(bucket, id string) string {
+	return pathJoin(bucketMetaPrefix, bucket, inventoryStateDir, getSHA256Hash([]byte(id))+".json")
+}
+
+// inventoryState is the schedule of the reports of an inventory configuration.
+type inventoryState struct {
+	LastRun time.Time `json:"lastRun"`
+}
+
+func loadInventoryState(ctx context.Context, objAPI ObjectLayer, bucket, id string) (state inventoryState, err error) {
+	data, err := readConfig(ctx, objAPI, inventoryStateFile(bucket, id))
+	if err != nil {
+		if errors.Is(err, errConfigNotFound) {
+			return state, nil
+		}
+		return state, err
+	}
+	if err = json.Unmarshal(data, &state); err != nil {
+		return state, err
+	}
+	return state, nil
+}
+
+func saveInventoryState(ctx context.Context, objAPI ObjectLayer, bucket, id string, state inventoryState) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	return saveConfig(ctx, objAPI, inventoryStateFile(bucket, id), data)
+}
+
+func deleteInventoryState(ctx context.Context, objAPI ObjectLayer, bucket, id string) error {
+	err := deleteConfig(ctx, objAPI, inventoryStateFile(bucket, id))
+	if errors.Is(err, errConfigNotFound) {
+		return nil
+	}
+	return err
+}
+
+// runInventoryJobs generates the due inventory reports of all the
+// buckets, it is called by the data scanner after each cycle.
+func runInventoryJobs(ctx context.Context, objAPI ObjectLayer) {
+	if !atomic.CompareAndSwapInt32(&inventoryJobsRunning, 0, 1) {
+		// Reports of the previous cycle are still being generated.
+		return
+	}
+	defer atomic.StoreInt32(&inventoryJobsRunning, 0)
+
+	buckets, err := objAPI.ListBuckets(ctx)
+	if err != nil {
+		logger.LogIf(ctx, err)
+		return
+	}
+	for _, bucket := range buckets {
+		configs, err := globalBucketMetadataSys.GetInventoryConfig(bucket.Name)
+		if err != nil {
+			logger.LogIf(ctx, err)
+			continue
+		}
+		for _, config := range configs.Configs {
+			state, err := loadInventoryState(ctx, objAPI, bucket.Name, config.ID)
+			if err != nil {
+				logger.LogIf(ctx, err)
+				continue
+			}
+			now := UTCNow()
+			if !config.Due(state.LastRun, now) {
+				continue
+			}
+			if err = generateInventoryReport(ctx, objAPI, bucket.Name, config, now); err != nil {
+				logger.LogIf(ctx, err)
+				continue
+			}
+			state.LastRun = now
+			logger.LogIf(ctx, saveInventoryState(ctx, objAPI, bucket.Name, config.ID, state))
+		}
+	}
+}
+
+// inventoryManifestFile describes a data file of an inventory report.
+type inventoryManifestFile struct {
+	Key         string `json:"key"`
+	Size        int64  `json:"size"`
+	MD5Checksum string `json:"MD5checksum"`
+}
+
+// inventoryManifest lists the data files of an inventory report.
+type inventoryManifest struct {
+	SourceBucket      string                  `json:"sourceBucket"`
+	DestinationBucket string                  `json:"destinationBucket"`
+	Version           string                  `json:"version"`
+	CreationTimestamp string                  `json:"creationTimestamp"`
+	FileFormat        inventory.Format        `json:"fileFormat"`
+	FileSchema        string                  `json:"fileSchema"`
+	Files             []inventoryManifestFile `json:"files"`
+}
+
+var inventoryContentTypes = map[inventory.Format]string{
+	inventory.CSV:     "application/x-gzip",
+	inventory.JSON:    "application/x-gzip",
+	inventory.Parquet: "application/octet-stream",
+}
+
+var inventoryFileExts = map[inventory.Format]string{
+	inventory.CSV:     ".csv.gz",
+	inventory.JSON:    ".json.gz",
+	inventory.Parquet: ".parquet",
+}
+
+// inventoryReportPrefix returns the prefix of the objects of the reports
+// of the inventory configuration of the bucket, in the destination bucket.
+// Data files are written to <prefix>/<bucket>/<id>/data/ and the manifests
+// of the reports to <prefix>/<bucket>/<id>/<time>/
+func inventoryReportPrefix(bucket string, config inventory.Config) string {
+	return pathJoin(config.Destination.S3BucketDestination.Prefix, bucket, config.ID) + SlashSeparator
+}
+
+// generateInventoryReport writes the data file of the report of the objects of the
+// bucket to the destination of the inventory configuration, and then its manifest.
+func generateInventoryReport(ctx context.Context, objAPI ObjectLayer, bucket string, config inventory.Config, now time.Time) error {
+	dest := config.Destination.S3BucketDestination
+	destBucket := dest.DestinationBucket()
+	format := dest.Format
+	fields := config.Fields()
+	encrypted := dest.IsEncrypted()
+
+	reportPrefix := inventoryReportPrefix(bucket, config)
+	dataKey := pathJoin(reportPrefix, "data", mustGetUUID()+inventoryFileExts[format])
+
+	pr, pw := io.Pipe()
+	md5Hash := md5.New()
+	go func() {
+		pw.CloseWithError(writeInventoryData(ctx, objAPI, bucket, config, fields, io.MultiWriter(pw, md5Hash)))
+	}()
+	defer pr.Close()
+
+	objInfo, err := putInventoryObject(ctx, objAPI, destBucket, dataKey, pr, inventoryContentTypes[format], encrypted)
+	if err != nil {
+		return err
+	}
+	size, err := objInfo.GetActualSize()
+	if err != nil {
+		return err
+	}
+
+	schema := make([]string, len(fields))
+	for i, field := range fields {
+		schema[i] = string(field)
+	}
+	manifest := inventoryManifest{
+		SourceBucket:      bucket,
+		DestinationBucket: dest.Bucket,
+		Version:           inventoryManifestVersion,
+		CreationTimestamp: strconv.FormatInt(now.UnixNano()/int64(time.Millisecond), 10),
+		FileFormat:        format,
+		FileSchema:        strings.Join(schema, ", "),
+		Files: []inventoryManifestFile{{
+			Key:         dataKey,
+			Size:        size,
+			MD5Checksum: hex.EncodeToString(md5Hash.Sum(nil)),
+		}},
+	}
+	manifestData, err := json.Marshal(manifest)
+	if err != nil {
+		return err
+	}
+
+	manifestPrefix := pathJoin(reportPrefix, now.Format("2006-01-02T15-04Z"))
+	if _, err = putInventoryObject(ctx, objAPI, destBucket, pathJoin(manifestPrefix, "manifest.json"),
+		bytes.NewReader(manifestData), "application/json", encrypted); err != nil {
+		return err
+	}
+	checksum := md5.Sum(manifestData)
+	_, err = putInventoryObject(ctx, objAPI, destBucket, pathJoin(manifestPrefix, "manifest.checksum"),
+		strings.NewReader(hex.EncodeToString(checksum[:])), "text/plain", encrypted)
+	return err
+}
+
+// putInventoryObject writes an object of a report, encrypted with SSE-S3
+// if requested by the inventory configuration.
+func putInventoryObject(ctx context.Context, objAPI ObjectLayer, bucket, object string, reader io.Reader, contentType string, encrypted bool) (ObjectInfo, error) {
+	hashReader, err := hash.NewReader(reader, -1, "", "", -1, globalCLIContext.StrictS3Compat)
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+	pReader := NewPutObjReader(hashReader)
+	opts := ObjectOptions{
+		Versioned:        globalBucketVersioningSys.PrefixEnabled(bucket, object),
+		VersionSuspended: globalBucketVersioningSys.PrefixSuspended(bucket, object),
+		UserDefined:      map[string]string{xhttp.ContentType: contentType},
+	}
+	if encrypted {
+		if GlobalKMS == nil {
+			return ObjectInfo{}, errKMSNotConfigured
+		}
+		reader, objectEncryptionKey, err := newEncryptReader(hashReader, nil, bucket, object, opts.UserDefined, true)
+		if err != nil {
+			return ObjectInfo{}, err
+		}
+		encReader, err := hash.NewReader(reader, -1, "", "", -1, globalCLIContext.StrictS3Compat)
+		if err != nil {
+			return ObjectInfo{}, err
+		}
+		if pReader, err = pReader.WithEncryption(encReader, &objectEncryptionKey); err != nil {
+			return ObjectInfo{}, err
+		}
+	}
+	return objAPI.PutObject(ctx, bucket, object, pReader, opts)
+}
+
+// writeInventoryData writes the records of the objects of the bucket
+// listed by the inventory configuration to w.
+func writeInventoryData(ctx context.Context, objAPI ObjectLayer, bucket string, config inventory.Config, fields []inventory.Field, w io.Writer) error {
+	iw, err := newInventoryWriter(config.Destination.S3BucketDestination.Format, fields, w)
+	if err != nil {
+		return err
+	}
+
+	prefix := config.Prefix()
+	if config.IncludedObjectVersions == inventory.AllVersions {
+		var marker, versionMarker string
+		for {
+			result, err := objAPI.ListObjectVersions(ctx, bucket, prefix, marker, versionMarker, "", maxObjectList)
+			if err != nil {
+				return err
+			}
+			for _, objInfo := range result.Objects {
+				if err = iw.write(inventoryRecord(objInfo, fields)); err != nil {
+					return err
+				}
+			}
+			if !result.IsTruncated {
+				break
+			}
+			marker, versionMarker = result.NextMarker, result.NextVersionIDMarker
+		}
+	} else {
+		var marker string
+		for {
+			result, err := objAPI.ListObjects(ctx, bucket, prefix, marker, "", maxObjectList)
+			if err != nil {
+				return err
+			}
+			for _, objInfo := range result.Objects {
+				if err = iw.write(inventoryRecord(objInfo, fields)); err != nil {
+					return err
+				}
+			}
+			if !result.IsTruncated {
+				break
+			}
+			marker = result.NextMarker
+		}
+	}
+	return iw.close()
+}
+
+// inventoryRecord returns the values of the fields of the object,
+// nil values are not set for the object.
+func inventoryRecord(objInfo ObjectInfo, fields []inventory.Field) []interface{} {
+	values := make([]interface{}, len(fields))
+	for i, field := range fields {
+		switch field {
+		case inventory.BucketField:
+			values[i] = objInfo.Bucket
+		case inventory.KeyField:
+			values[i] = objInfo.Name
+		case inventory.VersionIDField:
+			if objInfo.VersionID != "" {
+				values[i] = objInfo.VersionID
+			}
+		case inventory.IsLatestField:
+			values[i] = objInfo.IsLatest
+		case inventory.IsDeleteMarkerField:
+			values[i] = objInfo.DeleteMarker
+		case inventory.SizeField:
+			if !objInfo.DeleteMarker {
+				values[i] = objInfo.Size
+			}
+		case inventory.LastModifiedDateField:
+			values[i] = objInfo.ModTime.UTC().Format(iso8601TimeFormat)
+		case inventory.StorageClassField:
+			if !objInfo.DeleteMarker {
+				values[i] = objInfo.StorageClass
+			}
+		case inventory.ETagField:
+			if !objInfo.DeleteMarker {
+				values[i] = objInfo.ETag
+			}
+		case inventory.IsMultipartUploadedField:
+			if !objInfo.DeleteMarker {
+				// ETags of the objects uploaded in parts end with the number of parts.
+				values[i] = strings.Contains(objInfo.ETag, "-")
+			}
+		case inventory.ReplicationStatusField:
+			if status := objInfo.ReplicationStatus.String(); status != "" {
+				values[i] = status
+			}
+		case inventory.EncryptionStatusField:
+			if !objInfo.DeleteMarker {
+				values[i] = "NOT-SSE"
+				if kind, ok := crypto.IsEncrypted(objInfo.UserDefined); ok && kind != nil {
+					values[i] = kind.String()
+				}
+			}
+		case inventory.ObjectLockRetainUntilDateField:
+			if ret := objectlock.GetObjectRetentionMeta(objInfo.UserDefined); !ret.RetainUntilDate.IsZero() {
+				values[i] = ret.RetainUntilDate.UTC().Format(iso8601TimeFormat)
+			}
+		case inventory.ObjectLockModeField:
+			if ret := objectlock.GetObjectRetentionMeta(objInfo.UserDefined); ret.Mode != "" {
+				values[i] = string(ret.Mode)
+			}
+		case inventory.ObjectLockLegalHoldStatusField:
+			if hold := objectlock.GetObjectLegalHoldMeta(objInfo.UserDefined); hold.Status != "" {
+				values[i] = string(hold.Status)
+			}
+		}
+	}
+	return values
+}
+
+// inventoryWriter writes the records of an inventory report.
+type inventoryWriter interface {
+	write(values []interface{}) error
+	close() error
+}
+
+func newInventoryWriter(format inventory.Format, fields []inventory.Field, w io.Writer) (inventoryWriter, error) {
+	switch format {
+	case inventory.CSV:
+		gzw := gzip.NewWriter(w)
+		return &csvInventoryWriter{gzw: gzw, csvw: csv.NewWriter(gzw)}, nil
+	case inventory.JSON:
+		gzw := gzip.NewWriter(w)
+		return &jsonInventoryWriter{gzw: gzw, enc: json.NewEncoder(gzw), fields: fields}, nil
+	case inventory.Parquet:
+		columns := make([]parquet.Column, len(fields))
+		for i, field := range fields {
+			columns[i] = parquet.Column{Name: string(field), Type: parquet.String}
+			switch field {
+			case inventory.SizeField:
+				columns[i].Type = parquet.Int64
+			case inventory.IsLatestField, inventory.IsDeleteMarkerField, inventory.IsMultipartUploadedField:
+				columns[i].Type = parquet.Bool
+			}
+		}
+		pw, err := parquet.NewWriter(xioutil.NopCloser(w), columns, inventoryParquetRowGroupCount)
+		if err != nil {
+			return nil, err
+		}
+		return &parquetInventoryWriter{pw: pw}, nil
+	}
+	return nil, inventory.Errorf("Unsupported inventory format '%s'", format)
+}
+
+// csvInventoryWriter writes gzip compressed CSV records, without header.
+type csvInventoryWriter struct {
+	gzw  *gzip.Writer
+	csvw *csv.Writer
+}
+
+func (w *csvInventoryWriter) write(values []interface{}) error {
+	record := make([]string, len(values))
+	for i, value := range values {
+		switch v := value.(type) {
+		case string:
+			record[i] = v
+		case int64:
+			record[i] = strconv.FormatInt(v, 10)
+		case bool:
+			record[i] = strconv.FormatBool(v)
+		}
+	}
+	return w.csvw.Write(record)
+}
+
+func (w *csvInventoryWriter) close() error {
+	w.csvw.Flush()
+	if err := w.csvw.Error(); err != nil {
+		return err
+	}
+	return w.gzw.Close()
+}
+
+// jsonInventoryWriter writes gzip compressed JSON records, one per
+// line, the values which are not set are omitted.
+type jsonInventoryWriter struct {
+	gzw    *gzip.Writer
+	enc    *json.Encoder
+	fields []inventory.Field
+}
+
+func (w *jsonInventoryWriter) write(values []interface{}) error {
+	record := make(map[inventory.Field]interface{}, len(values))
+	for i, value := range values {
+		if value != nil {
+			record[w.fields[i]] = value
+		}
+	}
+	return w.enc.Encode(record)
+}
+
+func (w *jsonInventoryWriter) close() error {
+	return w.gzw.Close()
+}
+
+// parquetInventoryWriter writes Parquet records.
+type parquetInventoryWriter struct {
+	pw *parquet.Writer
+}
+
+func (w *parquetInventoryWriter) write(values []interface{}) error {
+	return w.pw.Write(values...)
+}
+
+func (w *parquetInventoryWriter) close() error {
+	return w.pw.Close()
+}
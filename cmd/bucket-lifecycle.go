@@ -317,15 +317,18 @@ func deleteTransitionedObject(ctx context.Context, objectAPI ObjectLayer, bucket
 	}
 
 	var opts ObjectOptions
-	opts.Versioned = globalBucketVersioningSys.Enabled(bucket)
+	opts.Versioned = globalBucketVersioningSys.PrefixEnabled(bucket, object)
 	opts.VersionID = lcOpts.VersionID
 	if restoredObject {
 		// delete locally restored copy of object or object version
 		// from the source, while leaving metadata behind. The data on
 		// transitioned tier lies untouched and still accessible
 		opts.TransitionStatus = lcOpts.TransitionStatus
-		_, err = objectAPI.DeleteObject(ctx, bucket, object, opts)
-		return err
+		if _, err = objectAPI.DeleteObject(ctx, bucket, object, opts); err != nil {
+			return err
+		}
+		invalidateCachedObject(bucket, object)
+		return nil
 	}
 
 	// When an object is past expiry, delete the data from transitioned tier and
@@ -342,6 +345,7 @@ func deleteTransitionedObject(ctx context.Context, objectAPI ObjectLayer, bucket
 	if err != nil {
 		return err
 	}
+	invalidateCachedObject(bucket, object)
 	eventName := event.ObjectRemovedDelete
 	if lcOpts.DeleteMarker {
 		eventName = event.ObjectRemovedDeleteMarkerCreated
@@ -406,7 +410,7 @@ func transitionObject(ctx context.Context, objectAPI ObjectLayer, objInfo Object
 	gr.Close()
 
 	var opts ObjectOptions
-	opts.Versioned = globalBucketVersioningSys.Enabled(oi.Bucket)
+	opts.Versioned = globalBucketVersioningSys.PrefixEnabled(oi.Bucket, oi.Name)
 	opts.VersionID = oi.VersionID
 	opts.TransitionStatus = lifecycle.TransitionComplete
 	eventName := event.ObjectTransitionComplete
@@ -599,7 +603,9 @@ func (r *RestoreObjectRequest) validate(ctx context.Context, objAPI ObjectLayer)
 		if r.OutputLocation.S3.Prefix == "" {
 			return fmt.Errorf("Prefix is a required parameter in OutputLocation")
 		}
-		if r.OutputLocation.S3.Encryption.EncryptionType != xhttp.AmzEncryptionAES {
+		switch r.OutputLocation.S3.Encryption.EncryptionType {
+		case "", xhttp.AmzEncryptionAES:
+		default:
 			return NotImplemented{}
 		}
 	}
@@ -617,19 +623,18 @@ func putRestoreOpts(bucket, object string, rreq *RestoreObjectRequest, objInfo O
 
 	if rreq.Type == SelectRestoreRequest {
 		for _, v := range rreq.OutputLocation.S3.UserMetadata {
-			if !strings.HasPrefix("x-amz-meta", strings.ToLower(v.Name)) {
+			if !strings.HasPrefix(strings.ToLower(v.Name), "x-amz-meta") {
 				meta["x-amz-meta-"+v.Name] = v.Value
 				continue
 			}
 			meta[v.Name] = v.Value
 		}
-		meta[xhttp.AmzObjectTagging] = rreq.OutputLocation.S3.Tagging.String()
-		if rreq.OutputLocation.S3.Encryption.EncryptionType != "" {
-			meta[xhttp.AmzServerSideEncryption] = xhttp.AmzEncryptionAES
+		if rreq.OutputLocation.S3.Tagging != nil {
+			meta[xhttp.AmzObjectTagging] = rreq.OutputLocation.S3.Tagging.String()
 		}
 		return ObjectOptions{
-			Versioned:        globalBucketVersioningSys.Enabled(bucket),
-			VersionSuspended: globalBucketVersioningSys.Suspended(bucket),
+			Versioned:        globalBucketVersioningSys.PrefixEnabled(bucket, object),
+			VersionSuspended: globalBucketVersioningSys.PrefixSuspended(bucket, object),
 			UserDefined:      meta,
 		}
 	}
@@ -639,8 +644,8 @@ func putRestoreOpts(bucket, object string, rreq *RestoreObjectRequest, objInfo O
 	meta[xhttp.AmzObjectTagging] = objInfo.UserTags
 
 	return ObjectOptions{
-		Versioned:        globalBucketVersioningSys.Enabled(bucket),
-		VersionSuspended: globalBucketVersioningSys.Suspended(bucket),
+		Versioned:        globalBucketVersioningSys.PrefixEnabled(bucket, object),
+		VersionSuspended: globalBucketVersioningSys.PrefixSuspended(bucket, object),
 		UserDefined:      meta,
 		VersionID:        objInfo.VersionID,
 		MTime:            objInfo.ModTime,
@@ -705,3 +710,91 @@ func restoreTransitionedObject(ctx context.Context, bucket, object string, objAP
 
 	return nil
 }
+
+// recordRestoreFailure records the failure of a background restore on the
+// source object so that clients see it on HEAD, an ongoing restore is
+// cleared so that the restore can be requested again.
+func recordRestoreFailure(ctx context.Context, bucket, object string, objAPI ObjectLayer, objInfo ObjectInfo, restoreErr error) error {
+	oi, err := objAPI.GetObjectInfo(ctx, bucket, object, ObjectOptions{VersionID: objInfo.VersionID})
+	if err != nil {
+		return err
+	}
+	metadata := cloneMSS(oi.UserDefined)
+	if strings.HasPrefix(metadata[xhttp.AmzRestore], "ongoing-request=true") {
+		delete(metadata, xhttp.AmzRestore)
+		delete(metadata, xhttp.AmzRestoreExpiryDays)
+		delete(metadata, xhttp.AmzRestoreRequestDate)
+	}
+	metadata[xhttp.MinIORestoreFailure] = fmt.Sprintf("failure-date=%s, error=%s", UTCNow().Format(http.TimeFormat), restoreErr)
+	oi.UserDefined = metadata
+	oi.metadataOnly = true // Perform only metadata updates.
+	_, err = objAPI.CopyObject(ctx, bucket, object, bucket, object, oi, ObjectOptions{
+		VersionID: oi.VersionID,
+	}, ObjectOptions{
+		VersionID: oi.VersionID,
+	})
+	return err
+}
+
+// restoreSelectTransitionedObject runs the select expression of a restore
+// request of type SELECT against the transitioned data and writes the
+// results to the requested output location, encrypted and tagged as
+// requested.
+func restoreSelectTransitionedObject(ctx context.Context, bucket, object string, objAPI ObjectLayer, objInfo ObjectInfo, rreq *RestoreObjectRequest, outputObject string, h http.Header) error {
+	getObject := func(offset, length int64) (rc io.ReadCloser, err error) {
+		isSuffixLength := false
+		if offset < 0 {
+			isSuffixLength = true
+		}
+
+		rs := &HTTPRangeSpec{
+			IsSuffixLength: isSuffixLength,
+			Start:          offset,
+			End:            offset + length,
+		}
+
+		return getTransitionedObjectReader(ctx, bucket, object, rs, h, objInfo, ObjectOptions{
+			VersionID: objInfo.VersionID,
+		})
+	}
+	if err := rreq.SelectParameters.Open(getObject); err != nil {
+		return err
+	}
+	defer rreq.SelectParameters.Close()
+
+	pr, pw := io.Pipe()
+	done := make(chan struct{})
+	go func() {
+		pw.CloseWithError(rreq.SelectParameters.EvaluateTo(pw))
+		close(done)
+	}()
+	// Closing the reader stops the evaluation, which must be done
+	// before the select parameters are closed.
+	defer func() {
+		pr.Close()
+		<-done
+	}()
+
+	outputBucket := rreq.OutputLocation.S3.BucketName
+	hashReader, err := hash.NewReader(pr, -1, "", "", -1, globalCLIContext.StrictS3Compat)
+	if err != nil {
+		return err
+	}
+	pReader := NewPutObjReader(hashReader)
+	opts := putRestoreOpts(outputBucket, outputObject, rreq, objInfo)
+	if rreq.OutputLocation.S3.Encryption.EncryptionType == xhttp.AmzEncryptionAES {
+		reader, objectEncryptionKey, err := newEncryptReader(hashReader, nil, outputBucket, outputObject, opts.UserDefined, true)
+		if err != nil {
+			return err
+		}
+		encReader, err := hash.NewReader(reader, -1, "", "", -1, globalCLIContext.StrictS3Compat)
+		if err != nil {
+			return err
+		}
+		if pReader, err = pReader.WithEncryption(encReader, &objectEncryptionKey); err != nil {
+			return err
+		}
+	}
+	_, err = objAPI.PutObject(ctx, outputBucket, outputObject, pReader, opts)
+	return err
+}
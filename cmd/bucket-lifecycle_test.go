@@ -0,0 +1,132 @@
+/*
+ * MinIO Cloud Storage, (C) 2021 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/minio/minio-go/v7/pkg/tags"
+	xhttp "github.com/minio/minio/cmd/http"
+)
+
+// Tests metadata set on the results of a restore request of type SELECT.
+func TestPutRestoreOptsSelect(t *testing.T) {
+	tagging, err := tags.MapToObjectTags(map[string]string{"class": "archive"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	objInfo := ObjectInfo{StorageClass: "STANDARD"}
+
+	testCases := []struct {
+		location     OutputLocation
+		wantMeta     map[string]string
+		wantNoTagged bool
+	}{
+		{
+			location: OutputLocation{S3: S3Location{
+				BucketName: "results",
+				Prefix:     "select",
+				UserMetadata: []MetadataEntry{
+					{Name: "project", Value: "a"},
+					{Name: "X-Amz-Meta-Owner", Value: "b"},
+				},
+			}},
+			wantMeta: map[string]string{
+				"x-amz-meta-project":                   "a",
+				"X-Amz-Meta-Owner":                     "b",
+				strings.ToLower(xhttp.AmzStorageClass): "STANDARD",
+			},
+			wantNoTagged: true,
+		},
+		{
+			location: OutputLocation{S3: S3Location{
+				BucketName:   "results",
+				Prefix:       "select",
+				StorageClass: "REDUCED_REDUNDANCY",
+				Tagging:      tagging,
+			}},
+			wantMeta: map[string]string{
+				xhttp.AmzObjectTagging:                 "class=archive",
+				strings.ToLower(xhttp.AmzStorageClass): "REDUCED_REDUNDANCY",
+			},
+		},
+	}
+
+	for i, tc := range testCases {
+		rreq := &RestoreObjectRequest{Type: SelectRestoreRequest, OutputLocation: tc.location}
+		opts := putRestoreOpts(tc.location.S3.BucketName, "object", rreq, objInfo)
+		for k, v := range tc.wantMeta {
+			if got := opts.UserDefined[k]; got != v {
+				t.Errorf("Test %d: expected %s=%q, got %q", i+1, k, v, got)
+			}
+		}
+		if _, ok := opts.UserDefined[xhttp.AmzObjectTagging]; ok && tc.wantNoTagged {
+			t.Errorf("Test %d: expected no tagging metadata", i+1)
+		}
+	}
+}
+
+// Tests that a failed restore is recorded on the source object.
+func TestRecordRestoreFailure(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	obj, disks, err := prepareErasure(ctx, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeRoots(disks)
+	setObjectLayer(obj)
+	newAllSubsystems()
+
+	bucket, object := "bucket", "object"
+	if err = obj.MakeBucketWithLocation(ctx, bucket, BucketOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	data := []byte("data")
+	objInfo, err := obj.PutObject(ctx, bucket, object, mustGetPutObjReader(t, bytes.NewReader(data), int64(len(data)), "", ""), ObjectOptions{
+		UserDefined: map[string]string{
+			xhttp.AmzRestore:            "ongoing-request=true",
+			xhttp.AmzRestoreExpiryDays:  "1",
+			xhttp.AmzRestoreRequestDate: UTCNow().Format(http.TimeFormat),
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = recordRestoreFailure(ctx, bucket, object, obj, objInfo, errors.New("tier unreachable")); err != nil {
+		t.Fatal(err)
+	}
+	oi, err := obj.GetObjectInfo(ctx, bucket, object, ObjectOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if failure := oi.UserDefined[xhttp.MinIORestoreFailure]; !strings.Contains(failure, "error=tier unreachable") {
+		t.Fatalf("expected the restore failure to be recorded, got %q", failure)
+	}
+	for _, k := range []string{xhttp.AmzRestore, xhttp.AmzRestoreExpiryDays, xhttp.AmzRestoreRequestDate} {
+		if _, ok := oi.UserDefined[k]; ok {
+			t.Fatalf("expected %s to be cleared, got %v", k, oi.UserDefined)
+		}
+	}
+}
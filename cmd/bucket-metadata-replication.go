@@ -0,0 +1,246 @@
+/*
+ * MinIO Cloud Storage, (C) 2021 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/minio/minio-go/v7/pkg/tags"
+	"github.com/minio/minio/cmd/logger"
+	"github.com/minio/minio/pkg/bucket/lifecycle"
+	objectlock "github.com/minio/minio/pkg/bucket/object/lock"
+	"github.com/minio/minio/pkg/bucket/policy"
+	"github.com/minio/minio/pkg/madmin"
+)
+
+const (
+	// Interval at which all the replicated configurations are pushed
+	// again, to catch up with the changes a target missed while offline.
+	bucketMetadataReplicationInterval = 15 * time.Minute
+
+	// Timeout of the push of a change to a single target.
+	bucketMetadataReplicationTimeout = 30 * time.Second
+
+	// Maximum size of a replicated configuration change.
+	maxBucketMetadataUpdateSize = 4 << 20
+)
+
+// Bucket configurations replicated to the replication targets with
+// metadata replication enabled.
+var replicatedBucketMetadata = map[string]madmin.BucketMetadataType{
+	bucketPolicyConfig:    madmin.BucketPolicyMetadata,
+	bucketTaggingConfig:   madmin.BucketTaggingMetadata,
+	bucketSSEConfig:       madmin.BucketSSEMetadata,
+	objectLockConfig:      madmin.BucketObjectLockMetadata,
+	bucketLifecycleConfig: madmin.BucketLifecycleMetadata,
+}
+
+func isReplicatedBucketMetadata(configFile string) bool {
+	_, ok := replicatedBucketMetadata[configFile]
+	return ok
+}
+
+// configUpdatedAt returns the time of the last update of the config file,
+// it is only tracked for the replicated configurations.
+func (b BucketMetadata) configUpdatedAt(configFile string) time.Time {
+	switch configFile {
+	case bucketPolicyConfig:
+		return b.PolicyConfigUpdatedAt
+	case bucketTaggingConfig:
+		return b.TaggingConfigUpdatedAt
+	case bucketSSEConfig:
+		return b.EncryptionConfigUpdatedAt
+	case objectLockConfig:
+		return b.ObjectLockConfigUpdatedAt
+	case bucketLifecycleConfig:
+		return b.LifecycleConfigUpdatedAt
+	}
+	return time.Time{}
+}
+
+func (b *BucketMetadata) setConfigUpdatedAt(configFile string, updatedAt time.Time) {
+	switch configFile {
+	case bucketPolicyConfig:
+		b.PolicyConfigUpdatedAt = updatedAt
+	case bucketTaggingConfig:
+		b.TaggingConfigUpdatedAt = updatedAt
+	case bucketSSEConfig:
+		b.EncryptionConfigUpdatedAt = updatedAt
+	case objectLockConfig:
+		b.ObjectLockConfigUpdatedAt = updatedAt
+	case bucketLifecycleConfig:
+		b.LifecycleConfigUpdatedAt = updatedAt
+	}
+}
+
+func (b BucketMetadata) configData(configFile string) []byte {
+	switch configFile {
+	case bucketPolicyConfig:
+		return b.PolicyConfigJSON
+	case bucketTaggingConfig:
+		return b.TaggingConfigXML
+	case bucketSSEConfig:
+		return b.EncryptionConfigXML
+	case objectLockConfig:
+		return b.ObjectLockConfigXML
+	case bucketLifecycleConfig:
+		return b.LifecycleConfigXML
+	}
+	return nil
+}
+
+// metadataReplicationTargets returns the replication targets of the bucket
+// with metadata replication enabled.
+func metadataReplicationTargets(ctx context.Context, bucket string) (targets []madmin.BucketTarget) {
+	tgts, err := globalBucketTargetSys.ListBucketTargets(ctx, bucket)
+	if err != nil {
+		return nil
+	}
+	for _, t := range tgts.Targets {
+		if t.Type == madmin.ReplicationService && t.ReplicateMetadata && t.Credentials != nil {
+			targets = append(targets, t)
+		}
+	}
+	return targets
+}
+
+// replicateBucketMetadata pushes the current value of the config file of the
+// bucket to its replication targets. Targets holding a newer value reject it
+// and immediately push their own value to their targets, this site included.
+func replicateBucketMetadata(ctx context.Context, meta BucketMetadata, configFile string) {
+	targets := metadataReplicationTargets(ctx, meta.Name)
+	if len(targets) == 0 {
+		return
+	}
+	update := madmin.BucketMetadataUpdate{
+		Type:      replicatedBucketMetadata[configFile],
+		Data:      meta.configData(configFile),
+		UpdatedAt: meta.configUpdatedAt(configFile),
+	}
+	for _, t := range targets {
+		// Policies refer to the bucket by name, they can
+		// only be applied to a bucket of the same name.
+		if configFile == bucketPolicyConfig && t.TargetBucket != meta.Name {
+			continue
+		}
+		err := pushBucketMetadata(ctx, t, update)
+		// Targets holding a newer value push it back on conflict.
+		if err != nil && madmin.ToErrorResponse(err).Code != errorCodes[ErrBucketMetadataConflict].Code {
+			logger.LogIf(ctx, fmt.Errorf("Unable to replicate %s configuration of bucket %s to %s: %w",
+				update.Type, meta.Name, t.Arn, err))
+		}
+	}
+}
+
+func pushBucketMetadata(ctx context.Context, t madmin.BucketTarget, update madmin.BucketMetadataUpdate) error {
+	clnt, err := madmin.New(t.Endpoint, t.Credentials.AccessKey, t.Credentials.SecretKey, t.Secure)
+	if err != nil {
+		return err
+	}
+	getRemoteTargetInstanceTransportOnce.Do(func() {
+		getRemoteTargetInstanceTransport = newGatewayHTTPTransport(10 * time.Minute)
+	})
+	clnt.SetCustomTransport(getRemoteTargetInstanceTransport)
+	ctx, cancel := context.WithTimeout(ctx, bucketMetadataReplicationTimeout)
+	defer cancel()
+	return clnt.ReplicateBucketMetadata(ctx, t.TargetBucket, update)
+}
+
+// replicateBucketMetadataPeriodically pushes the replicated configurations
+// of all the buckets to their targets. Unchanged configurations are ignored
+// by the targets.
+func (sys *BucketMetadataSys) replicateBucketMetadataPeriodically(ctx context.Context) {
+	ticker := time.NewTicker(bucketMetadataReplicationInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		sys.RLock()
+		metas := make([]BucketMetadata, 0, len(sys.metadataMap))
+		for _, meta := range sys.metadataMap {
+			metas = append(metas, meta)
+		}
+		sys.RUnlock()
+		for _, meta := range metas {
+			for configFile := range replicatedBucketMetadata {
+				if !meta.configUpdatedAt(configFile).IsZero() {
+					replicateBucketMetadata(ctx, meta, configFile)
+				}
+			}
+		}
+	}
+}
+
+// parseBucketMetadataUpdate validates a replicated change of a bucket
+// configuration and returns the config file it updates.
+func parseBucketMetadataUpdate(ctx context.Context, bucket string, update madmin.BucketMetadataUpdate) (string, error) {
+	if update.UpdatedAt.IsZero() {
+		return "", errInvalidArgument
+	}
+	var configFile string
+	for file, typ := range replicatedBucketMetadata {
+		if typ == update.Type {
+			configFile = file
+		}
+	}
+	if configFile == "" {
+		return "", errInvalidArgument
+	}
+	if len(update.Data) == 0 {
+		if configFile == objectLockConfig {
+			// Object lock configuration cannot be removed.
+			return "", errInvalidArgument
+		}
+		return configFile, nil
+	}
+
+	var err error
+	switch configFile {
+	case bucketPolicyConfig:
+		_, err = policy.ParseConfig(bytes.NewReader(update.Data), bucket)
+	case bucketTaggingConfig:
+		_, err = tags.ParseBucketXML(bytes.NewReader(update.Data))
+	case bucketSSEConfig:
+		if GlobalKMS == nil {
+			return "", errKMSNotConfigured
+		}
+		_, err = validateBucketSSEConfig(bytes.NewReader(update.Data))
+	case objectLockConfig:
+		if _, err = objectlock.ParseObjectLockConfig(bytes.NewReader(update.Data)); err != nil {
+			return "", err
+		}
+		// Object locking cannot be enabled on an existing bucket.
+		_, err = globalBucketMetadataSys.GetObjectLockConfig(bucket)
+	case bucketLifecycleConfig:
+		var lc *lifecycle.Lifecycle
+		if lc, err = lifecycle.ParseLifecycleConfig(bytes.NewReader(update.Data)); err != nil {
+			return "", err
+		}
+		if err = lc.Validate(); err != nil {
+			return "", err
+		}
+		err = validateLifecycleTransition(ctx, bucket, lc)
+	}
+	return configFile, err
+}
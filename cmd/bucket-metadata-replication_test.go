@@ -0,0 +1,145 @@
+/*
+ * MinIO Cloud Storage, (C) 2021 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/minio/minio/pkg/madmin"
+)
+
+func TestBucketMetadataUpdateReplicated(t *testing.T) {
+	objAPI, fsDir, err := prepareFS()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(fsDir)
+	newAllSubsystems()
+
+	const bucket = "bucket"
+	if err = objAPI.MakeBucketWithLocation(context.Background(), bucket, BucketOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	tagging := []byte(`<Tagging><TagSet><Tag><Key>key</Key><Value>value</Value></Tag></TagSet></Tagging>`)
+	update := madmin.BucketMetadataUpdate{
+		Type:      madmin.BucketTaggingMetadata,
+		Data:      tagging,
+		UpdatedAt: UTCNow().Truncate(time.Millisecond),
+	}
+	configFile, err := parseBucketMetadataUpdate(context.Background(), bucket, update)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if configFile != bucketTaggingConfig {
+		t.Fatalf("expected %s, got %s", bucketTaggingConfig, configFile)
+	}
+
+	sys := NewBucketMetadataSys()
+	if err = sys.update(objAPI, bucket, configFile, update.Data, update.UpdatedAt, true); err != nil {
+		t.Fatal(err)
+	}
+	meta, err := loadBucketMetadata(context.Background(), objAPI, bucket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(meta.TaggingConfigXML) != string(tagging) || !meta.TaggingConfigUpdatedAt.Equal(update.UpdatedAt) {
+		t.Fatalf("replicated tagging not applied: %s at %s", meta.TaggingConfigXML, meta.TaggingConfigUpdatedAt)
+	}
+
+	// Replaying the same change is a no-op, older changes are conflicts.
+	if err = sys.update(objAPI, bucket, configFile, update.Data, update.UpdatedAt, true); err != nil {
+		t.Fatalf("expected the same change to be ignored, got %v", err)
+	}
+	err = sys.update(objAPI, bucket, configFile, nil, update.UpdatedAt.Add(-time.Second), true)
+	if !errors.As(err, &BucketMetadataConflict{}) {
+		t.Fatalf("expected a conflict for an older change, got %v", err)
+	}
+
+	// Newer changes are applied, including removals.
+	if err = sys.update(objAPI, bucket, configFile, nil, update.UpdatedAt.Add(time.Second), true); err != nil {
+		t.Fatal(err)
+	}
+	meta, err = loadBucketMetadata(context.Background(), objAPI, bucket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(meta.TaggingConfigXML) != 0 {
+		t.Fatalf("expected the tagging to be removed, got %s", meta.TaggingConfigXML)
+	}
+
+	// Concurrent changes leave the newest one applied.
+	base := update.UpdatedAt.Add(time.Minute)
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			data := []byte(fmt.Sprintf(`<Tagging><TagSet><Tag><Key>key</Key><Value>%d</Value></Tag></TagSet></Tagging>`, i))
+			sys.update(objAPI, bucket, configFile, data, base.Add(time.Duration(i)*time.Second), true)
+		}(i)
+	}
+	wg.Wait()
+	meta, err = loadBucketMetadata(context.Background(), objAPI, bucket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !meta.TaggingConfigUpdatedAt.Equal(base.Add(9*time.Second)) || !strings.Contains(string(meta.TaggingConfigXML), "<Value>9</Value>") {
+		t.Fatalf("expected the newest change to be applied, got %s at %s", meta.TaggingConfigXML, meta.TaggingConfigUpdatedAt)
+	}
+
+	// Different changes made at the same time on two sites leave the
+	// same one applied on both sites.
+	tie := base.Add(time.Hour)
+	lower := []byte(`<Tagging><TagSet><Tag><Key>key</Key><Value>a</Value></Tag></TagSet></Tagging>`)
+	higher := []byte(`<Tagging><TagSet><Tag><Key>key</Key><Value>b</Value></Tag></TagSet></Tagging>`)
+	if err = sys.update(objAPI, bucket, configFile, lower, tie, true); err != nil {
+		t.Fatal(err)
+	}
+	if err = sys.update(objAPI, bucket, configFile, higher, tie, true); err != nil {
+		t.Fatal(err)
+	}
+	err = sys.update(objAPI, bucket, configFile, lower, tie, true)
+	if !errors.As(err, &BucketMetadataConflict{}) {
+		t.Fatalf("expected a conflict for a change made at the same time, got %v", err)
+	}
+	meta, err = loadBucketMetadata(context.Background(), objAPI, bucket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(meta.TaggingConfigXML) != string(higher) {
+		t.Fatalf("expected the same change to be kept on a tie, got %s", meta.TaggingConfigXML)
+	}
+
+	for _, update := range []madmin.BucketMetadataUpdate{
+		{Type: "unknown", UpdatedAt: UTCNow()},
+		{Type: madmin.BucketTaggingMetadata, Data: tagging},
+		{Type: madmin.BucketObjectLockMetadata, UpdatedAt: UTCNow()},
+		{Type: madmin.BucketTaggingMetadata, Data: []byte("<Tagging>"), UpdatedAt: UTCNow()},
+	} {
+		if _, err = parseBucketMetadataUpdate(context.Background(), bucket, update); err == nil {
+			t.Errorf("expected invalid update %s to be rejected", update.Type)
+		}
+	}
+}
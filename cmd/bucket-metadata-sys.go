@@ -21,12 +21,15 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"path"
 	"sync"
+	"time"
 
 	"github.com/minio/minio-go/v7/pkg/tags"
 	"github.com/minio/minio/cmd/crypto"
 	"github.com/minio/minio/cmd/logger"
 	bucketsse "github.com/minio/minio/pkg/bucket/encryption"
+	"github.com/minio/minio/pkg/bucket/inventory"
 	"github.com/minio/minio/pkg/bucket/lifecycle"
 	objectlock "github.com/minio/minio/pkg/bucket/object/lock"
 	"github.com/minio/minio/pkg/bucket/policy"
@@ -131,15 +134,62 @@ func (sys *BucketMetadataSys) Update(bucket string, configFile string, configDat
 		return NotImplemented{}
 	}
 
+	return sys.update(objAPI, bucket, configFile, configData, UTCNow(), false)
+}
+
+// UpdateReplicated updates bucket metadata for the specified config file
+// with a change replicated from another site, made at updatedAt. Changes
+// older than the configuration already applied are rejected.
+func (sys *BucketMetadataSys) UpdateReplicated(bucket string, configFile string, configData []byte, updatedAt time.Time) error {
+	objAPI := newObjectLayerFn()
+	if objAPI == nil {
+		return errServerNotInitialized
+	}
+
+	if globalIsGateway {
+		return NotImplemented{}
+	}
+
+	return sys.update(objAPI, bucket, configFile, configData, updatedAt, true)
+}
+
+func (sys *BucketMetadataSys) update(objAPI ObjectLayer, bucket string, configFile string, configData []byte, updatedAt time.Time, replicated bool) error {
 	if bucket == minioMetaBucket {
 		return errInvalidArgument
 	}
 
+	// Serialize the updates of the bucket on all the servers, replicated
+	// changes are compared with the applied configuration before being
+	// written. The metadata object itself is locked while it is written.
+	lk := objAPI.NewNSLock(minioMetaBucket, path.Join(bucketConfigPrefix, bucket, bucketMetadataLockFile))
+	if err := lk.GetLock(GlobalContext, globalOperationTimeout); err != nil {
+		return err
+	}
+	defer lk.Unlock()
+
 	meta, err := loadBucketMetadata(GlobalContext, objAPI, bucket)
 	if err != nil {
 		return err
 	}
 
+	if replicated {
+		lastUpdate := meta.configUpdatedAt(configFile)
+		switch {
+		case lastUpdate.Equal(updatedAt) && bytes.Equal(meta.configData(configFile), configData):
+			// Change was already applied.
+			return nil
+		case lastUpdate.After(updatedAt),
+			// Concurrent changes on two sites, all sites keep the
+			// same one whichever site they are received on.
+			lastUpdate.Equal(updatedAt) && bytes.Compare(meta.configData(configFile), configData) > 0:
+			// Push the newer configuration right away, the
+			// source would otherwise keep the older one until
+			// the next periodic replication.
+			go replicateBucketMetadata(GlobalContext, meta, configFile)
+			return BucketMetadataConflict{Bucket: bucket}
+		}
+	}
+
 	switch configFile {
 	case bucketPolicyConfig:
 		meta.PolicyConfigJSON = configData
@@ -147,6 +197,8 @@ func (sys *BucketMetadataSys) Update(bucket string, configFile string, configDat
 		meta.NotificationConfigXML = configData
 	case bucketLifecycleConfig:
 		meta.LifecycleConfigXML = configData
+	case bucketInventoryConfig:
+		meta.InventoryConfigXML = configData
 	case bucketSSEConfig:
 		meta.EncryptionConfigXML = configData
 	case bucketTaggingConfig:
@@ -154,14 +206,8 @@ func (sys *BucketMetadataSys) Update(bucket string, configFile string, configDat
 	case bucketQuotaConfigFile:
 		meta.QuotaConfigJSON = configData
 	case objectLockConfig:
-		if !globalIsErasure && !globalIsDistErasure {
-			return NotImplemented{}
-		}
 		meta.ObjectLockConfigXML = configData
 	case bucketVersioningConfig:
-		if !globalIsErasure && !globalIsDistErasure {
-			return NotImplemented{}
-		}
 		meta.VersioningConfigXML = configData
 	case bucketReplicationConfig:
 		if !globalIsErasure && !globalIsDistErasure {
@@ -176,6 +222,7 @@ func (sys *BucketMetadataSys) Update(bucket string, configFile string, configDat
 	default:
 		return fmt.Errorf("Unknown bucket %s metadata update requested %s", bucket, configFile)
 	}
+	meta.setConfigUpdatedAt(configFile, updatedAt)
 
 	if err := meta.Save(GlobalContext, objAPI); err != nil {
 		return err
@@ -184,6 +231,12 @@ func (sys *BucketMetadataSys) Update(bucket string, configFile string, configDat
 	sys.Set(bucket, meta)
 	globalNotificationSys.LoadBucketMetadata(GlobalContext, bucket)
 
+	// Changes replicated from another site are not replicated
+	// back, the other site pushes them to its own targets.
+	if !replicated && isReplicatedBucketMetadata(configFile) {
+		go replicateBucketMetadata(GlobalContext, meta, configFile)
+	}
+
 	return nil
 }
 
@@ -271,6 +324,23 @@ func (sys *BucketMetadataSys) GetLifecycleConfig(bucket string) (*lifecycle.Life
 	return meta.lifecycleConfig, nil
 }
 
+// GetInventoryConfig returns configured inventory configs, there
+// are no configs if none is configured.
+// The returned object may not be modified.
+func (sys *BucketMetadataSys) GetInventoryConfig(bucket string) (*inventory.Configurations, error) {
+	meta, err := sys.GetConfig(bucket)
+	if err != nil {
+		if errors.Is(err, errConfigNotFound) {
+			return &inventory.Configurations{}, nil
+		}
+		return nil, err
+	}
+	if meta.inventoryConfig == nil {
+		return &inventory.Configurations{}, nil
+	}
+	return meta.inventoryConfig, nil
+}
+
 // GetNotificationConfig returns configured notification config
 // The returned object may not be modified.
 func (sys *BucketMetadataSys) GetNotificationConfig(bucket string) (*event.Config, error) {
@@ -434,6 +504,11 @@ func (sys *BucketMetadataSys) Init(ctx context.Context, buckets []BucketInfo, ob
 
 	// Load bucket metadata sys in background
 	go sys.load(ctx, buckets, objAPI)
+
+	// Replicated configurations are pushed again by a single node.
+	if !globalIsDistErasure || globalEndpoints.FirstLocal() {
+		go sys.replicateBucketMetadataPeriodically(ctx)
+	}
 	return nil
 }
 
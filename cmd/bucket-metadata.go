@@ -32,6 +32,7 @@ import (
 	"github.com/minio/minio/cmd/crypto"
 	"github.com/minio/minio/cmd/logger"
 	bucketsse "github.com/minio/minio/pkg/bucket/encryption"
+	"github.com/minio/minio/pkg/bucket/inventory"
 	"github.com/minio/minio/pkg/bucket/lifecycle"
 	objectlock "github.com/minio/minio/pkg/bucket/object/lock"
 	"github.com/minio/minio/pkg/bucket/policy"
@@ -49,6 +50,9 @@ const (
 	bucketMetadataFile    = ".metadata.bin"
 	bucketMetadataFormat  = 1
 	bucketMetadataVersion = 1
+
+	// Name locked by the updates of the bucket metadata.
+	bucketMetadataLockFile = ".metadata.lock"
 )
 
 var (
@@ -78,6 +82,15 @@ type BucketMetadata struct {
 	ReplicationConfigXML        []byte
 	BucketTargetsConfigJSON     []byte
 	BucketTargetsConfigMetaJSON []byte
+	InventoryConfigXML          []byte
+
+	// Time of the last update of the configurations replicated to the
+	// replication targets, used to resolve conflicting updates.
+	PolicyConfigUpdatedAt     time.Time
+	TaggingConfigUpdatedAt    time.Time
+	EncryptionConfigUpdatedAt time.Time
+	ObjectLockConfigUpdatedAt time.Time
+	LifecycleConfigUpdatedAt  time.Time
 
 	// Unexported fields. Must be updated atomically.
 	policyConfig           *policy.Policy
@@ -91,6 +104,7 @@ type BucketMetadata struct {
 	replicationConfig      *replication.Config
 	bucketTargetConfig     *madmin.BucketTargets
 	bucketTargetConfigMeta map[string]string
+	inventoryConfig        *inventory.Configurations
 }
 
 // newBucketMetadata creates BucketMetadata with the supplied name and Created to Now.
@@ -247,6 +261,15 @@ func (b *BucketMetadata) parseAllConfigs(ctx context.Context, objectAPI ObjectLa
 	} else {
 		b.bucketTargetConfig = &madmin.BucketTargets{}
 	}
+
+	if len(b.InventoryConfigXML) != 0 {
+		b.inventoryConfig, err = inventory.ParseConfigurations(bytes.NewReader(b.InventoryConfigXML))
+		if err != nil {
+			return err
+		}
+	} else {
+		b.inventoryConfig = nil
+	}
 	return nil
 }
 
@@ -17,6 +17,7 @@
 package cmd
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/url"
@@ -26,9 +27,12 @@ import (
 
 	jsoniter "github.com/json-iterator/go"
 	miniogopolicy "github.com/minio/minio-go/v7/pkg/policy"
+	"github.com/minio/minio-go/v7/pkg/tags"
 	xhttp "github.com/minio/minio/cmd/http"
 	"github.com/minio/minio/cmd/logger"
+	"github.com/minio/minio/pkg/auth"
 	"github.com/minio/minio/pkg/bucket/policy"
+	"github.com/minio/minio/pkg/bucket/policy/condition"
 	"github.com/minio/minio/pkg/handlers"
 )
 
@@ -90,9 +94,6 @@ func getConditionValues(r *http.Request, lc string, username string, claims map[
 		"SourceIp":        {handlers.GetSourceIP(r)},
 		"UserAgent":       {r.UserAgent()},
 		"Referer":         {r.Referer()},
-		"principaltype":   {principalType},
-		"userid":          {username},
-		"username":        {username},
 		"versionid":       {vid},
 	}
 
@@ -145,6 +146,31 @@ func getConditionValues(r *http.Request, lc string, username string, claims map[
 		}
 	}
 
+	// Identity values are substituted for policy variables, so they are
+	// only set from the credentials and never from the request headers
+	// or query parameters.
+	args["principaltype"] = []string{principalType}
+	args["userid"] = []string{username}
+	args["username"] = []string{username}
+	for _, key := range append([]condition.Key{condition.LDAPUser}, condition.JWTKeys...) {
+		delete(args, key.Name())
+	}
+
+	// Object tag values are likewise only set from the tags.
+	for key := range args {
+		for _, tagKey := range condition.ObjectTagKeys {
+			if strings.HasPrefix(key, tagKey.Name()+"/") {
+				delete(args, key)
+			}
+		}
+	}
+	delete(args, condition.S3RequestObjectTagKeys.Name())
+	if tagging := r.Header.Get(xhttp.AmzObjectTagging); tagging != "" {
+		if objTags, err := tags.ParseObjectTags(tagging); err == nil {
+			setRequestObjectTagValues(args, objTags.ToMap())
+		}
+	}
+
 	// JWT specific values
 	for k, v := range claims {
 		vStr, ok := v.(string)
@@ -161,6 +187,98 @@ func getConditionValues(r *http.Request, lc string, username string, claims map[
 	return args
 }
 
+// setRequestObjectTagValues - sets the values of the s3:RequestObjectTag/<tag-key>
+// and s3:RequestObjectTagKeys condition keys.
+func setRequestObjectTagValues(values map[string][]string, objTags map[string]string) {
+	tagKeys := make([]string, 0, len(objTags))
+	for k, v := range objTags {
+		values[condition.S3RequestObjectTag.Name()+"/"+k] = []string{v}
+		tagKeys = append(tagKeys, k)
+	}
+	values[condition.S3RequestObjectTagKeys.Name()] = tagKeys
+}
+
+// setExistingObjectTagValues - sets the values of the s3:ExistingObjectTag/<tag-key>
+// condition keys.
+func setExistingObjectTagValues(values map[string][]string, objTags map[string]string) {
+	for k, v := range objTags {
+		values[condition.S3ExistingObjectTag.Name()+"/"+k] = []string{v}
+	}
+}
+
+// setExistingObjectTagConditionValues - sets the values of the
+// s3:ExistingObjectTag/<tag-key> condition keys for action on the object,
+// the tags are only loaded when the policies evaluated for the request
+// have a condition on them. The request must be denied on error, as a
+// Deny statement on the tags could not be evaluated.
+func setExistingObjectTagConditionValues(ctx context.Context, r *http.Request, action policy.Action, bucket, object string, cred auth.Credentials, owner bool, claims map[string]interface{}, values map[string][]string) error {
+	// Policies are not evaluated for the owner.
+	if owner || object == "" {
+		return nil
+	}
+
+	switch action {
+	case policy.GetObjectAction, policy.GetObjectTaggingAction,
+		policy.PutObjectTaggingAction, policy.DeleteObjectTaggingAction:
+	default:
+		return nil
+	}
+
+	if cred.AccessKey == "" {
+		p, err := globalPolicySys.Get(bucket)
+		if err != nil || !p.HasObjectTagKey(condition.S3ExistingObjectTag) {
+			return nil
+		}
+	} else if !globalIAMSys.UsesObjectTagKey(cred.AccessKey, claims, condition.S3ExistingObjectTag) {
+		return nil
+	}
+
+	objTags, err := getExistingObjectTags(ctx, r, bucket, object)
+	if err != nil {
+		return err
+	}
+	setExistingObjectTagValues(values, objTags)
+	return nil
+}
+
+// getExistingObjectTags - returns the tags of the object the request is for,
+// the object is not required to exist.
+func getExistingObjectTags(ctx context.Context, r *http.Request, bucket, object string) (map[string]string, error) {
+	objAPI := newObjectLayerFn()
+	if objAPI == nil || !objAPI.IsTaggingSupported() || object == "" {
+		return nil, nil
+	}
+
+	// The version of a copy source is in the copy source header.
+	vid := strings.TrimSpace(r.URL.Query().Get(xhttp.VersionID))
+	if cpSrcPath := r.Header.Get(xhttp.AmzCopySource); cpSrcPath != "" {
+		if u, err := url.Parse(cpSrcPath); err == nil {
+			if srcBucket, srcObject := path2BucketObject(u.Path); srcBucket == bucket && srcObject == object {
+				vid = strings.TrimSpace(u.Query().Get(xhttp.VersionID))
+			}
+		}
+	}
+
+	// Objects which do not exist have no tags, the handler returns the
+	// error to the client.
+	objInfo, err := objAPI.GetObjectInfo(ctx, bucket, object, ObjectOptions{VersionID: vid})
+	if err != nil {
+		if isErrObjectNotFound(err) || isErrVersionNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if objInfo.UserTags == "" {
+		return nil, nil
+	}
+
+	objTags, err := tags.ParseObjectTags(objInfo.UserTags)
+	if err != nil {
+		return nil, err
+	}
+	return objTags.ToMap(), nil
+}
+
 // PolicyToBucketAccessPolicy converts a MinIO policy into a minio-go policy data structure.
 func PolicyToBucketAccessPolicy(bucketPolicy *policy.Policy) (*miniogopolicy.BucketAccessPolicy, error) {
 	// Return empty BucketAccessPolicy for empty bucket policy.
@@ -0,0 +1,220 @@
+/*
+ * MinIO Cloud Storage, (C) 2021 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	xhttp "github.com/minio/minio/cmd/http"
+	"github.com/minio/minio/pkg/auth"
+	"github.com/minio/minio/pkg/bucket/policy"
+)
+
+func TestGetConditionValuesObjectTags(t *testing.T) {
+	r, err := http.NewRequest(http.MethodPut,
+		"http://127.0.0.1:9000/mybucket/myobject?ExistingObjectTag/classification=public&RequestObjectTagKeys=project&username=bob", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.Header.Set(xhttp.AmzObjectTagging, "classification=internal&project=blue")
+
+	values := getConditionValues(r, "", "alice", nil)
+
+	// Identity and object tag values are never set from the query parameters.
+	if _, ok := values["ExistingObjectTag/classification"]; ok {
+		t.Errorf("unexpected existing object tag %v", values["ExistingObjectTag/classification"])
+	}
+	if !reflect.DeepEqual(values["username"], []string{"alice"}) {
+		t.Errorf("expected username [alice], got %v", values["username"])
+	}
+
+	if !reflect.DeepEqual(values["RequestObjectTag/classification"], []string{"internal"}) {
+		t.Errorf("expected request object tag [internal], got %v", values["RequestObjectTag/classification"])
+	}
+	tagKeys := values["RequestObjectTagKeys"]
+	sort.Strings(tagKeys)
+	if !reflect.DeepEqual(tagKeys, []string{"classification", "project"}) {
+		t.Errorf("expected request object tag keys [classification project], got %v", tagKeys)
+	}
+}
+
+func TestGetExistingObjectTags(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	objLayer, fsDir, err := prepareFS()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(fsDir)
+	newAllSubsystems()
+	setObjectLayer(objLayer)
+	defer setObjectLayer(nil)
+
+	if err = objLayer.MakeBucketWithLocation(ctx, "mybucket", BucketOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	for object, tagging := range map[string]string{
+		"public.txt":   "classification=public",
+		"internal.txt": "classification=internal",
+	} {
+		_, err = objLayer.PutObject(ctx, "mybucket", object, mustGetPutObjReader(t, bytes.NewReader([]byte("hello")), 5, "", ""),
+			ObjectOptions{UserDefined: map[string]string{xhttp.AmzObjectTagging: tagging}})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	bucketPolicy, err := policy.ParseConfig(strings.NewReader(`{
+  "Version": "2012-10-17",
+  "Statement": [{
+    "Effect": "Allow",
+    "Principal": "*",
+    "Action": ["s3:GetObject"],
+    "Resource": ["arn:aws:s3:::mybucket/*"],
+    "Condition": {"StringEquals": {"s3:ExistingObjectTag/classification": ["public"]}}
+  }]
+}`), "mybucket")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		object          string
+		expectedTags    map[string]string
+		expectedAllowed bool
+	}{
+		{"public.txt", map[string]string{"classification": "public"}, true},
+		{"internal.txt", map[string]string{"classification": "internal"}, false},
+		{"nonexistent.txt", nil, false},
+	}
+
+	for i, testCase := range testCases {
+		r := mustNewRequest(http.MethodGet, "http://127.0.0.1:9000/mybucket/"+testCase.object, 0, nil, t)
+
+		objTags, err := getExistingObjectTags(ctx, r, "mybucket", testCase.object)
+		if err != nil {
+			t.Fatalf("case %v: %v", i+1, err)
+		}
+		if !reflect.DeepEqual(objTags, testCase.expectedTags) {
+			t.Fatalf("case %v: expected tags %v, got %v", i+1, testCase.expectedTags, objTags)
+		}
+
+		values := getConditionValues(r, "", "", nil)
+		setExistingObjectTagValues(values, objTags)
+		allowed := bucketPolicy.IsAllowed(policy.Args{
+			Action:          policy.GetObjectAction,
+			BucketName:      "mybucket",
+			ConditionValues: values,
+			ObjectName:      testCase.object,
+		})
+		if allowed != testCase.expectedAllowed {
+			t.Fatalf("case %v: expected allowed %v, got %v", i+1, testCase.expectedAllowed, allowed)
+		}
+	}
+
+	// Errors other than a missing object are returned, so that the
+	// request is denied.
+	r := mustNewRequest(http.MethodGet, "http://127.0.0.1:9000/nosuchbucket/object", 0, nil, t)
+	if _, err = getExistingObjectTags(ctx, r, "nosuchbucket", "object"); err == nil {
+		t.Fatal("expected an error reading the tags of an object in a missing bucket")
+	}
+}
+
+func TestSetExistingObjectTagConditionValues(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	objLayer, fsDir, err := prepareFS()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(fsDir)
+	newAllSubsystems()
+	setObjectLayer(objLayer)
+	defer setObjectLayer(nil)
+
+	for _, bucket := range []string{"tagpolicy", "notagpolicy"} {
+		if err = objLayer.MakeBucketWithLocation(ctx, bucket, BucketOptions{}); err != nil {
+			t.Fatal(err)
+		}
+		_, err = objLayer.PutObject(ctx, bucket, "object", mustGetPutObjReader(t, bytes.NewReader([]byte("hello")), 5, "", ""),
+			ObjectOptions{UserDefined: map[string]string{xhttp.AmzObjectTagging: "classification=secret"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		condition := ""
+		if bucket == "tagpolicy" {
+			condition = `, "Condition": {"StringEquals": {"s3:ExistingObjectTag/classification": ["public"]}}`
+		}
+		policyJSON := `{
+  "Version": "2012-10-17",
+  "Statement": [{
+    "Effect": "Allow",
+    "Principal": "*",
+    "Action": ["s3:GetObject"],
+    "Resource": ["arn:aws:s3:::` + bucket + `/*"]` + condition + `
+  }]
+}`
+		if err = globalBucketMetadataSys.Update(bucket, bucketPolicyConfig, []byte(policyJSON)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	testCases := []struct {
+		bucket       string
+		action       policy.Action
+		owner        bool
+		copySource   string
+		expectedTags bool
+	}{
+		{"tagpolicy", policy.GetObjectAction, false, "", true},
+		{"tagpolicy", policy.GetObjectTaggingAction, false, "", true},
+		{"tagpolicy", policy.GetObjectAction, true, "", false},
+		{"tagpolicy", policy.PutObjectAction, false, "", false},
+		// Tags are only loaded when the policy evaluated uses them.
+		{"notagpolicy", policy.GetObjectAction, false, "", false},
+		// Copy sources are read with the version of the copy source.
+		{"tagpolicy", policy.GetObjectAction, false, "/tagpolicy/object?versionId=" + nullVersionID, true},
+		{"tagpolicy", policy.GetObjectAction, false, "/tagpolicy/object?versionId=" + mustGetUUID(), false},
+	}
+
+	for i, testCase := range testCases {
+		r := mustNewRequest(http.MethodGet, "http://127.0.0.1:9000/"+testCase.bucket+"/object?versionId="+mustGetUUID(), 0, nil, t)
+		if testCase.copySource == "" {
+			r.URL.RawQuery = ""
+		} else {
+			r.Header.Set(xhttp.AmzCopySource, testCase.copySource)
+		}
+
+		values := getConditionValues(r, "", "", nil)
+		if err = setExistingObjectTagConditionValues(ctx, r, testCase.action, testCase.bucket, "object", auth.Credentials{}, testCase.owner, nil, values); err != nil {
+			t.Fatalf("case %v: %v", i+1, err)
+		}
+		_, ok := values["ExistingObjectTag/classification"]
+		if ok != testCase.expectedTags {
+			t.Fatalf("case %v: expected tags set %v, got %v", i+1, testCase.expectedTags, values)
+		}
+	}
+}
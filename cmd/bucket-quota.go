@@ -20,6 +20,8 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/minio/minio/cmd/logger"
@@ -27,9 +29,29 @@ import (
 	"github.com/minio/minio/pkg/madmin"
 )
 
+const (
+	// Prefixes with quotas are folders at most this deep below the
+	// bucket, the scanner always keeps entries for these folders in
+	// the data usage cache of the bucket.
+	maxQuotaPrefixDepth = dataUsageFlattenLevels
+
+	// TTL of the usage of the prefixes with quotas.
+	prefixUsageCacheTTL = 10 * time.Second
+)
+
 // BucketQuotaSys - map of bucket and quota configuration.
 type BucketQuotaSys struct {
 	bucketStorageCache timedValue
+
+	mu               sync.Mutex
+	prefixUsageCache map[string]*quotaUsageCache // usage of buckets with quotas, by bucket.
+}
+
+// quotaUsageCache holds the usage of the prefixes with quotas, it
+// is dropped as soon as the configuration of the bucket changes.
+type quotaUsageCache struct {
+	quota    *madmin.BucketQuota
+	prefixes timedValue
 }
 
 // Get - Get quota configuration.
@@ -47,7 +69,9 @@ func (sys *BucketQuotaSys) Get(bucketName string) (*madmin.BucketQuota, error) {
 
 // NewBucketQuotaSys returns initialized BucketQuotaSys
 func NewBucketQuotaSys() *BucketQuotaSys {
-	return &BucketQuotaSys{}
+	return &BucketQuotaSys{
+		prefixUsageCache: make(map[string]*quotaUsageCache),
+	}
 }
 
 // parseBucketQuota parses BucketQuota from json
@@ -59,10 +83,101 @@ func parseBucketQuota(bucket string, data []byte) (quotaCfg *madmin.BucketQuota,
 	if !quotaCfg.IsValid() {
 		return quotaCfg, fmt.Errorf("Invalid quota config %#v", quotaCfg)
 	}
+	for _, pq := range quotaCfg.Prefixes {
+		if strings.Count(quotaPrefix(pq.Prefix), SlashSeparator) >= maxQuotaPrefixDepth {
+			return quotaCfg, fmt.Errorf("Invalid quota config, prefix %s is more than %d folders deep", pq.Prefix, maxQuotaPrefixDepth)
+		}
+	}
 	return
 }
 
-func (sys *BucketQuotaSys) check(ctx context.Context, bucket string, size int64) error {
+// quotaPrefix returns the prefix of a prefix quota without the trailing slash.
+func quotaPrefix(prefix string) string {
+	return strings.TrimSuffix(prefix, SlashSeparator)
+}
+
+// loadPrefixUsage returns the usage of the prefixes of the bucket, summed
+// over the data usage caches of the bucket saved by the scanner of each set.
+// Prefixes not scanned yet are not part of the returned usage.
+func loadPrefixUsage(ctx context.Context, objAPI ObjectLayer, bucket string, prefixes []madmin.PrefixQuota) (map[string]dataUsageEntry, error) {
+	var stores []objectIO
+	switch z := objAPI.(type) {
+	case *erasureServerPools:
+		for _, pool := range z.serverPools {
+			for _, set := range pool.sets {
+				stores = append(stores, set)
+			}
+		}
+	case *FSObjects:
+		stores = append(stores, z)
+	default:
+		return nil, NotImplemented{}
+	}
+
+	usage := make(map[string]dataUsageEntry, len(prefixes))
+	for _, store := range stores {
+		var cache dataUsageCache
+		if err := cache.load(ctx, store, pathJoin(bucket, dataUsageCacheName)); err != nil {
+			return nil, err
+		}
+		for _, pq := range prefixes {
+			prefix := quotaPrefix(pq.Prefix)
+			if e := cache.sizeRecursive(pathJoin(bucket, prefix)); e != nil {
+				u := usage[prefix]
+				u.merge(*e)
+				usage[prefix] = u
+			}
+		}
+	}
+	return usage, nil
+}
+
+// usageCache returns the usage cache of the bucket, a new one
+// is created if the quota configuration of the bucket changed.
+func (sys *BucketQuotaSys) usageCache(objAPI ObjectLayer, bucket string, q *madmin.BucketQuota) *quotaUsageCache {
+	sys.mu.Lock()
+	defer sys.mu.Unlock()
+
+	c, ok := sys.prefixUsageCache[bucket]
+	if ok && c.quota == q {
+		return c
+	}
+	c = &quotaUsageCache{quota: q}
+	c.prefixes.TTL = prefixUsageCacheTTL
+	c.prefixes.Update = func() (interface{}, error) {
+		ctx, done := context.WithTimeout(context.Background(), 5*time.Second)
+		defer done()
+		return loadPrefixUsage(ctx, objAPI, bucket, q.Prefixes)
+	}
+	sys.prefixUsageCache[bucket] = c
+	return c
+}
+
+func (sys *BucketQuotaSys) prefixUsage(objAPI ObjectLayer, bucket string, q *madmin.BucketQuota) (map[string]dataUsageEntry, error) {
+	v, err := sys.usageCache(objAPI, bucket, q).prefixes.Get()
+	if err != nil {
+		return nil, err
+	}
+	return v.(map[string]dataUsageEntry), nil
+}
+
+// isOverwrite returns true if the write replaces an existing object,
+// overwriting it does not change the number of objects.
+func isOverwrite(ctx context.Context, objAPI ObjectLayer, bucket, object string) bool {
+	var opts ObjectOptions
+	switch {
+	case globalBucketVersioningSys.PrefixEnabled(bucket, object):
+		// Every write adds a version.
+		return false
+	case globalBucketVersioningSys.PrefixSuspended(bucket, object):
+		// Only the null version is replaced.
+		opts.VersionID = nullVersionID
+	}
+	_, err := objAPI.GetObjectInfo(ctx, bucket, object, opts)
+	return err == nil
+}
+
+func (sys *BucketQuotaSys) check(ctx context.Context, bucket, object string, size int64) error {
 	objAPI := newObjectLayerFn()
 	if objAPI == nil {
 		return errServerNotInitialized
@@ -81,36 +196,68 @@ func (sys *BucketQuotaSys) check(ctx context.Context, bucket string, size int64)
 	if err != nil {
 		return err
 	}
+	if q == nil {
+		return nil
+	}
 
-	if q != nil && q.Type == madmin.HardQuota && q.Quota > 0 {
+	hardQuota := q.Type == madmin.HardQuota && q.Quota > 0
+	if hardQuota || q.Objects > 0 {
 		v, err := sys.bucketStorageCache.Get()
 		if err != nil {
-			return err
+			// usage of the bucket is unknown, cannot enforce quota.
+			logger.LogIf(ctx, err)
+			return nil
 		}
 
-		dui := v.(DataUsageInfo)
-
-		bui, ok := dui.BucketsUsage[bucket]
+		bui, ok := v.(DataUsageInfo).BucketsUsage[bucket]
 		if !ok {
-			// bucket not found, cannot enforce quota
-			// call will fail anyways later.
+			// bucket not scanned yet, cannot enforce quota.
 			return nil
 		}
 
-		if (bui.Size + uint64(size)) >= q.Quota {
+		if hardQuota && bui.Size+uint64(size) >= q.Quota {
+			return BucketQuotaExceeded{Bucket: bucket}
+		}
+		if q.Objects > 0 && bui.ObjectsCount >= q.Objects && !isOverwrite(ctx, objAPI, bucket, object) {
 			return BucketQuotaExceeded{Bucket: bucket}
 		}
 	}
 
+	var usage map[string]dataUsageEntry
+	for _, pq := range q.Prefixes {
+		prefix := quotaPrefix(pq.Prefix)
+		if !strings.HasPrefix(object, prefix+SlashSeparator) {
+			continue
+		}
+		if usage == nil {
+			if usage, err = sys.prefixUsage(objAPI, bucket, q); err != nil {
+				// usage of the prefixes is unknown, cannot enforce quota.
+				logger.LogIf(ctx, err)
+				return nil
+			}
+		}
+		u, ok := usage[prefix]
+		if !ok {
+			// prefix not scanned yet, cannot enforce quota.
+			continue
+		}
+		if pq.Quota > 0 && uint64(u.Size)+uint64(size) >= pq.Quota {
+			return PrefixQuotaExceeded{Bucket: bucket, Object: prefix}
+		}
+		if pq.Objects > 0 && u.Objects >= pq.Objects && !isOverwrite(ctx, objAPI, bucket, object) {
+			return PrefixQuotaExceeded{Bucket: bucket, Object: prefix}
+		}
+	}
+
 	return nil
 }
 
-func enforceBucketQuota(ctx context.Context, bucket string, size int64) error {
+func enforceBucketQuota(ctx context.Context, bucket, object string, size int64) error {
 	if size < 0 {
 		return nil
 	}
 
-	return globalBucketQuotaSys.check(ctx, bucket, size)
+	return globalBucketQuotaSys.check(ctx, bucket, object, size)
 }
 
 // enforceFIFOQuota deletes objects in FIFO order until sufficient objects
@@ -138,10 +285,12 @@ func enforceFIFOQuotaBucket(ctx context.Context, objectAPI ObjectLayer, bucket s
 	// Allocate new results channel to receive ObjectInfo.
 	objInfoCh := make(chan ObjectInfo)
 
-	versioned := globalBucketVersioningSys.Enabled(bucket)
+	// Objects may have several versions as soon as versioning was
+	// configured, whatever prefixes it is enabled for now.
+	walkVersions := globalBucketVersioningSys.Enabled(bucket) || globalBucketVersioningSys.Suspended(bucket)
 
 	// Walk through all objects
-	if err := objectAPI.Walk(ctx, bucket, "", objInfoCh, ObjectOptions{WalkVersions: versioned}); err != nil {
+	if err := objectAPI.Walk(ctx, bucket, "", objInfoCh, ObjectOptions{WalkVersions: walkVersions}); err != nil {
 		logger.LogIf(ctx, err)
 		return
 	}
@@ -203,7 +352,12 @@ func enforceFIFOQuotaBucket(ctx context.Context, objectAPI ObjectLayer, bucket s
 
 		// Deletes a list of objects.
 		_, deleteErrs := objectAPI.DeleteObjects(ctx, bucket, objects, ObjectOptions{
-			Versioned: versioned,
+			PrefixEnabledFn: func(prefix string) bool {
+				return globalBucketVersioningSys.PrefixEnabled(bucket, prefix)
+			},
+			PrefixSuspendedFn: func(prefix string) bool {
+				return globalBucketVersioningSys.PrefixSuspended(bucket, prefix)
+			},
 		})
 		for i := range deleteErrs {
 			if deleteErrs[i] != nil {
@@ -211,6 +365,8 @@ func enforceFIFOQuotaBucket(ctx context.Context, objectAPI ObjectLayer, bucket s
 				continue
 			}
 
+			invalidateCachedObject(bucket, objects[i].ObjectName)
+
 			// Notify object deleted event.
 			sendEvent(eventArgs{
 				EventName:  event.ObjectRemovedDelete,
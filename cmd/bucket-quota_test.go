@@ -0,0 +1,180 @@
+/*
+ * MinIO Cloud Storage, (C) 2021 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/minio/minio/pkg/madmin"
+)
+
+func TestParseBucketQuota(t *testing.T) {
+	testCases := []struct {
+		data    string
+		success bool
+	}{
+		{`{"quota":1024,"quotatype":"hard"}`, true},
+		{`{"quota":1024}`, false},
+		{`{"objects":10}`, true},
+		{`{"prefixes":[{"prefix":"tenant1/","quota":1024},{"prefix":"tenant2/data","objects":10}]}`, true},
+		{`{"prefixes":[{"prefix":"tenant1/"}]}`, false},
+		{`{"prefixes":[{"prefix":"","quota":1024}]}`, false},
+		{`{"prefixes":[{"prefix":"tenant1/","quota":1024},{"prefix":"tenant1","objects":10}]}`, false},
+		{`{"prefixes":[{"prefix":"tenant1/data/logs/","quota":1024}]}`, false},
+	}
+	for i, tc := range testCases {
+		_, err := parseBucketQuota("bucket", []byte(tc.data))
+		if tc.success && err != nil {
+			t.Errorf("Test %d: expected success, got %v", i+1, err)
+		}
+		if !tc.success && err == nil {
+			t.Errorf("Test %d: expected failure", i+1)
+		}
+	}
+}
+
+func TestLoadPrefixUsage(t *testing.T) {
+	objAPI, fsDir, err := prepareFS()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(fsDir)
+	newAllSubsystems()
+
+	ctx := context.Background()
+	if err = objAPI.MakeBucketWithLocation(ctx, "bucket", BucketOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	cache := dataUsageCache{Info: dataUsageCacheInfo{Name: "bucket"}}
+	cache.replace("bucket", "", dataUsageEntry{Size: 1, Objects: 1})
+	cache.replace("bucket/a", "bucket", dataUsageEntry{Size: 10, Objects: 2})
+	cache.replace("bucket/a/b", "bucket/a", dataUsageEntry{Size: 100, Objects: 3})
+	if err = cache.save(ctx, objAPI.(*FSObjects), pathJoin("bucket", dataUsageCacheName)); err != nil {
+		t.Fatal(err)
+	}
+
+	usage, err := loadPrefixUsage(ctx, objAPI, "bucket", []madmin.PrefixQuota{
+		{Prefix: "a/", Quota: 1},
+		{Prefix: "a/b", Objects: 1},
+		{Prefix: "c/", Quota: 1},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u := usage["a"]; u.Size != 110 || u.Objects != 5 {
+		t.Errorf("expected prefix a/ to include its sub-folders, got %d bytes in %d objects", u.Size, u.Objects)
+	}
+	if u := usage["a/b"]; u.Size != 100 || u.Objects != 3 {
+		t.Errorf("unexpected usage of prefix a/b, got %d bytes in %d objects", u.Size, u.Objects)
+	}
+	if _, ok := usage["c"]; ok {
+		t.Error("expected no usage for a prefix not scanned yet")
+	}
+}
+
+func TestBucketQuotaCheck(t *testing.T) {
+	objAPI, fsDir, err := prepareFS()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(fsDir)
+	newAllSubsystems()
+	setObjectLayer(objAPI)
+	defer setObjectLayer(nil)
+
+	ctx := context.Background()
+	for _, bucket := range []string{"bucket", "versioned"} {
+		if err = objAPI.MakeBucketWithLocation(ctx, bucket, BucketOptions{}); err != nil {
+			t.Fatal(err)
+		}
+		for _, object := range []string{"a/x", "b/x"} {
+			if _, err = objAPI.PutObject(ctx, bucket, object, mustGetPutObjReader(t, bytes.NewReader([]byte("data")), 4, "", ""), ObjectOptions{}); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if err = globalBucketMetadataSys.Update("versioned", bucketVersioningConfig,
+		[]byte(`<VersioningConfiguration><Status>Enabled</Status></VersioningConfiguration>`)); err != nil {
+		t.Fatal(err)
+	}
+
+	sys := NewBucketQuotaSys()
+	setQuota := func(bucket, quota string) {
+		t.Helper()
+		if err := globalBucketMetadataSys.Update(bucket, bucketQuotaConfigFile, []byte(quota)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Quotas of buckets and prefixes not scanned yet are not enforced.
+	setQuota("bucket", `{"objects":1,"prefixes":[{"prefix":"a/","objects":1}]}`)
+	if err = sys.check(ctx, "bucket", "a/y", 4); err != nil {
+		t.Fatalf("expected an unscanned bucket to be allowed, got %v", err)
+	}
+
+	dui := make(chan DataUsageInfo, 1)
+	dui <- DataUsageInfo{BucketsUsage: map[string]BucketUsageInfo{
+		"bucket":    {Size: 8, ObjectsCount: 2},
+		"versioned": {Size: 8, ObjectsCount: 2},
+	}}
+	close(dui)
+	storeDataUsageInBackend(ctx, objAPI, dui)
+	sys = NewBucketQuotaSys()
+
+	setQuota("bucket", `{"objects":2}`)
+	if err = sys.check(ctx, "bucket", "c/x", 4); !errors.As(err, &BucketQuotaExceeded{}) {
+		t.Fatalf("expected the object quota of the bucket to be exceeded, got %v", err)
+	}
+	if err = sys.check(ctx, "bucket", "a/x", 4); err != nil {
+		t.Fatalf("expected an overwrite to be allowed, got %v", err)
+	}
+
+	// Writes to versioned buckets add a version.
+	setQuota("versioned", `{"objects":2}`)
+	if err = sys.check(ctx, "versioned", "a/x", 4); !errors.As(err, &BucketQuotaExceeded{}) {
+		t.Fatalf("expected the object quota of the versioned bucket to be exceeded, got %v", err)
+	}
+
+	// Changes of the configuration are enforced right away.
+	cache := dataUsageCache{Info: dataUsageCacheInfo{Name: "bucket"}}
+	cache.replace("bucket", "", dataUsageEntry{})
+	cache.replace("bucket/a", "bucket", dataUsageEntry{Size: 4, Objects: 1})
+	if err = cache.save(ctx, objAPI.(*FSObjects), pathJoin("bucket", dataUsageCacheName)); err != nil {
+		t.Fatal(err)
+	}
+	setQuota("bucket", `{"prefixes":[{"prefix":"a/","objects":1},{"prefix":"c/","objects":1}]}`)
+	if err = sys.check(ctx, "bucket", "b/y", 4); err != nil {
+		t.Fatalf("expected a new object outside of the prefixes to be allowed, got %v", err)
+	}
+	if err = sys.check(ctx, "bucket", "c/y", 4); err != nil {
+		t.Fatalf("expected a prefix not scanned yet to be allowed, got %v", err)
+	}
+	if err = sys.check(ctx, "bucket", "a/y", 4); !errors.As(err, &PrefixQuotaExceeded{}) {
+		t.Fatalf("expected the object quota of the prefix to be exceeded, got %v", err)
+	}
+	if err = sys.check(ctx, "bucket", "a/x", 4); err != nil {
+		t.Fatalf("expected an overwrite to be allowed, got %v", err)
+	}
+	setQuota("bucket", `{"prefixes":[{"prefix":"a/","objects":2}]}`)
+	if err = sys.check(ctx, "bucket", "a/y", 4); err != nil {
+		t.Fatalf("expected the new prefix quota to be applied, got %v", err)
+	}
+}
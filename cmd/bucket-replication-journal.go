@@ -0,0 +1,355 @@
+/*
+ * MinIO Cloud Storage, (C) 2021 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/minio/minio/cmd/logger"
+)
+
+const (
+	// Replication tasks are journaled under this prefix of the
+	// meta bucket, on one of the local drives of this node.
+	replicationJournalPrefix = bucketMetaPrefix + SlashSeparator + ".replication" + SlashSeparator + "queue"
+
+	replicationJournalExt = ".json"
+
+	// Interval at which the journal is scanned for tasks that could not
+	// be queued in memory, or whose retry backoff has elapsed.
+	replicationJournalScanInterval = 30 * time.Second
+
+	// Maximum number of times a FAILED replication is retried before the
+	// task is dropped from the journal and left for the scanner to heal.
+	replicationMaxRetries = 5
+
+	// Backoff before the first retry, doubled on every following retry.
+	replicationRetryBackoff = time.Minute
+
+	// Upper bound of the backoff between retries.
+	replicationMaxRetryBackoff = time.Hour
+)
+
+// replicationTask is a single object or delete replication queued for
+// a background worker.
+type replicationTask struct {
+	Bucket    string                    `json:"bucket"`
+	Object    string                    `json:"object,omitempty"`
+	VersionID string                    `json:"versionId,omitempty"`
+	Delete    *DeletedObjectVersionInfo `json:"delete,omitempty"`
+	Retries   int                       `json:"retries,omitempty"`
+	RetryAt   time.Time                 `json:"retryAt,omitempty"`
+
+	// ARN of the target being resynced and start time of the resync,
+	// for tasks queued by a resync.
+	ResyncArn   string    `json:"resyncArn,omitempty"`
+	ResyncStart time.Time `json:"resyncStart,omitempty"`
+	Size        int64     `json:"size,omitempty"`
+
+	// Name of the journal entry of this task, unique for every time a
+	// replication is queued, empty if the task is not journaled.
+	Entry string `json:"-"`
+}
+
+func newReplicationObjectTask(oi ObjectInfo) replicationTask {
+	return replicationTask{
+		Bucket:    oi.Bucket,
+		Object:    oi.Name,
+		VersionID: oi.VersionID,
+	}
+}
+
+func newReplicationDeleteTask(doi DeletedObjectVersionInfo) replicationTask {
+	return replicationTask{
+		Bucket: doi.Bucket,
+		Object: doi.ObjectName,
+		Delete: &doi,
+	}
+}
+
+// ID returns the key of the object version replicated by the task, it
+// is the same for all tasks replicating the same object version.
+func (t replicationTask) ID() string {
+	op, versionID := "object", t.VersionID
+	if t.Delete != nil {
+		op = "delete"
+		versionID = t.Delete.VersionID
+		if t.Delete.DeleteMarkerVersionID != "" {
+			versionID = t.Delete.DeleteMarkerVersionID
+		}
+	}
+	return getSHA256Hash([]byte(strings.Join([]string{op, t.Bucket, t.Object, versionID}, SlashSeparator)))
+}
+
+// retryBackoff returns the wait before the next retry of a task that
+// has already been retried 'retries' times.
+func retryBackoff(retries int) time.Duration {
+	backoff := replicationRetryBackoff
+	for i := 0; i < retries && backoff < replicationMaxRetryBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > replicationMaxRetryBackoff {
+		backoff = replicationMaxRetryBackoff
+	}
+	return backoff
+}
+
+var errReplicationJournalOffline = errors.New("no local drive available for the replication journal")
+
+// replicationJournalOp is a write to the journal applied in the background,
+// done is called with the result once it is applied.
+type replicationJournalOp struct {
+	task   replicationTask
+	delete bool
+	done   func(err error)
+}
+
+// replicationJournal persists replication tasks on the local drives of
+// this node, so that queued tasks are neither dropped when the in-memory
+// queue is full nor lost on restart. Entries are written and removed in
+// the background in the order they are queued, off the request path.
+type replicationJournal struct {
+	getDisks func() []StorageAPI
+	opCh     chan replicationJournalOp
+
+	// index of every entry of the journal, the number of entries is
+	// the depth of the journal.
+	mu      sync.Mutex
+	entries map[string]*replicationJournalEntry
+}
+
+// replicationJournalEntry is the in-memory state of a journal entry,
+// so that only entries which are due are read from the drives.
+type replicationJournalEntry struct {
+	disk    StorageAPI
+	retryAt time.Time
+
+	// entries which cannot be decoded are kept on the drive for
+	// inspection, but never replayed.
+	corrupt bool
+}
+
+func newReplicationJournal(getDisks func() []StorageAPI) *replicationJournal {
+	return &replicationJournal{
+		getDisks: getDisks,
+		opCh:     make(chan replicationJournalOp, 10000),
+		entries:  make(map[string]*replicationJournalEntry),
+	}
+}
+
+// depth returns the number of tasks in the journal.
+func (j *replicationJournal) depth() int64 {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return int64(len(j.entries))
+}
+
+func (j *replicationJournal) entryDisk(entry string) (StorageAPI, bool) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	e, ok := j.entries[entry]
+	if !ok {
+		return nil, false
+	}
+	return e.disk, true
+}
+
+// run applies the queued journal writes until ctx is canceled.
+func (j *replicationJournal) run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case op := <-j.opCh:
+			var err error
+			if op.delete {
+				err = j.delete(ctx, op.task)
+			} else {
+				err = j.save(ctx, &op.task)
+			}
+			if op.done != nil {
+				op.done(err)
+			} else if err != nil {
+				logger.LogOnceIf(ctx, fmt.Errorf("Unable to journal replication of %s/%s: %w", op.task.Bucket, op.task.Object, err), "replication-journal")
+			}
+		}
+	}
+}
+
+// queueSave journals a new task in the background, the task is
+// journaled right away when the background writes are lagging behind.
+func (j *replicationJournal) queueSave(ctx context.Context, t replicationTask) {
+	select {
+	case j.opCh <- replicationJournalOp{task: t}:
+	default:
+		if err := j.save(ctx, &t); err != nil {
+			logger.LogOnceIf(ctx, fmt.Errorf("Unable to journal replication of %s/%s: %w", t.Bucket, t.Object, err), "replication-journal")
+		}
+	}
+}
+
+// queueUpdate rewrites the journal entry of a task in the background.
+func (j *replicationJournal) queueUpdate(ctx context.Context, t replicationTask, done func(err error)) {
+	j.queue(ctx, replicationJournalOp{task: t, done: done})
+}
+
+// queueDelete removes the journal entry of a task in the background.
+func (j *replicationJournal) queueDelete(ctx context.Context, t replicationTask, done func(err error)) {
+	j.queue(ctx, replicationJournalOp{task: t, delete: true, done: done})
+}
+
+func (j *replicationJournal) queue(ctx context.Context, op replicationJournalOp) {
+	select {
+	case j.opCh <- op:
+	case <-ctx.Done():
+		if op.done != nil {
+			op.done(ctx.Err())
+		}
+	}
+}
+
+// save writes the task to its journal entry, on the drive the entry is
+// on or on any online local drive for new entries.
+func (j *replicationJournal) save(ctx context.Context, t *replicationTask) error {
+	data, err := json.Marshal(t)
+	if err != nil {
+		return err
+	}
+	if t.Entry == "" {
+		t.Entry = mustGetUUID()
+	}
+	name := pathJoin(replicationJournalPrefix, t.Entry+replicationJournalExt)
+	disk, ok := j.entryDisk(t.Entry)
+	if ok && disk.IsOnline() {
+		if err = disk.WriteAll(ctx, minioMetaBucket, name, data); err != nil {
+			return err
+		}
+		j.mu.Lock()
+		j.entries[t.Entry] = &replicationJournalEntry{disk: disk, retryAt: t.RetryAt}
+		j.mu.Unlock()
+		return nil
+	}
+	for _, disk := range j.getDisks() {
+		if err = disk.WriteAll(ctx, minioMetaBucket, name, data); err == nil {
+			j.mu.Lock()
+			j.entries[t.Entry] = &replicationJournalEntry{disk: disk, retryAt: t.RetryAt}
+			j.mu.Unlock()
+			return nil
+		}
+	}
+	if err == nil {
+		err = errReplicationJournalOffline
+	}
+	return err
+}
+
+// delete removes the journal entry of the task.
+func (j *replicationJournal) delete(ctx context.Context, t replicationTask) error {
+	disk, ok := j.entryDisk(t.Entry)
+	if !ok {
+		return nil
+	}
+	err := disk.Delete(ctx, minioMetaBucket, pathJoin(replicationJournalPrefix, t.Entry+replicationJournalExt), false)
+	if err != nil && !errors.Is(err, errFileNotFound) {
+		return err
+	}
+	j.mu.Lock()
+	delete(j.entries, t.Entry)
+	j.mu.Unlock()
+	return nil
+}
+
+// load adds the entries journaled on the local drives before a restart
+// to the journal, they are due until they are first read.
+func (j *replicationJournal) load(ctx context.Context) error {
+	for _, disk := range j.getDisks() {
+		entries, err := disk.ListDir(ctx, minioMetaBucket, replicationJournalPrefix, -1)
+		if err != nil {
+			if errors.Is(err, errFileNotFound) || errors.Is(err, errVolumeNotFound) {
+				continue
+			}
+			return err
+		}
+		j.mu.Lock()
+		for _, entry := range entries {
+			if strings.HasSuffix(entry, replicationJournalExt) {
+				j.entries[strings.TrimSuffix(entry, replicationJournalExt)] = &replicationJournalEntry{disk: disk}
+			}
+		}
+		j.mu.Unlock()
+	}
+	return nil
+}
+
+// walkDue calls fn with every task in the journal which is due at now
+// and is not skipped, until fn returns false. Only these entries are
+// read from the drives.
+func (j *replicationJournal) walkDue(ctx context.Context, now time.Time, skip func(entry string) bool, fn func(t replicationTask) bool) error {
+	j.mu.Lock()
+	entries := make(map[string]StorageAPI, len(j.entries))
+	for entry, e := range j.entries {
+		if !e.corrupt && !e.retryAt.After(now) {
+			entries[entry] = e.disk
+		}
+	}
+	j.mu.Unlock()
+
+	for entry, disk := range entries {
+		if skip(entry) {
+			continue
+		}
+		name := pathJoin(replicationJournalPrefix, entry+replicationJournalExt)
+		data, err := disk.ReadAll(ctx, minioMetaBucket, name)
+		if err != nil {
+			// The entry was removed once its task was replicated.
+			if errors.Is(err, errFileNotFound) {
+				continue
+			}
+			logger.LogOnceIf(ctx, fmt.Errorf("Unable to read replication journal entry %s: %w", name, err), "replication-journal-read")
+			continue
+		}
+		t := replicationTask{Entry: entry}
+		if err = json.Unmarshal(data, &t); err != nil {
+			logger.LogIf(ctx, fmt.Errorf("Unable to decode replication journal entry %s, it is not replayed: %w", name, err))
+			j.mu.Lock()
+			if e, ok := j.entries[entry]; ok {
+				e.corrupt = true
+			}
+			j.mu.Unlock()
+			continue
+		}
+		if t.RetryAt.After(now) {
+			// Entries loaded on startup whose backoff has not elapsed.
+			j.mu.Lock()
+			if e, ok := j.entries[entry]; ok {
+				e.retryAt = t.RetryAt
+			}
+			j.mu.Unlock()
+			continue
+		}
+		if !fn(t) {
+			return nil
+		}
+	}
+	return nil
+}
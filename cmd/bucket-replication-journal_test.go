@@ -0,0 +1,240 @@
+/*
+ * MinIO Cloud Storage, (C) 2021 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestReplicationTaskID(t *testing.T) {
+	oi := ObjectInfo{Bucket: "bucket", Name: "object", VersionID: "v1"}
+	if newReplicationObjectTask(oi).ID() != newReplicationObjectTask(oi).ID() {
+		t.Fatal("expected tasks of the same object version to have the same ID")
+	}
+	other := ObjectInfo{Bucket: "bucket", Name: "object", VersionID: "v2"}
+	if newReplicationObjectTask(oi).ID() == newReplicationObjectTask(other).ID() {
+		t.Fatal("expected tasks of different object versions to have different IDs")
+	}
+	dtask := newReplicationDeleteTask(DeletedObjectVersionInfo{
+		DeletedObject: DeletedObject{ObjectName: "object", VersionID: "v1"},
+		Bucket:        "bucket",
+	})
+	if newReplicationObjectTask(oi).ID() == dtask.ID() {
+		t.Fatal("expected object and delete tasks to have different IDs")
+	}
+}
+
+func TestReplicationRetryBackoff(t *testing.T) {
+	testCases := []struct {
+		retries int
+		want    time.Duration
+	}{
+		{0, time.Minute},
+		{1, 2 * time.Minute},
+		{3, 8 * time.Minute},
+		{6, replicationMaxRetryBackoff},
+		{100, replicationMaxRetryBackoff},
+	}
+	for _, tc := range testCases {
+		if got := retryBackoff(tc.retries); got != tc.want {
+			t.Errorf("retries %d: expected %s, got %s", tc.retries, tc.want, got)
+		}
+	}
+}
+
+func TestReplicationJournal(t *testing.T) {
+	disk, diskPath, err := newXLStorageTestSetup()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(diskPath)
+
+	ctx := context.Background()
+	j := newReplicationJournal(func() []StorageAPI {
+		return []StorageAPI{disk}
+	})
+
+	tasks := []replicationTask{
+		newReplicationObjectTask(ObjectInfo{Bucket: "bucket", Name: "dir/object", VersionID: "v1"}),
+		newReplicationDeleteTask(DeletedObjectVersionInfo{
+			DeletedObject: DeletedObject{
+				ObjectName:            "object",
+				DeleteMarker:          true,
+				DeleteMarkerVersionID: "v2",
+				DeleteMarkerMTime:     DeleteMarkerMTime{UTCNow().Truncate(time.Second)},
+			},
+			Bucket: "bucket",
+		}),
+		// The same object version queued again, while the first
+		// task is being replicated.
+		newReplicationObjectTask(ObjectInfo{Bucket: "bucket", Name: "dir/object", VersionID: "v1"}),
+	}
+	for i := range tasks {
+		if err = j.save(ctx, &tasks[i]); err != nil {
+			t.Fatal(err)
+		}
+		if tasks[i].Entry == "" {
+			t.Fatal("expected task to record its journal entry")
+		}
+	}
+	if tasks[0].Entry == tasks[2].Entry {
+		t.Fatal("expected every queued task to have its own journal entry")
+	}
+
+	// Saving the same task again must not add a new entry.
+	tasks[0].Retries = 2
+	if err = j.save(ctx, &tasks[0]); err != nil {
+		t.Fatal(err)
+	}
+	if depth := j.depth(); depth != int64(len(tasks)) {
+		t.Fatalf("expected a journal depth of %d, got %d", len(tasks), depth)
+	}
+
+	found := make(map[string]replicationTask)
+	if err = j.walkDue(ctx, UTCNow(), func(string) bool { return false }, func(task replicationTask) bool {
+		found[task.Entry] = task
+		return true
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if len(found) != len(tasks) {
+		t.Fatalf("expected %d journaled tasks, found %d", len(tasks), len(found))
+	}
+	if got := found[tasks[0].Entry]; got.Retries != 2 || got.Object != "dir/object" || got.VersionID != "v1" {
+		t.Errorf("unexpected journaled object task %#v", got)
+	}
+	got := found[tasks[1].Entry]
+	if got.Delete == nil || got.Delete.DeleteMarkerVersionID != "v2" ||
+		!got.Delete.DeleteMarkerMTime.Equal(tasks[1].Delete.DeleteMarkerMTime.Time) {
+		t.Errorf("unexpected journaled delete task %#v", got)
+	}
+
+	// Removing the first task keeps the task queued again for the
+	// same object version.
+	if err = j.delete(ctx, tasks[0]); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := j.entryDisk(tasks[2].Entry); !ok || j.depth() != 2 {
+		t.Fatalf("expected the task queued again to remain journaled, depth %d", j.depth())
+	}
+
+	// Only due entries which are not skipped are walked.
+	tasks[1].RetryAt = UTCNow().Add(time.Hour)
+	if err = j.save(ctx, &tasks[1]); err != nil {
+		t.Fatal(err)
+	}
+	walkDue := func(skip func(string) bool) (entries []string) {
+		if err := j.walkDue(ctx, UTCNow(), skip, func(task replicationTask) bool {
+			entries = append(entries, task.Entry)
+			return true
+		}); err != nil {
+			t.Fatal(err)
+		}
+		return entries
+	}
+	if entries := walkDue(func(string) bool { return false }); len(entries) != 1 || entries[0] != tasks[2].Entry {
+		t.Fatalf("expected only the due task to be walked, got %v", entries)
+	}
+	if entries := walkDue(func(entry string) bool { return entry == tasks[2].Entry }); len(entries) != 0 {
+		t.Fatalf("expected skipped tasks not to be walked, got %v", entries)
+	}
+
+	// Entries are loaded again after a restart, the backoff of a
+	// retried task is known once it is first read.
+	j = newReplicationJournal(j.getDisks)
+	if err = j.load(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if depth := j.depth(); depth != 2 {
+		t.Fatalf("expected a journal depth of 2 after a restart, got %d", depth)
+	}
+	if entries := walkDue(func(string) bool { return false }); len(entries) != 1 || entries[0] != tasks[2].Entry {
+		t.Fatalf("expected only the due task to be walked after a restart, got %v", entries)
+	}
+	if !j.entries[tasks[1].Entry].retryAt.Equal(tasks[1].RetryAt) {
+		t.Fatal("expected the retry time of the task to be recorded after it is read")
+	}
+
+	// Entries which cannot be decoded are kept but not walked.
+	corrupt := mustGetUUID()
+	if err = disk.WriteAll(ctx, minioMetaBucket, pathJoin(replicationJournalPrefix, corrupt+replicationJournalExt), []byte("{")); err != nil {
+		t.Fatal(err)
+	}
+	j.entries[corrupt] = &replicationJournalEntry{disk: disk}
+	if entries := walkDue(func(string) bool { return false }); len(entries) != 1 || entries[0] != tasks[2].Entry {
+		t.Fatalf("expected the undecodable entry not to be walked, got %v", entries)
+	}
+	if _, err = disk.ReadAll(ctx, minioMetaBucket, pathJoin(replicationJournalPrefix, corrupt+replicationJournalExt)); err != nil {
+		t.Fatalf("expected the undecodable entry to be kept, got %v", err)
+	}
+	if err = j.delete(ctx, replicationTask{Entry: corrupt}); err != nil {
+		t.Fatal(err)
+	}
+	for _, task := range tasks[1:] {
+		if err = j.delete(ctx, task); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if entries := walkDue(func(string) bool { return false }); len(entries) != 0 || j.depth() != 0 {
+		t.Fatalf("expected an empty journal, found %d tasks, depth %d", len(entries), j.depth())
+	}
+}
+
+func TestReplicationJournalQueue(t *testing.T) {
+	disk, diskPath, err := newXLStorageTestSetup()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(diskPath)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	j := newReplicationJournal(func() []StorageAPI {
+		return []StorageAPI{disk}
+	})
+	go j.run(ctx)
+
+	// Writes are applied in the order they are queued, a task removed
+	// right after it is queued is not left in the journal.
+	task := newReplicationObjectTask(ObjectInfo{Bucket: "bucket", Name: "object", VersionID: "v1"})
+	task.Entry = mustGetUUID()
+	j.queueSave(ctx, task)
+	task.Retries = 1
+	updated := make(chan error, 1)
+	j.queueUpdate(ctx, task, func(err error) { updated <- err })
+	if err = <-updated; err != nil {
+		t.Fatal(err)
+	}
+	if depth := j.depth(); depth != 1 {
+		t.Fatalf("expected a journal depth of 1, got %d", depth)
+	}
+	deleted := make(chan error, 1)
+	j.queueDelete(ctx, task, func(err error) { deleted <- err })
+	if err = <-deleted; err != nil {
+		t.Fatal(err)
+	}
+	if depth := j.depth(); depth != 0 {
+		t.Fatalf("expected an empty journal, got depth %d", depth)
+	}
+	if _, err = disk.ReadAll(ctx, minioMetaBucket, pathJoin(replicationJournalPrefix, task.Entry+replicationJournalExt)); !errors.Is(err, errFileNotFound) {
+		t.Fatalf("expected the journal entry to be removed, got %v", err)
+	}
+}
@@ -0,0 +1,496 @@
+/*
+ * MinIO Cloud Storage, (C) 2021 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	miniogo "github.com/minio/minio-go/v7"
+	"github.com/minio/minio/cmd/crypto"
+	"github.com/minio/minio/cmd/logger"
+	"github.com/minio/minio/pkg/bucket/replication"
+	"github.com/minio/minio/pkg/madmin"
+)
+
+const (
+	// Resync progress is saved under the metadata prefix of the
+	// bucket, in one file per replication target ARN.
+	replicationResyncDir = "replication-resync"
+
+	// Interval at which the progress of running resyncs is saved.
+	replicationResyncSaveInterval = 10 * time.Second
+
+	// A resync not updated for this long is assumed to have been
+	// interrupted by a restart of the node running it.
+	replicationResyncStaleAfter = 5 * time.Minute
+)
+
+func replicationResyncFile(bucket, arn string) string {
+	return pathJoin(bucketMetaPrefix, bucket, replicationResyncDir, getSHA256Hash([]byte(arn))+".json")
+}
+
+// replicationResyncOutcomesPrefix is the prefix of the outcomes of the
+// replication of the versions queued by the resync, one file per node.
+func replicationResyncOutcomesPrefix(bucket, arn string) string {
+	return pathJoin(bucketMetaPrefix, bucket, replicationResyncDir, getSHA256Hash([]byte(arn))) + SlashSeparator
+}
+
+func replicationResyncOutcomesFile(bucket, arn, node string) string {
+	return replicationResyncOutcomesPrefix(bucket, arn) + getSHA256Hash([]byte(node)) + ".json"
+}
+
+// replicationResync tracks the progress of the walk of a bucket by a
+// resync to a single replication target.
+type replicationResync struct {
+	mu      sync.Mutex
+	bucket  string
+	status  madmin.ResyncTargetStatus
+	walking bool // the bucket is being walked by this node.
+	dirty   bool // status has changed since it was last saved.
+}
+
+func (rs *replicationResync) update(fn func(s *madmin.ResyncTargetStatus)) {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	fn(&rs.status)
+	rs.status.LastUpdate = UTCNow()
+	rs.dirty = true
+}
+
+func (rs *replicationResync) save(ctx context.Context, objAPI ObjectLayer) error {
+	rs.mu.Lock()
+	if !rs.dirty {
+		rs.mu.Unlock()
+		return nil
+	}
+	data, err := json.Marshal(rs.status)
+	rs.dirty = false
+	rs.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	return saveConfig(ctx, objAPI, replicationResyncFile(rs.bucket, rs.status.Arn), data)
+}
+
+// replicationResyncOutcomes counts the outcomes of the replication of the
+// versions queued by a resync, as seen by a single node. Every node saves
+// its own outcomes, they are summed when the status is read.
+type replicationResyncOutcomes struct {
+	// Start time of the resync the outcomes belong to.
+	StartTime      time.Time `json:"startTime"`
+	Replicated     int64     `json:"replicated"`
+	ReplicatedSize int64     `json:"replicatedSize"`
+	Failed         int64     `json:"failed"`
+	Error          string    `json:"error,omitempty"`
+	LastUpdate     time.Time `json:"lastUpdate"`
+}
+
+func (o replicationResyncOutcomes) addTo(s *madmin.ResyncTargetStatus) {
+	s.Replicated += o.Replicated
+	s.ReplicatedSize += o.ReplicatedSize
+	s.Failed += o.Failed
+	if o.Error != "" && (s.Error == "" || o.LastUpdate.After(s.LastUpdate)) {
+		s.Error = o.Error
+	}
+	if o.LastUpdate.After(s.LastUpdate) {
+		s.LastUpdate = o.LastUpdate
+	}
+}
+
+// replicationResyncNode holds the outcomes recorded by this node for
+// a resync.
+type replicationResyncNode struct {
+	mu       sync.Mutex
+	bucket   string
+	arn      string
+	outcomes replicationResyncOutcomes
+	dirty    bool // outcomes have changed since they were last saved.
+}
+
+func (rn *replicationResyncNode) save(ctx context.Context, objAPI ObjectLayer) error {
+	rn.mu.Lock()
+	if !rn.dirty {
+		rn.mu.Unlock()
+		return nil
+	}
+	data, err := json.Marshal(rn.outcomes)
+	rn.dirty = false
+	rn.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	return saveConfig(ctx, objAPI, replicationResyncOutcomesFile(rn.bucket, rn.arn, GetLocalPeer(globalEndpoints)), data)
+}
+
+// replicationResyncState holds the resyncs walked by this node and the
+// outcomes of the replication of the versions they queued.
+type replicationResyncState struct {
+	mu      sync.Mutex
+	resyncs map[string]*replicationResync     // keyed by bucket and target ARN.
+	nodes   map[string]*replicationResyncNode // keyed by bucket and target ARN.
+}
+
+func newReplicationResyncState() *replicationResyncState {
+	return &replicationResyncState{
+		resyncs: make(map[string]*replicationResync),
+		nodes:   make(map[string]*replicationResyncNode),
+	}
+}
+
+func loadReplicationResyncStatus(ctx context.Context, objAPI ObjectLayer, bucket, arn string) (status madmin.ResyncTargetStatus, err error) {
+	data, err := readConfig(ctx, objAPI, replicationResyncFile(bucket, arn))
+	if err != nil {
+		return status, err
+	}
+	if err = json.Unmarshal(data, &status); err != nil {
+		return status, err
+	}
+	return status, nil
+}
+
+func loadReplicationResyncOutcomes(ctx context.Context, objAPI ObjectLayer, file string) (o replicationResyncOutcomes, err error) {
+	data, err := readConfig(ctx, objAPI, file)
+	if err != nil {
+		return o, err
+	}
+	if err = json.Unmarshal(data, &o); err != nil {
+		return o, err
+	}
+	return o, nil
+}
+
+// node returns the outcomes recorded by this node for the resync of the
+// bucket to the target, loading them from the drives if they are not
+// known to this node yet.
+func (r *replicationResyncState) node(ctx context.Context, objAPI ObjectLayer, bucket, arn string) *replicationResyncNode {
+	key := pathJoin(bucket, arn)
+	r.mu.Lock()
+	rn, ok := r.nodes[key]
+	r.mu.Unlock()
+	if ok {
+		return rn
+	}
+
+	outcomes, err := loadReplicationResyncOutcomes(ctx, objAPI, replicationResyncOutcomesFile(bucket, arn, GetLocalPeer(globalEndpoints)))
+	if err != nil && !errors.Is(err, errConfigNotFound) {
+		logger.LogIf(ctx, err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if rn, ok = r.nodes[key]; ok {
+		return rn
+	}
+	rn = &replicationResyncNode{bucket: bucket, arn: arn, outcomes: outcomes}
+	r.nodes[key] = rn
+	return rn
+}
+
+// Status returns the progress of the resync of the bucket to the target,
+// including the outcomes of the replication of the queued versions on
+// every node.
+func (r *replicationResyncState) Status(ctx context.Context, objAPI ObjectLayer, bucket, arn string) (madmin.ResyncTargetStatus, error) {
+	var status madmin.ResyncTargetStatus
+	key := pathJoin(bucket, arn)
+	r.mu.Lock()
+	rs, ok := r.resyncs[key]
+	rn := r.nodes[key]
+	r.mu.Unlock()
+	var walking bool
+	if ok {
+		rs.mu.Lock()
+		status, walking = rs.status, rs.walking
+		rs.mu.Unlock()
+	}
+	if !walking {
+		var err error
+		if status, err = loadReplicationResyncStatus(ctx, objAPI, bucket, arn); err != nil {
+			return status, err
+		}
+	}
+
+	// Outcomes of this node not saved yet are more recent than the
+	// saved ones.
+	local := replicationResyncOutcomesFile(bucket, arn, GetLocalPeer(globalEndpoints))
+	if rn != nil {
+		rn.mu.Lock()
+		if rn.outcomes.StartTime.Equal(status.StartTime) {
+			rn.outcomes.addTo(&status)
+		}
+		rn.mu.Unlock()
+	}
+	prefix := replicationResyncOutcomesPrefix(bucket, arn)
+	marker := ""
+	for {
+		res, err := objAPI.ListObjects(ctx, minioMetaBucket, prefix, marker, "", maxObjectList)
+		if err != nil {
+			return status, err
+		}
+		for _, obj := range res.Objects {
+			if rn != nil && obj.Name == local {
+				continue
+			}
+			o, err := loadReplicationResyncOutcomes(ctx, objAPI, obj.Name)
+			if err != nil {
+				if !errors.Is(err, errConfigNotFound) {
+					logger.LogIf(ctx, err)
+				}
+				continue
+			}
+			if o.StartTime.Equal(status.StartTime) {
+				o.addTo(&status)
+			}
+		}
+		if !res.IsTruncated {
+			break
+		}
+		marker = res.NextMarker
+	}
+
+	if !walking && status.Status == madmin.ResyncOngoing && UTCNow().Sub(status.LastUpdate) > replicationResyncStaleAfter {
+		status.Status = madmin.ResyncFailed
+		status.Error = "resync was interrupted"
+	}
+	return status, nil
+}
+
+// Start validates the replication configuration of the bucket and walks
+// it in the background, queuing every version missing on the target.
+func (r *replicationResyncState) Start(ctx context.Context, objAPI ObjectLayer, bucket, arn string) error {
+	cfg, err := getReplicationConfig(ctx, bucket)
+	if err != nil {
+		return err
+	}
+	if cfg.GetTargetDestination(arn).Bucket == "" {
+		return BucketRemoteTargetNotFound{Bucket: bucket}
+	}
+	if !cfg.HasExistingObjectReplication() {
+		return BucketExistingObjectReplicationDisabled{Bucket: bucket}
+	}
+	tgt := globalBucketTargetSys.GetRemoteTargetClient(ctx, arn)
+	if tgt == nil {
+		return BucketRemoteTargetNotFound{Bucket: bucket}
+	}
+
+	now := UTCNow()
+	rs := &replicationResync{
+		bucket: bucket,
+		status: madmin.ResyncTargetStatus{
+			Arn:        arn,
+			Status:     madmin.ResyncOngoing,
+			StartTime:  now,
+			LastUpdate: now,
+		},
+		walking: true,
+		dirty:   true,
+	}
+
+	// The resync is registered as walking before its status is checked
+	// on the drives, so that it is not started twice by this node.
+	key := pathJoin(bucket, arn)
+	r.mu.Lock()
+	if prev, ok := r.resyncs[key]; ok {
+		prev.mu.Lock()
+		walking := prev.walking
+		prev.mu.Unlock()
+		if walking {
+			r.mu.Unlock()
+			return BucketReplicationResyncInProgress{Bucket: bucket}
+		}
+	}
+	r.resyncs[key] = rs
+	r.mu.Unlock()
+
+	// The resync may be running on another node.
+	status, err := loadReplicationResyncStatus(ctx, objAPI, bucket, arn)
+	if err == nil && status.Status == madmin.ResyncOngoing && UTCNow().Sub(status.LastUpdate) <= replicationResyncStaleAfter {
+		err = BucketReplicationResyncInProgress{Bucket: bucket}
+	} else {
+		err = rs.save(ctx, objAPI)
+	}
+	if err != nil {
+		r.mu.Lock()
+		if r.resyncs[key] == rs {
+			delete(r.resyncs, key)
+		}
+		r.mu.Unlock()
+		return err
+	}
+
+	go r.resync(GlobalContext, objAPI, rs, cfg, tgt)
+	return nil
+}
+
+func (r *replicationResyncState) resync(ctx context.Context, objAPI ObjectLayer, rs *replicationResync, cfg *replication.Config, tgt *TargetClient) {
+	defer func() {
+		rs.mu.Lock()
+		rs.walking = false
+		rs.mu.Unlock()
+		logger.LogIf(ctx, rs.save(ctx, objAPI))
+	}()
+
+	bucket, arn, start := rs.bucket, rs.status.Arn, rs.status.StartTime
+	dest := cfg.GetTargetDestination(arn)
+	objInfoCh := make(chan ObjectInfo)
+	if err := objAPI.Walk(ctx, bucket, "", objInfoCh, ObjectOptions{WalkVersions: true}); err != nil {
+		rs.update(func(s *madmin.ResyncTargetStatus) {
+			s.Status = madmin.ResyncFailed
+			s.Error = err.Error()
+		})
+		return
+	}
+
+	lastSave := UTCNow()
+	for oi := range objInfoCh {
+		if UTCNow().Sub(lastSave) > replicationResyncSaveInterval {
+			logger.LogIf(ctx, rs.save(ctx, objAPI))
+			lastSave = UTCNow()
+		}
+
+		// VersionID is only set to evaluate the replication of versioned deletes.
+		opts := replication.ObjectOpts{
+			Name:           oi.Name,
+			UserTags:       oi.UserTags,
+			IsLatest:       oi.IsLatest,
+			DeleteMarker:   oi.DeleteMarker,
+			SSEC:           crypto.SSEC.IsEncrypted(oi.UserDefined),
+			ExistingObject: true,
+			TargetArn:      arn,
+		}
+		if oi.ReplicationStatus == replication.Replica || !cfg.Replicate(opts) {
+			rs.update(func(s *madmin.ResyncTargetStatus) {
+				s.Scanned++
+				s.Skipped++
+			})
+			continue
+		}
+
+		if oi.DeleteMarker {
+			// Replicating a delete marker present on the target is a no-op.
+			globalReplicationState.queueResyncTask(newReplicationDeleteTask(DeletedObjectVersionInfo{
+				DeletedObject: DeletedObject{
+					ObjectName:            oi.Name,
+					DeleteMarker:          true,
+					DeleteMarkerVersionID: oi.VersionID,
+					DeleteMarkerMTime:     DeleteMarkerMTime{oi.ModTime},
+				},
+				Bucket: bucket,
+			}), arn, start)
+			rs.update(func(s *madmin.ResyncTargetStatus) {
+				s.Scanned++
+				s.Queued++
+			})
+			continue
+		}
+
+		roi, err := tgt.StatObject(ctx, dest.Bucket, oi.Name, miniogo.StatObjectOptions{
+			VersionID: oi.VersionID,
+			Internal: miniogo.AdvancedGetOptions{
+				ReplicationProxyRequest: "false",
+			}})
+		if err == nil && getReplicationAction(oi, roi) == replicateNone {
+			rs.update(func(s *madmin.ResyncTargetStatus) {
+				s.Scanned++
+				s.Present++
+			})
+			continue
+		}
+		if err != nil && !isErrRemoteVersionNotFound(err) {
+			rs.update(func(s *madmin.ResyncTargetStatus) {
+				s.Scanned++
+				s.Failed++
+				s.Error = fmt.Sprintf("Unable to check %s (%s) on the target: %v", oi.Name, oi.VersionID, err)
+			})
+			continue
+		}
+
+		t := newReplicationObjectTask(oi)
+		t.Size = oi.Size
+		globalReplicationState.queueResyncTask(t, arn, start)
+		rs.update(func(s *madmin.ResyncTargetStatus) {
+			s.Scanned++
+			s.Queued++
+			s.QueuedSize += oi.Size
+		})
+	}
+
+	rs.update(func(s *madmin.ResyncTargetStatus) {
+		s.Status = madmin.ResyncCompleted
+	})
+}
+
+// isErrRemoteVersionNotFound returns true if the error returned by the
+// replication target means the version does not exist there.
+func isErrRemoteVersionNotFound(err error) bool {
+	switch miniogo.ToErrorResponse(err).Code {
+	case "NoSuchKey", "NoSuchVersion":
+		return true
+	}
+	return false
+}
+
+// done records the outcome of the replication of a version queued by
+// a resync, outcomes of a previous resync are ignored.
+func (r *replicationResyncState) done(ctx context.Context, objAPI ObjectLayer, t replicationTask, status replication.StatusType) {
+	rn := r.node(ctx, objAPI, t.Bucket, t.ResyncArn)
+	rn.mu.Lock()
+	defer rn.mu.Unlock()
+	switch {
+	case t.ResyncStart.Before(rn.outcomes.StartTime):
+		return
+	case t.ResyncStart.After(rn.outcomes.StartTime):
+		rn.outcomes = replicationResyncOutcomes{StartTime: t.ResyncStart}
+	}
+	if status == replication.Completed {
+		rn.outcomes.Replicated++
+		rn.outcomes.ReplicatedSize += t.Size
+	} else {
+		rn.outcomes.Failed++
+		rn.outcomes.Error = fmt.Sprintf("Unable to replicate %s (%s)", t.Object, t.VersionID)
+	}
+	rn.outcomes.LastUpdate = UTCNow()
+	rn.dirty = true
+}
+
+// persist periodically saves the outcomes recorded by this node, walking
+// resyncs save their own progress.
+func (r *replicationResyncState) persist(ctx context.Context, objAPI ObjectLayer) {
+	ticker := time.NewTicker(replicationResyncSaveInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		r.mu.Lock()
+		nodes := make([]*replicationResyncNode, 0, len(r.nodes))
+		for _, rn := range r.nodes {
+			nodes = append(nodes, rn)
+		}
+		r.mu.Unlock()
+		for _, rn := range nodes {
+			logger.LogIf(ctx, rn.save(ctx, objAPI))
+		}
+	}
+}
@@ -0,0 +1,117 @@
+/*
+ * MinIO Cloud Storage, (C) 2021 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/minio/minio/pkg/bucket/replication"
+	"github.com/minio/minio/pkg/madmin"
+)
+
+func TestReplicationResyncStatus(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	objAPI, fsDir, err := prepareFS()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(fsDir)
+
+	const bucket, arn = "bucket", "arn:minio:replication::id:target"
+	r := newReplicationResyncState()
+	if _, err = r.Status(ctx, objAPI, bucket, arn); !errors.Is(err, errConfigNotFound) {
+		t.Fatalf("expected no resync status, got %v", err)
+	}
+
+	// A resync left ongoing by a restart is reported as failed.
+	status := madmin.ResyncTargetStatus{
+		Arn:        arn,
+		Status:     madmin.ResyncOngoing,
+		StartTime:  UTCNow().Add(-2 * replicationResyncStaleAfter),
+		LastUpdate: UTCNow().Add(-2 * replicationResyncStaleAfter),
+		Queued:     2,
+		QueuedSize: 30,
+	}
+	data, err := json.Marshal(status)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = saveConfig(ctx, objAPI, replicationResyncFile(bucket, arn), data); err != nil {
+		t.Fatal(err)
+	}
+	got, err := r.Status(ctx, objAPI, bucket, arn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Status != madmin.ResyncFailed {
+		t.Fatalf("expected an interrupted resync to be failed, got %s", got.Status)
+	}
+
+	// Outcomes of the queued versions are saved by every node and
+	// summed with the progress.
+	task := newReplicationObjectTask(ObjectInfo{Bucket: bucket, Name: "object", VersionID: "v1"})
+	task.ResyncArn, task.ResyncStart, task.Size = arn, status.StartTime, 10
+	r.done(ctx, objAPI, task, replication.Completed)
+	task.VersionID = "v2"
+	r.done(ctx, objAPI, task, replication.Failed)
+	// Outcomes of a previous resync are ignored.
+	task.ResyncStart = status.StartTime.Add(-time.Hour)
+	r.done(ctx, objAPI, task, replication.Completed)
+
+	saveOutcomes := func(node string, o replicationResyncOutcomes) {
+		t.Helper()
+		data, err := json.Marshal(o)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = saveConfig(ctx, objAPI, replicationResyncOutcomesFile(bucket, arn, node), data); err != nil {
+			t.Fatal(err)
+		}
+	}
+	saveOutcomes("node2:9000", replicationResyncOutcomes{StartTime: status.StartTime, Replicated: 2, ReplicatedSize: 20, LastUpdate: UTCNow()})
+	saveOutcomes("node3:9000", replicationResyncOutcomes{StartTime: status.StartTime.Add(-time.Hour), Replicated: 5, ReplicatedSize: 50})
+
+	// Outcomes of this node not saved yet are included.
+	got, err = r.Status(ctx, objAPI, bucket, arn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Status != madmin.ResyncOngoing || got.Replicated != 3 || got.ReplicatedSize != 30 || got.Failed != 1 || got.Error == "" {
+		t.Fatalf("unexpected resync status %#v", got)
+	}
+
+	// Other nodes read the saved outcomes.
+	for _, rn := range r.nodes {
+		if err = rn.save(ctx, objAPI); err != nil {
+			t.Fatal(err)
+		}
+	}
+	got, err = newReplicationResyncState().Status(ctx, objAPI, bucket, arn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Replicated != 3 || got.ReplicatedSize != 30 || got.Failed != 1 || got.Error == "" {
+		t.Fatalf("unexpected resync status %#v", got)
+	}
+}
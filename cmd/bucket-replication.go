@@ -21,7 +21,10 @@ import (
 	"fmt"
 	"net/http"
 	"reflect"
+	"sort"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	minio "github.com/minio/minio-go/v7"
@@ -55,30 +58,44 @@ func getReplicationConfig(ctx context.Context, bucketName string) (rc *replicati
 // validateReplicationDestination returns error if replication destination bucket missing or not configured
 // It also returns true if replication destination is same as this server.
 func validateReplicationDestination(ctx context.Context, bucket string, rCfg *replication.Config) (bool, error) {
-	arn, err := madmin.ParseARN(rCfg.RoleArn)
+	var sameTarget bool
+	for _, arnStr := range rCfg.TargetArns() {
+		local, err := validateReplicationTarget(ctx, bucket, arnStr, rCfg.GetTargetDestination(arnStr))
+		if err != nil {
+			return false, err
+		}
+		sameTarget = sameTarget || local
+	}
+	return sameTarget, nil
+}
+
+// validateReplicationTarget returns error if the destination bucket is missing on the
+// replication target, it also returns true if the target is this server.
+func validateReplicationTarget(ctx context.Context, bucket, arnStr string, dest replication.Destination) (bool, error) {
+	arn, err := madmin.ParseARN(arnStr)
 	if err != nil {
 		return false, BucketRemoteArnInvalid{}
 	}
 	if arn.Type != madmin.ReplicationService {
 		return false, BucketRemoteArnTypeInvalid{}
 	}
-	clnt := globalBucketTargetSys.GetRemoteTargetClient(ctx, rCfg.RoleArn)
+	clnt := globalBucketTargetSys.GetRemoteTargetClient(ctx, arnStr)
 	if clnt == nil {
 		return false, BucketRemoteTargetNotFound{Bucket: bucket}
 	}
-	if found, _ := clnt.BucketExists(ctx, rCfg.GetDestination().Bucket); !found {
-		return false, BucketRemoteDestinationNotFound{Bucket: rCfg.GetDestination().Bucket}
+	if found, _ := clnt.BucketExists(ctx, dest.Bucket); !found {
+		return false, BucketRemoteDestinationNotFound{Bucket: dest.Bucket}
 	}
 	if ret, err := globalBucketObjectLockSys.Get(bucket); err == nil {
 		if ret.LockEnabled {
-			lock, _, _, _, err := clnt.GetObjectLockConfig(ctx, rCfg.GetDestination().Bucket)
+			lock, _, _, _, err := clnt.GetObjectLockConfig(ctx, dest.Bucket)
 			if err != nil || lock != "Enabled" {
-				return false, BucketReplicationDestinationMissingLock{Bucket: rCfg.GetDestination().Bucket}
+				return false, BucketReplicationDestinationMissingLock{Bucket: dest.Bucket}
 			}
 		}
 	}
 	// validate replication ARN against target endpoint
-	c, ok := globalBucketTargetSys.arnRemotesMap[rCfg.RoleArn]
+	c, ok := globalBucketTargetSys.arnRemotesMap[arnStr]
 	if ok {
 		if c.EndpointURL().String() == clnt.EndpointURL().String() {
 			sameTarget, _ := isLocalHost(clnt.EndpointURL().Hostname(), clnt.EndpointURL().Port(), globalMinioPort)
@@ -128,13 +145,20 @@ func mustReplicater(ctx context.Context, bucket, object string, meta map[string]
 	if ok {
 		opts.UserTags = tagStr
 	}
-	tgt := globalBucketTargetSys.GetRemoteTargetClient(ctx, cfg.RoleArn)
+	arns := cfg.FilterTargetArns(opts)
 	// the target online status should not be used here while deciding
 	// whether to replicate as the target could be temporarily down
-	if tgt != nil {
-		return cfg.Replicate(opts), tgt.replicateSync
+	return len(arns) > 0, isReplicateSync(ctx, arns)
+}
+
+// isReplicateSync returns true if replication to any of the targets is synchronous.
+func isReplicateSync(ctx context.Context, arns []string) bool {
+	for _, arn := range arns {
+		if tgt := globalBucketTargetSys.GetRemoteTargetClient(ctx, arn); tgt != nil && tgt.replicateSync {
+			return true
+		}
 	}
-	return cfg.Replicate(opts), false
+	return false
 }
 
 // Standard headers that needs to be extracted from User metadata.
@@ -186,7 +210,8 @@ func checkReplicateDelete(ctx context.Context, bucket string, dobj ObjectToDelet
 		DeleteMarker: oi.DeleteMarker,
 		VersionID:    dobj.VersionID,
 	}
-	replicate = rcfg.Replicate(opts)
+	arns := rcfg.FilterTargetArns(opts)
+	replicate = len(arns) > 0
 	// when incoming delete is removal of a delete marker( a.k.a versioned delete),
 	// GetObjectInfo returns extra information even though it returns errFileNotFound
 	if gerr != nil {
@@ -202,13 +227,43 @@ func checkReplicateDelete(ctx context.Context, bucket string, dobj ObjectToDelet
 		// is issued - this still needs to be replicated back to the other target
 		return oi.DeleteMarker, oi.VersionPurgeStatus == Pending || oi.VersionPurgeStatus == Failed, sync
 	}
-	tgt := globalBucketTargetSys.GetRemoteTargetClient(ctx, rcfg.RoleArn)
 	// the target online status should not be used here while deciding
 	// whether to replicate deletes as the target could be temporarily down
-	if tgt == nil {
+	var found bool
+	for _, arn := range arns {
+		if globalBucketTargetSys.GetRemoteTargetClient(ctx, arn) != nil {
+			found = true
+			break
+		}
+	}
+	if !found {
 		return oi.DeleteMarker, false, false
 	}
-	return oi.DeleteMarker, replicate, tgt.replicateSync
+	return oi.DeleteMarker, replicate, isReplicateSync(ctx, arns)
+}
+
+// deleteReplicationTargetArns returns the ARNs of the replication targets a delete
+// is replicated to. Tags of deleted versions are not known, so rules filtering
+// on tags never match deletes, unless all the rules replicate to the same target.
+// A non-empty targetArn restricts the delete to that target, as done by resyncs.
+func deleteReplicationTargetArns(rcfg *replication.Config, dobj DeletedObjectVersionInfo, targetArn string) []string {
+	arns := rcfg.TargetArns()
+	if len(arns) > 1 {
+		arns = rcfg.FilterTargetArns(replication.ObjectOpts{
+			Name:         dobj.ObjectName,
+			VersionID:    dobj.VersionID,
+			DeleteMarker: dobj.DeleteMarker,
+		})
+	}
+	if targetArn == "" {
+		return arns
+	}
+	for _, arn := range arns {
+		if arn == targetArn {
+			return []string{arn}
+		}
+	}
+	return nil
 }
 
 // replicate deletes to the designated replication target if replication configuration
@@ -220,8 +275,8 @@ func checkReplicateDelete(ctx context.Context, bucket string, dobj ObjectToDelet
 // then be retried by healing. In the case of permanent deletes, until the replication is completed on the
 // target cluster, the object version is marked deleted on the source and hidden from listing. It is permanently
 // deleted from the source when the VersionPurgeStatus changes to "Complete", i.e after replication succeeds
-// on target.
-func replicateDelete(ctx context.Context, dobj DeletedObjectVersionInfo, objectAPI ObjectLayer) {
+// on target. A non-empty targetArn only replicates the delete to that target.
+func replicateDelete(ctx context.Context, dobj DeletedObjectVersionInfo, objectAPI ObjectLayer, targetArn string) (status replication.StatusType) {
 	bucket := dobj.Bucket
 	versionID := dobj.DeleteMarkerVersionID
 	if versionID == "" {
@@ -242,12 +297,21 @@ func replicateDelete(ctx context.Context, dobj DeletedObjectVersionInfo, objectA
 			Host:      "Internal: [Replication]",
 			EventName: event.ObjectReplicationNotTracked,
 		})
-		return
+		return status
 	}
 
-	tgt := globalBucketTargetSys.GetRemoteTargetClient(ctx, rcfg.RoleArn)
-	if tgt == nil {
-		logger.LogIf(ctx, fmt.Errorf("failed to get target for bucket:%s arn:%s", bucket, rcfg.RoleArn))
+	var tgts []*TargetClient
+	var dests []replication.Destination
+	for _, arn := range deleteReplicationTargetArns(rcfg, dobj, targetArn) {
+		tgt := globalBucketTargetSys.GetRemoteTargetClient(ctx, arn)
+		if tgt == nil {
+			logger.LogIf(ctx, fmt.Errorf("failed to get target for bucket:%s arn:%s", bucket, arn))
+			continue
+		}
+		tgts = append(tgts, tgt)
+		dests = append(dests, rcfg.GetTargetDestination(arn))
+	}
+	if len(tgts) == 0 {
 		sendEvent(eventArgs{
 			BucketName: bucket,
 			Object: ObjectInfo{
@@ -259,18 +323,27 @@ func replicateDelete(ctx context.Context, dobj DeletedObjectVersionInfo, objectA
 			Host:      "Internal: [Replication]",
 			EventName: event.ObjectReplicationNotTracked,
 		})
-		return
+		return status
+	}
+
+	// The delete is marked failed if it could not be replicated
+	// to any of the targets, and is then replayed on all of them.
+	var rmErr error
+	for i, tgt := range tgts {
+		err := tgt.RemoveObject(ctx, dests[i].Bucket, dobj.ObjectName, miniogo.RemoveObjectOptions{
+			VersionID: versionID,
+			Internal: miniogo.AdvancedRemoveOptions{
+				ReplicationDeleteMarker: dobj.DeleteMarkerVersionID != "",
+				ReplicationMTime:        dobj.DeleteMarkerMTime.Time,
+				ReplicationStatus:       miniogo.ReplicationStatusReplica,
+			},
+		})
+		if err != nil {
+			rmErr = err
+			logger.LogIf(ctx, fmt.Errorf("Unable to replicate delete marker to %s/%s(%s): %s", dests[i].Bucket, dobj.ObjectName, versionID, err))
+		}
 	}
 
-	rmErr := tgt.RemoveObject(ctx, rcfg.GetDestination().Bucket, dobj.ObjectName, miniogo.RemoveObjectOptions{
-		VersionID: versionID,
-		Internal: miniogo.AdvancedRemoveOptions{
-			ReplicationDeleteMarker: dobj.DeleteMarkerVersionID != "",
-			ReplicationMTime:        dobj.DeleteMarkerMTime.Time,
-			ReplicationStatus:       miniogo.ReplicationStatusReplica,
-		},
-	})
-
 	replicationStatus := dobj.DeleteMarkerReplicationStatus
 	versionPurgeStatus := dobj.VersionPurgeStatus
 
@@ -280,7 +353,6 @@ func replicateDelete(ctx context.Context, dobj DeletedObjectVersionInfo, objectA
 		} else {
 			versionPurgeStatus = Failed
 		}
-		logger.LogIf(ctx, fmt.Errorf("Unable to replicate delete marker to %s/%s(%s): %s", rcfg.GetDestination().Bucket, dobj.ObjectName, versionID, rmErr))
 	} else {
 		if dobj.VersionID == "" {
 			replicationStatus = string(replication.Completed)
@@ -289,8 +361,10 @@ func replicateDelete(ctx context.Context, dobj DeletedObjectVersionInfo, objectA
 		}
 	}
 
+	status = replication.Completed
 	var eventName = event.ObjectReplicationComplete
 	if replicationStatus == string(replication.Failed) || versionPurgeStatus == Failed {
+		status = replication.Failed
 		eventName = event.ObjectReplicationFailed
 	}
 
@@ -299,8 +373,8 @@ func replicateDelete(ctx context.Context, dobj DeletedObjectVersionInfo, objectA
 		VersionID:                     versionID,
 		DeleteMarkerReplicationStatus: replicationStatus,
 		VersionPurgeStatus:            versionPurgeStatus,
-		Versioned:                     globalBucketVersioningSys.Enabled(bucket),
-		VersionSuspended:              globalBucketVersioningSys.Suspended(bucket),
+		Versioned:                     globalBucketVersioningSys.PrefixEnabled(bucket, dobj.ObjectName),
+		VersionSuspended:              globalBucketVersioningSys.PrefixSuspended(bucket, dobj.ObjectName),
 	})
 	if err != nil && !isErrVersionNotFound(err) { // VersionNotFound would be reported by pool that object version is missing on.
 		logger.LogIf(ctx, fmt.Errorf("Unable to update replication metadata for %s/%s(%s): %s", bucket, dobj.ObjectName, versionID, err))
@@ -323,6 +397,7 @@ func replicateDelete(ctx context.Context, dobj DeletedObjectVersionInfo, objectA
 			EventName:  eventName,
 		})
 	}
+	return status
 }
 
 func getCopyObjMetadata(oi ObjectInfo, dest replication.Destination) map[string]string {
@@ -557,12 +632,68 @@ func getReplicationAction(oi1 ObjectInfo, oi2 minio.ObjectInfo) replicationActio
 	return replicateNone
 }
 
-// replicateObject replicates the specified version of the object to destination bucket
-// The source object is then updated to reflect the replication status.
-func replicateObject(ctx context.Context, objInfo ObjectInfo, objectAPI ObjectLayer) {
+// replicationTargetStatusKey is the internal metadata key holding the replication
+// status of an object version on each replication target, X-Amz-Replication-Status
+// holds the status aggregated over all the targets.
+const replicationTargetStatusKey = ReservedMetadataPrefixLower + "replication-target-status"
+
+// replicationTargetStatuses is the replication status of an object version by target ARN.
+type replicationTargetStatuses map[string]replication.StatusType
+
+// parseReplicationTargetStatuses parses statuses in the arn1=STATUS;arn2=STATUS format.
+func parseReplicationTargetStatuses(s string) replicationTargetStatuses {
+	statuses := make(replicationTargetStatuses)
+	for _, kv := range strings.Split(s, ";") {
+		idx := strings.LastIndex(kv, "=")
+		if idx <= 0 {
+			continue
+		}
+		statuses[kv[:idx]] = replication.StatusType(kv[idx+1:])
+	}
+	return statuses
+}
+
+func (s replicationTargetStatuses) String() string {
+	arns := make([]string, 0, len(s))
+	for arn := range s {
+		arns = append(arns, arn)
+	}
+	sort.Strings(arns)
+	var sb strings.Builder
+	for _, arn := range arns {
+		sb.WriteString(arn + "=" + s[arn].String() + ";")
+	}
+	return sb.String()
+}
+
+// Status returns FAILED if the version failed to replicate to any target,
+// PENDING if it is pending on any target and COMPLETED otherwise.
+func (s replicationTargetStatuses) Status() replication.StatusType {
+	status := replication.Completed
+	for _, st := range s {
+		switch st {
+		case replication.Failed:
+			return replication.Failed
+		case replication.Pending:
+			status = replication.Pending
+		}
+	}
+	return status
+}
+
+// replicateObject replicates the specified version of the object to the destination
+// bucket of each of its replication targets. The source object is then updated to
+// reflect the replication status on each target.
+func replicateObject(ctx context.Context, objInfo ObjectInfo, objectAPI ObjectLayer) (status replication.StatusType) {
+	return replicateObjectToTargets(ctx, objInfo, objectAPI, "")
+}
+
+// replicateObjectToTargets replicates the object version like replicateObject, only
+// to the target with the specified ARN if not empty.
+func replicateObjectToTargets(ctx context.Context, objInfo ObjectInfo, objectAPI ObjectLayer, targetArn string) (status replication.StatusType) {
 	z, ok := objectAPI.(*erasureServerPools)
 	if !ok {
-		return
+		return status
 	}
 
 	bucket := objInfo.Bucket
@@ -577,48 +708,112 @@ func replicateObject(ctx context.Context, objInfo ObjectInfo, objectAPI ObjectLa
 			Object:     objInfo,
 			Host:       "Internal: [Replication]",
 		})
-		return
+		return status
 	}
-	tgt := globalBucketTargetSys.GetRemoteTargetClient(ctx, cfg.RoleArn)
-	if tgt == nil {
-		logger.LogIf(ctx, fmt.Errorf("failed to get target for bucket:%s arn:%s", bucket, cfg.RoleArn))
+	oi, err := objectAPI.GetObjectInfo(ctx, bucket, object, ObjectOptions{
+		VersionID: objInfo.VersionID,
+	})
+	if err != nil {
 		sendEvent(eventArgs{
 			EventName:  event.ObjectReplicationNotTracked,
 			BucketName: bucket,
 			Object:     objInfo,
 			Host:       "Internal: [Replication]",
 		})
-		return
+		logger.LogIf(ctx, err)
+		return status
 	}
-	gr, err := objectAPI.GetObjectNInfo(ctx, bucket, object, nil, http.Header{}, readLock, ObjectOptions{
-		VersionID: objInfo.VersionID,
+	objInfo = oi
+
+	arns := cfg.FilterTargetArns(replication.ObjectOpts{
+		Name:     object,
+		UserTags: objInfo.UserTags,
+		SSEC:     crypto.SSEC.IsEncrypted(objInfo.UserDefined),
 	})
-	if err != nil {
+	if len(arns) == 0 {
+		logger.LogIf(ctx, fmt.Errorf("Unable to replicate object %s(%s), no replication target", objInfo.Name, objInfo.VersionID))
 		sendEvent(eventArgs{
 			EventName:  event.ObjectReplicationNotTracked,
 			BucketName: bucket,
 			Object:     objInfo,
 			Host:       "Internal: [Replication]",
 		})
-		logger.LogIf(ctx, err)
-		return
+		return status
 	}
-	defer gr.Close() // hold read lock for entire transaction
 
-	objInfo = gr.ObjInfo
-	size, err := objInfo.GetActualSize()
+	prevStatuses := parseReplicationTargetStatuses(objInfo.UserDefined[replicationTargetStatusKey])
+	statuses := make(replicationTargetStatuses, len(arns))
+	for _, arn := range arns {
+		if targetArn != "" && arn != targetArn {
+			if st, ok := prevStatuses[arn]; ok {
+				statuses[arn] = st
+			}
+			continue
+		}
+		// Retries of a FAILED replication skip the targets the version was
+		// replicated to, a PENDING version is replicated to all the targets.
+		if objInfo.ReplicationStatus == replication.Failed && prevStatuses[arn] == replication.Completed {
+			statuses[arn] = replication.Completed
+			continue
+		}
+		statuses[arn] = replicateObjectToTarget(ctx, objInfo, objectAPI, arn, cfg.GetTargetDestination(arn))
+	}
+	replicationStatus := statuses.Status()
+
+	if objInfo.UserDefined == nil {
+		objInfo.UserDefined = make(map[string]string)
+	}
+	objInfo.UserDefined[xhttp.AmzBucketReplicationStatus] = replicationStatus.String()
+	objInfo.UserDefined[replicationTargetStatusKey] = statuses.String()
+	if objInfo.UserTags != "" {
+		objInfo.UserDefined[xhttp.AmzObjectTagging] = objInfo.UserTags
+	}
+
+	// FIXME: add support for missing replication events
+	// - event.ObjectReplicationMissedThreshold
+	// - event.ObjectReplicationReplicatedAfterThreshold
+	var eventName = event.ObjectReplicationComplete
+	if replicationStatus == replication.Failed {
+		eventName = event.ObjectReplicationFailed
+	}
+
+	// This lower level implementation is necessary to avoid write locks from CopyObject.
+	poolIdx, err := z.getPoolIdx(ctx, bucket, object, objInfo.Size)
 	if err != nil {
-		logger.LogIf(ctx, err)
+		logger.LogIf(ctx, fmt.Errorf("Unable to update replication metadata for %s/%s(%s): %w", bucket, objInfo.Name, objInfo.VersionID, err))
+	} else {
+		if err = z.serverPools[poolIdx].getHashedSet(object).updateObjectMeta(ctx, bucket, object, objInfo.UserDefined, ObjectOptions{
+			VersionID: objInfo.VersionID,
+		}); err != nil {
+			logger.LogIf(ctx, fmt.Errorf("Unable to update replication metadata for %s/%s(%s): %w", bucket, objInfo.Name, objInfo.VersionID, err))
+		}
+	}
+	sendEvent(eventArgs{
+		EventName:  eventName,
+		BucketName: bucket,
+		Object:     objInfo,
+		Host:       "Internal: [Replication]",
+	})
+	return replicationStatus
+}
+
+// replicateObjectToTarget replicates the specified version of the object to the
+// destination bucket on the replication target with the specified ARN.
+func replicateObjectToTarget(ctx context.Context, objInfo ObjectInfo, objectAPI ObjectLayer, arn string, dest replication.Destination) replication.StatusType {
+	bucket := objInfo.Bucket
+	object := objInfo.Name
+
+	tgt := globalBucketTargetSys.GetRemoteTargetClient(ctx, arn)
+	if tgt == nil {
+		logger.LogIf(ctx, fmt.Errorf("failed to get target for bucket:%s arn:%s", bucket, arn))
 		sendEvent(eventArgs{
 			EventName:  event.ObjectReplicationNotTracked,
 			BucketName: bucket,
 			Object:     objInfo,
 			Host:       "Internal: [Replication]",
 		})
-		return
+		return replication.Failed
 	}
-
-	dest := cfg.GetDestination()
 	if dest.Bucket == "" {
 		logger.LogIf(ctx, fmt.Errorf("Unable to replicate object %s(%s), bucket is empty", objInfo.Name, objInfo.VersionID))
 		sendEvent(eventArgs{
@@ -627,7 +822,34 @@ func replicateObject(ctx context.Context, objInfo ObjectInfo, objectAPI ObjectLa
 			Object:     objInfo,
 			Host:       "Internal: [Replication]",
 		})
-		return
+		return replication.Failed
+	}
+	gr, err := objectAPI.GetObjectNInfo(ctx, bucket, object, nil, http.Header{}, readLock, ObjectOptions{
+		VersionID: objInfo.VersionID,
+	})
+	if err != nil {
+		sendEvent(eventArgs{
+			EventName:  event.ObjectReplicationNotTracked,
+			BucketName: bucket,
+			Object:     objInfo,
+			Host:       "Internal: [Replication]",
+		})
+		logger.LogIf(ctx, err)
+		return replication.Failed
+	}
+	defer gr.Close() // hold read lock for entire transaction
+
+	objInfo = gr.ObjInfo
+	size, err := objInfo.GetActualSize()
+	if err != nil {
+		logger.LogIf(ctx, err)
+		sendEvent(eventArgs{
+			EventName:  event.ObjectReplicationNotTracked,
+			BucketName: bucket,
+			Object:     objInfo,
+			Host:       "Internal: [Replication]",
+		})
+		return replication.Failed
 	}
 
 	rtype := replicateAll
@@ -641,7 +863,7 @@ func replicateObject(ctx context.Context, objInfo ObjectInfo, objectAPI ObjectLa
 		if rtype == replicateNone {
 			// object with same VersionID already exists, replication kicked off by
 			// PutObject might have completed.
-			return
+			return replication.Completed
 		}
 	}
 	replicationStatus := replication.Completed
@@ -659,28 +881,28 @@ func replicateObject(ctx context.Context, objInfo ObjectInfo, objectAPI ObjectLa
 			logger.LogIf(ctx, fmt.Errorf("Unable to replicate metadata for object %s/%s(%s): %s", bucket, objInfo.Name, objInfo.VersionID, err))
 		}
 	} else {
-		target, err := globalBucketMetadataSys.GetBucketTarget(bucket, cfg.RoleArn)
+		target, err := globalBucketMetadataSys.GetBucketTarget(bucket, arn)
 		if err != nil {
-			logger.LogIf(ctx, fmt.Errorf("failed to get target for replication bucket:%s cfg:%s err:%s", bucket, cfg.RoleArn, err))
+			logger.LogIf(ctx, fmt.Errorf("failed to get target for replication bucket:%s arn:%s err:%s", bucket, arn, err))
 			sendEvent(eventArgs{
 				EventName:  event.ObjectReplicationNotTracked,
 				BucketName: bucket,
 				Object:     objInfo,
 				Host:       "Internal: [Replication]",
 			})
-			return
+			return replication.Failed
 		}
 
 		putOpts, err := putReplicationOpts(ctx, dest, objInfo)
 		if err != nil {
-			logger.LogIf(ctx, fmt.Errorf("failed to get target for replication bucket:%s cfg:%s err:%w", bucket, cfg.RoleArn, err))
+			logger.LogIf(ctx, fmt.Errorf("failed to get target for replication bucket:%s arn:%s err:%w", bucket, arn, err))
 			sendEvent(eventArgs{
 				EventName:  event.ObjectReplicationNotTracked,
 				BucketName: bucket,
 				Object:     objInfo,
 				Host:       "Internal: [Replication]",
 			})
-			return
+			return replication.Failed
 		}
 
 		// Setup bandwidth throttling
@@ -696,44 +918,14 @@ func replicateObject(ctx context.Context, objInfo ObjectInfo, objectAPI ObjectLa
 		}
 
 		// r takes over closing gr.
-		r := bandwidth.NewMonitoredReader(ctx, globalBucketMonitor, objInfo.Bucket, objInfo.Name, gr, headerSize, b, target.BandwidthLimit)
+		r := bandwidth.NewMonitoredTargetReader(ctx, globalBucketMonitor, objInfo.Bucket, arn, objInfo.Name, gr, headerSize, b, target.BandwidthLimit)
 		if _, err = c.PutObject(ctx, dest.Bucket, object, r, size, "", "", putOpts); err != nil {
 			replicationStatus = replication.Failed
 			logger.LogIf(ctx, fmt.Errorf("Unable to replicate for object %s/%s(%s): %w", bucket, objInfo.Name, objInfo.VersionID, err))
 		}
 		defer r.Close()
 	}
-
-	objInfo.UserDefined[xhttp.AmzBucketReplicationStatus] = replicationStatus.String()
-	if objInfo.UserTags != "" {
-		objInfo.UserDefined[xhttp.AmzObjectTagging] = objInfo.UserTags
-	}
-
-	// FIXME: add support for missing replication events
-	// - event.ObjectReplicationMissedThreshold
-	// - event.ObjectReplicationReplicatedAfterThreshold
-	var eventName = event.ObjectReplicationComplete
-	if replicationStatus == replication.Failed {
-		eventName = event.ObjectReplicationFailed
-	}
-
-	// This lower level implementation is necessary to avoid write locks from CopyObject.
-	poolIdx, err := z.getPoolIdx(ctx, bucket, object, objInfo.Size)
-	if err != nil {
-		logger.LogIf(ctx, fmt.Errorf("Unable to update replication metadata for %s/%s(%s): %w", bucket, objInfo.Name, objInfo.VersionID, err))
-	} else {
-		if err = z.serverPools[poolIdx].getHashedSet(object).updateObjectMeta(ctx, bucket, object, objInfo.UserDefined, ObjectOptions{
-			VersionID: objInfo.VersionID,
-		}); err != nil {
-			logger.LogIf(ctx, fmt.Errorf("Unable to update replication metadata for %s/%s(%s): %w", bucket, objInfo.Name, objInfo.VersionID, err))
-		}
-	}
-	sendEvent(eventArgs{
-		EventName:  eventName,
-		BucketName: bucket,
-		Object:     objInfo,
-		Host:       "Internal: [Replication]",
-	})
+	return replicationStatus
 }
 
 // filterReplicationStatusMetadata filters replication status metadata for COPY
@@ -756,6 +948,7 @@ func filterReplicationStatusMetadata(metadata map[string]string) map[string]stri
 	}
 
 	delKey(xhttp.AmzBucketReplicationStatus)
+	delKey(replicationTargetStatusKey)
 	return dst
 }
 
@@ -765,29 +958,101 @@ type DeletedObjectVersionInfo struct {
 	Bucket string
 }
 type replicationState struct {
-	// add future metrics here
-	replicaCh       chan ObjectInfo
-	replicaDeleteCh chan DeletedObjectVersionInfo
+	taskCh  chan replicationTask
+	journal *replicationJournal
+	resync  *replicationResyncState
+
+	// journal entries that are queued in memory, being replicated or
+	// whose journal entry is being updated.
+	mu     sync.Mutex
+	queued map[string]struct{}
+
+	// metrics
+	inflight int64 // number of tasks queued in memory or being replicated.
+	retries  int64 // number of retries scheduled for FAILED tasks.
+	errors   int64 // number of tasks dropped after exhausting all retries.
 }
 
 func (r *replicationState) queueReplicaTask(oi ObjectInfo) {
 	if r == nil {
 		return
 	}
-	select {
-	case r.replicaCh <- oi:
-	default:
-	}
+	r.queueTask(newReplicationObjectTask(oi))
 }
 
 func (r *replicationState) queueReplicaDeleteTask(doi DeletedObjectVersionInfo) {
 	if r == nil {
 		return
 	}
+	r.queueTask(newReplicationDeleteTask(doi))
+}
+
+func (r *replicationState) queueResyncTask(t replicationTask, arn string, start time.Time) {
+	if r == nil {
+		return
+	}
+	t.ResyncArn, t.ResyncStart = arn, start
+	r.queueTask(t)
+}
+
+// queueTask journals the task in the background and queues it in memory,
+// if the in-memory queue is full the task is picked up later from the
+// journal.
+func (r *replicationState) queueTask(t replicationTask) {
+	journal := r.getJournal()
+	if journal != nil {
+		t.Entry = mustGetUUID()
+		r.markQueued(t.Entry)
+		journal.queueSave(GlobalContext, t)
+	}
 	select {
-	case r.replicaDeleteCh <- doi:
+	case r.taskCh <- t:
+		atomic.AddInt64(&r.inflight, 1)
 	default:
+		r.unmarkQueued(t.Entry)
+		if journal == nil {
+			logger.LogOnceIf(GlobalContext, fmt.Errorf("Replication queue is full, %s/%s will be replicated by the scanner", t.Bucket, t.Object), "replication-queue-full")
+		}
+	}
+}
+
+func (r *replicationState) getJournal() *replicationJournal {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.journal
+}
+
+// journalDepth returns the number of tasks in the journal.
+func (r *replicationState) journalDepth() int64 {
+	journal := r.getJournal()
+	if journal == nil {
+		return 0
+	}
+	return journal.depth()
+}
+
+// markQueued returns false if the journal entry is already queued.
+func (r *replicationState) markQueued(entry string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.queued[entry]; ok {
+		return false
 	}
+	r.queued[entry] = struct{}{}
+	return true
+}
+
+func (r *replicationState) isQueued(entry string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	_, ok := r.queued[entry]
+	return ok
+}
+
+func (r *replicationState) unmarkQueued(entry string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.queued, entry)
 }
 
 var (
@@ -796,13 +1061,13 @@ var (
 
 func newReplicationState() *replicationState {
 	rs := &replicationState{
-		replicaCh:       make(chan ObjectInfo, 10000),
-		replicaDeleteCh: make(chan DeletedObjectVersionInfo, 10000),
+		taskCh: make(chan replicationTask, 10000),
+		resync: newReplicationResyncState(),
+		queued: make(map[string]struct{}),
 	}
 	go func() {
 		<-GlobalContext.Done()
-		close(rs.replicaCh)
-		close(rs.replicaDeleteCh)
+		close(rs.taskCh)
 	}()
 	return rs
 }
@@ -815,26 +1080,121 @@ func (r *replicationState) addWorker(ctx context.Context, objectAPI ObjectLayer)
 			select {
 			case <-ctx.Done():
 				return
-			case oi, ok := <-r.replicaCh:
+			case t, ok := <-r.taskCh:
 				if !ok {
 					return
 				}
-				replicateObject(ctx, oi, objectAPI)
-			case doi, ok := <-r.replicaDeleteCh:
-				if !ok {
-					return
-				}
-				replicateDelete(ctx, doi, objectAPI)
+				r.process(ctx, t, objectAPI)
 			}
 		}
 	}()
 }
 
+// process replicates a task and updates its journal entry, a FAILED
+// replication is retried with an exponential backoff until
+// replicationMaxRetries is reached. The entry stays marked as queued
+// until its journal entry is updated, so that it is not replayed with
+// its previous state.
+func (r *replicationState) process(ctx context.Context, t replicationTask, objectAPI ObjectLayer) {
+	defer atomic.AddInt64(&r.inflight, -1)
+
+	var status replication.StatusType
+	// Resyncs only replicate to the target being resynced.
+	if t.Delete != nil {
+		status = replicateDelete(ctx, *t.Delete, objectAPI, t.ResyncArn)
+	} else {
+		status = replicateObjectToTargets(ctx, ObjectInfo{
+			Bucket:    t.Bucket,
+			Name:      t.Object,
+			VersionID: t.VersionID,
+		}, objectAPI, t.ResyncArn)
+	}
+
+	journal := r.getJournal()
+	journaled := journal != nil && t.Entry != ""
+	if journaled && status == replication.Failed {
+		if t.Retries < replicationMaxRetries {
+			t.RetryAt = UTCNow().Add(retryBackoff(t.Retries))
+			t.Retries++
+			journal.queueUpdate(ctx, t, func(err error) {
+				if err != nil {
+					logger.LogIf(ctx, fmt.Errorf("Unable to journal replication retry of %s/%s: %w", t.Bucket, t.Object, err))
+				} else {
+					atomic.AddInt64(&r.retries, 1)
+				}
+				r.unmarkQueued(t.Entry)
+			})
+			return
+		}
+		atomic.AddInt64(&r.errors, 1)
+	}
+	if t.ResyncArn != "" {
+		r.resync.done(ctx, objectAPI, t, status)
+	}
+	if !journaled {
+		return
+	}
+	journal.queueDelete(ctx, t, func(err error) {
+		if err != nil {
+			logger.LogIf(ctx, fmt.Errorf("Unable to remove journaled replication of %s/%s: %w", t.Bucket, t.Object, err))
+		}
+		r.unmarkQueued(t.Entry)
+	})
+}
+
+// replayJournal queues journaled tasks which are not queued in memory
+// and are due, either because the in-memory queue was full, the server
+// restarted or the backoff of a FAILED replication has elapsed.
+func (r *replicationState) replayJournal(ctx context.Context) {
+	ticker := time.NewTicker(replicationJournalScanInterval)
+	defer ticker.Stop()
+
+	journal := r.getJournal()
+	if err := journal.load(ctx); err != nil {
+		logger.LogIf(ctx, fmt.Errorf("Unable to load replication journal: %w", err))
+	}
+
+	for {
+		now := UTCNow()
+		err := journal.walkDue(ctx, now, r.isQueued, func(t replicationTask) bool {
+			if !r.markQueued(t.Entry) {
+				return true
+			}
+			select {
+			case r.taskCh <- t:
+				atomic.AddInt64(&r.inflight, 1)
+				return true
+			case <-ctx.Done():
+				r.unmarkQueued(t.Entry)
+				return false
+			}
+		})
+		if err != nil {
+			logger.LogIf(ctx, fmt.Errorf("Unable to read replication journal: %w", err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
 func initBackgroundReplication(ctx context.Context, objectAPI ObjectLayer) {
 	if globalReplicationState == nil {
 		return
 	}
 
+	if z, ok := objectAPI.(*erasureServerPools); ok {
+		globalReplicationState.mu.Lock()
+		globalReplicationState.journal = newReplicationJournal(localOnlineDisks(z))
+		globalReplicationState.mu.Unlock()
+		go globalReplicationState.journal.run(ctx)
+		go globalReplicationState.replayJournal(ctx)
+		go globalReplicationState.resync.persist(ctx, objectAPI)
+	}
+
 	// Start replication workers per count set in api config or MINIO_API_REPLICATION_WORKERS.
 	for i := 0; i < globalAPIConfig.getReplicationWorkers(); i++ {
 		globalReplicationState.addWorker(ctx, objectAPI)
@@ -890,8 +1250,21 @@ func isProxyable(ctx context.Context, bucket string) bool {
 	if err != nil {
 		return false
 	}
-	dest := cfg.GetDestination()
-	return dest.Bucket == bucket
+	return len(proxyTargetArns(ctx, cfg, bucket)) > 0
+}
+
+// proxyTargetArns returns the ARNs of the targets requests are proxied to,
+// these are in active-active replication with the bucket and allow proxying.
+func proxyTargetArns(ctx context.Context, cfg *replication.Config, bucket string) (arns []string) {
+	for _, arn := range cfg.TargetArns() {
+		if cfg.GetTargetDestination(arn).Bucket != bucket { // not active-active
+			continue
+		}
+		if tgt := globalBucketTargetSys.GetRemoteTargetClient(ctx, arn); tgt != nil && !tgt.disableProxy {
+			arns = append(arns, arn)
+		}
+	}
+	return arns
 }
 
 func proxyHeadToRepTarget(ctx context.Context, bucket, object string, opts ObjectOptions) (tgt *TargetClient, oi ObjectInfo, proxy bool, err error) {
@@ -904,26 +1277,10 @@ func proxyHeadToRepTarget(ctx context.Context, bucket, object string, opts Objec
 	if err != nil {
 		return nil, oi, false, err
 	}
-	dest := cfg.GetDestination()
-	if dest.Bucket != bucket { // not active-active
-		return nil, oi, false, err
-	}
 	ssec := false
 	if opts.ServerSideEncryption != nil {
 		ssec = opts.ServerSideEncryption.Type() == encrypt.SSEC
 	}
-	ropts := replication.ObjectOpts{
-		Name: object,
-		SSEC: ssec,
-	}
-	if !cfg.Replicate(ropts) { // no matching rule for object prefix
-		return nil, oi, false, nil
-	}
-	tgt = globalBucketTargetSys.GetRemoteTargetClient(ctx, cfg.RoleArn)
-	if tgt == nil || tgt.isOffline() {
-		return nil, oi, false, fmt.Errorf("target is offline or not configured")
-	}
-
 	gopts := miniogo.GetObjectOptions{
 		VersionID:            opts.VersionID,
 		ServerSideEncryption: opts.ServerSideEncryption,
@@ -932,7 +1289,31 @@ func proxyHeadToRepTarget(ctx context.Context, bucket, object string, opts Objec
 		},
 	}
 
-	objInfo, err := tgt.StatObject(ctx, dest.Bucket, object, gopts)
+	// Proxy to the first target holding the object.
+	var objInfo minio.ObjectInfo
+	err = fmt.Errorf("target is offline or not configured")
+	for _, arn := range proxyTargetArns(ctx, cfg, bucket) {
+		ropts := replication.ObjectOpts{
+			Name:      object,
+			SSEC:      ssec,
+			TargetArn: arn,
+		}
+		if !cfg.Replicate(ropts) { // no matching rule for object prefix
+			continue
+		}
+		tgt = globalBucketTargetSys.GetRemoteTargetClient(ctx, arn)
+		if tgt == nil || tgt.isOffline() {
+			continue
+		}
+		objInfo, err = tgt.StatObject(ctx, bucket, object, gopts)
+		if err == nil {
+			break
+		}
+	}
+	if tgt == nil {
+		// no matching rule for object prefix
+		return nil, oi, false, nil
+	}
 	if err != nil {
 		return nil, oi, false, err
 	}
@@ -984,7 +1365,7 @@ func scheduleReplication(ctx context.Context, objInfo ObjectInfo, o ObjectLayer,
 
 func scheduleReplicationDelete(ctx context.Context, dv DeletedObjectVersionInfo, o ObjectLayer, sync bool) {
 	if sync {
-		replicateDelete(ctx, dv, o)
+		replicateDelete(ctx, dv, o, "")
 	} else {
 		globalReplicationState.queueReplicaDeleteTask(dv)
 	}
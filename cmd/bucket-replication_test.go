@@ -0,0 +1,83 @@
+/*
+ * MinIO Cloud Storage, (C) 2021 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/minio/minio/pkg/bucket/replication"
+)
+
+func TestReplicationTargetStatuses(t *testing.T) {
+	const arn1, arn2 = "arn:minio:replication::id1:bucket1", "arn:minio:replication::id2:bucket2"
+	testCases := []struct {
+		statuses replicationTargetStatuses
+		want     replication.StatusType
+	}{
+		{replicationTargetStatuses{arn1: replication.Completed, arn2: replication.Completed}, replication.Completed},
+		{replicationTargetStatuses{arn1: replication.Completed, arn2: replication.Pending}, replication.Pending},
+		{replicationTargetStatuses{arn1: replication.Pending, arn2: replication.Failed}, replication.Failed},
+	}
+	for i, tc := range testCases {
+		if got := tc.statuses.Status(); got != tc.want {
+			t.Errorf("Test %d: expected status %s, got %s", i+1, tc.want, got)
+		}
+		s := tc.statuses.String()
+		if got := parseReplicationTargetStatuses(s); len(got) != len(tc.statuses) ||
+			got[arn1] != tc.statuses[arn1] || got[arn2] != tc.statuses[arn2] {
+			t.Errorf("Test %d: expected %v to be parsed from %q, got %v", i+1, tc.statuses, s, got)
+		}
+	}
+	if got := parseReplicationTargetStatuses(""); len(got) != 0 {
+		t.Errorf("expected no statuses, got %v", got)
+	}
+}
+
+func TestDeleteReplicationTargetArns(t *testing.T) {
+	const arn1, arn2 = "arn:minio:replication::id1:bucket1", "arn:minio:replication::id2:bucket2"
+	rcfg := &replication.Config{Rules: []replication.Rule{
+		{
+			Status:                  replication.Enabled,
+			DeleteMarkerReplication: replication.DeleteMarkerReplication{Status: replication.Enabled},
+			Destination:             replication.Destination{Bucket: "bucket1", ARN: arn1},
+		},
+		{
+			Status:                  replication.Enabled,
+			DeleteMarkerReplication: replication.DeleteMarkerReplication{Status: replication.Enabled},
+			Destination:             replication.Destination{Bucket: "bucket2", ARN: arn2},
+		},
+	}}
+	dobj := DeletedObjectVersionInfo{
+		DeletedObject: DeletedObject{ObjectName: "object", DeleteMarker: true},
+	}
+	testCases := []struct {
+		targetArn string
+		want      []string
+	}{
+		{"", []string{arn1, arn2}},
+		// Resyncs only replicate delete markers to the target being resynced.
+		{arn2, []string{arn2}},
+		{"arn:minio:replication::id3:bucket3", nil},
+	}
+	for i, tc := range testCases {
+		got := deleteReplicationTargetArns(rcfg, dobj, tc.targetArn)
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("Test %d: expected targets %v, got %v", i+1, tc.want, got)
+		}
+	}
+}
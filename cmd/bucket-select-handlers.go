@@ -0,0 +1,247 @@
+/*
+ * MinIO Cloud Storage, (C) 2021 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sync"
+
+	"github.com/gorilla/mux"
+	"github.com/minio/minio/cmd/crypto"
+	xhttp "github.com/minio/minio/cmd/http"
+	"github.com/minio/minio/cmd/logger"
+	"github.com/minio/minio/pkg/bucket/policy"
+	"github.com/minio/minio/pkg/event"
+	"github.com/minio/minio/pkg/handlers"
+	iampolicy "github.com/minio/minio/pkg/iam/policy"
+	"github.com/minio/minio/pkg/s3select"
+)
+
+// Maximum number of objects read at once by a select over a prefix.
+const selectObjectsParallel = 8
+
+// SelectObjectsContentHandler - POST Bucket?select&select-type=2&prefix=
+// ----------
+// This MinIO extension runs the SQL expression of a
+// SelectObjectContent request over all objects of the bucket matching
+// the prefix. The results of all objects are sent in a single event
+// stream, with an ObjectStats event sent once an object is read.
+// Objects which cannot be read, for example as access is denied, are
+// skipped and reported with an error in their ObjectStats event.
+func (api objectAPIHandlers) SelectObjectsContentHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, w, "SelectObjects")
+
+	defer logger.AuditLog(ctx, w, r, mustGetClaimsFromToken(r))
+
+	objectAPI := api.ObjectAPI()
+	if objectAPI == nil {
+		writeErrorResponse(ctx, w, errorCodes.ToAPIErr(ErrServerNotInitialized), r.URL, guessIsBrowserReq(r))
+		return
+	}
+
+	if crypto.S3.IsRequested(r.Header) || crypto.S3KMS.IsRequested(r.Header) { // If SSE-S3 or SSE-KMS present -> AWS fails with undefined error
+		writeErrorResponse(ctx, w, errorCodes.ToAPIErr(ErrBadRequest), r.URL, guessIsBrowserReq(r))
+		return
+	}
+
+	if _, ok := crypto.IsRequested(r.Header); ok && !objectAPI.IsEncryptionSupported() {
+		writeErrorResponse(ctx, w, errorCodes.ToAPIErr(ErrBadRequest), r.URL, guessIsBrowserReq(r))
+		return
+	}
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+	prefix := r.URL.Query().Get("prefix")
+
+	if s3Error := checkRequestAuthType(ctx, r, policy.ListBucketAction, bucket, ""); s3Error != ErrNone {
+		writeErrorResponse(ctx, w, errorCodes.ToAPIErr(s3Error), r.URL, guessIsBrowserReq(r))
+		return
+	}
+
+	if r.Header.Get(xhttp.Range) != "" {
+		writeErrorResponse(ctx, w, errorCodes.ToAPIErr(ErrUnsupportedRangeHeader), r.URL, guessIsBrowserReq(r))
+		return
+	}
+
+	if r.ContentLength <= 0 {
+		writeErrorResponse(ctx, w, errorCodes.ToAPIErr(ErrEmptyRequestBody), r.URL, guessIsBrowserReq(r))
+		return
+	}
+
+	// Check if bucket exists.
+	if _, err := objectAPI.GetBucketInfo(ctx, bucket); err != nil {
+		writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL, guessIsBrowserReq(r))
+		return
+	}
+
+	listObjects := objectAPI.ListObjects
+	getObjectNInfo := objectAPI.GetObjectNInfo
+	if api.CacheAPI() != nil {
+		getObjectNInfo = api.CacheAPI().GetObjectNInfo
+	}
+
+	s3Select, err := s3select.NewS3Select(r.Body)
+	if err != nil {
+		writeSelectErrorResponse(ctx, w, r, bucket, prefix, err)
+		return
+	}
+
+	// Objects queried, to notify once the select is done.
+	var (
+		accessedMu sync.Mutex
+		accessed   []ObjectInfo
+	)
+
+	// List the objects matching the prefix a page at a time.
+	var (
+		objects     []ObjectInfo
+		marker      string
+		isTruncated = true
+	)
+	next := func() (*s3select.SelectObject, error) {
+		for len(objects) == 0 {
+			if !isTruncated {
+				return nil, io.EOF
+			}
+			result, err := listObjects(ctx, bucket, prefix, marker, "", maxObjectList)
+			if err != nil {
+				return nil, err
+			}
+			objects, marker, isTruncated = result.Objects, result.NextMarker, result.IsTruncated
+			if isTruncated && marker == "" && len(objects) > 0 {
+				marker = objects[len(objects)-1].Name
+			}
+		}
+		objInfo := objects[0]
+		objects = objects[1:]
+		return selectObject(ctx, r, objectAPI, getObjectNInfo, objInfo, func() {
+			accessedMu.Lock()
+			accessed = append(accessed, objInfo)
+			accessedMu.Unlock()
+		}), nil
+	}
+
+	if err = s3Select.OpenObjects(next, selectObjectsParallel); err != nil {
+		writeSelectErrorResponse(ctx, w, r, bucket, prefix, err)
+		return
+	}
+	s3Select.Evaluate(w)
+	s3Select.Close()
+
+	// Notify objects accessed via a GET request.
+	for _, objInfo := range accessed {
+		sendEvent(eventArgs{
+			EventName:    event.ObjectAccessedGet,
+			BucketName:   bucket,
+			Object:       objInfo,
+			ReqParams:    extractReqParams(r),
+			RespElements: extractRespElements(w),
+			UserAgent:    r.UserAgent(),
+			Host:         handlers.GetSourceIP(r),
+		})
+	}
+}
+
+// selectObject returns the object of a select over a prefix for a
+// listed object, with an error set if the object cannot be read with
+// the credentials and encryption keys of the request. onRead is called
+// when the object content is read.
+func selectObject(ctx context.Context, r *http.Request, objectAPI ObjectLayer, getObjectNInfo func(context.Context, string, string, *HTTPRangeSpec, http.Header, LockType, ObjectOptions) (*GetObjectReader, error), objInfo ObjectInfo, onRead func()) *s3select.SelectObject {
+	bucket, object := objInfo.Bucket, objInfo.Name
+	selectObj := &s3select.SelectObject{
+		Name: object,
+		Size: -1,
+	}
+	setError := func(apiErr APIError) *s3select.SelectObject {
+		selectObj.ErrorCode, selectObj.ErrorMessage = apiErr.Code, apiErr.Description
+		return selectObj
+	}
+
+	// The request signature is verified by the ListBucket check, it
+	// cannot be verified again once the request body is read.
+	if s3Error := isPutActionAllowed(ctx, getRequestAuthType(r), bucket, object, r, iampolicy.GetObjectAction); s3Error != ErrNone {
+		return setError(errorCodes.ToAPIErr(s3Error))
+	}
+
+	opts, err := getOpts(ctx, r, bucket, object)
+	if err != nil {
+		return setError(toAPIError(ctx, err))
+	}
+
+	if objectAPI.IsEncryptionSupported() {
+		if _, err = DecryptObjectInfo(&objInfo, r); err != nil {
+			return setError(toAPIError(ctx, err))
+		}
+		if crypto.SSEC.IsEncrypted(objInfo.UserDefined) {
+			// Validate the SSE-C Key set in the header.
+			if _, err = crypto.SSEC.UnsealObjectKey(r.Header, objInfo.UserDefined, bucket, object); err != nil {
+				return setError(toAPIError(ctx, err))
+			}
+		}
+	}
+
+	if actualSize, err := objInfo.GetActualSize(); err == nil {
+		selectObj.Size = actualSize
+	}
+	// Objects compressed by the server are read decompressed.
+	selectObj.Compressed = objInfo.IsCompressed()
+
+	var once sync.Once
+	selectObj.GetReader = func(offset, length int64) (io.ReadCloser, error) {
+		once.Do(onRead)
+
+		isSuffixLength := false
+		if offset < 0 {
+			isSuffixLength = true
+		}
+
+		if length > 0 {
+			length--
+		}
+
+		rs := &HTTPRangeSpec{
+			IsSuffixLength: isSuffixLength,
+			Start:          offset,
+			End:            offset + length,
+		}
+
+		return getObjectNInfo(ctx, bucket, object, rs, r.Header, readLock, opts)
+	}
+	return selectObj
+}
+
+// writeSelectErrorResponse writes the error of a select request which
+// could not start.
+func writeSelectErrorResponse(ctx context.Context, w http.ResponseWriter, r *http.Request, bucket, object string, err error) {
+	serr, ok := err.(s3select.SelectError)
+	if !ok {
+		writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL, guessIsBrowserReq(r))
+		return
+	}
+	encodedErrorResponse := encodeResponse(APIErrorResponse{
+		Code:       serr.ErrorCode(),
+		Message:    serr.ErrorMessage(),
+		BucketName: bucket,
+		Key:        object,
+		Resource:   r.URL.Path,
+		RequestID:  w.Header().Get(xhttp.AmzRequestID),
+		HostID:     globalDeploymentID,
+	})
+	writeResponse(w, serr.HTTPStatusCode(), encodedErrorResponse, mimeXML)
+}
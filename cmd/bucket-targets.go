@@ -183,7 +183,7 @@ func (sys *BucketTargetSys) RemoveTarget(ctx context.Context, bucket, arnStr str
 		}
 		// reject removal of remote target if replication configuration is present
 		rcfg, err := getReplicationConfig(ctx, bucket)
-		if err == nil && rcfg.RoleArn == arnStr {
+		if err == nil && rcfg.GetTargetDestination(arnStr).Bucket != "" {
 			if _, ok := sys.arnRemotesMap[arnStr]; ok {
 				return BucketRemoteRemoveDisallowed{Bucket: bucket}
 			}
@@ -377,6 +377,7 @@ func (sys *BucketTargetSys) getRemoteTargetClient(tcfg *madmin.BucketTarget) (*T
 		healthCheckDuration: hcDuration,
 		bucket:              tcfg.TargetBucket,
 		replicateSync:       tcfg.ReplicationSync,
+		disableProxy:        tcfg.DisableProxy,
 	}
 	go tc.healthCheck()
 	return tc, nil
@@ -451,6 +452,7 @@ type TargetClient struct {
 	healthCheckDuration time.Duration
 	bucket              string // remote bucket target
 	replicateSync       bool
+	disableProxy        bool // do not proxy requests for objects missing locally to this target
 }
 
 func (tc *TargetClient) isOffline() bool {
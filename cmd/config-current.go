@@ -28,6 +28,7 @@ import (
 	"github.com/minio/minio/cmd/config/cache"
 	"github.com/minio/minio/cmd/config/compress"
 	"github.com/minio/minio/cmd/config/dns"
+	xetcd "github.com/minio/minio/cmd/config/etcd"
 	"github.com/minio/minio/cmd/config/heal"
 	xldap "github.com/minio/minio/cmd/config/identity/ldap"
 	"github.com/minio/minio/cmd/config/identity/openid"
@@ -47,6 +48,7 @@ func initHelp() {
 	var kvs = map[string]config.KVS{
 		config.CacheSubSys:          cache.DefaultKVS,
 		config.CompressionSubSys:    compress.DefaultKVS,
+		config.EtcdSubSys:           xetcd.DefaultKVS,
 		config.IdentityLDAPSubSys:   xldap.DefaultKVS,
 		config.IdentityOpenIDSubSys: openid.DefaultKVS,
 		config.PolicyOPASubSys:      opa.DefaultKVS,
@@ -196,6 +198,7 @@ func initHelp() {
 		config.StorageClassSubSys:   storageclass.Help,
 		config.CacheSubSys:          cache.Help,
 		config.CompressionSubSys:    compress.Help,
+		config.EtcdSubSys:           xetcd.Help,
 		config.HealSubSys:           heal.Help,
 		config.ScannerSubSys:        scanner.Help,
 		config.IdentityOpenIDSubSys: openid.Help,
@@ -250,6 +253,10 @@ func validateConfig(s config.Config, setDriveCounts []int) error {
 		return err
 	}
 
+	if _, err := xetcd.LookupConfig(s[config.EtcdSubSys][config.Default], globalRootCAs); err != nil {
+		return err
+	}
+
 	compCfg, err := compress.LookupConfig(s[config.CompressionSubSys][config.Default])
 	if err != nil {
 		return err
@@ -347,6 +354,26 @@ func lookupConfigs(s config.Config, setDriveCounts []int) {
 		}
 	}
 
+	etcdCfg, err := xetcd.LookupConfig(s[config.EtcdSubSys][config.Default], globalRootCAs)
+	if err != nil {
+		if globalIsGateway {
+			logger.FatalIf(err, "Unable to initialize etcd config")
+		} else {
+			logger.LogIf(ctx, fmt.Errorf("Unable to initialize etcd config: %w", err))
+		}
+	}
+
+	if etcdCfg.Enabled && globalEtcdClient == nil {
+		globalEtcdClient, err = xetcd.New(etcdCfg)
+		if err != nil {
+			if globalIsGateway {
+				logger.FatalIf(err, "Unable to initialize etcd config")
+			} else {
+				logger.LogIf(ctx, fmt.Errorf("Unable to initialize etcd config: %w", err))
+			}
+		}
+	}
+
 	globalServerRegion, err = config.LookupRegion(s[config.RegionSubSys][config.Default])
 	if err != nil {
 		logger.LogIf(ctx, fmt.Errorf("Invalid region configuration: %w", err))
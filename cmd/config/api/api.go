@@ -23,6 +23,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/dustin/go-humanize"
 	"github.com/minio/minio/cmd/config"
 	"github.com/minio/minio/pkg/env"
 )
@@ -37,6 +38,9 @@ const (
 	apiListQuorum                 = "list_quorum"
 	apiExtendListCacheLife        = "extend_list_cache_life"
 	apiReplicationWorkers         = "replication_workers"
+	apiRateLimitBy                = "rate_limit_by"
+	apiRateLimitRequests          = "rate_limit_requests"
+	apiRateLimitBytes             = "rate_limit_bytes"
 	EnvAPIRequestsMax             = "MINIO_API_REQUESTS_MAX"
 	EnvAPIRequestsDeadline        = "MINIO_API_REQUESTS_DEADLINE"
 	EnvAPIClusterDeadline         = "MINIO_API_CLUSTER_DEADLINE"
@@ -46,6 +50,16 @@ const (
 	EnvAPIExtendListCacheLife     = "MINIO_API_EXTEND_LIST_CACHE_LIFE"
 	EnvAPISecureCiphers           = "MINIO_API_SECURE_CIPHERS"
 	EnvAPIReplicationWorkers      = "MINIO_API_REPLICATION_WORKERS"
+	EnvAPIRateLimitBy             = "MINIO_API_RATE_LIMIT_BY"
+	EnvAPIRateLimitRequests       = "MINIO_API_RATE_LIMIT_REQUESTS"
+	EnvAPIRateLimitBytes          = "MINIO_API_RATE_LIMIT_BYTES"
+)
+
+// Keys of the rate limits
+const (
+	RateLimitByAccessKey = "access_key"
+	RateLimitByBucket    = "bucket"
+	RateLimitBySourceIP  = "source_ip"
 )
 
 // Deprecated key and ENVs
@@ -89,6 +103,18 @@ var (
 			Key:   apiReplicationWorkers,
 			Value: "100",
 		},
+		config.KV{
+			Key:   apiRateLimitBy,
+			Value: "",
+		},
+		config.KV{
+			Key:   apiRateLimitRequests,
+			Value: "0",
+		},
+		config.KV{
+			Key:   apiRateLimitBytes,
+			Value: "0",
+		},
 	}
 )
 
@@ -102,6 +128,9 @@ type Config struct {
 	ListQuorum              string        `json:"list_strict_quorum"`
 	ExtendListLife          time.Duration `json:"extend_list_cache_life"`
 	ReplicationWorkers      int           `json:"replication_workers"`
+	RateLimitBy             string        `json:"rate_limit_by"`
+	RateLimitRequests       uint64        `json:"rate_limit_requests"`
+	RateLimitBytes          uint64        `json:"rate_limit_bytes"`
 }
 
 // UnmarshalJSON - Validate SS and RRS parity when unmarshalling JSON.
@@ -188,6 +217,23 @@ func LookupConfig(kvs config.KVS) (cfg Config, err error) {
 		return cfg, config.ErrInvalidReplicationWorkersValue(nil).Msg("Minimum number of replication workers should be 1")
 	}
 
+	rateLimitBy := env.Get(EnvAPIRateLimitBy, kvs.Get(apiRateLimitBy))
+	switch rateLimitBy {
+	case "", RateLimitByAccessKey, RateLimitByBucket, RateLimitBySourceIP:
+	default:
+		return cfg, errors.New("invalid value for rate limit key")
+	}
+
+	rateLimitRequests, err := strconv.ParseUint(env.Get(EnvAPIRateLimitRequests, kvs.Get(apiRateLimitRequests)), 10, 64)
+	if err != nil {
+		return cfg, err
+	}
+
+	rateLimitBytes, err := humanize.ParseBytes(env.Get(EnvAPIRateLimitBytes, kvs.Get(apiRateLimitBytes)))
+	if err != nil {
+		return cfg, err
+	}
+
 	return Config{
 		RequestsMax:             requestsMax,
 		RequestsDeadline:        requestsDeadline,
@@ -197,5 +243,8 @@ func LookupConfig(kvs config.KVS) (cfg Config, err error) {
 		ListQuorum:              listQuorum,
 		ExtendListLife:          listLife,
 		ReplicationWorkers:      replicationWorkers,
+		RateLimitBy:             rateLimitBy,
+		RateLimitRequests:       rateLimitRequests,
+		RateLimitBytes:          rateLimitBytes,
 	}, nil
 }
@@ -51,5 +51,23 @@ var (
 			Optional:    true,
 			Type:        "number",
 		},
+		config.HelpKV{
+			Key:         apiRateLimitBy,
+			Description: `rate limit requests by "access_key", "bucket" or "source_ip", disabled by default`,
+			Optional:    true,
+			Type:        "string",
+		},
+		config.HelpKV{
+			Key:         apiRateLimitRequests,
+			Description: `set the maximum number of requests per second of each rate limit key e.g. "100"`,
+			Optional:    true,
+			Type:        "number",
+		},
+		config.HelpKV{
+			Key:         apiRateLimitBytes,
+			Description: `set the maximum bytes per second sent and received by each rate limit key e.g. "100MiB"`,
+			Optional:    true,
+			Type:        "string",
+		},
 	}
 )
@@ -22,8 +22,10 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/dustin/go-humanize"
 	"github.com/minio/minio/cmd/config"
 	"github.com/minio/minio/pkg/ellipses"
+	"github.com/minio/minio/pkg/sys"
 )
 
 // Config represents cache config settings
@@ -39,8 +41,29 @@ type Config struct {
 	WatermarkHigh   int      `json:"watermark_high"`
 	Range           bool     `json:"range"`
 	CommitWriteback bool     `json:"-"`
+	Policy          string   `json:"policy"`
+
+	// MemorySize is the capacity of the in-memory cache of small
+	// objects in bytes, the cache is disabled when 0.
+	MemorySize          uint64 `json:"-"`
+	MemoryMaxObjectSize uint64 `json:"-"`
 }
 
+// Cache eviction policies
+const (
+	// PolicyLRU evicts the least recently accessed entries first,
+	// weighted by their size and number of hits.
+	PolicyLRU = "lru"
+	// PolicyLFU evicts the least frequently accessed entries first.
+	PolicyLFU = "lfu"
+	// PolicyARC adapts between recency and frequency based on the
+	// entries that are requested again after being evicted.
+	PolicyARC = "arc"
+	// PolicyGDSF (GreedyDual-Size-Frequency) evicts large, rarely
+	// accessed entries first.
+	PolicyGDSF = "gdsf"
+)
+
 // UnmarshalJSON - implements JSON unmarshal interface for unmarshalling
 // json entries for CacheConfig.
 func (cfg *Config) UnmarshalJSON(data []byte) (err error) {
@@ -77,6 +100,11 @@ func (cfg *Config) UnmarshalJSON(data []byte) (err error) {
 	if _cfg.WatermarkLow > 0 && (_cfg.WatermarkLow >= _cfg.WatermarkHigh) {
 		return errors.New("config low watermark value should be less than high watermark")
 	}
+	if _cfg.Policy != "" {
+		if _cfg.Policy, err = parseCachePolicy(_cfg.Policy); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -163,3 +191,35 @@ func parseCacheCommitMode(commitStr string) (bool, error) {
 	}
 	return false, config.ErrInvalidCacheCommitValue(nil).Msg("cache commit value must be `writeback` or `writethrough`")
 }
+
+// memory limit divisor for automatic memory cache sizing.
+const autoMemoryDivisor = 16
+
+// parseCacheMemory returns the size of the memory cache in bytes,
+// "auto" sizes it from the memory limit of the process, including
+// cgroup limits.
+func parseCacheMemory(memoryStr string) (uint64, error) {
+	switch strings.ToLower(memoryStr) {
+	case "", config.EnableOff:
+		return 0, nil
+	case MemoryAuto:
+		stats, err := sys.GetStats()
+		if err != nil {
+			return 0, config.ErrInvalidCacheMemory(err)
+		}
+		return stats.TotalRAM / autoMemoryDivisor, nil
+	}
+	size, err := humanize.ParseBytes(memoryStr)
+	if err != nil {
+		return 0, config.ErrInvalidCacheMemory(err)
+	}
+	return size, nil
+}
+
+func parseCachePolicy(policyStr string) (string, error) {
+	switch policy := strings.ToLower(policyStr); policy {
+	case PolicyLRU, PolicyLFU, PolicyARC, PolicyGDSF:
+		return policy, nil
+	}
+	return "", config.ErrInvalidCachePolicy(nil).Msg("cache policy value must be one of `lru`, `lfu`, `arc` or `gdsf`")
+}
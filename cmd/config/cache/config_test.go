@@ -125,3 +125,32 @@ func TestParseCacheExclude(t *testing.T) {
 		}
 	}
 }
+
+// Tests cache eviction policy parsing.
+func TestParseCachePolicy(t *testing.T) {
+	testCases := []struct {
+		policyStr      string
+		expectedPolicy string
+		success        bool
+	}{
+		{"lru", PolicyLRU, true},
+		{"LFU", PolicyLFU, true},
+		{"arc", PolicyARC, true},
+		{"gdsf", PolicyGDSF, true},
+		{"fifo", "", false},
+		{"", "", false},
+	}
+
+	for i, testCase := range testCases {
+		policy, err := parseCachePolicy(testCase.policyStr)
+		if err != nil && testCase.success {
+			t.Errorf("Test %d: Expected success but failed instead %s", i+1, err)
+		}
+		if err == nil && !testCase.success {
+			t.Errorf("Test %d: Expected failure but passed instead", i+1)
+		}
+		if err == nil && policy != testCase.expectedPolicy {
+			t.Errorf("Test %d: Expected %v, got %v", i+1, testCase.expectedPolicy, policy)
+		}
+	}
+}
@@ -80,5 +80,23 @@ var (
 			Optional:    true,
 			Type:        "string",
 		},
+		config.HelpKV{
+			Key:         Policy,
+			Description: `cache eviction policy "lru", "lfu", "arc" or "gdsf", defaults to "lru"`,
+			Optional:    true,
+			Type:        "string",
+		},
+		config.HelpKV{
+			Key:         Memory,
+			Description: `size of the in-memory cache of small objects e.g. "2GiB", "auto" sizes it from the memory limit, defaults to "off"`,
+			Optional:    true,
+			Type:        "string",
+		},
+		config.HelpKV{
+			Key:         MemoryMaxSize,
+			Description: `largest object kept in the in-memory cache, defaults to "1MiB"`,
+			Optional:    true,
+			Type:        "string",
+		},
 	}
 )
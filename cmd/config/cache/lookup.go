@@ -20,6 +20,7 @@ import (
 	"errors"
 	"strconv"
 
+	"github.com/dustin/go-humanize"
 	"github.com/minio/minio/cmd/config"
 	"github.com/minio/minio/pkg/env"
 )
@@ -36,6 +37,9 @@ const (
 	WatermarkHigh = "watermark_high"
 	Range         = "range"
 	Commit        = "commit"
+	Policy        = "policy"
+	Memory        = "memory"
+	MemoryMaxSize = "memory_max_object_size"
 
 	EnvCacheDrives        = "MINIO_CACHE_DRIVES"
 	EnvCacheExclude       = "MINIO_CACHE_EXCLUDE"
@@ -47,6 +51,9 @@ const (
 	EnvCacheWatermarkHigh = "MINIO_CACHE_WATERMARK_HIGH"
 	EnvCacheRange         = "MINIO_CACHE_RANGE"
 	EnvCacheCommit        = "MINIO_CACHE_COMMIT"
+	EnvCachePolicy        = "MINIO_CACHE_POLICY"
+	EnvCacheMemory        = "MINIO_CACHE_MEMORY"
+	EnvCacheMemoryMaxSize = "MINIO_CACHE_MEMORY_MAX_OBJECT_SIZE"
 
 	EnvCacheEncryptionMasterKey = "MINIO_CACHE_ENCRYPTION_MASTER_KEY"
 
@@ -56,6 +63,12 @@ const (
 	DefaultWaterMarkLow  = "70"
 	DefaultWaterMarkHigh = "80"
 	DefaultCacheCommit   = "writethrough"
+	DefaultCachePolicy   = PolicyLRU
+	DefaultMemory        = config.EnableOff
+	DefaultMemoryMaxSize = "1MiB"
+
+	// MemoryAuto sizes the memory cache from the memory limit of the process.
+	MemoryAuto = "auto"
 )
 
 // DefaultKVS - default KV settings for caching.
@@ -97,6 +110,18 @@ var (
 			Key:   Commit,
 			Value: DefaultCacheCommit,
 		},
+		config.KV{
+			Key:   Policy,
+			Value: DefaultCachePolicy,
+		},
+		config.KV{
+			Key:   Memory,
+			Value: DefaultMemory,
+		},
+		config.KV{
+			Key:   MemoryMaxSize,
+			Value: DefaultMemoryMaxSize,
+		},
 	}
 )
 
@@ -107,7 +132,8 @@ const (
 // Enabled returns if cache is enabled.
 func Enabled(kvs config.KVS) bool {
 	drives := kvs.Get(Drives)
-	return drives != ""
+	memory := kvs.Get(Memory)
+	return drives != "" || (memory != "" && memory != config.EnableOff)
 }
 
 // LookupConfig - extracts cache configuration provided by environment
@@ -118,12 +144,27 @@ func LookupConfig(kvs config.KVS) (Config, error) {
 		return cfg, err
 	}
 
+	var err error
+	cfg.MemorySize, err = parseCacheMemory(env.Get(EnvCacheMemory, kvs.Get(Memory)))
+	if err != nil {
+		return cfg, err
+	}
+	if cfg.MemorySize > 0 {
+		maxSize := env.Get(EnvCacheMemoryMaxSize, kvs.Get(MemoryMaxSize))
+		if maxSize == "" {
+			maxSize = DefaultMemoryMaxSize
+		}
+		cfg.MemoryMaxObjectSize, err = humanize.ParseBytes(maxSize)
+		if err != nil {
+			return cfg, config.ErrInvalidCacheMemory(err)
+		}
+	}
+
 	drives := env.Get(EnvCacheDrives, kvs.Get(Drives))
 	if len(drives) == 0 {
 		return cfg, nil
 	}
 
-	var err error
 	cfg.Drives, err = parseCacheDrives(drives)
 	if err != nil {
 		return cfg, err
@@ -228,5 +269,13 @@ func LookupConfig(kvs config.KVS) (Config, error) {
 		}
 	}
 
+	cfg.Policy = DefaultCachePolicy
+	if policy := env.Get(EnvCachePolicy, kvs.Get(Policy)); policy != "" {
+		cfg.Policy, err = parseCachePolicy(policy)
+		if err != nil {
+			return cfg, err
+		}
+	}
+
 	return cfg, nil
 }
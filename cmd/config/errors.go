@@ -114,6 +114,18 @@ var (
 		"MINIO_CACHE_COMMIT: Valid expected value is `writeback` or `writethrough`",
 	)
 
+	ErrInvalidCachePolicy = newErrFn(
+		"Invalid cache policy value",
+		"Please check the passed value",
+		"MINIO_CACHE_POLICY: Valid expected value is `lru`, `lfu`, `arc` or `gdsf`",
+	)
+
+	ErrInvalidCacheMemory = newErrFn(
+		"Invalid cache memory value",
+		"Please check the passed value",
+		"MINIO_CACHE_MEMORY: Valid expected value is a size such as `2GiB`, `auto` or `off`",
+	)
+
 	ErrInvalidCacheSetting = newErrFn(
 		"Incompatible cache setting",
 		"Please check the passed value",
@@ -46,6 +46,7 @@ const (
 	dataCrawlSleepPerFolder  = time.Millisecond // Time to wait between folders.
 	dataCrawlStartDelay      = 1 * time.Minute  // Time to wait on startup and between cycles.
 	dataUsageUpdateDirCycles = 16               // Visit all folders every n cycles.
+	dataUsageFlattenLevels   = 2                // Folders at most this deep below the bucket keep their own entry.
 
 	healDeleteDangling    = true
 	healFolderIncludeProb = 32  // Include a clean folder one in n cycles.
@@ -123,6 +124,9 @@ func runDataScanner(ctx context.Context, objAPI ObjectLayer) {
 			err = objAPI.CrawlAndGetDataUsage(ctx, bf, results)
 			close(results)
 			logger.LogIf(ctx, err)
+
+			// Generate the inventory reports that are due.
+			go runInventoryJobs(ctx, objAPI)
 			if err == nil {
 				// Store new cycle...
 				nextBloomCycle++
@@ -232,7 +236,7 @@ func crawlDataFolder(ctx context.Context, basePath string, cache dataUsageCache,
 	}
 
 	done := ctx.Done()
-	var flattenLevels = 2
+	var flattenLevels = dataUsageFlattenLevels
 
 	if s.dataUsageCrawlDebug {
 		console.Debugf(logPrefix+"Cycle: %v, Entries: %v %s\n", cache.Info.NextCycle, len(cache.Cache), logSuffix)
@@ -771,6 +775,19 @@ type sizeSummary struct {
 	pendingSize    int64
 	failedSize     int64
 	replicaSize    int64
+	ownerSizes     map[string]int64
+}
+
+// addOwnerSize adds the size of the object to the size of its owner.
+func (s *sizeSummary) addOwnerSize(oi ObjectInfo, size int64) {
+	owner := oi.UserDefined[objectOwnerKey]
+	if owner == "" || size <= 0 {
+		return
+	}
+	if s.ownerSizes == nil {
+		s.ownerSizes = make(map[string]int64, 1)
+	}
+	s.ownerSizes[owner] += size
 }
 
 type getSizeFn func(item crawlItem) (sizeSummary, error)
@@ -951,7 +968,7 @@ func evalActionFromLifecycle(ctx context.Context, lc lifecycle.Lifecycle, obj Ob
 func applyTransitionAction(ctx context.Context, action lifecycle.Action, objLayer ObjectLayer, obj ObjectInfo) bool {
 	opts := ObjectOptions{}
 	if obj.TransitionStatus == "" {
-		opts.Versioned = globalBucketVersioningSys.Enabled(obj.Bucket)
+		opts.Versioned = globalBucketVersioningSys.PrefixEnabled(obj.Bucket, obj.Name)
 		opts.VersionID = obj.VersionID
 		opts.TransitionStatus = lifecycle.TransitionPending
 		if _, err := objLayer.DeleteObject(ctx, obj.Bucket, obj.Name, opts); err != nil {
@@ -1001,10 +1018,11 @@ func applyExpiryOnNonTransitionedObjects(ctx context.Context, objLayer ObjectLay
 		opts.VersionID = obj.VersionID
 	}
 	if opts.VersionID == "" {
-		opts.Versioned = globalBucketVersioningSys.Enabled(obj.Bucket)
+		opts.Versioned = globalBucketVersioningSys.PrefixEnabled(obj.Bucket, obj.Name)
 	}
 
-	obj, err := objLayer.DeleteObject(ctx, obj.Bucket, obj.Name, opts)
+	bucket, object := obj.Bucket, obj.Name
+	obj, err := objLayer.DeleteObject(ctx, bucket, object, opts)
 	if err != nil {
 		if isErrObjectNotFound(err) || isErrVersionNotFound(err) {
 			return false
@@ -1013,6 +1031,7 @@ func applyExpiryOnNonTransitionedObjects(ctx context.Context, objLayer ObjectLay
 		logger.LogIf(ctx, err)
 		return false
 	}
+	invalidateCachedObject(bucket, object)
 
 	eventName := event.ObjectRemovedDelete
 	if obj.DeleteMarker {
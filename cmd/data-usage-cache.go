@@ -54,6 +54,22 @@ type dataUsageEntry struct {
 	Objects                uint64
 	ObjSizes               sizeHistogram
 	Children               dataUsageHashMap
+	// Size of the objects by owner, the parent user of the
+	// credentials the objects were uploaded with.
+	Owners map[string]int64
+}
+
+//msgp:tuple dataUsageEntryV3
+type dataUsageEntryV3 struct {
+	// These fields do no include any children.
+	Size                   int64
+	ReplicatedSize         uint64
+	ReplicationPendingSize uint64
+	ReplicationFailedSize  uint64
+	ReplicaSize            uint64
+	Objects                uint64
+	ObjSizes               sizeHistogram
+	Children               dataUsageHashMap
 }
 
 //msgp:tuple dataUsageEntryV2
@@ -65,13 +81,20 @@ type dataUsageEntryV2 struct {
 	Children dataUsageHashMap
 }
 
-// dataUsageCache contains a cache of data usage entries latest version 3.
+// dataUsageCache contains a cache of data usage entries latest version 4.
 type dataUsageCache struct {
 	Info  dataUsageCacheInfo
 	Disks []string
 	Cache map[string]dataUsageEntry
 }
 
+// dataUsageCache contains a cache of data usage entries version 3.
+type dataUsageCacheV3 struct {
+	Info  dataUsageCacheInfo
+	Disks []string
+	Cache map[string]dataUsageEntryV3
+}
+
 // dataUsageCache contains a cache of data usage entries version 2.
 type dataUsageCacheV2 struct {
 	Info  dataUsageCacheInfo
@@ -104,6 +127,12 @@ func (e *dataUsageEntry) addSizes(summary sizeSummary) {
 	e.ReplicationFailedSize += uint64(summary.failedSize)
 	e.ReplicationPendingSize += uint64(summary.pendingSize)
 	e.ReplicaSize += uint64(summary.replicaSize)
+	for owner, size := range summary.ownerSizes {
+		if e.Owners == nil {
+			e.Owners = make(map[string]int64, len(summary.ownerSizes))
+		}
+		e.Owners[owner] += size
+	}
 }
 
 // merge other data usage entry into this, excluding children.
@@ -118,6 +147,31 @@ func (e *dataUsageEntry) merge(other dataUsageEntry) {
 	for i, v := range other.ObjSizes[:] {
 		e.ObjSizes[i] += v
 	}
+
+	if len(other.Owners) > 0 {
+		// Entries are copied by value, the map of owners
+		// may be shared with the entry this one was copied from.
+		owners := make(map[string]int64, len(e.Owners)+len(other.Owners))
+		for owner, size := range e.Owners {
+			owners[owner] = size
+		}
+		for owner, size := range other.Owners {
+			owners[owner] += size
+		}
+		e.Owners = owners
+	}
+}
+
+// ownersUsage returns the size of the objects of each owner.
+func (e dataUsageEntry) ownersUsage() map[string]uint64 {
+	if len(e.Owners) == 0 {
+		return nil
+	}
+	dst := make(map[string]uint64, len(e.Owners))
+	for owner, size := range e.Owners {
+		dst[owner] = uint64(size)
+	}
+	return dst
 }
 
 // mod returns true if the hash mod cycles == cycle.
@@ -257,6 +311,7 @@ func (d *dataUsageCache) dui(path string, buckets []BucketInfo) DataUsageInfo {
 		ReplicaSize:            flat.ReplicaSize,
 		BucketsCount:           uint64(len(e.Children)),
 		BucketsUsage:           d.bucketsUsageInfo(buckets),
+		OwnersUsage:            flat.ownersUsage(),
 	}
 }
 
@@ -533,6 +588,7 @@ func (d *dataUsageCache) save(ctx context.Context, store objectIO, name string)
 // Bumping the cache version will drop data from previous versions
 // and write new data with the new version.
 const (
+	dataUsageCacheVerV4 = 4
 	dataUsageCacheVerV3 = 3
 	dataUsageCacheVerV2 = 2
 	dataUsageCacheVerV1 = 1
@@ -541,7 +597,7 @@ const (
 // serialize the contents of the cache.
 func (d *dataUsageCache) serializeTo(dst io.Writer) error {
 	// Add version and compress.
-	_, err := dst.Write([]byte{dataUsageCacheVerV3})
+	_, err := dst.Write([]byte{dataUsageCacheVerV4})
 	if err != nil {
 		return err
 	}
@@ -610,6 +666,34 @@ func (d *dataUsageCache) deserialize(r io.Reader) error {
 		}
 		defer dec.Close()
 
+		dold := &dataUsageCacheV3{}
+		if err = dold.DecodeMsg(msgp.NewReader(dec)); err != nil {
+			return err
+		}
+		d.Info = dold.Info
+		d.Disks = dold.Disks
+		d.Cache = make(map[string]dataUsageEntry, len(dold.Cache))
+		for k, v := range dold.Cache {
+			d.Cache[k] = dataUsageEntry{
+				Size:                   v.Size,
+				ReplicatedSize:         v.ReplicatedSize,
+				ReplicationPendingSize: v.ReplicationPendingSize,
+				ReplicationFailedSize:  v.ReplicationFailedSize,
+				ReplicaSize:            v.ReplicaSize,
+				Objects:                v.Objects,
+				ObjSizes:               v.ObjSizes,
+				Children:               v.Children,
+			}
+		}
+		return nil
+	case dataUsageCacheVerV4:
+		// Zstd compressed.
+		dec, err := zstd.NewReader(r, zstd.WithDecoderConcurrency(2))
+		if err != nil {
+			return err
+		}
+		defer dec.Close()
+
 		return d.DecodeMsg(msgp.NewReader(dec))
 	}
 	return fmt.Errorf("dataUsageCache: unknown version: %d", int(b[0]))
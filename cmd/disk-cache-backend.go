@@ -149,6 +149,7 @@ type diskCache struct {
 	enableRange      bool
 	commitWriteback  bool
 	retryWritebackCh chan ObjectInfo
+	policy           cacheEvictionPolicy
 	// nsMutex namespace lock
 	nsMutex *nsLockMap
 	// Object functions pointing to the corresponding functions of backend implementation.
@@ -176,6 +177,7 @@ func newDiskCache(ctx context.Context, dir string, config cache.Config) (*diskCa
 		enableRange:      config.Range,
 		commitWriteback:  config.CommitWriteback,
 		retryWritebackCh: make(chan ObjectInfo, 10000),
+		policy:           newCacheEvictionPolicy(config.Policy),
 		online:           1,
 		pool: sync.Pool{
 			New: func() interface{} {
@@ -298,6 +300,7 @@ func (c *diskCache) purge(ctx context.Context) {
 	// defaulting max hits count to 100
 	// ignore error we know what value we are passing.
 	scorer, _ := newFileScorer(toFree, time.Now().Unix(), 100)
+	scorer.scoreFn = c.policy.scoreFn()
 
 	// this function returns FileInfo for cached range files and cache data file.
 	fiStatFn := func(ranges map[string]string, dataFile, pathPrefix string) map[string]os.FileInfo {
@@ -391,6 +394,7 @@ func (c *diskCache) purge(ctx context.Context) {
 			fname := fileName[slashIdx+1:]
 			if fname == cacheDataFile {
 				removeAll(fileNamePrefix)
+				c.policy.evicted(fileNamePrefix, qfile.hits)
 			}
 		}
 	})
@@ -700,6 +704,9 @@ func (c *diskCache) Put(ctx context.Context, bucket, object string, data io.Read
 	defer cLock.Unlock()
 
 	meta, _, numHits, err := c.statCache(ctx, cachePath)
+	if osIsNotExist(err) {
+		c.policy.missed(cachePath)
+	}
 	// Case where object not yet cached
 	if osIsNotExist(err) && c.after >= 1 {
 		return oi, c.saveMetadata(ctx, bucket, object, opts.UserDefined, size, nil, "", false)
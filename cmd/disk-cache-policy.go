@@ -0,0 +1,193 @@
+/*
+ * MinIO Cloud Storage, (C) 2021 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"container/list"
+	"math"
+	"sync"
+
+	"github.com/minio/minio/cmd/config/cache"
+)
+
+const (
+	// age in seconds at which the recency of an entry weighs half
+	// as much as its eviction class.
+	cacheAgeScale = 3600.0
+
+	// maximum number of evicted entries remembered by the ARC policy.
+	arcMaxGhosts = 100000
+
+	// adjustment of the ARC target on every request of an evicted entry.
+	arcAdaptStep = 0.05
+)
+
+// cacheEvictionPolicy decides in which order the entries of a cache
+// drive are evicted once the high watermark is reached.
+type cacheEvictionPolicy interface {
+	// String returns the name of the policy as configured.
+	String() string
+	// scoreFn returns the scoring function for a purge, entries with
+	// the highest score are evicted first. nil selects the default
+	// scoring of the fileScorer.
+	scoreFn() func(age float64, size uint64, hits int) float64
+	// evicted is called for every entry removed by a purge.
+	evicted(cachePath string, hits int)
+	// missed is called when an entry absent from the cache is requested.
+	missed(cachePath string)
+}
+
+// newCacheEvictionPolicy returns the eviction policy configured by name,
+// defaults to LRU.
+func newCacheEvictionPolicy(policy string) cacheEvictionPolicy {
+	switch policy {
+	case cache.PolicyLFU:
+		return lfuEvictionPolicy{}
+	case cache.PolicyARC:
+		return newARCEvictionPolicy(arcMaxGhosts)
+	case cache.PolicyGDSF:
+		return gdsfEvictionPolicy{}
+	}
+	return lruEvictionPolicy{}
+}
+
+// ageWeight maps the age of an entry to [0, 1).
+func ageWeight(age float64) float64 {
+	age = math.Max(0, age)
+	return age / (age + cacheAgeScale)
+}
+
+// lruEvictionPolicy evicts the least recently accessed entries first.
+type lruEvictionPolicy struct{}
+
+func (lruEvictionPolicy) String() string { return cache.PolicyLRU }
+
+func (lruEvictionPolicy) scoreFn() func(age float64, size uint64, hits int) float64 { return nil }
+
+func (lruEvictionPolicy) evicted(cachePath string, hits int) {}
+
+func (lruEvictionPolicy) missed(cachePath string) {}
+
+// lfuEvictionPolicy evicts the entries with the fewest hits first,
+// the least recently accessed first among entries with as many hits.
+type lfuEvictionPolicy struct{}
+
+func (lfuEvictionPolicy) String() string { return cache.PolicyLFU }
+
+func (lfuEvictionPolicy) scoreFn() func(age float64, size uint64, hits int) float64 {
+	return func(age float64, size uint64, hits int) float64 {
+		return ageWeight(age) - float64(hits)
+	}
+}
+
+func (lfuEvictionPolicy) evicted(cachePath string, hits int) {}
+
+func (lfuEvictionPolicy) missed(cachePath string) {}
+
+// gdsfEvictionPolicy implements GreedyDual-Size-Frequency with a uniform
+// cost, the entries with the lowest hits to size ratio are evicted first.
+// Instead of an inflation value entries age with the time since their
+// last access.
+type gdsfEvictionPolicy struct{}
+
+func (gdsfEvictionPolicy) String() string { return cache.PolicyGDSF }
+
+func (gdsfEvictionPolicy) scoreFn() func(age float64, size uint64, hits int) float64 {
+	return func(age float64, size uint64, hits int) float64 {
+		return float64(size+1) / float64(hits+1) * (1 + math.Max(0, age)/cacheAgeScale)
+	}
+}
+
+func (gdsfEvictionPolicy) evicted(cachePath string, hits int) {}
+
+func (gdsfEvictionPolicy) missed(cachePath string) {}
+
+// arcEvictionPolicy splits the entries like ARC in the entries accessed
+// once and the entries accessed more often, each ordered by recency.
+// Evicted entries are remembered, when they are requested again the
+// share of their list grows.
+type arcEvictionPolicy struct {
+	mu sync.Mutex
+	// target share of the entries accessed once, between 0 and 1.
+	p float64
+
+	maxGhosts int
+	ghosts    map[string]*list.Element
+	// ghostList holds the evicted entries, most recent at the front.
+	ghostList list.List
+}
+
+type arcGhost struct {
+	cachePath string
+	frequent  bool
+}
+
+func newARCEvictionPolicy(maxGhosts int) *arcEvictionPolicy {
+	return &arcEvictionPolicy{
+		p:         0.5,
+		maxGhosts: maxGhosts,
+		ghosts:    make(map[string]*list.Element),
+	}
+}
+
+func (a *arcEvictionPolicy) String() string { return cache.PolicyARC }
+
+func (a *arcEvictionPolicy) target() float64 {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.p
+}
+
+func (a *arcEvictionPolicy) scoreFn() func(age float64, size uint64, hits int) float64 {
+	p := a.target()
+	return func(age float64, size uint64, hits int) float64 {
+		if hits > 1 {
+			return p + ageWeight(age)
+		}
+		return 1 - p + ageWeight(age)
+	}
+}
+
+func (a *arcEvictionPolicy) evicted(cachePath string, hits int) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if e, ok := a.ghosts[cachePath]; ok {
+		a.ghostList.Remove(e)
+	}
+	a.ghosts[cachePath] = a.ghostList.PushFront(arcGhost{cachePath: cachePath, frequent: hits > 1})
+	for a.ghostList.Len() > a.maxGhosts {
+		e := a.ghostList.Back()
+		delete(a.ghosts, e.Value.(arcGhost).cachePath)
+		a.ghostList.Remove(e)
+	}
+}
+
+func (a *arcEvictionPolicy) missed(cachePath string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	e, ok := a.ghosts[cachePath]
+	if !ok {
+		return
+	}
+	delete(a.ghosts, cachePath)
+	a.ghostList.Remove(e)
+	if e.Value.(arcGhost).frequent {
+		a.p = math.Max(0, a.p-arcAdaptStep)
+	} else {
+		a.p = math.Min(1, a.p+arcAdaptStep)
+	}
+}
@@ -0,0 +1,100 @@
+/*
+ * MinIO Cloud Storage, (C) 2021 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/minio/minio/cmd/config/cache"
+)
+
+func TestCacheEvictionPolicies(t *testing.T) {
+	now := time.Now()
+	type cachedFile struct {
+		name  string
+		atime time.Time
+		size  int64
+		hits  int
+	}
+	files := []cachedFile{
+		{"old-popular", now.Add(-24 * time.Hour), 100, 50},
+		{"recent-once", now.Add(-time.Minute), 100, 1},
+		{"old-once", now.Add(-2 * time.Hour), 100, 1},
+		{"large", now.Add(-time.Minute), 800, 5},
+	}
+	testCases := []struct {
+		policy   string
+		toFree   uint64
+		expected []string
+	}{
+		{cache.PolicyLRU, 200, []string{"old-popular", "old-once"}},
+		{cache.PolicyLFU, 200, []string{"old-once", "recent-once"}},
+		{cache.PolicyARC, 200, []string{"old-popular", "old-once"}},
+		{cache.PolicyGDSF, 200, []string{"old-once", "large"}},
+	}
+	for _, testCase := range testCases {
+		policy := newCacheEvictionPolicy(testCase.policy)
+		if policy.String() != testCase.policy {
+			t.Errorf("%s: unexpected policy %s", testCase.policy, policy)
+		}
+		scorer, err := newFileScorer(testCase.toFree, now.Unix(), 100)
+		if err != nil {
+			t.Fatal(err)
+		}
+		scorer.scoreFn = policy.scoreFn()
+		for _, f := range files {
+			scorer.addFile(f.name, f.atime, f.size, f.hits)
+		}
+		if names := scorer.fileNames(); !reflect.DeepEqual(names, testCase.expected) {
+			t.Errorf("%s: expected %v, got %v\n%s", testCase.policy, testCase.expected, names, scorer.queueString())
+		}
+	}
+
+	// Requests of entries evicted from the recent list protect that list.
+	arc := newARCEvictionPolicy(2)
+	for i, cachePath := range []string{"a", "b", "c"} {
+		arc.evicted(cachePath, i)
+	}
+	if len(arc.ghosts) != 2 || arc.ghostList.Len() != 2 {
+		t.Fatalf("expected 2 ghost entries, got %d", len(arc.ghosts))
+	}
+	arc.missed("a")
+	if arc.target() != 0.5 {
+		t.Fatalf("expected an evicted entry beyond the limit to be forgotten, target is %v", arc.target())
+	}
+	arc.missed("b")
+	if arc.target() != 0.5+arcAdaptStep {
+		t.Fatalf("expected the target of the recent list to grow, got %v", arc.target())
+	}
+	arc.missed("c")
+	if arc.target() != 0.5 {
+		t.Fatalf("expected the target of the recent list to shrink, got %v", arc.target())
+	}
+	scoreFn := arc.scoreFn()
+	arc.evicted("a", 1)
+	arc.missed("a")
+	arc.evicted("b", 1)
+	arc.missed("b")
+	if scoreFn(60, 100, 1) != scoreFn(60, 100, 5) {
+		t.Fatal("expected the score function to keep the target of the purge")
+	}
+	if scoreFn = arc.scoreFn(); scoreFn(60, 100, 1) >= scoreFn(60, 100, 5) {
+		t.Fatal("expected the entries accessed once to be kept over frequent ones")
+	}
+}
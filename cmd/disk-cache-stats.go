@@ -48,7 +48,10 @@ type CacheStats struct {
 	BytesServed  uint64
 	Hits         uint64
 	Misses       uint64
+	Policy       string // eviction policy of the cache drives
 	GetDiskStats func() []CacheDiskStats
+	// GetMemoryStats is nil unless the in-memory cache is enabled.
+	GetMemoryStats func() CacheMemoryStats
 }
 
 // Increase total bytes served from cache
@@ -81,6 +84,15 @@ func (s *CacheStats) getMisses() uint64 {
 	return atomic.LoadUint64(&s.Misses)
 }
 
+// Get the ratio of cache hits to all cache lookups
+func (s *CacheStats) getHitRatio() float64 {
+	hits, misses := s.getHits(), s.getMisses()
+	if hits+misses == 0 {
+		return 0
+	}
+	return float64(hits) / float64(hits+misses)
+}
+
 // Prepare new CacheStats structure
 func newCacheStats() *CacheStats {
 	return &CacheStats{}
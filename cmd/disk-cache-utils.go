@@ -300,12 +300,17 @@ type fileScorer struct {
 	queue       list.List
 	queuedBytes uint64
 	seenBytes   uint64
+
+	// scoreFn when set overrides the default scoring of files
+	// by age, size and number of hits.
+	scoreFn func(age float64, size uint64, hits int) float64
 }
 
 type queuedFile struct {
 	name      string
 	versionID string
 	size      uint64
+	hits      int
 	score     float64
 }
 
@@ -341,6 +346,7 @@ func (f *fileScorer) addFileWithObjInfo(objInfo ObjectInfo, hits int) {
 		name:      objInfo.Name,
 		versionID: objInfo.VersionID,
 		size:      uint64(objInfo.Size),
+		hits:      hits,
 	}
 	f.seenBytes += uint64(objInfo.Size)
 
@@ -353,11 +359,15 @@ func (f *fileScorer) addFileWithObjInfo(objInfo ObjectInfo, hits int) {
 		score = float64(f.now - objInfo.ModTime.Unix())
 	}
 
-	// Size as fraction of how much we want to save, 0->1.
-	szWeight := math.Max(0, (math.Min(1, float64(file.size)*f.sizeMult)))
-	// 0 at f.maxHits, 1 at 0.
-	hitsWeight := (1.0 - math.Max(0, math.Min(1.0, float64(hits)/float64(f.maxHits))))
-	file.score = score * (1 + 0.25*szWeight + 0.25*hitsWeight)
+	if f.scoreFn != nil {
+		file.score = f.scoreFn(score, file.size, hits)
+	} else {
+		// Size as fraction of how much we want to save, 0->1.
+		szWeight := math.Max(0, (math.Min(1, float64(file.size)*f.sizeMult)))
+		// 0 at f.maxHits, 1 at 0.
+		hitsWeight := (1.0 - math.Max(0, math.Min(1.0, float64(hits)/float64(f.maxHits))))
+		file.score = score * (1 + 0.25*szWeight + 0.25*hitsWeight)
+	}
 	// If we still haven't saved enough, just add the file
 	if f.queuedBytes < f.saveBytes {
 		f.insertFile(file)
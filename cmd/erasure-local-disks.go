@@ -0,0 +1,35 @@
+/*
+ * MinIO Cloud Storage, (C) 2021 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+// localOnlineDisks returns the online local drives of this node. Local
+// drives are always returned in the same order, so that new files are
+// written to the same drive while it is online.
+func localOnlineDisks(z *erasureServerPools) func() []StorageAPI {
+	return func() (disks []StorageAPI) {
+		for _, pool := range z.serverPools {
+			for _, set := range pool.sets {
+				for _, disk := range set.getDisks() {
+					if disk != nil && disk.IsLocal() && disk.IsOnline() && !disk.Healing() {
+						disks = append(disks, disk)
+					}
+				}
+			}
+		}
+		return disks
+	}
+}
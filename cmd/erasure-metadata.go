@@ -102,7 +102,7 @@ func (fi FileInfo) IsValid() bool {
 func (fi FileInfo) ToObjectInfo(bucket, object string) ObjectInfo {
 	object = decodeDirObject(object)
 	versionID := fi.VersionID
-	if globalBucketVersioningSys.Enabled(bucket) && versionID == "" {
+	if globalBucketVersioningSys.PrefixEnabled(bucket, object) && versionID == "" {
 		versionID = nullVersionID
 	}
 
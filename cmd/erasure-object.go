@@ -902,7 +902,14 @@ func (er erasureObjects) DeleteObjects(ctx context.Context, bucket string, objec
 			if uuid == "" {
 				uuid = mustGetUUID()
 			}
-			if opts.Versioned || opts.VersionSuspended {
+			versioned, suspended := opts.Versioned, opts.VersionSuspended
+			if opts.PrefixEnabledFn != nil {
+				versioned = opts.PrefixEnabledFn(objects[i].ObjectName)
+			}
+			if opts.PrefixSuspendedFn != nil {
+				suspended = opts.PrefixSuspendedFn(objects[i].ObjectName)
+			}
+			if versioned || suspended {
 				versions[i] = FileInfo{
 					Name:                          objects[i].ObjectName,
 					ModTime:                       modTime,
@@ -910,7 +917,7 @@ func (er erasureObjects) DeleteObjects(ctx context.Context, bucket string, objec
 					DeleteMarkerReplicationStatus: objects[i].DeleteMarkerReplicationStatus,
 					VersionPurgeStatus:            objects[i].VersionPurgeStatus,
 				}
-				if opts.Versioned {
+				if versioned {
 					versions[i].VersionID = uuid
 				}
 				continue
@@ -0,0 +1,135 @@
+/*
+ * MinIO Cloud Storage, (C) 2021 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/minio/minio/cmd/logger"
+	etcd "go.etcd.io/etcd/client/v3"
+)
+
+var errEtcdUnreachable = errors.New("etcd is unreachable, please check your endpoints")
+
+// Default timeout of the requests sent to etcd.
+const defaultContextTimeout = 30 * time.Second
+
+func etcdErrToErr(err error, etcdEndpoints []string) error {
+	if err == nil {
+		return nil
+	}
+	switch err {
+	case context.DeadlineExceeded:
+		return fmt.Errorf("%w %s", errEtcdUnreachable, etcdEndpoints)
+	default:
+		return fmt.Errorf("unexpected error %w from etcd, please check your endpoints %s", err, etcdEndpoints)
+	}
+}
+
+func saveKeyEtcdWithTTL(ctx context.Context, client *etcd.Client, key string, data []byte, ttl int64) error {
+	timeoutCtx, cancel := context.WithTimeout(ctx, defaultContextTimeout)
+	defer cancel()
+	lease, err := client.Grant(timeoutCtx, ttl)
+	if err != nil {
+		return etcdErrToErr(err, client.Endpoints())
+	}
+	_, err = client.Put(timeoutCtx, key, string(data), etcd.WithLease(lease.ID))
+	logger.LogIf(ctx, err)
+	return etcdErrToErr(err, client.Endpoints())
+}
+
+func saveKeyEtcd(ctx context.Context, client *etcd.Client, key string, data []byte, opts ...options) error {
+	if len(opts) > 0 {
+		return saveKeyEtcdWithTTL(ctx, client, key, data, opts[0].ttl)
+	}
+	timeoutCtx, cancel := context.WithTimeout(ctx, defaultContextTimeout)
+	defer cancel()
+	_, err := client.Put(timeoutCtx, key, string(data))
+	logger.LogIf(ctx, err)
+	return etcdErrToErr(err, client.Endpoints())
+}
+
+// saveKeyEtcdIfAbsent saves the key unless it is present already, it
+// returns whether the key was saved.
+func saveKeyEtcdIfAbsent(ctx context.Context, client *etcd.Client, key string, data []byte) (bool, error) {
+	timeoutCtx, cancel := context.WithTimeout(ctx, defaultContextTimeout)
+	defer cancel()
+	resp, err := client.Txn(timeoutCtx).
+		If(etcd.Compare(etcd.CreateRevision(key), "=", 0)).
+		Then(etcd.OpPut(key, string(data))).
+		Commit()
+	if err != nil {
+		logger.LogIf(ctx, err)
+		return false, etcdErrToErr(err, client.Endpoints())
+	}
+	return resp.Succeeded, nil
+}
+
+func deleteKeyEtcd(ctx context.Context, client *etcd.Client, key string) error {
+	timeoutCtx, cancel := context.WithTimeout(ctx, defaultContextTimeout)
+	defer cancel()
+
+	resp, err := client.Delete(timeoutCtx, key)
+	if err != nil {
+		logger.LogIf(ctx, err)
+		return etcdErrToErr(err, client.Endpoints())
+	}
+	if resp.Deleted == 0 {
+		return errConfigNotFound
+	}
+	return nil
+}
+
+func readKeyEtcd(ctx context.Context, client *etcd.Client, key string) ([]byte, error) {
+	timeoutCtx, cancel := context.WithTimeout(ctx, defaultContextTimeout)
+	defer cancel()
+	resp, err := client.Get(timeoutCtx, key)
+	if err != nil {
+		logger.LogIf(ctx, err)
+		return nil, etcdErrToErr(err, client.Endpoints())
+	}
+	if resp.Count == 0 {
+		return nil, errConfigNotFound
+	}
+	for _, ev := range resp.Kvs {
+		if string(ev.Key) == key {
+			return ev.Value, nil
+		}
+	}
+	return nil, errConfigNotFound
+}
+
+// readKeysEtcd returns the values of all keys under the prefix, by
+// their key without the prefix.
+func readKeysEtcd(ctx context.Context, client *etcd.Client, prefix string) (map[string][]byte, error) {
+	timeoutCtx, cancel := context.WithTimeout(ctx, defaultContextTimeout)
+	defer cancel()
+	resp, err := client.Get(timeoutCtx, prefix, etcd.WithPrefix())
+	if err != nil {
+		logger.LogIf(ctx, err)
+		return nil, etcdErrToErr(err, client.Endpoints())
+	}
+	values := make(map[string][]byte, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		values[strings.TrimPrefix(string(kv.Key), prefix)] = kv.Value
+	}
+	return values, nil
+}
@@ -118,6 +118,8 @@ type fsMetaV1 struct {
 	Meta map[string]string `json:"meta,omitempty"`
 	// parts info for current object - used in encryption.
 	Parts []ObjectPartInfo `json:"parts,omitempty"`
+	// Version ID of the current object, empty for the null version.
+	VersionID string `json:"versionId,omitempty"`
 }
 
 // IsValid - tells if the format is sane by validating the version
@@ -149,8 +151,13 @@ func (m fsMetaV1) ToObjectInfo(bucket, object string, fi os.FileInfo) ObjectInfo
 	}
 
 	objInfo := ObjectInfo{
-		Bucket: bucket,
-		Name:   object,
+		Bucket:    bucket,
+		Name:      object,
+		VersionID: m.VersionID,
+		IsLatest:  true,
+	}
+	if objInfo.VersionID == "" && bucket != minioMetaBucket && globalBucketVersioningSys.PrefixEnabled(bucket, object) {
+		objInfo.VersionID = nullVersionID
 	}
 
 	// We set file info only if its valid.
@@ -718,6 +718,8 @@ func (fs *FSObjects) CompleteMultipartUpload(ctx context.Context, bucket string,
 	fsMetaPath := pathJoin(bucketMetaDir, bucket, object, fs.metaJSONFile)
 	metaFile, err := fs.rwPool.Write(fsMetaPath)
 	var freshFile bool
+	// Metadata of the version being replaced.
+	curMeta := fs.defaultFsJSON(object)
 	if err != nil {
 		if !errors.Is(err, errFileNotFound) {
 			logger.LogIf(ctx, err)
@@ -729,6 +731,11 @@ func (fs *FSObjects) CompleteMultipartUpload(ctx context.Context, bucket string,
 			return oi, toObjectErr(err, bucket, object)
 		}
 		freshFile = true
+	} else {
+		var m fsMetaV1
+		if _, err = m.ReadFrom(ctx, metaFile); err == nil {
+			curMeta = m
+		}
 	}
 	defer metaFile.Close()
 	defer func() {
@@ -761,12 +768,13 @@ func (fs *FSObjects) CompleteMultipartUpload(ctx context.Context, bucket string,
 	fsMeta.Meta["etag"] = s3MD5
 	// Save consolidated actual size.
 	fsMeta.Meta[ReservedMetadataPrefix+"actual-size"] = strconv.FormatInt(objectActualSize, 10)
+	fsMeta.VersionID = fsNewVersionID(opts)
 	if _, err = fsMeta.WriteTo(metaFile); err != nil {
 		logger.LogIf(ctx, err)
 		return oi, toObjectErr(err, bucket, object)
 	}
 
-	err = fsRenameFile(ctx, appendFilePath, pathJoin(fs.fsPath, bucket, object))
+	err = fs.renameVersion(ctx, bucket, object, appendFilePath, curMeta, fsMeta.VersionID)
 	if err != nil {
 		logger.LogIf(ctx, err)
 		return oi, toObjectErr(err, bucket, object)
@@ -0,0 +1,660 @@
+/*
+ * MinIO Cloud Storage, (C) 2021 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/minio/minio/cmd/logger"
+)
+
+// Versions of an object in FS mode. The latest version of an object,
+// when it is not a delete marker, lives in the namespace as before and
+// its version ID is saved in `fs.json`. All the other versions are saved
+// newest first in `fs.versions.json` next to `fs.json`, with the data of
+// each of them under `fs.versions/`. An object whose latest version is a
+// delete marker is absent from the namespace.
+const (
+	// fs.versions.json noncurrent versions of an object.
+	fsVersionsJSONFile = "fs.versions.json"
+
+	// Directory holding the data of the noncurrent versions.
+	fsVersionsDataDir = "fs.versions"
+
+	// FS backend versions 1.0.0
+	fsVersionsVersion = "1.0.0"
+)
+
+// fsVersionV1 - a noncurrent version or a delete marker of an object.
+type fsVersionV1 struct {
+	// Empty for the null version.
+	VersionID    string    `json:"versionId,omitempty"`
+	ModTime      time.Time `json:"modTime"`
+	DeleteMarker bool      `json:"deleteMarker,omitempty"`
+	Size         int64     `json:"size,omitempty"`
+	// Name of the data file under `fs.versions/`.
+	DataFile string            `json:"dataFile,omitempty"`
+	Meta     map[string]string `json:"meta,omitempty"`
+	Parts    []ObjectPartInfo  `json:"parts,omitempty"`
+}
+
+// ToObjectInfo - converts the version to object info.
+func (v fsVersionV1) ToObjectInfo(bucket, object string, latest bool) ObjectInfo {
+	if v.DeleteMarker {
+		versionID := v.VersionID
+		if versionID == "" {
+			versionID = nullVersionID
+		}
+		return ObjectInfo{
+			Bucket:       bucket,
+			Name:         object,
+			VersionID:    versionID,
+			ModTime:      v.ModTime,
+			IsLatest:     latest,
+			DeleteMarker: true,
+		}
+	}
+
+	fsMeta := fsMetaV1{
+		Version:   fsMetaVersion,
+		VersionID: v.VersionID,
+		Meta:      cloneMSS(v.Meta),
+		Parts:     v.Parts,
+	}
+	objInfo := fsMeta.ToObjectInfo(bucket, object, nil)
+	objInfo.ModTime = v.ModTime
+	objInfo.Size = v.Size
+	objInfo.IsLatest = latest
+	return objInfo
+}
+
+// fsVersionsV1 - versions of an object other than the one in the
+// namespace, newest first.
+type fsVersionsV1 struct {
+	Version  string        `json:"version"`
+	Versions []fsVersionV1 `json:"versions"`
+}
+
+// fsVersionMatches - returns true if versionID, empty for the null
+// version, is the requested version.
+func fsVersionMatches(versionID, requested string) bool {
+	if requested == nullVersionID {
+		return versionID == "" || versionID == nullVersionID
+	}
+	return versionID == requested
+}
+
+// find - returns the index of the requested version, -1 if not found.
+func (v fsVersionsV1) find(versionID string) int {
+	for i := range v.Versions {
+		if fsVersionMatches(v.Versions[i].VersionID, versionID) {
+			return i
+		}
+	}
+	return -1
+}
+
+// remove - removes the versions with the version ID, empty for the null
+// version, returns their data files.
+func (v *fsVersionsV1) remove(versionID string) (dataFiles []string) {
+	versions := v.Versions[:0]
+	for _, version := range v.Versions {
+		if version.VersionID != versionID {
+			versions = append(versions, version)
+			continue
+		}
+		if version.DataFile != "" {
+			dataFiles = append(dataFiles, version.DataFile)
+		}
+	}
+	v.Versions = versions
+	return dataFiles
+}
+
+// fsNewVersionID - returns the version ID of a new version of the
+// object, empty for the null version.
+func fsNewVersionID(opts ObjectOptions) string {
+	if !opts.Versioned {
+		return ""
+	}
+	if opts.VersionID != "" {
+		return opts.VersionID
+	}
+	return mustGetUUID()
+}
+
+func (fs *FSObjects) getVersionsPath(bucket, object string) string {
+	return pathJoin(fs.fsPath, minioMetaBucket, bucketMetaPrefix, bucket, object, fsVersionsJSONFile)
+}
+
+func (fs *FSObjects) getVersionDataPath(bucket, object, dataFile string) string {
+	return pathJoin(fs.fsPath, minioMetaBucket, bucketMetaPrefix, bucket, object, fsVersionsDataDir, dataFile)
+}
+
+// readVersions - reads `fs.versions.json` of the object, an object
+// without one has no other versions.
+func (fs *FSObjects) readVersions(ctx context.Context, bucket, object string) (fsVersionsV1, error) {
+	versions := fsVersionsV1{Version: fsVersionsVersion}
+	if HasSuffix(object, SlashSeparator) {
+		// Directories are not versioned.
+		return versions, nil
+	}
+
+	rc, _, err := fsOpenFile(ctx, fs.getVersionsPath(bucket, object), 0)
+	if err != nil {
+		if err == errFileNotFound {
+			return versions, nil
+		}
+		return versions, err
+	}
+	defer rc.Close()
+
+	versionsBuf, err := ioutil.ReadAll(rc)
+	if err != nil {
+		logger.LogIf(ctx, err)
+		return versions, err
+	}
+
+	var json = jsoniter.ConfigCompatibleWithStandardLibrary
+	if err = json.Unmarshal(versionsBuf, &versions); err != nil {
+		logger.LogIf(ctx, err)
+		return versions, errCorruptedFormat
+	}
+	return versions, nil
+}
+
+// writeVersions - saves `fs.versions.json` of the object, it is removed
+// when there are no versions left.
+func (fs *FSObjects) writeVersions(ctx context.Context, bucket, object string, versions fsVersionsV1) error {
+	versionsPath := fs.getVersionsPath(bucket, object)
+	if len(versions.Versions) == 0 {
+		err := fsDeleteFile(ctx, pathJoin(fs.fsPath, minioMetaBucket), versionsPath)
+		if err != nil && err != errFileNotFound {
+			return err
+		}
+		return nil
+	}
+
+	var json = jsoniter.ConfigCompatibleWithStandardLibrary
+	versionsBuf, err := json.Marshal(versions)
+	if err != nil {
+		logger.LogIf(ctx, err)
+		return err
+	}
+
+	// Write to a temporary file first so that readers never see
+	// a partially written `fs.versions.json`.
+	tmpPath := pathJoin(fs.fsPath, minioMetaTmpBucket, fs.fsUUID, mustGetUUID())
+	if err = ioutil.WriteFile(tmpPath, versionsBuf, 0644); err != nil {
+		logger.LogIf(ctx, err)
+		return err
+	}
+	if err = fsRenameFile(ctx, tmpPath, versionsPath); err != nil {
+		fsRemoveFile(ctx, tmpPath)
+		return err
+	}
+	return nil
+}
+
+// removeVersionData - removes the data files of purged versions.
+func (fs *FSObjects) removeVersionData(ctx context.Context, bucket, object string, dataFiles []string) {
+	for _, dataFile := range dataFiles {
+		err := fsDeleteFile(ctx, pathJoin(fs.fsPath, minioMetaBucket), fs.getVersionDataPath(bucket, object, dataFile))
+		if err != nil && err != errFileNotFound {
+			logger.LogIf(ctx, err)
+		}
+	}
+}
+
+// readCurrentVersion - returns the metadata of the version in the
+// namespace, found is false when there is none.
+func (fs *FSObjects) readCurrentVersion(ctx context.Context, bucket, object string) (fsMeta fsMetaV1, found bool, err error) {
+	if _, err = fsStatFile(ctx, pathJoin(fs.fsPath, bucket, object)); err != nil {
+		if err == errFileNotFound {
+			return fsMeta, false, nil
+		}
+		return fsMeta, false, err
+	}
+
+	fsMeta = fs.defaultFsJSON(object)
+	rc, _, err := fsOpenFile(ctx, pathJoin(fs.fsPath, minioMetaBucket, bucketMetaPrefix, bucket, object, fs.metaJSONFile), 0)
+	if err != nil {
+		// Ignore if `fs.json` is not available, this is true for pre-existing data.
+		if err == errFileNotFound {
+			return fsMeta, true, nil
+		}
+		return fsMeta, false, err
+	}
+	defer rc.Close()
+
+	fsMetaBuf, err := ioutil.ReadAll(rc)
+	if err == nil {
+		var json = jsoniter.ConfigCompatibleWithStandardLibrary
+		var m fsMetaV1
+		if json.Unmarshal(fsMetaBuf, &m) == nil {
+			fsMeta = m
+		}
+	}
+	return fsMeta, true, nil
+}
+
+// archiveVersion - moves the version in the namespace, described by
+// fsMeta, to the noncurrent versions of the object.
+func (fs *FSObjects) archiveVersion(ctx context.Context, bucket, object string, fsMeta fsMetaV1, versions *fsVersionsV1) error {
+	fsObjPath := pathJoin(fs.fsPath, bucket, object)
+	fi, err := fsStatFile(ctx, fsObjPath)
+	if err != nil {
+		return err
+	}
+
+	dataFile := mustGetUUID()
+	if err = fsRenameFile(ctx, fsObjPath, fs.getVersionDataPath(bucket, object, dataFile)); err != nil {
+		return err
+	}
+
+	versions.Versions = append([]fsVersionV1{{
+		VersionID: fsMeta.VersionID,
+		ModTime:   fi.ModTime(),
+		Size:      fi.Size(),
+		DataFile:  dataFile,
+		Meta:      fsMeta.Meta,
+		Parts:     fsMeta.Parts,
+	}}, versions.Versions...)
+	return nil
+}
+
+// promoteVersion - moves the newest noncurrent version back to the
+// namespace once the latest version was removed, unless it is a
+// delete marker.
+func (fs *FSObjects) promoteVersion(ctx context.Context, bucket, object string, versions *fsVersionsV1) error {
+	if len(versions.Versions) == 0 || versions.Versions[0].DeleteMarker {
+		return nil
+	}
+
+	v := versions.Versions[0]
+	fsObjPath := pathJoin(fs.fsPath, bucket, object)
+	dataPath := fs.getVersionDataPath(bucket, object, v.DataFile)
+	if err := fsRenameFile(ctx, dataPath, fsObjPath); err != nil {
+		return err
+	}
+	// Remove `fs.versions/` if it was the last data file.
+	fsDeleteFile(ctx, pathJoin(fs.fsPath, minioMetaBucket), path.Dir(dataPath))
+	// Modification time of the object is the one of the namespace file.
+	if err := os.Chtimes(fsObjPath, v.ModTime, v.ModTime); err != nil {
+		logger.LogIf(ctx, err)
+	}
+
+	fsMeta := newFSMetaV1()
+	fsMeta.VersionID = v.VersionID
+	fsMeta.Meta = v.Meta
+	fsMeta.Parts = v.Parts
+	wlk, err := fs.rwPool.Create(pathJoin(fs.fsPath, minioMetaBucket, bucketMetaPrefix, bucket, object, fs.metaJSONFile))
+	if err != nil {
+		logger.LogIf(ctx, err)
+		return err
+	}
+	defer wlk.Close()
+	if _, err = fsMeta.WriteTo(wlk); err != nil {
+		return err
+	}
+
+	versions.Versions = versions.Versions[1:]
+	return nil
+}
+
+// renameVersion - renames the data of a new version of the object from
+// srcPath to the namespace. The version it replaces, described by
+// curMeta, is kept as a noncurrent version unless both are the null
+// version, a noncurrent version with the same version ID is replaced.
+func (fs *FSObjects) renameVersion(ctx context.Context, bucket, object, srcPath string, curMeta fsMetaV1, versionID string) error {
+	versions, err := fs.readVersions(ctx, bucket, object)
+	if err != nil {
+		return err
+	}
+
+	fsObjPath := pathJoin(fs.fsPath, bucket, object)
+	var archived bool
+	if curMeta.VersionID != versionID && fsIsFile(ctx, fsObjPath) {
+		if err = fs.archiveVersion(ctx, bucket, object, curMeta, &versions); err != nil {
+			return err
+		}
+		archived = true
+	}
+	purged := versions.remove(versionID)
+
+	if err = fsRenameFile(ctx, srcPath, fsObjPath); err != nil {
+		if archived {
+			// Restore the version which was to be replaced.
+			fsRenameFile(ctx, fs.getVersionDataPath(bucket, object, versions.Versions[0].DataFile), fsObjPath)
+		}
+		return err
+	}
+
+	if !archived && len(purged) == 0 {
+		return nil
+	}
+	if err = fs.writeVersions(ctx, bucket, object, versions); err != nil {
+		return err
+	}
+	fs.removeVersionData(ctx, bucket, object, purged)
+	return nil
+}
+
+// getObjectVersionInfo - returns the object info of the requested
+// version, the latest one if versionID is empty. The returned version is
+// nil if it is the version in the namespace. A latest version which is
+// a delete marker is returned along with errFileNotFound.
+func (fs *FSObjects) getObjectVersionInfo(ctx context.Context, bucket, object, versionID string) (ObjectInfo, *fsVersionV1, error) {
+	oi, err := fs.getObjectInfo(ctx, bucket, object)
+	if err == nil && (versionID == "" || fsVersionMatches(oi.VersionID, versionID)) {
+		return oi, nil, nil
+	}
+	if err != nil && err != errFileNotFound {
+		return oi, nil, err
+	}
+	current := err == nil
+
+	versions, verr := fs.readVersions(ctx, bucket, object)
+	if verr != nil {
+		return ObjectInfo{}, nil, verr
+	}
+
+	if versionID == "" {
+		if len(versions.Versions) > 0 && versions.Versions[0].DeleteMarker {
+			v := versions.Versions[0]
+			return v.ToObjectInfo(bucket, object, true), &v, errFileNotFound
+		}
+		return ObjectInfo{}, nil, errFileNotFound
+	}
+
+	idx := versions.find(versionID)
+	if idx < 0 {
+		return ObjectInfo{}, nil, VersionNotFound{
+			Bucket:    bucket,
+			Object:    object,
+			VersionID: versionID,
+		}
+	}
+	v := versions.Versions[idx]
+	return v.ToObjectInfo(bucket, object, idx == 0 && !current), &v, nil
+}
+
+// getObjectVersions - returns all the versions of the object, newest
+// first.
+func (fs *FSObjects) getObjectVersions(ctx context.Context, bucket, object string) ([]ObjectInfo, error) {
+	var objInfos []ObjectInfo
+	oi, err := fs.getObjectInfoNoFSLock(ctx, bucket, object)
+	switch err {
+	case nil:
+		objInfos = append(objInfos, oi)
+	case errFileNotFound:
+	default:
+		return nil, err
+	}
+
+	versions, err := fs.readVersions(ctx, bucket, object)
+	if err != nil {
+		return nil, err
+	}
+
+	latest := len(objInfos) == 0
+	for i, v := range versions.Versions {
+		vi := v.ToObjectInfo(bucket, object, latest && i == 0)
+		if len(objInfos) > 0 {
+			vi.SuccessorModTime = objInfos[len(objInfos)-1].ModTime
+		}
+		objInfos = append(objInfos, vi)
+	}
+	return objInfos, nil
+}
+
+// putVersionMeta - updates the metadata of a noncurrent version of the
+// object, found is false when versionID is the version in the namespace.
+func (fs *FSObjects) putVersionMeta(ctx context.Context, bucket, object, versionID string, updateFn func(meta map[string]string) map[string]string) (oi ObjectInfo, found bool, err error) {
+	curMeta, current, err := fs.readCurrentVersion(ctx, bucket, object)
+	if err != nil {
+		return oi, false, toObjectErr(err, bucket, object)
+	}
+	if current && fsVersionMatches(curMeta.VersionID, versionID) {
+		return oi, false, nil
+	}
+
+	versions, err := fs.readVersions(ctx, bucket, object)
+	if err != nil {
+		return oi, false, toObjectErr(err, bucket, object)
+	}
+	idx := versions.find(versionID)
+	if idx < 0 {
+		return oi, false, VersionNotFound{
+			Bucket:    bucket,
+			Object:    object,
+			VersionID: versionID,
+		}
+	}
+	v := &versions.Versions[idx]
+	if v.DeleteMarker {
+		return v.ToObjectInfo(bucket, object, idx == 0 && !current), true, toObjectErr(errMethodNotAllowed, bucket, object)
+	}
+
+	v.Meta = updateFn(cloneMSS(v.Meta))
+	if err = fs.writeVersions(ctx, bucket, object, versions); err != nil {
+		return oi, true, toObjectErr(err, bucket, object)
+	}
+	return v.ToObjectInfo(bucket, object, idx == 0 && !current), true, nil
+}
+
+// getObjectVersion - reads the data of a noncurrent version of the object.
+func (fs *FSObjects) getObjectVersion(ctx context.Context, bucket, object string, v fsVersionV1, offset int64, length int64, writer io.Writer, etag string) error {
+	if etag != "" && etag != defaultEtag && extractETag(v.Meta) != etag {
+		logger.LogIf(ctx, InvalidETag{}, logger.Application)
+		return toObjectErr(InvalidETag{}, bucket, object)
+	}
+
+	reader, size, err := fsOpenFile(ctx, fs.getVersionDataPath(bucket, object, v.DataFile), offset)
+	if err != nil {
+		return toObjectErr(err, bucket, object)
+	}
+	defer reader.Close()
+
+	// For negative length we read everything.
+	if length < 0 {
+		length = size - offset
+	}
+
+	// Reply back invalid range if the input offset and length fall out of range.
+	if offset > size || offset+length > size {
+		err = InvalidRange{offset, length, size}
+		logger.LogIf(ctx, err, logger.Application)
+		return err
+	}
+
+	_, err = io.Copy(writer, io.LimitReader(reader, length))
+	// The writer will be closed incase of range queries, which will emit ErrClosedPipe.
+	if err == io.ErrClosedPipe {
+		err = nil
+	}
+	return toObjectErr(err, bucket, object)
+}
+
+// deleteObjectVersion - deletes an object in a bucket with versioning
+// configured. Without a version ID a delete marker becomes the latest
+// version of the object, otherwise the version is removed permanently.
+func (fs *FSObjects) deleteObjectVersion(ctx context.Context, bucket, object string, opts ObjectOptions) (objInfo ObjectInfo, err error) {
+	versions, err := fs.readVersions(ctx, bucket, object)
+	if err != nil {
+		return objInfo, toObjectErr(err, bucket, object)
+	}
+	curMeta, current, err := fs.readCurrentVersion(ctx, bucket, object)
+	if err != nil {
+		return objInfo, toObjectErr(err, bucket, object)
+	}
+
+	bucketDir := pathJoin(fs.fsPath, bucket)
+	fsObjPath := pathJoin(bucketDir, object)
+	minioMetaBucketDir := pathJoin(fs.fsPath, minioMetaBucket)
+	fsMetaPath := pathJoin(minioMetaBucketDir, bucketMetaPrefix, bucket, object, fs.metaJSONFile)
+
+	// removeCurrent removes the version in the namespace, along with
+	// the directories left empty.
+	removeCurrent := func() error {
+		err := fsDeleteFile(ctx, bucketDir, fsObjPath)
+		if err == errFileNotFound {
+			// Already archived, only remove the empty directories.
+			err = fsDeleteFile(ctx, bucketDir, path.Dir(fsObjPath))
+		}
+		if err != nil && err != errFileNotFound {
+			return err
+		}
+		if err := fsDeleteFile(ctx, minioMetaBucketDir, fsMetaPath); err != nil && err != errFileNotFound {
+			return err
+		}
+		return nil
+	}
+
+	if !current && len(versions.Versions) == 0 {
+		return objInfo, ObjectNotFound{Bucket: bucket, Object: object}
+	}
+
+	var purged []string
+	if opts.VersionID == "" {
+		marker := fsVersionV1{
+			ModTime:      opts.MTime,
+			DeleteMarker: true,
+		}
+		if marker.ModTime.IsZero() {
+			marker.ModTime = UTCNow()
+		}
+		if opts.Versioned {
+			marker.VersionID = mustGetUUID()
+		}
+
+		if current {
+			if curMeta.VersionID != marker.VersionID {
+				if err = fs.archiveVersion(ctx, bucket, object, curMeta, &versions); err != nil {
+					return objInfo, toObjectErr(err, bucket, object)
+				}
+			}
+			if err = removeCurrent(); err != nil {
+				return objInfo, toObjectErr(err, bucket, object)
+			}
+		}
+
+		// A null delete marker replaces the null version.
+		purged = versions.remove(marker.VersionID)
+		versions.Versions = append([]fsVersionV1{marker}, versions.Versions...)
+		objInfo = marker.ToObjectInfo(bucket, object, true)
+	} else {
+		var removed fsVersionV1
+		if current && fsVersionMatches(curMeta.VersionID, opts.VersionID) {
+			if err = removeCurrent(); err != nil {
+				return objInfo, toObjectErr(err, bucket, object)
+			}
+			current = false
+		} else {
+			idx := versions.find(opts.VersionID)
+			if idx < 0 {
+				return objInfo, VersionNotFound{
+					Bucket:    bucket,
+					Object:    object,
+					VersionID: opts.VersionID,
+				}
+			}
+			removed = versions.Versions[idx]
+			purged = versions.remove(removed.VersionID)
+		}
+		if !current {
+			if err = fs.promoteVersion(ctx, bucket, object, &versions); err != nil {
+				return objInfo, toObjectErr(err, bucket, object)
+			}
+		}
+		objInfo = ObjectInfo{
+			Bucket:       bucket,
+			Name:         object,
+			VersionID:    opts.VersionID,
+			DeleteMarker: removed.DeleteMarker,
+		}
+	}
+
+	if err = fs.writeVersions(ctx, bucket, object, versions); err != nil {
+		return objInfo, toObjectErr(err, bucket, object)
+	}
+	fs.removeVersionData(ctx, bucket, object, purged)
+	return objInfo, nil
+}
+
+// listVersionsDirFactory - returns a listDir which also returns the
+// objects whose latest version is a delete marker, they are only
+// present in the metadata directory of the bucket.
+func (fs *FSObjects) listVersionsDirFactory() ListDirFunc {
+	listDir := func(bucket, prefixDir, prefixEntry string) (emptyDir bool, entries []string, delayIsLeaf bool) {
+		entries, err := readDir(pathJoin(fs.fsPath, bucket, prefixDir))
+		if err != nil && err != errFileNotFound {
+			logger.LogIf(GlobalContext, err)
+			return false, nil, false
+		}
+		metaDir := pathJoin(fs.fsPath, minioMetaBucket, bucketMetaPrefix, bucket, prefixDir)
+		metaEntries, err := readDir(metaDir)
+		if err != nil && err != errFileNotFound {
+			logger.LogIf(GlobalContext, err)
+			return false, nil, false
+		}
+
+		found := make(map[string]struct{}, len(entries))
+		for _, entry := range entries {
+			found[entry] = struct{}{}
+		}
+		addEntry := func(entry string) {
+			if _, ok := found[entry]; !ok {
+				found[entry] = struct{}{}
+				entries = append(entries, entry)
+			}
+		}
+		for _, metaEntry := range metaEntries {
+			if !HasSuffix(metaEntry, SlashSeparator) || !HasPrefix(metaEntry, prefixEntry) {
+				continue
+			}
+			children, err := readDir(pathJoin(metaDir, metaEntry))
+			if err != nil {
+				continue
+			}
+			for _, child := range children {
+				switch {
+				case child == fsVersionsJSONFile:
+					addEntry(strings.TrimSuffix(metaEntry, SlashSeparator))
+				case HasSuffix(child, SlashSeparator) && child != fsVersionsDataDir+SlashSeparator:
+					addEntry(metaEntry)
+				}
+			}
+		}
+
+		if len(entries) == 0 {
+			return true, nil, false
+		}
+		entries, delayIsLeaf = filterListEntries(bucket, prefixDir, entries, prefixEntry, fs.isLeaf)
+		return false, entries, delayIsLeaf
+	}
+
+	// Return list factory instance.
+	return listDir
+}
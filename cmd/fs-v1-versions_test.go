@@ -0,0 +1,244 @@
+/*
+ * MinIO Cloud Storage, (C) 2021 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	xhttp "github.com/minio/minio/cmd/http"
+	objectlock "github.com/minio/minio/pkg/bucket/object/lock"
+)
+
+func TestFSVersioning(t *testing.T) {
+	obj, disk, err := prepareFS()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(disk)
+	newAllSubsystems()
+
+	fs := obj.(*FSObjects)
+	ctx := GlobalContext
+	bucket, object := "bucket", "object"
+	if err = obj.MakeBucketWithLocation(ctx, bucket, BucketOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	putObject := func(object, data string, versioned bool) ObjectInfo {
+		t.Helper()
+		oi, err := obj.PutObject(ctx, bucket, object, mustGetPutObjReader(t, bytes.NewReader([]byte(data)), int64(len(data)), "", ""),
+			ObjectOptions{Versioned: versioned})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return oi
+	}
+	getObject := func(versionID string) string {
+		t.Helper()
+		var buf bytes.Buffer
+		if err := obj.GetObject(ctx, bucket, object, 0, -1, &buf, "", ObjectOptions{VersionID: versionID}); err != nil {
+			t.Fatal(err)
+		}
+		return buf.String()
+	}
+
+	// A null version written before versioning was enabled, then two versions.
+	putObject(object, "null", false)
+	v1 := putObject(object, "v1", true)
+	v2 := putObject(object, "v2", true)
+	if v1.VersionID == "" || v2.VersionID == "" || v1.VersionID == v2.VersionID {
+		t.Fatalf("expected distinct version IDs, got %q and %q", v1.VersionID, v2.VersionID)
+	}
+	for versionID, expected := range map[string]string{"": "v2", v2.VersionID: "v2", v1.VersionID: "v1", nullVersionID: "null"} {
+		if data := getObject(versionID); data != expected {
+			t.Fatalf("version %q: expected %q, got %q", versionID, expected, data)
+		}
+	}
+	if _, err = obj.GetObjectInfo(ctx, bucket, object, ObjectOptions{VersionID: mustGetUUID()}); !isErrVersionNotFound(err) {
+		t.Fatalf("expected VersionNotFound, got %v", err)
+	}
+
+	// Tags of a noncurrent version.
+	if _, err = obj.PutObjectTags(ctx, bucket, object, "k=v", ObjectOptions{VersionID: v1.VersionID}); err != nil {
+		t.Fatal(err)
+	}
+	if oi, err := obj.GetObjectInfo(ctx, bucket, object, ObjectOptions{VersionID: v1.VersionID}); err != nil || oi.UserTags != "k=v" {
+		t.Fatalf("expected the tags of the version to be updated, got %q: %v", oi.UserTags, err)
+	}
+
+	// A delete marker hides the object.
+	marker, err := obj.DeleteObject(ctx, bucket, object, ObjectOptions{Versioned: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !marker.DeleteMarker || marker.VersionID == "" {
+		t.Fatalf("expected a delete marker, got %+v", marker)
+	}
+	oi, err := obj.GetObjectInfo(ctx, bucket, object, ObjectOptions{})
+	if !isErrObjectNotFound(err) || !oi.DeleteMarker {
+		t.Fatalf("expected ObjectNotFound for a delete marker, got %+v: %v", oi, err)
+	}
+	var methodNotAllowed MethodNotAllowed
+	if _, err = obj.GetObjectInfo(ctx, bucket, object, ObjectOptions{VersionID: marker.VersionID}); !errors.As(err, &methodNotAllowed) {
+		t.Fatalf("expected MethodNotAllowed, got %v", err)
+	}
+	var bucketNotEmpty BucketNotEmpty
+	if err = obj.DeleteBucket(ctx, bucket, false); !errors.As(err, &bucketNotEmpty) {
+		t.Fatalf("expected BucketNotEmpty, got %v", err)
+	}
+
+	// Versions are listed newest first, across pages.
+	expected := []string{marker.VersionID, v2.VersionID, v1.VersionID, nullVersionID}
+	loi, err := obj.ListObjectVersions(ctx, bucket, "", "", "", "", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !loi.IsTruncated || len(loi.Objects) != 2 || loi.NextMarker != object || loi.NextVersionIDMarker != v2.VersionID {
+		t.Fatalf("unexpected first page %+v", loi)
+	}
+	if !loi.Objects[0].IsLatest || !loi.Objects[0].DeleteMarker || loi.Objects[1].IsLatest {
+		t.Fatalf("expected the delete marker to be the latest version, got %+v", loi.Objects)
+	}
+	next, err := obj.ListObjectVersions(ctx, bucket, "", loi.NextMarker, loi.NextVersionIDMarker, "", 1000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if next.IsTruncated || len(next.Objects) != 2 {
+		t.Fatalf("unexpected second page %+v", next)
+	}
+	for i, oi := range append(loi.Objects, next.Objects...) {
+		if !fsVersionMatches(oi.VersionID, expected[i]) {
+			t.Fatalf("version %d: expected %q, got %q", i, expected[i], oi.VersionID)
+		}
+	}
+
+	// Objects whose latest version is a delete marker are listed under their prefix.
+	putObject("dir/object", "data", true)
+	if _, err = obj.DeleteObject(ctx, bucket, "dir/object", ObjectOptions{Versioned: true}); err != nil {
+		t.Fatal(err)
+	}
+	if loi, err = obj.ListObjectVersions(ctx, bucket, "", "", "", SlashSeparator, 1000); err != nil {
+		t.Fatal(err)
+	}
+	if len(loi.Prefixes) != 1 || loi.Prefixes[0] != "dir/" || len(loi.Objects) != len(expected) {
+		t.Fatalf("unexpected listing %+v", loi)
+	}
+
+	// Removing the delete marker, then the latest version, restores the
+	// previous version.
+	for _, versionID := range []string{marker.VersionID, v2.VersionID} {
+		if _, err = obj.DeleteObject(ctx, bucket, object, ObjectOptions{VersionID: versionID}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if data := getObject(""); data != "v1" {
+		t.Fatalf("expected v1 to be the latest version, got %q", data)
+	}
+	if oi, err = obj.GetObjectInfo(ctx, bucket, object, ObjectOptions{}); err != nil || oi.VersionID != v1.VersionID || oi.UserTags != "k=v" {
+		t.Fatalf("unexpected latest version %+v: %v", oi, err)
+	}
+
+	// With versioning suspended a delete replaces the null version.
+	if marker, err = obj.DeleteObject(ctx, bucket, object, ObjectOptions{VersionSuspended: true}); err != nil {
+		t.Fatal(err)
+	}
+	if marker.VersionID != nullVersionID || !marker.DeleteMarker {
+		t.Fatalf("expected a null delete marker, got %+v", marker)
+	}
+	if loi, err = obj.ListObjectVersions(ctx, bucket, object, "", "", "", 1000); err != nil {
+		t.Fatal(err)
+	}
+	if len(loi.Objects) != 2 || !loi.Objects[0].DeleteMarker || loi.Objects[1].VersionID != v1.VersionID {
+		t.Fatalf("unexpected versions %+v", loi.Objects)
+	}
+
+	// Removing all the versions removes the object entirely.
+	for _, versionID := range []string{nullVersionID, v1.VersionID} {
+		if _, err = obj.DeleteObject(ctx, bucket, object, ObjectOptions{VersionID: versionID}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err = os.Stat(pathJoin(fs.fsPath, minioMetaBucket, bucketMetaPrefix, bucket, object)); !os.IsNotExist(err) {
+		t.Fatalf("expected the metadata of the object to be removed, got %v", err)
+	}
+	if loi, err = obj.ListObjectVersions(ctx, bucket, object, "", "", "", 1000); err != nil || len(loi.Objects) != 0 {
+		t.Fatalf("expected no versions, got %+v: %v", loi.Objects, err)
+	}
+
+	// Versions under retention or legal hold cannot be deleted, even
+	// once they are noncurrent.
+	lockedBucket := "locked"
+	if err = obj.MakeBucketWithLocation(ctx, lockedBucket, BucketOptions{LockEnabled: true, VersioningEnabled: true}); err != nil {
+		t.Fatal(err)
+	}
+	retained, err := obj.PutObject(ctx, lockedBucket, object, mustGetPutObjReader(t, bytes.NewReader([]byte("v1")), 2, "", ""),
+		ObjectOptions{Versioned: true, UserDefined: map[string]string{
+			strings.ToLower(xhttp.AmzObjectLockMode):            string(objectlock.RetCompliance),
+			strings.ToLower(xhttp.AmzObjectLockRetainUntilDate): UTCNow().Add(time.Hour).Format(time.RFC3339),
+		}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	held, err := obj.PutObject(ctx, lockedBucket, object, mustGetPutObjReader(t, bytes.NewReader([]byte("v2")), 2, "", ""),
+		ObjectOptions{Versioned: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	setLegalHold := func(status objectlock.LegalHoldStatus) {
+		t.Helper()
+		oi, err := obj.GetObjectInfo(ctx, lockedBucket, object, ObjectOptions{VersionID: held.VersionID})
+		if err != nil {
+			t.Fatal(err)
+		}
+		oi.UserDefined[strings.ToLower(xhttp.AmzObjectLockLegalHold)] = string(status)
+		oi.metadataOnly = true
+		if _, err = obj.CopyObject(ctx, lockedBucket, object, lockedBucket, object, oi,
+			ObjectOptions{VersionID: held.VersionID}, ObjectOptions{VersionID: held.VersionID}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	setLegalHold(objectlock.LegalHoldOn)
+	if _, err = obj.PutObject(ctx, lockedBucket, object, mustGetPutObjReader(t, bytes.NewReader([]byte("v3")), 2, "", ""),
+		ObjectOptions{Versioned: true}); err != nil {
+		t.Fatal(err)
+	}
+	enforce := func(versionID string) APIErrorCode {
+		t.Helper()
+		oi, gerr := obj.GetObjectInfo(ctx, lockedBucket, object, ObjectOptions{VersionID: versionID})
+		r := httptest.NewRequest(http.MethodDelete, "/"+lockedBucket+"/"+object+"?versionId="+versionID, nil)
+		return enforceRetentionBypassForDelete(ctx, r, lockedBucket, ObjectToDelete{ObjectName: object, VersionID: versionID}, oi, gerr)
+	}
+	for _, versionID := range []string{retained.VersionID, held.VersionID} {
+		if code := enforce(versionID); code != ErrObjectLocked {
+			t.Fatalf("version %s: expected ErrObjectLocked, got %v", versionID, code)
+		}
+	}
+	setLegalHold(objectlock.LegalHoldOff)
+	if code := enforce(held.VersionID); code != ErrNone {
+		t.Fatalf("expected the version to be deletable once its legal hold is removed, got %v", code)
+	}
+	if oi, err = obj.GetObjectInfo(ctx, lockedBucket, object, ObjectOptions{VersionID: retained.VersionID}); err != nil || !enforceRetentionForDeletion(ctx, oi) {
+		t.Fatalf("expected the retained version to be kept by lifecycle: %v", err)
+	}
+}
@@ -73,6 +73,9 @@ type FSObjects struct {
 	// ListObjects pool management.
 	listPool *TreeWalkPool
 
+	// ListObjectVersions pool management.
+	versionsListPool *TreeWalkPool
+
 	diskMount bool
 
 	appendFileMap   map[string]*fsAppendFile
@@ -162,10 +165,11 @@ func NewFSObjectLayer(fsPath string) (ObjectLayer, error) {
 		rwPool: &fsIOPool{
 			readersMap: make(map[string]*lock.RLockedFile),
 		},
-		nsMutex:       newNSLock(false),
-		listPool:      NewTreeWalkPool(globalLookupTimeout),
-		appendFileMap: make(map[string]*fsAppendFile),
-		diskMount:     mountinfo.IsLikelyMountPoint(fsPath),
+		nsMutex:          newNSLock(false),
+		listPool:         NewTreeWalkPool(globalLookupTimeout),
+		versionsListPool: NewTreeWalkPool(globalLookupTimeout),
+		appendFileMap:    make(map[string]*fsAppendFile),
+		diskMount:        mountinfo.IsLikelyMountPoint(fsPath),
 	}
 
 	// Once the filesystem has initialized hold the read lock for
@@ -354,11 +358,12 @@ func (fs *FSObjects) crawlBucket(ctx context.Context, bucket string, cache dataU
 
 		oi := fsMeta.ToObjectInfo(bucket, object, fi)
 		sz := item.applyActions(ctx, fs, actionMeta{oi: oi})
-		if sz >= 0 {
-			return sizeSummary{totalSize: sz}, nil
+		if sz < 0 {
+			sz = fi.Size()
 		}
-
-		return sizeSummary{totalSize: fi.Size()}, nil
+		sizeS := sizeSummary{totalSize: sz}
+		sizeS.addOwnerSize(oi, sz)
+		return sizeS, nil
 	})
 
 	return cache, err
@@ -391,10 +396,6 @@ func (fs *FSObjects) statBucketDir(ctx context.Context, bucket string) (os.FileI
 
 // MakeBucketWithLocation - create a new bucket, returns if it already exists.
 func (fs *FSObjects) MakeBucketWithLocation(ctx context.Context, bucket string, opts BucketOptions) error {
-	if opts.LockEnabled || opts.VersioningEnabled {
-		return NotImplemented{}
-	}
-
 	// Verify if bucket is valid.
 	if s3utils.CheckValidBucketNameStrict(bucket) != nil {
 		return BucketNameInvalid{Bucket: bucket}
@@ -416,6 +417,11 @@ func (fs *FSObjects) MakeBucketWithLocation(ctx context.Context, bucket string,
 	}
 
 	meta := newBucketMetadata(bucket)
+	if opts.LockEnabled {
+		meta.VersioningConfigXML = enabledBucketVersioningConfig
+		meta.ObjectLockConfigXML = enabledBucketObjectLockConfig
+	}
+
 	if err := meta.Save(ctx, fs); err != nil {
 		return toObjectErr(err, bucket)
 	}
@@ -557,6 +563,16 @@ func (fs *FSObjects) DeleteBucket(ctx context.Context, bucket string, forceDelet
 	}
 
 	if !forceDelete {
+		// Noncurrent versions and delete markers are not in the
+		// namespace, a bucket holding them is not empty.
+		loi, err := fs.ListObjectVersions(ctx, bucket, "", "", "", "", 1)
+		if err != nil {
+			return toObjectErr(err, bucket)
+		}
+		if len(loi.Objects) > 0 || len(loi.Prefixes) > 0 {
+			return BucketNotEmpty{Bucket: bucket}
+		}
+
 		// Attempt to delete regular bucket.
 		if err = fsRemoveDir(ctx, bucketDir); err != nil {
 			return toObjectErr(err, bucket)
@@ -590,14 +606,6 @@ func (fs *FSObjects) DeleteBucket(ctx context.Context, bucket string, forceDelet
 // if source object and destination object are same we only
 // update metadata.
 func (fs *FSObjects) CopyObject(ctx context.Context, srcBucket, srcObject, dstBucket, dstObject string, srcInfo ObjectInfo, srcOpts, dstOpts ObjectOptions) (oi ObjectInfo, e error) {
-	if srcOpts.VersionID != "" && srcOpts.VersionID != nullVersionID {
-		return oi, VersionNotFound{
-			Bucket:    srcBucket,
-			Object:    srcObject,
-			VersionID: srcOpts.VersionID,
-		}
-	}
-
 	cpSrcDstSame := isStringEqual(pathJoin(srcBucket, srcObject), pathJoin(dstBucket, dstObject))
 	defer ObjectPathUpdated(path.Join(dstBucket, dstObject))
 
@@ -618,7 +626,21 @@ func (fs *FSObjects) CopyObject(ctx context.Context, srcBucket, srcObject, dstBu
 		return oi, toObjectErr(err, srcBucket)
 	}
 
-	if cpSrcDstSame && srcInfo.metadataOnly {
+	// Metadata of a version is updated in place, unless the copy adds
+	// a new version of the object.
+	if cpSrcDstSame && srcInfo.metadataOnly &&
+		(dstOpts.VersionID != "" && srcOpts.VersionID == dstOpts.VersionID || !dstOpts.Versioned && srcOpts.VersionID == "") {
+		if srcOpts.VersionID != "" {
+			oi, found, err := fs.putVersionMeta(ctx, srcBucket, srcObject, srcOpts.VersionID, func(map[string]string) map[string]string {
+				meta := cloneMSS(srcInfo.UserDefined)
+				meta["etag"] = srcInfo.ETag
+				return meta
+			})
+			if found || err != nil {
+				return oi, err
+			}
+		}
+
 		fsMetaPath := pathJoin(fs.fsPath, minioMetaBucket, bucketMetaPrefix, srcBucket, srcObject, fs.metaJSONFile)
 		wlk, err := fs.rwPool.Write(fsMetaPath)
 		if err != nil {
@@ -658,7 +680,13 @@ func (fs *FSObjects) CopyObject(ctx context.Context, srcBucket, srcObject, dstBu
 		return ObjectInfo{}, err
 	}
 
-	objInfo, err := fs.putObject(ctx, dstBucket, dstObject, srcInfo.PutObjReader, ObjectOptions{ServerSideEncryption: dstOpts.ServerSideEncryption, UserDefined: srcInfo.UserDefined})
+	objInfo, err := fs.putObject(ctx, dstBucket, dstObject, srcInfo.PutObjReader, ObjectOptions{
+		ServerSideEncryption: dstOpts.ServerSideEncryption,
+		UserDefined:          srcInfo.UserDefined,
+		Versioned:            dstOpts.Versioned,
+		VersionID:            dstOpts.VersionID,
+		MTime:                dstOpts.MTime,
+	})
 	if err != nil {
 		return oi, toObjectErr(err, dstBucket, dstObject)
 	}
@@ -669,13 +697,6 @@ func (fs *FSObjects) CopyObject(ctx context.Context, srcBucket, srcObject, dstBu
 // GetObjectNInfo - returns object info and a reader for object
 // content.
 func (fs *FSObjects) GetObjectNInfo(ctx context.Context, bucket, object string, rs *HTTPRangeSpec, h http.Header, lockType LockType, opts ObjectOptions) (gr *GetObjectReader, err error) {
-	if opts.VersionID != "" && opts.VersionID != nullVersionID {
-		return nil, VersionNotFound{
-			Bucket:    bucket,
-			Object:    object,
-			VersionID: opts.VersionID,
-		}
-	}
 	if err = checkGetObjArgs(ctx, bucket, object); err != nil {
 		return nil, err
 	}
@@ -709,11 +730,19 @@ func (fs *FSObjects) GetObjectNInfo(ctx context.Context, bucket, object string,
 	}
 
 	// Otherwise we get the object info
-	var objInfo ObjectInfo
-	if objInfo, err = fs.getObjectInfo(ctx, bucket, object); err != nil {
+	objInfo, version, err := fs.getObjectVersionInfo(ctx, bucket, object, opts.VersionID)
+	if err != nil {
 		nsUnlocker()
+		if version != nil {
+			// Make sure to return object info to provide extra information.
+			return &GetObjectReader{ObjInfo: objInfo}, toObjectErr(err, bucket, object)
+		}
 		return nil, toObjectErr(err, bucket, object)
 	}
+	if version != nil && version.DeleteMarker {
+		nsUnlocker()
+		return &GetObjectReader{ObjInfo: objInfo}, toObjectErr(errMethodNotAllowed, bucket, object)
+	}
 	// For a directory, we need to return a reader that returns no bytes.
 	if HasSuffix(object, SlashSeparator) {
 		// The lock taken above is released when
@@ -742,6 +771,9 @@ func (fs *FSObjects) GetObjectNInfo(ctx context.Context, bucket, object string,
 
 	// Read the object, doesn't exist returns an s3 compatible error.
 	fsObjPath := pathJoin(fs.fsPath, bucket, object)
+	if version != nil {
+		fsObjPath = fs.getVersionDataPath(bucket, object, version.DataFile)
+	}
 	readCloser, size, err := fsOpenFile(ctx, fsObjPath, off)
 	if err != nil {
 		rwPoolUnlocker()
@@ -774,14 +806,6 @@ func (fs *FSObjects) GetObjectNInfo(ctx context.Context, bucket, object string,
 // startOffset indicates the starting read location of the object.
 // length indicates the total length of the object.
 func (fs *FSObjects) GetObject(ctx context.Context, bucket, object string, offset int64, length int64, writer io.Writer, etag string, opts ObjectOptions) (err error) {
-	if opts.VersionID != "" && opts.VersionID != nullVersionID {
-		return VersionNotFound{
-			Bucket:    bucket,
-			Object:    object,
-			VersionID: opts.VersionID,
-		}
-	}
-
 	if err = checkGetObjArgs(ctx, bucket, object); err != nil {
 		return err
 	}
@@ -799,6 +823,22 @@ func (fs *FSObjects) GetObject(ctx context.Context, bucket, object string, offse
 		atomic.AddInt64(&fs.activeIOCount, -1)
 	}()
 
+	if opts.VersionID != "" {
+		if _, err = fs.statBucketDir(ctx, bucket); err != nil {
+			return toObjectErr(err, bucket)
+		}
+		_, version, err := fs.getObjectVersionInfo(ctx, bucket, object, opts.VersionID)
+		if err != nil {
+			return toObjectErr(err, bucket, object)
+		}
+		if version != nil {
+			if version.DeleteMarker {
+				return toObjectErr(errMethodNotAllowed, bucket, object)
+			}
+			return fs.getObjectVersion(ctx, bucket, object, *version, offset, length, writer, etag)
+		}
+	}
+
 	return fs.getObject(ctx, bucket, object, offset, length, writer, etag, true)
 }
 
@@ -1005,7 +1045,7 @@ func (fs *FSObjects) getObjectInfo(ctx context.Context, bucket, object string) (
 }
 
 // getObjectInfoWithLock - reads object metadata and replies back ObjectInfo.
-func (fs *FSObjects) getObjectInfoWithLock(ctx context.Context, bucket, object string) (oi ObjectInfo, e error) {
+func (fs *FSObjects) getObjectInfoWithLock(ctx context.Context, bucket, object, versionID string) (oi ObjectInfo, e error) {
 	// Lock the object before reading.
 	lk := fs.NewNSLock(bucket, object)
 	if err := lk.GetRLock(ctx, globalOperationTimeout); err != nil {
@@ -1025,25 +1065,22 @@ func (fs *FSObjects) getObjectInfoWithLock(ctx context.Context, bucket, object s
 		return oi, errFileNotFound
 	}
 
-	return fs.getObjectInfo(ctx, bucket, object)
+	oi, version, err := fs.getObjectVersionInfo(ctx, bucket, object, versionID)
+	if err == nil && version != nil && version.DeleteMarker {
+		// Make sure to return object info to provide extra information.
+		return oi, errMethodNotAllowed
+	}
+	return oi, err
 }
 
 // GetObjectInfo - reads object metadata and replies back ObjectInfo.
 func (fs *FSObjects) GetObjectInfo(ctx context.Context, bucket, object string, opts ObjectOptions) (oi ObjectInfo, e error) {
-	if opts.VersionID != "" && opts.VersionID != nullVersionID {
-		return oi, VersionNotFound{
-			Bucket:    bucket,
-			Object:    object,
-			VersionID: opts.VersionID,
-		}
-	}
-
 	atomic.AddInt64(&fs.activeIOCount, 1)
 	defer func() {
 		atomic.AddInt64(&fs.activeIOCount, -1)
 	}()
 
-	oi, err := fs.getObjectInfoWithLock(ctx, bucket, object)
+	oi, err := fs.getObjectInfoWithLock(ctx, bucket, object, opts.VersionID)
 	if err == errCorruptedFormat || err == io.EOF {
 		lk := fs.NewNSLock(bucket, object)
 		if err = lk.GetLock(ctx, globalOperationTimeout); err != nil {
@@ -1057,7 +1094,7 @@ func (fs *FSObjects) GetObjectInfo(ctx context.Context, bucket, object string, o
 			return oi, toObjectErr(err, bucket, object)
 		}
 
-		oi, err = fs.getObjectInfoWithLock(ctx, bucket, object)
+		oi, err = fs.getObjectInfoWithLock(ctx, bucket, object, opts.VersionID)
 	}
 	return oi, toObjectErr(err, bucket, object)
 }
@@ -1087,10 +1124,6 @@ func (fs *FSObjects) parentDirIsObject(ctx context.Context, bucket, parent strin
 // Additionally writes `fs.json` which carries the necessary metadata
 // for future object operations.
 func (fs *FSObjects) PutObject(ctx context.Context, bucket string, object string, r *PutObjReader, opts ObjectOptions) (objInfo ObjectInfo, retErr error) {
-	if opts.Versioned {
-		return objInfo, NotImplemented{}
-	}
-
 	if err := checkPutObjectArgs(ctx, bucket, object, fs); err != nil {
 		return ObjectInfo{}, err
 	}
@@ -1159,7 +1192,11 @@ func (fs *FSObjects) putObject(ctx context.Context, bucket string, object string
 	}
 
 	var wlk *lock.LockedFile
+	// Metadata of the version being replaced.
+	curMeta := fs.defaultFsJSON(object)
 	if bucket != minioMetaBucket {
+		fsMeta.VersionID = fsNewVersionID(opts)
+
 		bucketMetaDir := pathJoin(fs.fsPath, minioMetaBucket, bucketMetaPrefix)
 		fsMetaPath := pathJoin(bucketMetaDir, bucket, object, fs.metaJSONFile)
 		wlk, err = fs.rwPool.Write(fsMetaPath)
@@ -1171,6 +1208,11 @@ func (fs *FSObjects) putObject(ctx context.Context, bucket string, object string
 				return ObjectInfo{}, toObjectErr(err, bucket, object)
 			}
 			freshFile = true
+		} else {
+			var m fsMetaV1
+			if _, err = m.ReadFrom(ctx, wlk); err == nil {
+				curMeta = m
+			}
 		}
 		// This close will allow for locks to be synchronized on `fs.json`.
 		defer wlk.Close()
@@ -1213,7 +1255,12 @@ func (fs *FSObjects) putObject(ctx context.Context, bucket string, object string
 
 	// Entire object was written to the temp location, now it's safe to rename it to the actual location.
 	fsNSObjPath := pathJoin(fs.fsPath, bucket, object)
-	if err = fsRenameFile(ctx, fsTmpObjPath, fsNSObjPath); err != nil {
+	if bucket != minioMetaBucket {
+		err = fs.renameVersion(ctx, bucket, object, fsTmpObjPath, curMeta, fsMeta.VersionID)
+	} else {
+		err = fsRenameFile(ctx, fsTmpObjPath, fsNSObjPath)
+	}
+	if err != nil {
 		return ObjectInfo{}, toObjectErr(err, bucket, object)
 	}
 
@@ -1224,6 +1271,13 @@ func (fs *FSObjects) putObject(ctx context.Context, bucket string, object string
 		}
 	}
 
+	// Preserve the modification time of a replicated or copied version.
+	if !opts.MTime.IsZero() {
+		if err = os.Chtimes(fsNSObjPath, opts.MTime, opts.MTime); err != nil {
+			logger.LogIf(ctx, err)
+		}
+	}
+
 	// Stat the file to fetch timestamp, size.
 	fi, err := fsStatFile(ctx, pathJoin(fs.fsPath, bucket, object))
 	if err != nil {
@@ -1240,18 +1294,24 @@ func (fs *FSObjects) DeleteObjects(ctx context.Context, bucket string, objects [
 	errs := make([]error, len(objects))
 	dobjects := make([]DeletedObject, len(objects))
 	for idx, object := range objects {
-		if object.VersionID != "" {
-			errs[idx] = VersionNotFound{
-				Bucket:    bucket,
-				Object:    object.ObjectName,
-				VersionID: object.VersionID,
-			}
-			continue
+		objOpts := opts
+		objOpts.VersionID = object.VersionID
+		if opts.PrefixEnabledFn != nil {
+			objOpts.Versioned = opts.PrefixEnabledFn(object.ObjectName)
+		}
+		if opts.PrefixSuspendedFn != nil {
+			objOpts.VersionSuspended = opts.PrefixSuspendedFn(object.ObjectName)
 		}
-		_, errs[idx] = fs.DeleteObject(ctx, bucket, object.ObjectName, opts)
+		var objInfo ObjectInfo
+		objInfo, errs[idx] = fs.DeleteObject(ctx, bucket, object.ObjectName, objOpts)
 		if errs[idx] == nil || isErrObjectNotFound(errs[idx]) {
 			dobjects[idx] = DeletedObject{
 				ObjectName: object.ObjectName,
+				VersionID:  object.VersionID,
+			}
+			if object.VersionID == "" && objInfo.DeleteMarker {
+				dobjects[idx].DeleteMarker = true
+				dobjects[idx].DeleteMarkerVersionID = objInfo.VersionID
 			}
 			errs[idx] = nil
 		}
@@ -1262,14 +1322,6 @@ func (fs *FSObjects) DeleteObjects(ctx context.Context, bucket string, objects [
 // DeleteObject - deletes an object from a bucket, this operation is destructive
 // and there are no rollbacks supported.
 func (fs *FSObjects) DeleteObject(ctx context.Context, bucket, object string, opts ObjectOptions) (objInfo ObjectInfo, err error) {
-	if opts.VersionID != "" && opts.VersionID != nullVersionID {
-		return objInfo, VersionNotFound{
-			Bucket:    bucket,
-			Object:    object,
-			VersionID: opts.VersionID,
-		}
-	}
-
 	// Acquire a write lock before deleting the object.
 	lk := fs.NewNSLock(bucket, object)
 	if err = lk.GetLock(ctx, globalOperationTimeout); err != nil {
@@ -1292,6 +1344,12 @@ func (fs *FSObjects) DeleteObject(ctx context.Context, bucket, object string, op
 		return objInfo, toObjectErr(err, bucket)
 	}
 
+	// Directories are not versioned.
+	if bucket != minioMetaBucket && !HasSuffix(object, SlashSeparator) &&
+		(opts.VersionID != "" || opts.Versioned || opts.VersionSuspended) {
+		return fs.deleteObjectVersion(ctx, bucket, object, opts)
+	}
+
 	var rwlk *lock.LockedFile
 
 	minioMetaBucketDir := pathJoin(fs.fsPath, minioMetaBucket)
@@ -1440,9 +1498,87 @@ func (fs *FSObjects) getObjectETag(ctx context.Context, bucket, entry string, lo
 	return extractETag(fsMeta.Meta), nil
 }
 
-// ListObjectVersions not implemented for FS mode.
+// ListObjectVersions - list all the versions of the objects at prefix upto maxKeys,
+// optionally delimited by '/'.
 func (fs *FSObjects) ListObjectVersions(ctx context.Context, bucket, prefix, marker, versionMarker, delimiter string, maxKeys int) (loi ListObjectVersionsInfo, e error) {
-	return loi, NotImplemented{}
+	if marker == "" && versionMarker != "" {
+		return loi, NotImplemented{}
+	}
+
+	atomic.AddInt64(&fs.activeIOCount, 1)
+	defer func() {
+		atomic.AddInt64(&fs.activeIOCount, -1)
+	}()
+
+	// Over flowing count - reset to maxObjectList.
+	if maxKeys < 0 || maxKeys > maxObjectList {
+		maxKeys = maxObjectList
+	}
+
+	// Versions are listed after listing the objects.
+	getObjInfo := func(ctx context.Context, bucket, object string) (ObjectInfo, error) {
+		return ObjectInfo{Bucket: bucket, Name: object}, nil
+	}
+	lo, err := listObjects(ctx, fs, bucket, prefix, marker, delimiter, maxKeys, fs.versionsListPool,
+		fs.listVersionsDirFactory(), fs.isLeaf, fs.isLeafDir, getObjInfo, fs.getObjectInfoNoFSLock)
+	if err != nil {
+		return loi, err
+	}
+
+	var objects []ObjectInfo
+	if versionMarker != "" && HasPrefix(marker, prefix) {
+		// Versions of the marker object after the version marker.
+		versions, err := fs.getObjectVersions(ctx, bucket, marker)
+		if err != nil {
+			return loi, toObjectErr(err, bucket, marker)
+		}
+		for i := range versions {
+			if fsVersionMatches(versions[i].VersionID, versionMarker) {
+				objects = append(objects, versions[i+1:]...)
+				break
+			}
+		}
+	}
+
+	entries := lo.Objects
+	prefixes := make(map[string]struct{}, len(lo.Prefixes))
+	for _, p := range lo.Prefixes {
+		entries = append(entries, ObjectInfo{Bucket: bucket, Name: p, IsDir: true})
+		prefixes[p] = struct{}{}
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Name < entries[j].Name
+	})
+	for _, entry := range entries {
+		if entry.IsDir {
+			objects = append(objects, entry)
+			continue
+		}
+		versions, err := fs.getObjectVersions(ctx, bucket, entry.Name)
+		if err != nil {
+			return loi, toObjectErr(err, bucket, entry.Name)
+		}
+		objects = append(objects, versions...)
+	}
+
+	loi.IsTruncated = lo.IsTruncated
+	if len(objects) > maxKeys {
+		objects = objects[:maxKeys]
+		loi.IsTruncated = true
+	}
+	for _, obj := range objects {
+		if _, ok := prefixes[obj.Name]; ok && obj.IsDir {
+			loi.Prefixes = append(loi.Prefixes, obj.Name)
+		} else {
+			loi.Objects = append(loi.Objects, obj)
+		}
+	}
+	if loi.IsTruncated && len(objects) > 0 {
+		last := objects[len(objects)-1]
+		loi.NextMarker = last.Name
+		loi.NextVersionIDMarker = last.VersionID
+	}
+	return loi, nil
 }
 
 // ListObjects - list all objects at prefix upto maxKeys., optionally delimited by '/'. Maintains the list pool
@@ -1459,14 +1595,7 @@ func (fs *FSObjects) ListObjects(ctx context.Context, bucket, prefix, marker, de
 
 // GetObjectTags - get object tags from an existing object
 func (fs *FSObjects) GetObjectTags(ctx context.Context, bucket, object string, opts ObjectOptions) (*tags.Tags, error) {
-	if opts.VersionID != "" && opts.VersionID != nullVersionID {
-		return nil, VersionNotFound{
-			Bucket:    bucket,
-			Object:    object,
-			VersionID: opts.VersionID,
-		}
-	}
-	oi, err := fs.GetObjectInfo(ctx, bucket, object, ObjectOptions{})
+	oi, err := fs.GetObjectInfo(ctx, bucket, object, ObjectOptions{VersionID: opts.VersionID})
 	if err != nil {
 		return nil, err
 	}
@@ -1476,11 +1605,22 @@ func (fs *FSObjects) GetObjectTags(ctx context.Context, bucket, object string, o
 
 // PutObjectTags - replace or add tags to an existing object
 func (fs *FSObjects) PutObjectTags(ctx context.Context, bucket, object string, tags string, opts ObjectOptions) (ObjectInfo, error) {
-	if opts.VersionID != "" && opts.VersionID != nullVersionID {
-		return ObjectInfo{}, VersionNotFound{
-			Bucket:    bucket,
-			Object:    object,
-			VersionID: opts.VersionID,
+	if opts.VersionID != "" {
+		lk := fs.NewNSLock(bucket, object)
+		if err := lk.GetLock(ctx, globalOperationTimeout); err != nil {
+			return ObjectInfo{}, err
+		}
+		defer lk.Unlock()
+
+		oi, found, err := fs.putVersionMeta(ctx, bucket, object, opts.VersionID, func(meta map[string]string) map[string]string {
+			delete(meta, xhttp.AmzObjectTagging)
+			if tags != "" {
+				meta[xhttp.AmzObjectTagging] = tags
+			}
+			return meta
+		})
+		if found || err != nil {
+			return oi, err
 		}
 	}
 
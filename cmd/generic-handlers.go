@@ -17,19 +17,28 @@
 package cmd
 
 import (
+	"bufio"
 	"context"
+	"errors"
+	"io"
+	"math"
+	"net"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/minio/minio-go/v7/pkg/set"
 
 	humanize "github.com/dustin/go-humanize"
+	"github.com/minio/minio/cmd/config/api"
 	"github.com/minio/minio/cmd/config/dns"
 	"github.com/minio/minio/cmd/crypto"
 	xhttp "github.com/minio/minio/cmd/http"
 	"github.com/minio/minio/cmd/http/stats"
 	"github.com/minio/minio/cmd/logger"
+	"github.com/minio/minio/pkg/handlers"
+	"github.com/minio/minio/pkg/ratelimit"
 )
 
 // Adds limiting body size middleware
@@ -373,7 +382,6 @@ var notImplementedBucketResourceNames = map[string]struct{}{
 	"metrics":        {},
 	"website":        {},
 	"logging":        {},
-	"inventory":      {},
 	"accelerate":     {},
 	"requestPayment": {},
 }
@@ -532,6 +540,144 @@ func setRequestValidityHandler(h http.Handler) http.Handler {
 	})
 }
 
+// rateLimitKey returns the key the request is rate limited by, empty
+// if the request is not rate limited.
+func (l *rateLimiter) rateLimitKey(r *http.Request) string {
+	switch l.by {
+	case api.RateLimitByAccessKey:
+		if accessKey := verifiedAccessKey(r); accessKey != "" {
+			return accessKey
+		}
+		// Anonymous requests, and requests whose signature cannot be
+		// verified, are limited by source IP.
+		return handlers.GetSourceIP(r)
+	case api.RateLimitByBucket:
+		bucket, _ := request2BucketObjectName(r)
+		return bucket
+	case api.RateLimitBySourceIP:
+		return handlers.GetSourceIP(r)
+	}
+	return ""
+}
+
+// verifiedAccessKey returns the access key of the request if it is signed
+// by it, empty otherwise. Only the signature is verified, the payload is
+// verified as it is read by the handlers.
+func verifiedAccessKey(r *http.Request) string {
+	s3Err := ErrAccessDenied
+	switch getRequestAuthType(r) {
+	case authTypeSigned, authTypePresigned, authTypeStreamingSigned:
+		s3Err = reqSignatureV4Verify(r, globalServerRegion, serviceS3)
+	case authTypeSignedV2, authTypePresignedV2:
+		s3Err = isReqAuthenticatedV2(r)
+	case authTypeJWT:
+		if _, _, err := webRequestAuthenticate(r); err == nil {
+			s3Err = ErrNone
+		}
+	}
+	if s3Err != ErrNone {
+		return ""
+	}
+	return getReqAccessCred(r, globalServerRegion).AccessKey
+}
+
+// rateLimitedReader charges the bytes read from the request body to
+// the bytes rate limit of the request.
+type rateLimitedReader struct {
+	io.ReadCloser
+	limiter *ratelimit.Limiter
+}
+
+func (r *rateLimitedReader) Read(p []byte) (n int, err error) {
+	n, err = r.ReadCloser.Read(p)
+	r.limiter.Charge(time.Now(), float64(n))
+	return n, err
+}
+
+// rateLimitedWriter charges the bytes written to the response to
+// the bytes rate limit of the request.
+type rateLimitedWriter struct {
+	http.ResponseWriter
+	limiter *ratelimit.Limiter
+}
+
+func (w *rateLimitedWriter) Write(p []byte) (n int, err error) {
+	n, err = w.ResponseWriter.Write(p)
+	w.limiter.Charge(time.Now(), float64(n))
+	return n, err
+}
+
+// Flush calls the underlying Flush, if any.
+func (w *rateLimitedWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Hijack calls the underlying Hijack, the bytes of a hijacked
+// connection are not charged.
+func (w *rateLimitedWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	if h, ok := w.ResponseWriter.(http.Hijacker); ok {
+		return h.Hijack()
+	}
+	return nil, nil, errors.New("response writer does not support hijacking")
+}
+
+// CloseNotify calls the underlying CloseNotify, the returned channel
+// never fires if the underlying writer does not support it.
+func (w *rateLimitedWriter) CloseNotify() <-chan bool {
+	if cn, ok := w.ResponseWriter.(http.CloseNotifier); ok {
+		return cn.CloseNotify()
+	}
+	return make(chan bool)
+}
+
+// setRateLimitHandler rejects the S3 API requests exceeding the request or
+// bytes rate limits of their access key, bucket or source IP with SlowDown.
+func setRateLimitHandler(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		limiter := globalAPIConfig.getRateLimiter()
+		if limiter == nil || guessIsHealthCheckReq(r) || guessIsMetricsReq(r) ||
+			guessIsRPCReq(r) || guessIsLoginSTSReq(r) || isAdminReq(r) ||
+			strings.HasPrefix(r.URL.Path, minioReservedBucketPath) {
+			h.ServeHTTP(w, r)
+			return
+		}
+
+		key := limiter.rateLimitKey(r)
+		if key == "" {
+			h.ServeHTTP(w, r)
+			return
+		}
+
+		now := time.Now()
+		var wait time.Duration
+		// Bytes are charged as they are transferred, requests are
+		// rejected until the bytes owed by the key are refilled.
+		var bytesLimiter *ratelimit.Limiter
+		if limiter.bytes != nil {
+			bytesLimiter = limiter.bytes.Get(key, now)
+			wait = bytesLimiter.Take(now, 0)
+		}
+		if wait == 0 && limiter.requests != nil {
+			wait = limiter.requests.Get(key, now).Take(now, 1)
+		}
+		if wait > 0 {
+			w.Header().Set(xhttp.RetryAfter, strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+			writeErrorResponse(r.Context(), w, errorCodes.ToAPIErr(ErrSlowDown), r.URL, guessIsBrowserReq(r))
+			return
+		}
+
+		if bytesLimiter != nil {
+			if r.Body != nil {
+				r.Body = &rateLimitedReader{ReadCloser: r.Body, limiter: bytesLimiter}
+			}
+			w = &rateLimitedWriter{ResponseWriter: w, limiter: bytesLimiter}
+		}
+		h.ServeHTTP(w, r)
+	})
+}
+
 // setBucketForwardingHandler middleware forwards the path style requests
 // on a bucket to the right bucket location, bucket to IP configuration
 // is obtained from centralized etcd configuration service.
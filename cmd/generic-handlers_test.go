@@ -20,11 +20,15 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"os"
 	"strconv"
 	"testing"
+	"time"
 
+	"github.com/minio/minio/cmd/config/api"
 	"github.com/minio/minio/cmd/crypto"
 	xhttp "github.com/minio/minio/cmd/http"
+	"github.com/minio/minio/pkg/ratelimit"
 )
 
 // Tests getRedirectLocation function for all its criteria.
@@ -250,3 +254,112 @@ func TestSSETLSHandler(t *testing.T) {
 		}
 	}
 }
+
+func TestRateLimitHandler(t *testing.T) {
+	defer func(l *rateLimiter) { globalAPIConfig.rateLimiter = l }(globalAPIConfig.rateLimiter)
+	globalAPIConfig.rateLimiter = newRateLimiter(api.Config{
+		RateLimitBy:       api.RateLimitBySourceIP,
+		RateLimitRequests: 1,
+	})
+
+	var okHandler http.HandlerFunc = func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}
+	h := setRateLimitHandler(okHandler)
+
+	testCases := []struct {
+		remoteAddr string
+		path       string
+		code       int
+	}{
+		{"10.0.0.1:9000", "/bucket/object", http.StatusOK},
+		{"10.0.0.1:9000", "/bucket/object", http.StatusServiceUnavailable},
+		// Other source IPs are limited independently.
+		{"10.0.0.2:9000", "/bucket/object", http.StatusOK},
+		// Admin requests are not limited.
+		{"10.0.0.1:9000", adminPathPrefix + adminAPIVersionPrefix + "/info", http.StatusOK},
+	}
+	for i, testCase := range testCases {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, testCase.path, nil)
+		r.RemoteAddr = testCase.remoteAddr
+		h.ServeHTTP(w, r)
+		if w.Code != testCase.code {
+			t.Errorf("Test %d: expected HTTP %d, got HTTP %d", i+1, testCase.code, w.Code)
+		}
+		if w.Code == http.StatusServiceUnavailable && w.Header().Get(xhttp.RetryAfter) != "1" {
+			t.Errorf("Test %d: expected Retry-After 1, got %q", i+1, w.Header().Get(xhttp.RetryAfter))
+		}
+	}
+}
+
+func TestRateLimitKey(t *testing.T) {
+	obj, fsDir, err := prepareFS()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(fsDir)
+	if err = newTestConfig(globalMinioDefaultRegion, obj); err != nil {
+		t.Fatal(err)
+	}
+
+	l := newRateLimiter(api.Config{
+		RateLimitBy:       api.RateLimitByAccessKey,
+		RateLimitRequests: 1,
+	})
+	cred := globalActiveCred
+
+	signed, err := newTestSignedRequestV4(http.MethodGet, "/bucket/object", 0, nil, cred.AccessKey, cred.SecretKey, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Requests not signed with the secret key of the access key
+	// are not charged to it.
+	forged, err := newTestSignedRequestV4(http.MethodGet, "/bucket/object", 0, nil, cred.AccessKey, "forged-secret-key", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	anonymous := httptest.NewRequest(http.MethodGet, "/bucket/object", nil)
+
+	testCases := []struct {
+		r    *http.Request
+		want string
+	}{
+		{signed, cred.AccessKey},
+		{forged, "10.0.0.1"},
+		{anonymous, "10.0.0.1"},
+	}
+	for i, testCase := range testCases {
+		testCase.r.RemoteAddr = "10.0.0.1:9000"
+		if got := l.rateLimitKey(testCase.r); got != testCase.want {
+			t.Errorf("Test %d: expected key %q, got %q", i+1, testCase.want, got)
+		}
+	}
+}
+
+// plainResponseWriter implements neither http.Flusher nor http.Hijacker.
+type plainResponseWriter struct {
+	http.ResponseWriter
+}
+
+func TestRateLimitedWriter(t *testing.T) {
+	limiter := ratelimit.NewLimiter(1<<20, 1<<20, time.Now())
+
+	w := &rateLimitedWriter{ResponseWriter: plainResponseWriter{httptest.NewRecorder()}, limiter: limiter}
+	w.Flush()
+	if _, _, err := w.Hijack(); err == nil {
+		t.Fatal("expected hijacking an unsupported writer to fail")
+	}
+	select {
+	case <-w.CloseNotify():
+		t.Fatal("unexpected close notification")
+	default:
+	}
+
+	rec := httptest.NewRecorder()
+	w = &rateLimitedWriter{ResponseWriter: rec, limiter: limiter}
+	w.Flush()
+	if !rec.Flushed {
+		t.Fatal("expected the underlying writer to be flushed")
+	}
+}
@@ -42,6 +42,7 @@ import (
 	"github.com/minio/minio/pkg/certs"
 	"github.com/minio/minio/pkg/event"
 	"github.com/minio/minio/pkg/pubsub"
+	etcd "go.etcd.io/etcd/client/v3"
 )
 
 // minio configuration related constants.
@@ -98,6 +99,9 @@ const (
 	// Limit of location constraint XML for unauthenticted PUT bucket operations.
 	maxLocationConstraintSize = 3 * humanize.MiByte
 
+	// Maximum size of the tags XML read for the object tag conditions.
+	maxObjectTaggingSize = 1 * humanize.MiByte
+
 	// Maximum size of default bucket encryption configuration allowed
 	maxBucketSSEConfigSize = 1 * humanize.MiByte
 
@@ -221,6 +225,9 @@ var (
 
 	globalBucketObjectLockSys *BucketObjectLockSys
 	globalBucketQuotaSys      *BucketQuotaSys
+	globalUserQuotaSys        *UserQuotaSys
+	globalBatchJobsSys        *BatchJobsSys
+	globalBandwidthLimitsSys  *BandwidthLimitsSys
 	globalBucketVersioningSys *BucketVersioningSys
 
 	// Disk cache drives
@@ -230,7 +237,7 @@ var (
 	globalCacheKMS crypto.KMS
 
 	// Allocated etcd endpoint for config and bucket DNS.
-	globalEtcdClient interface{}
+	globalEtcdClient *etcd.Client
 
 	// Is set to true when Bucket federation is requested
 	// and is 'true' when etcdConfig.PathPrefix is empty
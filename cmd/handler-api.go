@@ -23,6 +23,7 @@ import (
 
 	"github.com/minio/minio/cmd/config/api"
 	"github.com/minio/minio/cmd/logger"
+	"github.com/minio/minio/pkg/ratelimit"
 	"github.com/minio/minio/pkg/sys"
 )
 
@@ -38,6 +39,7 @@ type apiConfig struct {
 	// total drives per erasure set across pools.
 	totalDriveCount    int
 	replicationWorkers int
+	rateLimiter        *rateLimiter
 }
 
 func (t *apiConfig) init(cfg api.Config, setDriveCounts []int) {
@@ -80,6 +82,10 @@ func (t *apiConfig) init(cfg api.Config, setDriveCounts []int) {
 	t.listQuorum = cfg.GetListQuorum()
 	t.extendListLife = cfg.ExtendListLife
 	t.replicationWorkers = cfg.ReplicationWorkers
+	if !t.rateLimiter.sameConfig(cfg) {
+		// Changing the limits resets the token buckets.
+		t.rateLimiter = newRateLimiter(cfg)
+	}
 }
 
 func (t *apiConfig) getListQuorum() int {
@@ -160,6 +166,54 @@ func maxClients(f http.HandlerFunc) http.HandlerFunc {
 	}
 }
 
+func (t *apiConfig) getRateLimiter() *rateLimiter {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return t.rateLimiter
+}
+
+// rateLimiter limits the rate of the S3 API requests, and of the bytes
+// they send and receive, of each access key, bucket or source IP.
+type rateLimiter struct {
+	by           string
+	requestsRate uint64
+	bytesRate    uint64
+	requests     *ratelimit.KeyedLimiter // nil if requests are not limited.
+	bytes        *ratelimit.KeyedLimiter // nil if bytes are not limited.
+}
+
+// newRateLimiter returns the rate limiter of the config, nil if rate
+// limits are disabled. The token buckets hold one second worth of
+// requests and bytes.
+func newRateLimiter(cfg api.Config) *rateLimiter {
+	if cfg.RateLimitBy == "" || (cfg.RateLimitRequests == 0 && cfg.RateLimitBytes == 0) {
+		return nil
+	}
+	l := &rateLimiter{
+		by:           cfg.RateLimitBy,
+		requestsRate: cfg.RateLimitRequests,
+		bytesRate:    cfg.RateLimitBytes,
+	}
+	now := time.Now()
+	if cfg.RateLimitRequests > 0 {
+		rate := float64(cfg.RateLimitRequests)
+		l.requests = ratelimit.NewKeyedLimiter(rate, rate, now)
+	}
+	if cfg.RateLimitBytes > 0 {
+		rate := float64(cfg.RateLimitBytes)
+		l.bytes = ratelimit.NewKeyedLimiter(rate, rate, now)
+	}
+	return l
+}
+
+func (l *rateLimiter) sameConfig(cfg api.Config) bool {
+	if l == nil {
+		return newRateLimiter(cfg) == nil
+	}
+	return l.by == cfg.RateLimitBy && l.requestsRate == cfg.RateLimitRequests && l.bytesRate == cfg.RateLimitBytes
+}
+
 func (t *apiConfig) getReplicationWorkers() int {
 	t.mu.RLock()
 	defer t.mu.RUnlock()
@@ -165,6 +165,8 @@ const (
 	MinIODeleteMarkerReplicationStatus = "X-Minio-Replication-DeleteMarker-Status"
 	// Header indicates if its a GET/HEAD proxy request for active-active replication
 	MinIOSourceProxyRequest = "X-Minio-Source-Proxy-Request"
+	// Header reports why the last restore request of a transitioned object failed
+	MinIORestoreFailure = "X-Minio-Restore-Failure"
 )
 
 // Common http query params S3 API
@@ -0,0 +1,547 @@
+/*
+ * MinIO Cloud Storage, (C) 2021 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"path"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/minio/minio/cmd/logger"
+	"github.com/minio/minio/pkg/auth"
+	iampolicy "github.com/minio/minio/pkg/iam/policy"
+	"github.com/minio/minio/pkg/madmin"
+	etcd "go.etcd.io/etcd/client/v3"
+)
+
+// IAMEtcdStore implements IAMStorageAPI, the IAM entries are shared
+// by all deployments federated with the same etcd.
+type IAMEtcdStore struct {
+	sync.RWMutex
+
+	client *etcd.Client
+
+	// Object layer holding the IAM entries saved before etcd was
+	// configured, they are migrated to etcd.
+	objAPI ObjectLayer
+}
+
+func newIAMEtcdStore(client *etcd.Client, objAPI ObjectLayer) *IAMEtcdStore {
+	return &IAMEtcdStore{client: client, objAPI: objAPI}
+}
+
+func (ies *IAMEtcdStore) lock() {
+	ies.Lock()
+}
+
+func (ies *IAMEtcdStore) unlock() {
+	ies.Unlock()
+}
+
+func (ies *IAMEtcdStore) rlock() {
+	ies.RLock()
+}
+
+func (ies *IAMEtcdStore) runlock() {
+	ies.RUnlock()
+}
+
+// migrateFromObjectStore copies the IAM entries of the object store to
+// etcd, the user and group quotas included. Entries present in etcd
+// already, for example saved by another federated deployment, are kept.
+func (ies *IAMEtcdStore) migrateFromObjectStore(ctx context.Context) error {
+	// Bring the object store entries to the current format first.
+	if err := newIAMObjectStore(ies.objAPI).migrateBackendFormat(ctx); err != nil {
+		return err
+	}
+
+	for item := range listIAMConfigItems(ctx, ies.objAPI, iamConfigPrefix+SlashSeparator) {
+		if item.Err != nil {
+			return item.Err
+		}
+
+		itemPath := pathJoin(iamConfigPrefix, item.Item)
+		if itemPath == getIAMFormatFilePath() {
+			continue
+		}
+		// Entries are copied as they are, encrypted or not.
+		data, err := readConfig(ctx, ies.objAPI, itemPath)
+		if err != nil {
+			if err == errConfigNotFound {
+				continue
+			}
+			return err
+		}
+		if _, err = saveKeyEtcdIfAbsent(ctx, ies.client, itemPath, data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Should be called under config migration lock
+func (ies *IAMEtcdStore) migrateBackendFormat(ctx context.Context) error {
+	var iamFmt iamFormat
+	path := getIAMFormatFilePath()
+	if err := ies.loadIAMConfig(ctx, &iamFmt, path); err != nil {
+		switch err {
+		case errConfigNotFound:
+			// Need to migrate from the object store.
+		default:
+			return err
+		}
+	} else {
+		if iamFmt.Version >= iamFormatVersion1 {
+			// Nothing to do.
+			return nil
+		}
+		// This case should not happen
+		// (i.e. Version is 0 or negative.)
+		return errors.New("got an invalid IAM format version")
+	}
+
+	// Gateways have no IAM entries in their object layer.
+	if ies.objAPI != nil && !globalIsGateway {
+		if err := ies.migrateFromObjectStore(ctx); err != nil {
+			logger.LogIf(ctx, err)
+			return err
+		}
+	}
+
+	// Save iam format to version 1, after all entries are migrated.
+	if err := ies.saveIAMConfig(ctx, newIAMFormatVersion1(), path); err != nil {
+		logger.LogIf(ctx, err)
+		return err
+	}
+	return nil
+}
+
+func (ies *IAMEtcdStore) saveIAMConfig(ctx context.Context, item interface{}, path string, opts ...options) error {
+	data, err := json.Marshal(item)
+	if err != nil {
+		return err
+	}
+	if globalConfigEncrypted {
+		data, err = madmin.EncryptData(globalActiveCred.String(), data)
+		if err != nil {
+			return err
+		}
+	}
+	return saveKeyEtcd(ctx, ies.client, path, data, opts...)
+}
+
+// getIAMConfig decodes an IAM entry read from etcd.
+func getIAMConfig(item interface{}, data []byte) error {
+	var err error
+	if globalConfigEncrypted && !utf8.Valid(data) {
+		data, err = madmin.DecryptData(globalActiveCred.String(), bytes.NewReader(data))
+		if err != nil {
+			return err
+		}
+	}
+	return json.Unmarshal(data, item)
+}
+
+func (ies *IAMEtcdStore) loadIAMConfig(ctx context.Context, item interface{}, path string) error {
+	data, err := readKeyEtcd(ctx, ies.client, path)
+	if err != nil {
+		return err
+	}
+	return getIAMConfig(item, data)
+}
+
+func (ies *IAMEtcdStore) deleteIAMConfig(ctx context.Context, path string) error {
+	return deleteKeyEtcd(ctx, ies.client, path)
+}
+
+func (ies *IAMEtcdStore) loadPolicyDoc(ctx context.Context, policy string, m map[string]iampolicy.Policy) error {
+	var p iampolicy.Policy
+	err := ies.loadIAMConfig(ctx, &p, getPolicyDocPath(policy))
+	if err != nil {
+		if err == errConfigNotFound {
+			return errNoSuchPolicy
+		}
+		return err
+	}
+	m[policy] = p
+	return nil
+}
+
+func (ies *IAMEtcdStore) loadPolicyDocs(ctx context.Context, m map[string]iampolicy.Policy) error {
+	items, err := readKeysEtcd(ctx, ies.client, iamConfigPoliciesPrefix)
+	if err != nil {
+		return err
+	}
+	for item, data := range items {
+		var p iampolicy.Policy
+		if err = getIAMConfig(&p, data); err != nil {
+			return err
+		}
+		m[path.Dir(item)] = p
+	}
+	return nil
+}
+
+// addUser adds the credentials of a user identity read from etcd to m,
+// deleting the identity instead if it is expired.
+func (ies *IAMEtcdStore) addUser(ctx context.Context, user string, userType IAMUserType, u UserIdentity, m map[string]auth.Credentials) error {
+	if u.Credentials.IsExpired() {
+		// Delete expired identity - ignoring errors here.
+		ies.deleteIAMConfig(ctx, getUserIdentityPath(user, userType))
+		ies.deleteIAMConfig(ctx, getMappedPolicyPath(user, userType, false))
+		return nil
+	}
+
+	// If this is a service account, rotate the session key if needed
+	if rotateSessionToken(&u) {
+		if err := ies.saveIAMConfig(ctx, &u, getUserIdentityPath(user, userType)); err != nil {
+			return err
+		}
+	}
+
+	if u.Credentials.AccessKey == "" {
+		u.Credentials.AccessKey = user
+	}
+
+	m[user] = u.Credentials
+	return nil
+}
+
+func (ies *IAMEtcdStore) loadUser(ctx context.Context, user string, userType IAMUserType, m map[string]auth.Credentials) error {
+	var u UserIdentity
+	err := ies.loadIAMConfig(ctx, &u, getUserIdentityPath(user, userType))
+	if err != nil {
+		if err == errConfigNotFound {
+			return errNoSuchUser
+		}
+		return err
+	}
+	return ies.addUser(ctx, user, userType, u, m)
+}
+
+func (ies *IAMEtcdStore) loadUsers(ctx context.Context, userType IAMUserType, m map[string]auth.Credentials) error {
+	var basePrefix string
+	switch userType {
+	case srvAccUser:
+		basePrefix = iamConfigServiceAccountsPrefix
+	case stsUser:
+		basePrefix = iamConfigSTSPrefix
+	default:
+		basePrefix = iamConfigUsersPrefix
+	}
+
+	items, err := readKeysEtcd(ctx, ies.client, basePrefix)
+	if err != nil {
+		return err
+	}
+	for item, data := range items {
+		var u UserIdentity
+		if err = getIAMConfig(&u, data); err != nil {
+			return err
+		}
+		if err = ies.addUser(ctx, path.Dir(item), userType, u, m); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (ies *IAMEtcdStore) loadGroup(ctx context.Context, group string, m map[string]GroupInfo) error {
+	var gi GroupInfo
+	err := ies.loadIAMConfig(ctx, &gi, getGroupInfoPath(group))
+	if err != nil {
+		if err == errConfigNotFound {
+			return errNoSuchGroup
+		}
+		return err
+	}
+	m[group] = gi
+	return nil
+}
+
+func (ies *IAMEtcdStore) loadGroups(ctx context.Context, m map[string]GroupInfo) error {
+	items, err := readKeysEtcd(ctx, ies.client, iamConfigGroupsPrefix)
+	if err != nil {
+		return err
+	}
+	for item, data := range items {
+		var gi GroupInfo
+		if err = getIAMConfig(&gi, data); err != nil {
+			return err
+		}
+		m[path.Dir(item)] = gi
+	}
+	return nil
+}
+
+func (ies *IAMEtcdStore) loadMappedPolicy(ctx context.Context, name string, userType IAMUserType, isGroup bool,
+	m map[string]MappedPolicy) error {
+
+	var p MappedPolicy
+	err := ies.loadIAMConfig(ctx, &p, getMappedPolicyPath(name, userType, isGroup))
+	if err != nil {
+		if err == errConfigNotFound {
+			return errNoSuchPolicy
+		}
+		return err
+	}
+	m[name] = p
+	return nil
+}
+
+func (ies *IAMEtcdStore) loadMappedPolicies(ctx context.Context, userType IAMUserType, isGroup bool, m map[string]MappedPolicy) error {
+	var basePath string
+	if isGroup {
+		basePath = iamConfigPolicyDBGroupsPrefix
+	} else {
+		switch userType {
+		case srvAccUser:
+			basePath = iamConfigPolicyDBServiceAccountsPrefix
+		case stsUser:
+			basePath = iamConfigPolicyDBSTSUsersPrefix
+		default:
+			basePath = iamConfigPolicyDBUsersPrefix
+		}
+	}
+
+	items, err := readKeysEtcd(ctx, ies.client, basePath)
+	if err != nil {
+		return err
+	}
+	for item, data := range items {
+		var p MappedPolicy
+		if err = getIAMConfig(&p, data); err != nil {
+			return err
+		}
+		m[strings.TrimSuffix(item, ".json")] = p
+	}
+	return nil
+}
+
+func (ies *IAMEtcdStore) loadAll(ctx context.Context, sys *IAMSys) error {
+	return sys.Load(ctx, ies)
+}
+
+func (ies *IAMEtcdStore) savePolicyDoc(ctx context.Context, policyName string, p iampolicy.Policy) error {
+	return ies.saveIAMConfig(ctx, &p, getPolicyDocPath(policyName))
+}
+
+func (ies *IAMEtcdStore) saveMappedPolicy(ctx context.Context, name string, userType IAMUserType, isGroup bool, mp MappedPolicy, opts ...options) error {
+	return ies.saveIAMConfig(ctx, mp, getMappedPolicyPath(name, userType, isGroup), opts...)
+}
+
+func (ies *IAMEtcdStore) saveUserIdentity(ctx context.Context, name string, userType IAMUserType, u UserIdentity, opts ...options) error {
+	return ies.saveIAMConfig(ctx, u, getUserIdentityPath(name, userType), opts...)
+}
+
+func (ies *IAMEtcdStore) saveGroupInfo(ctx context.Context, name string, gi GroupInfo) error {
+	return ies.saveIAMConfig(ctx, gi, getGroupInfoPath(name))
+}
+
+func (ies *IAMEtcdStore) deletePolicyDoc(ctx context.Context, name string) error {
+	err := ies.deleteIAMConfig(ctx, getPolicyDocPath(name))
+	if err == errConfigNotFound {
+		err = errNoSuchPolicy
+	}
+	return err
+}
+
+func (ies *IAMEtcdStore) deleteMappedPolicy(ctx context.Context, name string, userType IAMUserType, isGroup bool) error {
+	err := ies.deleteIAMConfig(ctx, getMappedPolicyPath(name, userType, isGroup))
+	if err == errConfigNotFound {
+		err = errNoSuchPolicy
+	}
+	return err
+}
+
+func (ies *IAMEtcdStore) deleteUserIdentity(ctx context.Context, name string, userType IAMUserType) error {
+	err := ies.deleteIAMConfig(ctx, getUserIdentityPath(name, userType))
+	if err == errConfigNotFound {
+		err = errNoSuchUser
+	}
+	return err
+}
+
+func (ies *IAMEtcdStore) deleteGroupInfo(ctx context.Context, name string) error {
+	err := ies.deleteIAMConfig(ctx, getGroupInfoPath(name))
+	if err == errConfigNotFound {
+		err = errNoSuchGroup
+	}
+	return err
+}
+
+// reloadAll reloads all IAM entries, it returns the etcd revision the
+// entries are at least as recent as.
+func (ies *IAMEtcdStore) reloadAll(ctx context.Context, sys *IAMSys) (int64, error) {
+	timeoutCtx, cancel := context.WithTimeout(ctx, defaultContextTimeout)
+	resp, err := ies.client.Get(timeoutCtx, getIAMFormatFilePath(), etcd.WithCountOnly())
+	cancel()
+	if err != nil {
+		return 0, etcdErrToErr(err, ies.client.Endpoints())
+	}
+	if err = ies.loadAll(ctx, sys); err != nil {
+		return 0, err
+	}
+	return resp.Header.Revision, nil
+}
+
+// watch applies the changes of the IAM entries in etcd as they happen.
+// All entries are reloaded when the watch starts, and again if changes
+// are lost as etcd compacted its history.
+func (ies *IAMEtcdStore) watch(ctx context.Context, sys *IAMSys) {
+	var rev int64
+	for {
+		if rev == 0 {
+			var err error
+			if rev, err = ies.reloadAll(ctx, sys); err != nil {
+				logger.LogIf(ctx, err)
+			}
+		}
+
+		if rev != 0 {
+			rev = ies.watchFrom(ctx, sys, rev)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(time.Second):
+			// Retry after an error.
+		}
+	}
+}
+
+// watchFrom applies the changes of the IAM entries after the revision
+// until the watch fails, it returns the revision the entries are then
+// at or 0 if changes are lost.
+func (ies *IAMEtcdStore) watchFrom(ctx context.Context, sys *IAMSys, rev int64) int64 {
+	watchCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	watchCh := ies.client.Watch(watchCtx, iamConfigPrefix+SlashSeparator,
+		etcd.WithPrefix(), etcd.WithRev(rev+1))
+	for watchResp := range watchCh {
+		if watchResp.CompactRevision != 0 {
+			// Changes are lost, reload all entries.
+			return 0
+		}
+		if err := watchResp.Err(); err != nil {
+			logger.LogIf(ctx, err)
+			return rev
+		}
+		for _, event := range watchResp.Events {
+			ies.lock()
+			ies.reloadFromEvent(ctx, sys, event)
+			ies.unlock()
+			rev = event.Kv.ModRevision
+		}
+	}
+	return rev
+}
+
+// reloadFromEvent applies the change of an IAM entry to sys, the store
+// lock must be held.
+func (ies *IAMEtcdStore) reloadFromEvent(ctx context.Context, sys *IAMSys, event *etcd.Event) {
+	key := string(event.Kv.Key)
+	isPut := event.Type == etcd.EventTypePut
+	isMinIOUsersSys := sys.usersSysType == MinIOUsersSysType
+
+	reloadUser := func(prefix string, userType IAMUserType) {
+		user := path.Dir(strings.TrimPrefix(key, prefix))
+		if !isPut {
+			delete(sys.iamUsersMap, user)
+			return
+		}
+		var u UserIdentity
+		if err := getIAMConfig(&u, event.Kv.Value); err != nil {
+			logger.LogIf(ctx, err)
+			return
+		}
+		logger.LogIf(ctx, ies.addUser(ctx, user, userType, u, sys.iamUsersMap))
+	}
+
+	reloadMappedPolicy := func(prefix string, m map[string]MappedPolicy) {
+		name := strings.TrimSuffix(strings.TrimPrefix(key, prefix), ".json")
+		if !isPut {
+			delete(m, name)
+			return
+		}
+		var mp MappedPolicy
+		if err := getIAMConfig(&mp, event.Kv.Value); err != nil {
+			logger.LogIf(ctx, err)
+			return
+		}
+		m[name] = mp
+	}
+
+	switch {
+	case strings.HasPrefix(key, iamConfigUsersPrefix):
+		if isMinIOUsersSys {
+			reloadUser(iamConfigUsersPrefix, regularUser)
+		}
+	case strings.HasPrefix(key, iamConfigSTSPrefix):
+		reloadUser(iamConfigSTSPrefix, stsUser)
+	case strings.HasPrefix(key, iamConfigServiceAccountsPrefix):
+		reloadUser(iamConfigServiceAccountsPrefix, srvAccUser)
+	case strings.HasPrefix(key, iamConfigGroupsPrefix):
+		if !isMinIOUsersSys {
+			return
+		}
+		group := path.Dir(strings.TrimPrefix(key, iamConfigGroupsPrefix))
+		// Remove the group from the memberships of its previous
+		// members, before adding it to the current members.
+		sys.removeGroupFromMembershipsMap(group)
+		if !isPut {
+			delete(sys.iamGroupsMap, group)
+			delete(sys.iamGroupPolicyMap, group)
+			return
+		}
+		var gi GroupInfo
+		if err := getIAMConfig(&gi, event.Kv.Value); err != nil {
+			logger.LogIf(ctx, err)
+			return
+		}
+		sys.iamGroupsMap[group] = gi
+		sys.updateGroupMembershipsMap(group, &gi)
+	case strings.HasPrefix(key, iamConfigPoliciesPrefix):
+		policyName := path.Dir(strings.TrimPrefix(key, iamConfigPoliciesPrefix))
+		if !isPut {
+			delete(sys.iamPolicyDocsMap, policyName)
+			return
+		}
+		var p iampolicy.Policy
+		if err := getIAMConfig(&p, event.Kv.Value); err != nil {
+			logger.LogIf(ctx, err)
+			return
+		}
+		sys.iamPolicyDocsMap[policyName] = p
+	case strings.HasPrefix(key, iamConfigPolicyDBUsersPrefix):
+		reloadMappedPolicy(iamConfigPolicyDBUsersPrefix, sys.iamUserPolicyMap)
+	case strings.HasPrefix(key, iamConfigPolicyDBSTSUsersPrefix):
+		reloadMappedPolicy(iamConfigPolicyDBSTSUsersPrefix, sys.iamUserPolicyMap)
+	case strings.HasPrefix(key, iamConfigPolicyDBServiceAccountsPrefix):
+		reloadMappedPolicy(iamConfigPolicyDBServiceAccountsPrefix, sys.iamUserPolicyMap)
+	case strings.HasPrefix(key, iamConfigPolicyDBGroupsPrefix):
+		reloadMappedPolicy(iamConfigPolicyDBGroupsPrefix, sys.iamGroupPolicyMap)
+	}
+}
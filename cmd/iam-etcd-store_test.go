@@ -0,0 +1,327 @@
+/*
+ * MinIO Cloud Storage, (C) 2021 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/minio/minio/pkg/auth"
+	iampolicy "github.com/minio/minio/pkg/iam/policy"
+	etcd "go.etcd.io/etcd/client/v3"
+	"go.etcd.io/etcd/server/v3/embed"
+)
+
+// newTestEtcdClient starts an embedded etcd server, it returns a
+// client connected to it.
+func newTestEtcdClient(t *testing.T) *etcd.Client {
+	t.Helper()
+
+	dir, err := ioutil.TempDir(globalTestTmpDir, "minio-etcd-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	cfg := embed.NewConfig()
+	cfg.Dir = dir
+	cfg.LogLevel = "error"
+	cfg.LCUrls = []url.URL{{Scheme: "http", Host: "127.0.0.1:0"}}
+	cfg.LPUrls = []url.URL{{Scheme: "http", Host: "127.0.0.1:0"}}
+	e, err := embed.StartEtcd(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(e.Close)
+
+	select {
+	case <-e.Server.ReadyNotify():
+	case <-time.After(time.Minute):
+		t.Fatal("embedded etcd server did not start")
+	}
+
+	client, err := etcd.New(etcd.Config{
+		Endpoints:   []string{e.Clients[0].Addr().String()},
+		DialTimeout: 5 * time.Second,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+func testIAMPolicy(t *testing.T) iampolicy.Policy {
+	t.Helper()
+
+	p, err := iampolicy.ParseConfig(strings.NewReader(`{
+  "Version": "2012-10-17",
+  "Statement": [{"Effect": "Allow", "Action": ["s3:GetObject"], "Resource": ["arn:aws:s3:::mybucket/*"]}]
+}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return *p
+}
+
+func TestIAMEtcdStoreSaveLoad(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ies := newIAMEtcdStore(newTestEtcdClient(t), nil)
+	if err := ies.migrateBackendFormat(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	cred, err := auth.GetNewCredentials()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = ies.saveUserIdentity(ctx, "user1", regularUser, newUserIdentity(cred)); err != nil {
+		t.Fatal(err)
+	}
+	if err = ies.savePolicyDoc(ctx, "getonly", testIAMPolicy(t)); err != nil {
+		t.Fatal(err)
+	}
+	if err = ies.saveMappedPolicy(ctx, "user1", regularUser, false, newMappedPolicy("getonly")); err != nil {
+		t.Fatal(err)
+	}
+	if err = ies.saveGroupInfo(ctx, "group1", newGroupInfo([]string{"user1"})); err != nil {
+		t.Fatal(err)
+	}
+	if err = ies.saveMappedPolicy(ctx, "group1", regularUser, true, newMappedPolicy("readwrite")); err != nil {
+		t.Fatal(err)
+	}
+
+	sys := NewIAMSys()
+	if err = ies.loadAll(ctx, sys); err != nil {
+		t.Fatal(err)
+	}
+	if sys.iamUsersMap["user1"].SecretKey != cred.SecretKey {
+		t.Errorf("expected user1 credentials to be loaded, got %#v", sys.iamUsersMap["user1"])
+	}
+	if _, ok := sys.iamPolicyDocsMap["getonly"]; !ok {
+		t.Error("expected policy getonly to be loaded")
+	}
+	if sys.iamUserPolicyMap["user1"].Policies != "getonly" {
+		t.Errorf("expected user1 to be mapped to getonly, got %q", sys.iamUserPolicyMap["user1"].Policies)
+	}
+	if sys.iamGroupPolicyMap["group1"].Policies != "readwrite" {
+		t.Errorf("expected group1 to be mapped to readwrite, got %q", sys.iamGroupPolicyMap["group1"].Policies)
+	}
+	if !sys.iamUserGroupMemberships["user1"].Contains("group1") {
+		t.Error("expected user1 to be a member of group1")
+	}
+
+	users := make(map[string]auth.Credentials)
+	if err = ies.loadUser(ctx, "user2", regularUser, users); err != errNoSuchUser {
+		t.Errorf("expected %v, got %v", errNoSuchUser, err)
+	}
+	if err = ies.deleteUserIdentity(ctx, "user1", regularUser); err != nil {
+		t.Fatal(err)
+	}
+	if err = ies.deleteUserIdentity(ctx, "user1", regularUser); err != errNoSuchUser {
+		t.Errorf("expected %v, got %v", errNoSuchUser, err)
+	}
+	if err = ies.deletePolicyDoc(ctx, "nonexistent"); err != errNoSuchPolicy {
+		t.Errorf("expected %v, got %v", errNoSuchPolicy, err)
+	}
+}
+
+func TestIAMEtcdStoreWatch(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	client := newTestEtcdClient(t)
+	ies := newIAMEtcdStore(client, nil)
+	if err := ies.migrateBackendFormat(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	sys := NewIAMSys()
+	go ies.watch(ctx, sys)
+
+	// waitFor polls the IAM maps of sys until check succeeds.
+	waitFor := func(desc string, check func() bool) {
+		t.Helper()
+		deadline := time.Now().Add(10 * time.Second)
+		for {
+			ies.rlock()
+			ok := check()
+			ies.runlock()
+			if ok {
+				return
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("timed out waiting for %s", desc)
+			}
+			time.Sleep(20 * time.Millisecond)
+		}
+	}
+
+	// Changes are saved by another deployment sharing the same etcd.
+	other := newIAMEtcdStore(client, nil)
+	cred, err := auth.GetNewCredentials()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = other.saveUserIdentity(ctx, "user1", regularUser, newUserIdentity(cred)); err != nil {
+		t.Fatal(err)
+	}
+	if err = other.savePolicyDoc(ctx, "getonly", testIAMPolicy(t)); err != nil {
+		t.Fatal(err)
+	}
+	if err = other.saveMappedPolicy(ctx, "user1", regularUser, false, newMappedPolicy("getonly")); err != nil {
+		t.Fatal(err)
+	}
+	if err = other.saveGroupInfo(ctx, "group1", newGroupInfo([]string{"user1"})); err != nil {
+		t.Fatal(err)
+	}
+
+	waitFor("user1 to be added", func() bool {
+		return sys.iamUsersMap["user1"].SecretKey == cred.SecretKey
+	})
+	waitFor("policy getonly to be added", func() bool {
+		_, ok := sys.iamPolicyDocsMap["getonly"]
+		return ok
+	})
+	waitFor("user1 to be mapped to getonly", func() bool {
+		return sys.iamUserPolicyMap["user1"].Policies == "getonly"
+	})
+	waitFor("user1 to be a member of group1", func() bool {
+		return sys.iamUserGroupMemberships["user1"].Contains("group1")
+	})
+
+	if err = other.saveGroupInfo(ctx, "group1", newGroupInfo(nil)); err != nil {
+		t.Fatal(err)
+	}
+	waitFor("user1 to be removed from group1", func() bool {
+		return !sys.iamUserGroupMemberships["user1"].Contains("group1")
+	})
+
+	if err = other.deleteMappedPolicy(ctx, "user1", regularUser, false); err != nil {
+		t.Fatal(err)
+	}
+	if err = other.deleteUserIdentity(ctx, "user1", regularUser); err != nil {
+		t.Fatal(err)
+	}
+	if err = other.deleteGroupInfo(ctx, "group1"); err != nil {
+		t.Fatal(err)
+	}
+	waitFor("user1 to be deleted", func() bool {
+		_, userOK := sys.iamUsersMap["user1"]
+		_, mappingOK := sys.iamUserPolicyMap["user1"]
+		_, groupOK := sys.iamGroupsMap["group1"]
+		return !userOK && !mappingOK && !groupOK
+	})
+}
+
+func TestIAMEtcdStoreMigrateFromObjectStore(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	objLayer, fsDir, err := prepareFS()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(fsDir)
+
+	// IAM entries saved before etcd was configured.
+	iamOS := newIAMObjectStore(objLayer)
+	if err = iamOS.migrateBackendFormat(ctx); err != nil {
+		t.Fatal(err)
+	}
+	cred1, err := auth.GetNewCredentials()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cred2, err := auth.GetNewCredentials()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = iamOS.saveUserIdentity(ctx, "user1", regularUser, newUserIdentity(cred1)); err != nil {
+		t.Fatal(err)
+	}
+	if err = iamOS.saveUserIdentity(ctx, "user2", regularUser, newUserIdentity(cred2)); err != nil {
+		t.Fatal(err)
+	}
+	if err = iamOS.savePolicyDoc(ctx, "getonly", testIAMPolicy(t)); err != nil {
+		t.Fatal(err)
+	}
+	if err = iamOS.saveMappedPolicy(ctx, "user1", regularUser, false, newMappedPolicy("getonly")); err != nil {
+		t.Fatal(err)
+	}
+	quotas := userQuotaConfig{Version: userQuotaConfigVersion, Users: map[string]uint64{"user1": 100}}
+	if err = iamOS.saveIAMConfig(ctx, quotas, userQuotaConfigFile); err != nil {
+		t.Fatal(err)
+	}
+
+	// user2 was saved in etcd already by another deployment.
+	ies := newIAMEtcdStore(newTestEtcdClient(t), objLayer)
+	cred3, err := auth.GetNewCredentials()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = ies.saveUserIdentity(ctx, "user2", regularUser, newUserIdentity(cred3)); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = ies.migrateBackendFormat(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	sys := NewIAMSys()
+	if err = ies.loadAll(ctx, sys); err != nil {
+		t.Fatal(err)
+	}
+	if sys.iamUsersMap["user1"].SecretKey != cred1.SecretKey {
+		t.Errorf("expected user1 to be migrated, got %#v", sys.iamUsersMap["user1"])
+	}
+	if sys.iamUsersMap["user2"].SecretKey != cred3.SecretKey {
+		t.Errorf("expected user2 in etcd to be kept, got %#v", sys.iamUsersMap["user2"])
+	}
+	if _, ok := sys.iamPolicyDocsMap["getonly"]; !ok {
+		t.Error("expected policy getonly to be migrated")
+	}
+	if sys.iamUserPolicyMap["user1"].Policies != "getonly" {
+		t.Errorf("expected user1 mapping to be migrated, got %q", sys.iamUserPolicyMap["user1"].Policies)
+	}
+	var migratedQuotas userQuotaConfig
+	if err = ies.loadIAMConfig(ctx, &migratedQuotas, userQuotaConfigFile); err != nil {
+		t.Fatal(err)
+	}
+	if migratedQuotas.quota("user1", false) != 100 {
+		t.Errorf("expected the quotas to be migrated, got %v", migratedQuotas.Users)
+	}
+
+	// Entries are migrated only once.
+	if err = iamOS.saveUserIdentity(ctx, "user3", regularUser, newUserIdentity(cred3)); err != nil {
+		t.Fatal(err)
+	}
+	if err = ies.migrateBackendFormat(ctx); err != nil {
+		t.Fatal(err)
+	}
+	users := make(map[string]auth.Credentials)
+	if err = ies.loadUser(ctx, "user3", regularUser, users); err != errNoSuchUser {
+		t.Errorf("expected %v, got %v", errNoSuchUser, err)
+	}
+}
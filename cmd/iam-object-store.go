@@ -281,22 +281,9 @@ func (iamOS *IAMObjectStore) loadUser(ctx context.Context, user string, userType
 	}
 
 	// If this is a service account, rotate the session key if needed
-	if globalOldCred.IsValid() && u.Credentials.IsServiceAccount() {
-		if !globalOldCred.Equal(globalActiveCred) {
-			m := jwtgo.MapClaims{}
-			stsTokenCallback := func(t *jwtgo.Token) (interface{}, error) {
-				return []byte(globalOldCred.SecretKey), nil
-			}
-			if _, err := jwtgo.ParseWithClaims(u.Credentials.SessionToken, m, stsTokenCallback); err == nil {
-				jwt := jwtgo.NewWithClaims(jwtgo.SigningMethodHS512, jwtgo.MapClaims(m))
-				if token, err := jwt.SignedString([]byte(globalActiveCred.SecretKey)); err == nil {
-					u.Credentials.SessionToken = token
-					err := iamOS.saveIAMConfig(ctx, &u, getUserIdentityPath(user, userType))
-					if err != nil {
-						return err
-					}
-				}
-			}
+	if rotateSessionToken(&u) {
+		if err := iamOS.saveIAMConfig(ctx, &u, getUserIdentityPath(user, userType)); err != nil {
+			return err
 		}
 	}
 
@@ -455,6 +442,29 @@ func (iamOS *IAMObjectStore) deleteGroupInfo(ctx context.Context, name string) e
 	return err
 }
 
+// rotateSessionToken signs the session token of a service account
+// with the active credentials if it was signed with the old ones,
+// it returns true if the token is rotated.
+func rotateSessionToken(u *UserIdentity) bool {
+	if !globalOldCred.IsValid() || !u.Credentials.IsServiceAccount() || globalOldCred.Equal(globalActiveCred) {
+		return false
+	}
+	m := jwtgo.MapClaims{}
+	stsTokenCallback := func(t *jwtgo.Token) (interface{}, error) {
+		return []byte(globalOldCred.SecretKey), nil
+	}
+	if _, err := jwtgo.ParseWithClaims(u.Credentials.SessionToken, m, stsTokenCallback); err != nil {
+		return false
+	}
+	jwt := jwtgo.NewWithClaims(jwtgo.SigningMethodHS512, jwtgo.MapClaims(m))
+	token, err := jwt.SignedString([]byte(globalActiveCred.SecretKey))
+	if err != nil {
+		return false
+	}
+	u.Credentials.SessionToken = token
+	return true
+}
+
 // helper type for listIAMConfigItems
 type itemOrErr struct {
 	Item string
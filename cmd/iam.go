@@ -33,6 +33,7 @@ import (
 	"github.com/minio/minio/cmd/config"
 	"github.com/minio/minio/cmd/logger"
 	"github.com/minio/minio/pkg/auth"
+	"github.com/minio/minio/pkg/bucket/policy/condition"
 	iampolicy "github.com/minio/minio/pkg/iam/policy"
 	"github.com/minio/minio/pkg/madmin"
 )
@@ -416,14 +417,21 @@ func (sys *IAMSys) doIAMConfigMigration(ctx context.Context) error {
 	return sys.store.migrateBackendFormat(ctx)
 }
 
+// newIAMStore returns the etcd IAM store if etcd is configured, the
+// object store otherwise.
+func newIAMStore(objAPI ObjectLayer) IAMStorageAPI {
+	if globalEtcdClient == nil {
+		return newIAMObjectStore(objAPI)
+	}
+	return newIAMEtcdStore(globalEtcdClient, objAPI)
+}
+
 // InitStore initializes IAM stores
 func (sys *IAMSys) InitStore(objAPI ObjectLayer) {
 	sys.Lock()
 	defer sys.Unlock()
 
-	if globalEtcdClient == nil {
-		sys.store = newIAMObjectStore(objAPI)
-	}
+	sys.store = newIAMStore(objAPI)
 
 	if globalLDAPConfig.Enabled {
 		sys.EnableLDAPSys()
@@ -606,6 +614,7 @@ func (sys *IAMSys) Init(ctx context.Context, objAPI ObjectLayer) {
 			if errors.Is(err, errDiskNotFound) ||
 				errors.Is(err, errConfigNotFound) ||
 				errors.Is(err, context.DeadlineExceeded) ||
+				errors.Is(err, errEtcdUnreachable) ||
 				errors.As(err, &rquorum) ||
 				errors.As(err, &wquorum) ||
 				isErrBucketNotFound(err) {
@@ -627,6 +636,7 @@ func (sys *IAMSys) Init(ctx context.Context, objAPI ObjectLayer) {
 			if errors.Is(err, errDiskNotFound) ||
 				errors.Is(err, errConfigNotFound) ||
 				errors.Is(err, context.DeadlineExceeded) ||
+				errors.Is(err, errEtcdUnreachable) ||
 				errors.As(err, &rquorum) ||
 				errors.As(err, &wquorum) ||
 				isErrBucketNotFound(err) {
@@ -2071,6 +2081,52 @@ func (sys *IAMSys) IsAllowed(args iampolicy.Args) bool {
 	return sys.GetCombinedPolicy(policies...).IsAllowed(args)
 }
 
+// UsesObjectTagKey - returns whether the policies evaluated for the
+// account may have a condition on tagKey used with a tag key.
+func (sys *IAMSys) UsesObjectTagKey(accountName string, claims map[string]interface{}, tagKey condition.Key) bool {
+	// Policies evaluated by OPA are not known.
+	if globalPolicyOPA != nil {
+		return true
+	}
+
+	if !sys.Initialized() {
+		return false
+	}
+
+	// Session policies are only parsed when evaluated, look for the
+	// key in the policy text.
+	if spolicy, ok := claims[iampolicy.SessionPolicyName].(string); ok && strings.Contains(spolicy, tagKey.Name()) {
+		return true
+	}
+
+	sys.store.rlock()
+	defer sys.store.runlock()
+
+	// Regular users are evaluated with their own and their groups'
+	// policies, temporary users and service accounts are evaluated
+	// with the policies of their claims, look through all policies
+	// for those.
+	if len(claims) == 0 {
+		policies, err := sys.policyDBGet(accountName, false)
+		if err != nil {
+			return false
+		}
+		for _, pname := range policies {
+			if p, ok := sys.iamPolicyDocsMap[pname]; ok && p.HasObjectTagKey(tagKey) {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, p := range sys.iamPolicyDocsMap {
+		if p.HasObjectTagKey(tagKey) {
+			return true
+		}
+	}
+	return false
+}
+
 // Set default canned policies only if not already overridden by users.
 func setDefaultCannedPolicies(policies map[string]iampolicy.Policy) {
 	_, ok := policies["writeonly"]
@@ -0,0 +1,594 @@
+/*
+ * MinIO Cloud Storage, (C) 2021 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"container/list"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/minio/minio/cmd/config/cache"
+	"github.com/minio/minio/cmd/crypto"
+	"github.com/minio/minio/pkg/wildcard"
+)
+
+// CacheMemoryStats represents the statistics of the in-memory cache.
+type CacheMemoryStats struct {
+	Hits          uint64
+	Misses        uint64
+	UsedBytes     uint64
+	TotalCapacity uint64
+}
+
+// memCacheEntry is the content of one object version held in memory.
+type memCacheEntry struct {
+	bucket, object string
+	versionID      string
+	etag           string
+	data           []byte
+}
+
+// memCache is a bounded LRU cache of object contents. Entries are keyed
+// by bucket/object and then by version and ETag, so that all versions
+// of an object are invalidated at once.
+type memCache struct {
+	mu       sync.Mutex
+	capacity int64
+	used     int64
+	// lru holds the entries, most recently used at the front.
+	lru     list.List
+	objects map[string]map[string]*list.Element
+	// onChange is called with the lock held when the first version of
+	// an object is cached or the last one is evicted, it is not called
+	// on invalidation.
+	onChange func(key string, cached bool)
+	// gen is incremented by every invalidation, content read before
+	// an invalidation may be stale and is not cached.
+	gen uint64
+
+	hits, misses uint64
+}
+
+func newMemCache(capacity int64) *memCache {
+	return &memCache{
+		capacity: capacity,
+		objects:  make(map[string]map[string]*list.Element),
+		onChange: func(key string, cached bool) {},
+	}
+}
+
+// contains returns true if any version of the object is cached.
+func (m *memCache) contains(bucket, object string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.objects[pathJoin(bucket, object)]) > 0
+}
+
+func memCacheVersionKey(versionID, etag string) string {
+	return versionID + SlashSeparator + etag
+}
+
+// get returns the cached entry of an object version with the given
+// ETag, nil if not cached.
+func (m *memCache) get(bucket, object, versionID, etag string) *memCacheEntry {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e, ok := m.objects[pathJoin(bucket, object)][memCacheVersionKey(versionID, etag)]
+	if !ok {
+		return nil
+	}
+	m.lru.MoveToFront(e)
+	return e.Value.(*memCacheEntry)
+}
+
+// generation returns the current invalidation generation.
+func (m *memCache) generation() uint64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.gen
+}
+
+// put caches the content of an object version read at generation gen,
+// evicting the least recently used entries to make room for it.
+func (m *memCache) put(entry *memCacheEntry, gen uint64) {
+	size := int64(len(entry.data))
+	if size > m.capacity {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if gen != m.gen {
+		return
+	}
+	key := pathJoin(entry.bucket, entry.object)
+	_, cached := m.objects[key]
+	// Drop the stale content of the version.
+	for _, e := range m.objects[key] {
+		if e.Value.(*memCacheEntry).versionID == entry.versionID {
+			m.remove(e)
+		}
+	}
+	for m.used+size > m.capacity {
+		evicted := m.lru.Back().Value.(*memCacheEntry)
+		if m.remove(m.lru.Back()) {
+			if evictedKey := pathJoin(evicted.bucket, evicted.object); evictedKey != key {
+				m.onChange(evictedKey, false)
+			}
+		}
+	}
+	versions, ok := m.objects[key]
+	if !ok {
+		versions = make(map[string]*list.Element)
+		m.objects[key] = versions
+	}
+	versions[memCacheVersionKey(entry.versionID, entry.etag)] = m.lru.PushFront(entry)
+	m.used += size
+	if !cached {
+		m.onChange(key, true)
+	}
+}
+
+// remove drops a cached entry and returns true if it was the last
+// version of its object, must be called with the lock held.
+func (m *memCache) remove(e *list.Element) bool {
+	entry := m.lru.Remove(e).(*memCacheEntry)
+	m.used -= int64(len(entry.data))
+	key := pathJoin(entry.bucket, entry.object)
+	versions := m.objects[key]
+	delete(versions, memCacheVersionKey(entry.versionID, entry.etag))
+	if len(versions) == 0 {
+		delete(m.objects, key)
+		return true
+	}
+	return false
+}
+
+// invalidate drops all the cached versions of an object, it returns
+// true if any version was cached.
+func (m *memCache) invalidate(key string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.gen++
+	versions := m.objects[key]
+	cached := len(versions) > 0
+	for _, e := range versions {
+		m.remove(e)
+	}
+	return cached
+}
+
+func (m *memCache) stats() CacheMemoryStats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return CacheMemoryStats{
+		Hits:          atomic.LoadUint64(&m.hits),
+		Misses:        atomic.LoadUint64(&m.misses),
+		UsedBytes:     uint64(m.used),
+		TotalCapacity: uint64(m.capacity),
+	}
+}
+
+const (
+	// memCacheUpdateInterval is the interval at which invalidations
+	// and cached objects are sent to the peers in a batch.
+	memCacheUpdateInterval = 100 * time.Millisecond
+	// memCacheWriteWindow is how long written objects are remembered,
+	// a peer announcing one of them may have cached it before the
+	// write and is sent an invalidation.
+	memCacheWriteWindow = 5 * time.Second
+)
+
+// memCacheUpdate is a batch of changes to the in-memory cache of a
+// server sent to its peers. Objects are keyed by bucket/object.
+type memCacheUpdate struct {
+	// Invalidated objects are dropped from the cache of the peers.
+	Invalidated []string
+	// Cached holds +1 for the objects the sender cached and -1 for the
+	// objects it evicted.
+	Cached map[string]int
+}
+
+func (u memCacheUpdate) isEmpty() bool {
+	return len(u.Invalidated) == 0 && len(u.Cached) == 0
+}
+
+// memCacheObjects keeps small hot objects in memory in front of the
+// disk cache, or the object layer when disk caching is disabled.
+// Cached objects are served only if their ETag matches the object
+// metadata in the backend, so that a server which has not yet learnt
+// of a write on another server never serves stale content. Writes
+// invalidate the object on the servers caching it to free memory.
+type memCacheObjects struct {
+	cache         *memCache
+	maxObjectSize int64
+	// file path patterns to exclude from cache
+	exclude []string
+	// disk cache layer, nil when disk caching is disabled.
+	inner      CacheObjectLayer
+	cacheStats *CacheStats
+
+	// peersMu protects peerCached, written and pending.
+	peersMu sync.Mutex
+	// peerCached counts the peers caching an object, as reported by
+	// their updates.
+	peerCached map[string]int
+	// written holds the time of the recent writes of objects.
+	written map[string]time.Time
+	// pending changes not yet sent to the peers.
+	pending    memCacheUpdate
+	flushPeers sync.Once
+
+	InnerGetObjectNInfoFn func(ctx context.Context, bucket, object string, rs *HTTPRangeSpec, h http.Header, lockType LockType, opts ObjectOptions) (gr *GetObjectReader, err error)
+	InnerGetObjectInfoFn  func(ctx context.Context, bucket, object string, opts ObjectOptions) (objInfo ObjectInfo, err error)
+	InnerDeleteObjectFn   func(ctx context.Context, bucket, object string, opts ObjectOptions) (objInfo ObjectInfo, err error)
+	InnerDeleteObjectsFn  func(ctx context.Context, bucket string, objects []ObjectToDelete, opts ObjectOptions) ([]DeletedObject, []error)
+	InnerPutObjectFn      func(ctx context.Context, bucket, object string, data *PutObjReader, opts ObjectOptions) (objInfo ObjectInfo, err error)
+	InnerCopyObjectFn     func(ctx context.Context, srcBucket, srcObject, destBucket, destObject string, srcInfo ObjectInfo, srcOpts, dstOpts ObjectOptions) (objInfo ObjectInfo, err error)
+	// UpdatePeersFn sends a batch of changes to the peers.
+	UpdatePeersFn func(update memCacheUpdate)
+}
+
+// Returns memCacheObjects for use by Server, inner is the disk cache
+// layer if configured.
+func newMemCacheObjects(config cache.Config, inner CacheObjectLayer) *memCacheObjects {
+	m := &memCacheObjects{
+		cache:         newMemCache(int64(config.MemorySize)),
+		maxObjectSize: int64(config.MemoryMaxObjectSize),
+		exclude:       config.Exclude,
+		inner:         inner,
+		peerCached:    make(map[string]int),
+		written:       make(map[string]time.Time),
+		InnerGetObjectNInfoFn: func(ctx context.Context, bucket, object string, rs *HTTPRangeSpec, h http.Header, lockType LockType, opts ObjectOptions) (gr *GetObjectReader, err error) {
+			return newObjectLayerFn().GetObjectNInfo(ctx, bucket, object, rs, h, lockType, opts)
+		},
+		// Cached objects are always validated with the backend,
+		// even when the disk cache is enabled.
+		InnerGetObjectInfoFn: func(ctx context.Context, bucket, object string, opts ObjectOptions) (ObjectInfo, error) {
+			return newObjectLayerFn().GetObjectInfo(ctx, bucket, object, opts)
+		},
+		InnerDeleteObjectFn: func(ctx context.Context, bucket, object string, opts ObjectOptions) (ObjectInfo, error) {
+			return newObjectLayerFn().DeleteObject(ctx, bucket, object, opts)
+		},
+		InnerDeleteObjectsFn: func(ctx context.Context, bucket string, objects []ObjectToDelete, opts ObjectOptions) ([]DeletedObject, []error) {
+			return newObjectLayerFn().DeleteObjects(ctx, bucket, objects, opts)
+		},
+		InnerPutObjectFn: func(ctx context.Context, bucket, object string, data *PutObjReader, opts ObjectOptions) (objInfo ObjectInfo, err error) {
+			return newObjectLayerFn().PutObject(ctx, bucket, object, data, opts)
+		},
+		InnerCopyObjectFn: func(ctx context.Context, srcBucket, srcObject, destBucket, destObject string, srcInfo ObjectInfo, srcOpts, dstOpts ObjectOptions) (objInfo ObjectInfo, err error) {
+			return newObjectLayerFn().CopyObject(ctx, srcBucket, srcObject, destBucket, destObject, srcInfo, srcOpts, dstOpts)
+		},
+		UpdatePeersFn: func(update memCacheUpdate) {
+			if globalNotificationSys != nil {
+				globalNotificationSys.UpdateMemCache(GlobalContext, update)
+			}
+		},
+	}
+	m.cache.onChange = m.queueCached
+	if inner != nil {
+		// The disk cache validates its entries with the backend.
+		m.InnerGetObjectNInfoFn = inner.GetObjectNInfo
+		m.InnerDeleteObjectFn = inner.DeleteObject
+		m.InnerDeleteObjectsFn = inner.DeleteObjects
+		m.InnerPutObjectFn = inner.PutObject
+		m.InnerCopyObjectFn = inner.CopyObject
+		m.cacheStats = inner.CacheStats()
+	} else {
+		m.cacheStats = newCacheStats()
+		m.cacheStats.Policy = cache.PolicyLRU
+		m.cacheStats.GetDiskStats = func() []CacheDiskStats { return nil }
+	}
+	m.cacheStats.GetMemoryStats = m.cache.stats
+	return m
+}
+
+// updateMemCache applies the update of a peer to the local in-memory
+// cache.
+func updateMemCache(update memCacheUpdate) {
+	if m, ok := newCachedObjectLayerFn().(*memCacheObjects); ok {
+		m.applyPeerUpdate(update)
+	}
+}
+
+// invalidateCachedObject drops an object modified outside of the cache
+// layer from the in-memory cache of all the servers.
+func invalidateCachedObject(bucket, object string) {
+	if m, ok := newCachedObjectLayerFn().(*memCacheObjects); ok {
+		m.invalidate(bucket, object)
+	}
+}
+
+func (m *memCacheObjects) isCacheExclude(bucket, object string) bool {
+	// exclude directories from cache
+	if strings.HasSuffix(object, SlashSeparator) {
+		return true
+	}
+	for _, pattern := range m.exclude {
+		matchStr := fmt.Sprintf("%s/%s", bucket, object)
+		if ok := wildcard.MatchSimple(pattern, matchStr); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// isCacheable returns true if the content of the object can be kept
+// in memory, encrypted and compressed objects are never cached.
+func (m *memCacheObjects) isCacheable(objInfo ObjectInfo) bool {
+	if objInfo.DeleteMarker || objInfo.Size > m.maxObjectSize || objInfo.IsCompressed() {
+		return false
+	}
+	if _, ok := crypto.IsEncrypted(objInfo.UserDefined); ok {
+		return false
+	}
+	if cc := cacheControlOpts(objInfo); cc != nil && cc.noStore {
+		return false
+	}
+	return true
+}
+
+// invalidate drops the object from the local memory cache, and from
+// the memory cache of the peers when any server has it cached.
+func (m *memCacheObjects) invalidate(bucket, object string) {
+	key := pathJoin(bucket, object)
+	cached := m.cache.invalidate(key)
+
+	m.peersMu.Lock()
+	defer m.peersMu.Unlock()
+	m.written[key] = UTCNow()
+	m.startFlushPeers()
+	// Changes of the object not sent yet are void.
+	delete(m.pending.Cached, key)
+	if !cached && m.peerCached[key] <= 0 {
+		return
+	}
+	delete(m.peerCached, key)
+	m.pending.Invalidated = append(m.pending.Invalidated, key)
+}
+
+// queueCached records an object cached or evicted locally for the
+// next update of the peers.
+func (m *memCacheObjects) queueCached(key string, cached bool) {
+	m.peersMu.Lock()
+	defer m.peersMu.Unlock()
+	if m.pending.Cached == nil {
+		m.pending.Cached = make(map[string]int)
+	}
+	if cached {
+		m.pending.Cached[key]++
+	} else {
+		m.pending.Cached[key]--
+	}
+	if m.pending.Cached[key] == 0 {
+		delete(m.pending.Cached, key)
+	}
+	m.startFlushPeers()
+}
+
+// startFlushPeers starts sending the pending changes to the peers in
+// the background, must be called with peersMu held.
+func (m *memCacheObjects) startFlushPeers() {
+	m.flushPeers.Do(func() {
+		go func() {
+			ticker := time.NewTicker(memCacheUpdateInterval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-GlobalContext.Done():
+					return
+				case <-ticker.C:
+				}
+				m.peersMu.Lock()
+				update := m.pending
+				m.pending = memCacheUpdate{}
+				for key, t := range m.written {
+					if UTCNow().Sub(t) > memCacheWriteWindow {
+						delete(m.written, key)
+					}
+				}
+				m.peersMu.Unlock()
+				if !update.isEmpty() {
+					m.UpdatePeersFn(update)
+				}
+			}
+		}()
+	})
+}
+
+// applyPeerUpdate drops the objects invalidated by a peer and counts
+// the objects it cached. Invalidations apply first, an object may be
+// cached again after it was invalidated. Objects cached by the peer
+// while they were written here are invalidated again.
+func (m *memCacheObjects) applyPeerUpdate(update memCacheUpdate) {
+	for _, key := range update.Invalidated {
+		m.cache.invalidate(key)
+	}
+
+	m.peersMu.Lock()
+	defer m.peersMu.Unlock()
+	for _, key := range update.Invalidated {
+		delete(m.peerCached, key)
+		delete(m.pending.Cached, key)
+	}
+	for key, n := range update.Cached {
+		if _, ok := m.written[key]; ok && n > 0 {
+			m.pending.Invalidated = append(m.pending.Invalidated, key)
+			m.startFlushPeers()
+			continue
+		}
+		m.peerCached[key] += n
+		if m.peerCached[key] <= 0 {
+			delete(m.peerCached, key)
+		}
+	}
+}
+
+// GetObjectNInfo serves an object from memory if the requested version
+// is cached with the ETag found in the backend, otherwise the object is
+// read from the backend and cached when it is read in full.
+func (m *memCacheObjects) GetObjectNInfo(ctx context.Context, bucket, object string, rs *HTTPRangeSpec, h http.Header, lockType LockType, opts ObjectOptions) (gr *GetObjectReader, err error) {
+	if m.isCacheExclude(bucket, object) || !m.cache.contains(bucket, object) {
+		return m.getObjectNInfo(ctx, bucket, object, rs, h, lockType, opts)
+	}
+
+	// Errors are returned by reading the object from the backend.
+	objInfo, err := m.InnerGetObjectInfoFn(ctx, bucket, object, opts)
+	if err != nil || !m.isCacheable(objInfo) {
+		return m.getObjectNInfo(ctx, bucket, object, rs, h, lockType, opts)
+	}
+	if entry := m.cache.get(bucket, object, objInfo.VersionID, objInfo.ETag); entry != nil && int64(len(entry.data)) == objInfo.Size {
+		off, length, err := rs.GetOffsetLength(objInfo.Size)
+		if err != nil {
+			return nil, err
+		}
+		atomic.AddUint64(&m.cache.hits, 1)
+		m.cacheStats.incHit()
+		m.cacheStats.incBytesServed(length)
+		objInfo.CacheStatus = CacheHit
+		objInfo.CacheLookupStatus = CacheHit
+		return NewGetObjectReaderFromReader(bytes.NewReader(entry.data[off:off+length]), objInfo, opts)
+	}
+	return m.getObjectNInfo(ctx, bucket, object, rs, h, lockType, opts)
+}
+
+// getObjectNInfo reads an object from the backend and caches it when
+// it is read in full.
+func (m *memCacheObjects) getObjectNInfo(ctx context.Context, bucket, object string, rs *HTTPRangeSpec, h http.Header, lockType LockType, opts ObjectOptions) (gr *GetObjectReader, err error) {
+	if m.isCacheExclude(bucket, object) {
+		return m.InnerGetObjectNInfoFn(ctx, bucket, object, rs, h, lockType, opts)
+	}
+	atomic.AddUint64(&m.cache.misses, 1)
+	if m.inner == nil {
+		m.cacheStats.incMiss()
+	}
+
+	gen := m.cache.generation()
+	bkReader, err := m.InnerGetObjectNInfoFn(ctx, bucket, object, rs, h, lockType, opts)
+	if err != nil {
+		return bkReader, err
+	}
+	if rs != nil || !m.isCacheable(bkReader.ObjInfo) {
+		return bkReader, nil
+	}
+	fillReader := &memCacheFillReader{
+		reader: bkReader,
+		entry: &memCacheEntry{
+			bucket:    bucket,
+			object:    object,
+			versionID: bkReader.ObjInfo.VersionID,
+			etag:      bkReader.ObjInfo.ETag,
+		},
+		size:  bkReader.ObjInfo.Size,
+		gen:   gen,
+		cache: m.cache,
+	}
+	return NewGetObjectReaderFromReader(fillReader, bkReader.ObjInfo, opts, func() { bkReader.Close() })
+}
+
+// memCacheFillReader caches the content of an object read in full.
+type memCacheFillReader struct {
+	reader io.Reader
+	entry  *memCacheEntry
+	size   int64
+	buf    bytes.Buffer
+	gen    uint64
+	cache  *memCache
+}
+
+func (r *memCacheFillReader) Read(p []byte) (n int, err error) {
+	n, err = r.reader.Read(p)
+	if n > 0 && int64(r.buf.Len()+n) <= r.size {
+		r.buf.Write(p[:n])
+	}
+	if err == io.EOF && int64(r.buf.Len()) == r.size {
+		r.entry.data = r.buf.Bytes()
+		r.cache.put(r.entry, r.gen)
+	}
+	return n, err
+}
+
+// GetObjectInfo - returns the object info from the backend.
+func (m *memCacheObjects) GetObjectInfo(ctx context.Context, bucket, object string, opts ObjectOptions) (ObjectInfo, error) {
+	if m.inner != nil {
+		return m.inner.GetObjectInfo(ctx, bucket, object, opts)
+	}
+	return m.InnerGetObjectInfoFn(ctx, bucket, object, opts)
+}
+
+// DeleteObject invalidates the object in the cache of all nodes.
+func (m *memCacheObjects) DeleteObject(ctx context.Context, bucket, object string, opts ObjectOptions) (ObjectInfo, error) {
+	objInfo, err := m.InnerDeleteObjectFn(ctx, bucket, object, opts)
+	if err == nil {
+		m.invalidate(bucket, object)
+	}
+	return objInfo, err
+}
+
+// DeleteObjects invalidates the deleted objects in the cache of all nodes.
+func (m *memCacheObjects) DeleteObjects(ctx context.Context, bucket string, objects []ObjectToDelete, opts ObjectOptions) ([]DeletedObject, []error) {
+	deletedObjects, errs := m.InnerDeleteObjectsFn(ctx, bucket, objects, opts)
+	for i, object := range objects {
+		if errs[i] == nil {
+			m.invalidate(bucket, object.ObjectName)
+		}
+	}
+	return deletedObjects, errs
+}
+
+// PutObject invalidates the previous content of the object in the cache
+// of all nodes.
+func (m *memCacheObjects) PutObject(ctx context.Context, bucket, object string, data *PutObjReader, opts ObjectOptions) (ObjectInfo, error) {
+	objInfo, err := m.InnerPutObjectFn(ctx, bucket, object, data, opts)
+	if err == nil {
+		m.invalidate(bucket, object)
+	}
+	return objInfo, err
+}
+
+// CopyObject invalidates the destination object in the cache of all nodes.
+func (m *memCacheObjects) CopyObject(ctx context.Context, srcBucket, srcObject, dstBucket, dstObject string, srcInfo ObjectInfo, srcOpts, dstOpts ObjectOptions) (ObjectInfo, error) {
+	objInfo, err := m.InnerCopyObjectFn(ctx, srcBucket, srcObject, dstBucket, dstObject, srcInfo, srcOpts, dstOpts)
+	if err == nil {
+		m.invalidate(dstBucket, dstObject)
+	}
+	return objInfo, err
+}
+
+// StorageInfo - returns the storage statistics of the disk cache, or
+// the memory cache when disk caching is disabled.
+func (m *memCacheObjects) StorageInfo(ctx context.Context) CacheStorageInfo {
+	if m.inner != nil {
+		return m.inner.StorageInfo(ctx)
+	}
+	stats := m.cache.stats()
+	return CacheStorageInfo{
+		Total: stats.TotalCapacity,
+		Free:  stats.TotalCapacity - stats.UsedBytes,
+	}
+}
+
+// CacheStats - returns the cache statistics.
+func (m *memCacheObjects) CacheStats() *CacheStats {
+	return m.cacheStats
+}
@@ -0,0 +1,210 @@
+/*
+ * MinIO Cloud Storage, (C) 2021 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/minio/minio/cmd/config/cache"
+)
+
+func TestMemCache(t *testing.T) {
+	m := newMemCache(10)
+	var changes []string
+	m.onChange = func(key string, cached bool) {
+		changes = append(changes, fmt.Sprintf("%s:%t", key, cached))
+	}
+	gen := m.generation()
+	m.put(&memCacheEntry{bucket: "bucket", object: "a", etag: "e1", data: []byte("aaaa")}, gen)
+	m.put(&memCacheEntry{bucket: "bucket", object: "a", versionID: "v1", etag: "e1", data: []byte("aaaa")}, gen)
+	m.put(&memCacheEntry{bucket: "bucket", object: "b", etag: "e1", data: []byte("bb")}, gen)
+	if e := m.get("bucket", "a", "", "e1"); e == nil || string(e.data) != "aaaa" {
+		t.Fatalf("expected a cached entry, got %v", e)
+	}
+	if e := m.get("bucket", "a", "v2", "e1"); e != nil {
+		t.Fatalf("expected no entry for another version, got %v", e)
+	}
+	if e := m.get("bucket", "a", "", "e2"); e != nil {
+		t.Fatalf("expected no entry for another ETag, got %v", e)
+	}
+
+	// The least recently used entry is evicted.
+	m.put(&memCacheEntry{bucket: "bucket", object: "c", etag: "e1", data: []byte("cccc")}, gen)
+	if m.get("bucket", "a", "v1", "e1") != nil || m.get("bucket", "b", "", "e1") == nil {
+		t.Fatal("expected the least recently used entry to be evicted")
+	}
+	// Entries larger than the cache are ignored.
+	m.put(&memCacheEntry{bucket: "bucket", object: "d", data: make([]byte, 11)}, gen)
+	if m.contains("bucket", "d") {
+		t.Fatal("expected an entry larger than the cache to be ignored")
+	}
+
+	if !m.invalidate(pathJoin("bucket", "a")) || m.contains("bucket", "a") {
+		t.Fatal("expected all versions of the object to be invalidated")
+	}
+	if stats := m.stats(); stats.UsedBytes != 6 || stats.TotalCapacity != 10 {
+		t.Fatalf("unexpected stats %+v", stats)
+	}
+	// Content read before an invalidation is not cached.
+	m.put(&memCacheEntry{bucket: "bucket", object: "a", data: []byte("a")}, gen)
+	if m.contains("bucket", "a") {
+		t.Fatal("expected content read before an invalidation not to be cached")
+	}
+
+	want := []string{"bucket/a:true", "bucket/b:true", "bucket/c:true"}
+	if !reflect.DeepEqual(changes, want) {
+		t.Fatalf("expected changes %v, got %v", want, changes)
+	}
+}
+
+func TestMemCacheObjects(t *testing.T) {
+	ExecObjectLayerTest(t, testMemCacheObjects)
+}
+
+func testMemCacheObjects(obj ObjectLayer, instanceType string, t TestErrHandler) {
+	ctx := context.Background()
+	bucket, object := "memcache-bucket", "object"
+	if err := obj.MakeBucketWithLocation(ctx, bucket, BucketOptions{}); err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+
+	m := newMemCacheObjects(cache.Config{MemorySize: 1 << 20, MemoryMaxObjectSize: 1 << 10}, nil)
+	m.InnerGetObjectNInfoFn = obj.GetObjectNInfo
+	m.InnerGetObjectInfoFn = obj.GetObjectInfo
+	m.InnerPutObjectFn = obj.PutObject
+	m.UpdatePeersFn = func(update memCacheUpdate) {}
+
+	putObject := func(put func(ctx context.Context, bucket, object string, data *PutObjReader, opts ObjectOptions) (ObjectInfo, error), data string) {
+		if _, err := put(ctx, bucket, object, mustGetPutObjReader(t, bytes.NewReader([]byte(data)), int64(len(data)), "", ""), ObjectOptions{}); err != nil {
+			t.Fatalf("%s: %v", instanceType, err)
+		}
+	}
+	getObject := func(rs *HTTPRangeSpec) (string, CacheStatusType) {
+		gr, err := m.GetObjectNInfo(ctx, bucket, object, rs, http.Header{}, readLock, ObjectOptions{})
+		if err != nil {
+			t.Fatalf("%s: %v", instanceType, err)
+		}
+		defer gr.Close()
+		data, err := ioutil.ReadAll(gr)
+		if err != nil {
+			t.Fatalf("%s: %v", instanceType, err)
+		}
+		return string(data), gr.ObjInfo.CacheStatus
+	}
+
+	putObject(m.PutObject, "hello world")
+	if data, status := getObject(nil); data != "hello world" || status == CacheHit {
+		t.Fatalf("%s: expected a miss, got %q %v", instanceType, data, status)
+	}
+	if data, status := getObject(nil); data != "hello world" || status != CacheHit {
+		t.Fatalf("%s: expected a hit, got %q %v", instanceType, data, status)
+	}
+	if data, status := getObject(&HTTPRangeSpec{Start: 6, End: 10}); data != "world" || status != CacheHit {
+		t.Fatalf("%s: expected a ranged hit, got %q %v", instanceType, data, status)
+	}
+
+	// Writes through the cache invalidate the object.
+	putObject(m.PutObject, "hello again")
+	if m.cache.contains(bucket, object) {
+		t.Fatalf("%s: expected the object to be invalidated", instanceType)
+	}
+	getObject(nil)
+
+	// Writes bypassing the cache, as on another server which has not
+	// invalidated the object yet, are never served stale.
+	putObject(obj.PutObject, "bypassed")
+	if data, status := getObject(nil); data != "bypassed" || status == CacheHit {
+		t.Fatalf("%s: expected a miss, got %q %v", instanceType, data, status)
+	}
+	if data, status := getObject(nil); data != "bypassed" || status != CacheHit {
+		t.Fatalf("%s: expected a hit, got %q %v", instanceType, data, status)
+	}
+
+	// Objects above the size limit are not cached.
+	putObject(m.PutObject, string(make([]byte, 2<<10)))
+	getObject(nil)
+	if m.cache.contains(bucket, object) {
+		t.Fatalf("%s: expected a large object not to be cached", instanceType)
+	}
+	if stats := m.CacheStats(); stats.getHits() != 3 || stats.GetMemoryStats == nil {
+		t.Fatalf("%s: unexpected stats %+v", instanceType, stats)
+	}
+}
+
+func TestMemCacheObjectsPeers(t *testing.T) {
+	m := newMemCacheObjects(cache.Config{MemorySize: 1 << 20, MemoryMaxObjectSize: 1 << 10}, nil)
+	updates := make(chan memCacheUpdate, 10)
+	m.UpdatePeersFn = func(update memCacheUpdate) {
+		updates <- update
+	}
+	nextUpdate := func() memCacheUpdate {
+		select {
+		case update := <-updates:
+			return update
+		case <-time.After(10 * memCacheUpdateInterval):
+			t.Fatal("expected an update of the peers")
+		}
+		return memCacheUpdate{}
+	}
+
+	// Writes of objects cached nowhere are not sent to the peers.
+	m.invalidate("bucket", "a")
+	m.invalidate("bucket", "b")
+
+	// Cached objects are announced, writes of objects cached locally or
+	// by a peer are sent in a batch.
+	m.cache.put(&memCacheEntry{bucket: "bucket", object: "c", etag: "e1", data: []byte("c")}, m.cache.generation())
+	m.applyPeerUpdate(memCacheUpdate{Cached: map[string]int{"bucket/d": 1, "bucket/e": 1}})
+	if update := nextUpdate(); !reflect.DeepEqual(update, memCacheUpdate{Cached: map[string]int{"bucket/c": 1}}) {
+		t.Fatalf("unexpected update %+v", update)
+	}
+	m.invalidate("bucket", "c")
+	m.invalidate("bucket", "d")
+	m.invalidate("bucket", "f")
+	if update := nextUpdate(); !reflect.DeepEqual(update.Invalidated, []string{"bucket/c", "bucket/d"}) {
+		t.Fatalf("unexpected update %+v", update)
+	}
+
+	// A peer caching a recently written object may have read it before
+	// the write.
+	m.applyPeerUpdate(memCacheUpdate{Cached: map[string]int{"bucket/f": 1}})
+	if update := nextUpdate(); !reflect.DeepEqual(update.Invalidated, []string{"bucket/f"}) {
+		t.Fatalf("unexpected update %+v", update)
+	}
+
+	// Invalidations of a peer drop the local copy and the objects are
+	// no longer cached by any peer.
+	m.applyPeerUpdate(memCacheUpdate{Invalidated: []string{"bucket/e"}})
+	m.invalidate("bucket", "e")
+	select {
+	case update := <-updates:
+		t.Fatalf("unexpected update %+v", update)
+	case <-time.After(3 * memCacheUpdateInterval):
+	}
+	m.cache.put(&memCacheEntry{bucket: "bucket", object: "g", etag: "e1", data: []byte("g")}, m.cache.generation())
+	m.applyPeerUpdate(memCacheUpdate{Invalidated: []string{"bucket/g"}})
+	if m.cache.contains("bucket", "g") {
+		t.Fatal("expected the object to be invalidated")
+	}
+}
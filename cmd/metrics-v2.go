@@ -23,6 +23,7 @@ import (
 	"runtime"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/minio/minio/cmd/logger"
@@ -83,7 +84,9 @@ const (
 	offlineTotal  MetricName = "offline_total"
 	onlineTotal   MetricName = "online_total"
 	openTotal     MetricName = "open_total"
+	queuedTotal   MetricName = "queued_total"
 	readTotal     MetricName = "read_total"
+	retryTotal    MetricName = "retry_total"
 	writeTotal    MetricName = "write_total"
 	total         MetricName = "total"
 
@@ -99,6 +102,13 @@ const (
 	writeBytes    MetricName = "write_bytes"
 	wcharBytes    MetricName = "wchar_bytes"
 
+	hitRatio MetricName = "hit_ratio"
+
+	memoryHitsTotal   MetricName = "memory_hits_total"
+	memoryMissedTotal MetricName = "memory_missed_total"
+	memoryUsedBytes   MetricName = "memory_used_bytes"
+	memoryTotalBytes  MetricName = "memory_total_bytes"
+
 	usagePercent MetricName = "update_percent"
 
 	commitInfo  MetricName = "commit_info"
@@ -181,6 +191,7 @@ func GetGeneratorsForPeer() []MetricsGenerator {
 		getMinioProcMetrics,
 		getMinioVersionMetrics,
 		getNetworkMetrics,
+		getReplicationQueueMetrics,
 		getS3TTFBMetric,
 	}
 	return g
@@ -337,6 +348,42 @@ func getBucketRepReceivedBytesMD() MetricDescription {
 		Type:      gaugeMetric,
 	}
 }
+func getNodeRepQueuedTotalMD() MetricDescription {
+	return MetricDescription{
+		Namespace: nodeMetricNamespace,
+		Subsystem: replicationSubsystem,
+		Name:      queuedTotal,
+		Help:      "Total number of replication tasks journaled on this node, awaiting replication or a retry.",
+		Type:      gaugeMetric,
+	}
+}
+func getNodeRepInflightTotalMD() MetricDescription {
+	return MetricDescription{
+		Namespace: nodeMetricNamespace,
+		Subsystem: replicationSubsystem,
+		Name:      inflightTotal,
+		Help:      "Total number of replication tasks queued in memory or being replicated.",
+		Type:      gaugeMetric,
+	}
+}
+func getNodeRepRetryTotalMD() MetricDescription {
+	return MetricDescription{
+		Namespace: nodeMetricNamespace,
+		Subsystem: replicationSubsystem,
+		Name:      retryTotal,
+		Help:      "Total number of retries scheduled for failed replication tasks.",
+		Type:      counterMetric,
+	}
+}
+func getNodeRepErrorsTotalMD() MetricDescription {
+	return MetricDescription{
+		Namespace: nodeMetricNamespace,
+		Subsystem: replicationSubsystem,
+		Name:      errorsTotal,
+		Help:      "Total number of replication tasks that failed after exhausting all retries.",
+		Type:      counterMetric,
+	}
+}
 func getBucketObjectDistributionMD() MetricDescription {
 	return MetricDescription{
 		Namespace: bucketMetricNamespace,
@@ -436,6 +483,51 @@ func getCacheHitsMissedTotalMD() MetricDescription {
 		Type:      counterMetric,
 	}
 }
+func getCacheHitRatioMD() MetricDescription {
+	return MetricDescription{
+		Namespace: minioNamespace,
+		Subsystem: cacheSubsystem,
+		Name:      hitRatio,
+		Help:      "Ratio of disk cache hits to all disk cache lookups",
+		Type:      gaugeMetric,
+	}
+}
+func getCacheMemoryHitsTotalMD() MetricDescription {
+	return MetricDescription{
+		Namespace: minioNamespace,
+		Subsystem: cacheSubsystem,
+		Name:      memoryHitsTotal,
+		Help:      "Total number of memory cache hits",
+		Type:      counterMetric,
+	}
+}
+func getCacheMemoryMissedTotalMD() MetricDescription {
+	return MetricDescription{
+		Namespace: minioNamespace,
+		Subsystem: cacheSubsystem,
+		Name:      memoryMissedTotal,
+		Help:      "Total number of memory cache misses",
+		Type:      counterMetric,
+	}
+}
+func getCacheMemoryUsedBytesMD() MetricDescription {
+	return MetricDescription{
+		Namespace: minioNamespace,
+		Subsystem: cacheSubsystem,
+		Name:      memoryUsedBytes,
+		Help:      "Current memory cache usage in bytes",
+		Type:      gaugeMetric,
+	}
+}
+func getCacheMemoryTotalBytesMD() MetricDescription {
+	return MetricDescription{
+		Namespace: minioNamespace,
+		Subsystem: cacheSubsystem,
+		Name:      memoryTotalBytes,
+		Help:      "Total size of the memory cache in bytes",
+		Type:      gaugeMetric,
+	}
+}
 func getCacheUsagePercentMD() MetricDescription {
 	return MetricDescription{
 		Namespace: minioNamespace,
@@ -906,18 +998,45 @@ func getCacheMetrics() MetricsGroup {
 			if cacheObjLayer == nil {
 				return
 			}
+			policyLabels := map[string]string{"policy": cacheObjLayer.CacheStats().Policy}
+			m.Metrics = append(m.Metrics, Metric{
+				Description:    getCacheHitsTotalMD(),
+				Value:          float64(cacheObjLayer.CacheStats().getHits()),
+				VariableLabels: policyLabels,
+			})
 			m.Metrics = append(m.Metrics, Metric{
-				Description: getCacheHitsTotalMD(),
-				Value:       float64(cacheObjLayer.CacheStats().getHits()),
+				Description:    getCacheHitsMissedTotalMD(),
+				Value:          float64(cacheObjLayer.CacheStats().getMisses()),
+				VariableLabels: policyLabels,
 			})
 			m.Metrics = append(m.Metrics, Metric{
-				Description: getCacheHitsMissedTotalMD(),
-				Value:       float64(cacheObjLayer.CacheStats().getMisses()),
+				Description:    getCacheHitRatioMD(),
+				Value:          cacheObjLayer.CacheStats().getHitRatio(),
+				VariableLabels: policyLabels,
 			})
 			m.Metrics = append(m.Metrics, Metric{
 				Description: getCacheSentBytesMD(),
 				Value:       float64(cacheObjLayer.CacheStats().getBytesServed()),
 			})
+			if getMemoryStats := cacheObjLayer.CacheStats().GetMemoryStats; getMemoryStats != nil {
+				memStats := getMemoryStats()
+				m.Metrics = append(m.Metrics, Metric{
+					Description: getCacheMemoryHitsTotalMD(),
+					Value:       float64(memStats.Hits),
+				})
+				m.Metrics = append(m.Metrics, Metric{
+					Description: getCacheMemoryMissedTotalMD(),
+					Value:       float64(memStats.Misses),
+				})
+				m.Metrics = append(m.Metrics, Metric{
+					Description: getCacheMemoryUsedBytesMD(),
+					Value:       float64(memStats.UsedBytes),
+				})
+				m.Metrics = append(m.Metrics, Metric{
+					Description: getCacheMemoryTotalBytesMD(),
+					Value:       float64(memStats.TotalCapacity),
+				})
+			}
 			for _, cdStats := range cacheObjLayer.CacheStats().GetDiskStats() {
 				m.Metrics = append(m.Metrics, Metric{
 					Description:    getCacheUsagePercentMD(),
@@ -1102,6 +1221,34 @@ func getLocalStorageMetrics() MetricsGroup {
 		},
 	}
 }
+func getReplicationQueueMetrics() MetricsGroup {
+	return MetricsGroup{
+		Metrics: []Metric{},
+		initialize: func(ctx context.Context, metrics *MetricsGroup) {
+			rs := globalReplicationState
+			// Service not initialized yet
+			if rs == nil {
+				return
+			}
+			metrics.Metrics = append(metrics.Metrics, Metric{
+				Description: getNodeRepQueuedTotalMD(),
+				Value:       float64(rs.journalDepth()),
+			})
+			metrics.Metrics = append(metrics.Metrics, Metric{
+				Description: getNodeRepInflightTotalMD(),
+				Value:       float64(atomic.LoadInt64(&rs.inflight)),
+			})
+			metrics.Metrics = append(metrics.Metrics, Metric{
+				Description: getNodeRepRetryTotalMD(),
+				Value:       float64(atomic.LoadInt64(&rs.retries)),
+			})
+			metrics.Metrics = append(metrics.Metrics, Metric{
+				Description: getNodeRepErrorsTotalMD(),
+				Value:       float64(atomic.LoadInt64(&rs.errors)),
+			})
+		},
+	}
+}
 func getClusterStorageMetrics() MetricsGroup {
 	return MetricsGroup{
 		Metrics: []Metric{},
@@ -693,6 +693,27 @@ func (sys *NotificationSys) DeleteBucketMetadata(ctx context.Context, bucketName
 	}
 }
 
+// UpdateMemCache - sends invalidations and cached objects of the
+// in-memory cache to all peers
+func (sys *NotificationSys) UpdateMemCache(ctx context.Context, update memCacheUpdate) {
+	ng := WithNPeers(len(sys.peerClients))
+	for idx, client := range sys.peerClients {
+		if client == nil {
+			continue
+		}
+		client := client
+		ng.Go(ctx, func() error {
+			return client.UpdateMemCache(update)
+		}, idx, *client.host)
+	}
+	for _, nErr := range ng.Wait() {
+		reqInfo := (&logger.ReqInfo{}).AppendTags("peerAddress", nErr.Host.String())
+		if nErr.Err != nil {
+			logger.LogIf(logger.SetReqInfo(ctx, reqInfo), nErr.Err)
+		}
+	}
+}
+
 // Loads notification policies for all buckets into NotificationSys.
 func (sys *NotificationSys) load(buckets []BucketInfo) {
 	for _, bucket := range buckets {
@@ -1443,12 +1464,47 @@ func (sys *NotificationSys) GetBandwidthReports(ctx context.Context, buckets ...
 				d.LimitInBytesPerSecond = report.BucketStats[bucket].LimitInBytesPerSecond
 			}
 			d.CurrentBandwidthInBytesPerSecond += report.BucketStats[bucket].CurrentBandwidthInBytesPerSecond
+			for arn, td := range report.BucketStats[bucket].TargetStats {
+				if d.TargetStats == nil {
+					d.TargetStats = make(map[string]bandwidth.Details)
+				}
+				ctd := d.TargetStats[arn]
+				if ctd.LimitInBytesPerSecond < td.LimitInBytesPerSecond {
+					ctd.LimitInBytesPerSecond = td.LimitInBytesPerSecond
+				}
+				ctd.CurrentBandwidthInBytesPerSecond += td.CurrentBandwidthInBytesPerSecond
+				d.TargetStats[arn] = ctd
+			}
 			consolidatedReport.BucketStats[bucket] = d
 		}
+		consolidatedReport.BucketClientStats = mergeClientBandwidthStats(consolidatedReport.BucketClientStats, report.BucketClientStats)
+		consolidatedReport.UserClientStats = mergeClientBandwidthStats(consolidatedReport.UserClientStats, report.UserClientStats)
 	}
 	return consolidatedReport
 }
 
+// mergeClientBandwidthStats adds the client bandwidth of a node to the
+// bandwidth of the cluster, all nodes report the same cluster limits.
+func mergeClientBandwidthStats(cluster, node map[string]bandwidth.ClientDetails) map[string]bandwidth.ClientDetails {
+	merge := func(c, n bandwidth.Details) bandwidth.Details {
+		if c.LimitInBytesPerSecond < n.LimitInBytesPerSecond {
+			c.LimitInBytesPerSecond = n.LimitInBytesPerSecond
+		}
+		c.CurrentBandwidthInBytesPerSecond += n.CurrentBandwidthInBytesPerSecond
+		return c
+	}
+	for name, nd := range node {
+		if cluster == nil {
+			cluster = make(map[string]bandwidth.ClientDetails)
+		}
+		cd := cluster[name]
+		cd.Get = merge(cd.Get, nd.Get)
+		cd.Put = merge(cd.Put, nd.Put)
+		cluster[name] = cd
+	}
+	return cluster
+}
+
 // GetClusterMetrics - gets the cluster metrics from all nodes excluding self.
 func (sys *NotificationSys) GetClusterMetrics(ctx context.Context) chan Metric {
 	g := errgroup.WithNErrs(len(sys.peerClients))
@@ -134,6 +134,10 @@ type DataUsageInfo struct {
 	// - object size histogram per bucket
 	BucketsUsage map[string]BucketUsageInfo `json:"bucketsUsageInfo"`
 
+	// Size of the objects across all buckets by owner, the
+	// parent user of the credentials they were uploaded with.
+	OwnersUsage map[string]uint64 `json:"ownersUsage,omitempty"`
+
 	// Deprecated kept here for backward compatibility reasons.
 	BucketSizes map[string]uint64 `json:"bucketsSizes"`
 }
@@ -359,6 +359,16 @@ func (e BucketLifecycleNotFound) Error() string {
 	return "No bucket lifecycle configuration found for bucket : " + e.Bucket
 }
 
+// BucketInventoryConfigNotFound - no bucket inventory config found with the id.
+type BucketInventoryConfigNotFound struct {
+	Bucket string
+	ID     string
+}
+
+func (e BucketInventoryConfigNotFound) Error() string {
+	return "No bucket inventory configuration found for bucket: " + e.Bucket + " with id: " + e.ID
+}
+
 // BucketSSEConfigNotFound - no bucket encryption found
 type BucketSSEConfigNotFound GenericError
 
@@ -394,6 +404,53 @@ func (e BucketQuotaExceeded) Error() string {
 	return "Bucket quota exceeded for bucket: " + e.Bucket
 }
 
+// PrefixQuotaExceeded - quota of a prefix of the bucket exceeded.
+type PrefixQuotaExceeded GenericError
+
+func (e PrefixQuotaExceeded) Error() string {
+	return "Prefix quota exceeded for prefix: " + e.Bucket + "/" + e.Object
+}
+
+// UserQuotaExceeded - quota of the user or of one of its groups exceeded.
+type UserQuotaExceeded struct {
+	Name    string
+	IsGroup bool
+}
+
+func (e UserQuotaExceeded) Error() string {
+	if e.IsGroup {
+		return "Group quota exceeded for group: " + e.Name
+	}
+	return "User quota exceeded for user: " + e.Name
+}
+
+// BatchJobNotFound - no batch job found with the ID.
+type BatchJobNotFound struct {
+	ID string
+}
+
+func (e BatchJobNotFound) Error() string {
+	return "No batch job found with id: " + e.ID
+}
+
+// BatchJobNotRunning - the batch job has already stopped.
+type BatchJobNotRunning struct {
+	ID string
+}
+
+func (e BatchJobNotRunning) Error() string {
+	return "Batch job is not running: " + e.ID
+}
+
+// InvalidBatchJob - the specification of a batch job is invalid.
+type InvalidBatchJob struct {
+	Reason string
+}
+
+func (e InvalidBatchJob) Error() string {
+	return "Invalid batch job: " + e.Reason
+}
+
 // BucketReplicationConfigNotFound - no bucket replication config found
 type BucketReplicationConfigNotFound GenericError
 
@@ -478,6 +535,27 @@ func (e BucketReplicationSourceNotVersioned) Error() string {
 	return "Replication source does not have versioning enabled: " + e.Bucket
 }
 
+// BucketReplicationResyncInProgress resync to the replication target is already running.
+type BucketReplicationResyncInProgress GenericError
+
+func (e BucketReplicationResyncInProgress) Error() string {
+	return "Replication resync is already in progress for bucket: " + e.Bucket
+}
+
+// BucketExistingObjectReplicationDisabled no replication rule replicates existing objects.
+type BucketExistingObjectReplicationDisabled GenericError
+
+func (e BucketExistingObjectReplicationDisabled) Error() string {
+	return "Existing object replication is not enabled for bucket: " + e.Bucket
+}
+
+// BucketMetadataConflict the replicated bucket configuration is older than the one applied.
+type BucketMetadataConflict GenericError
+
+func (e BucketMetadataConflict) Error() string {
+	return "A newer configuration is already applied to bucket: " + e.Bucket
+}
+
 /// Bucket related errors.
 
 // BucketNameInvalid - bucketname provided is invalid.
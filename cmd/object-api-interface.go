@@ -55,6 +55,11 @@ type ObjectOptions struct {
 	ProxyRequest                  bool                                                  // only set for GET/HEAD in active-active replication scenario
 	ProxyHeaderSet                bool                                                  // only set for GET/HEAD in active-active replication scenario
 	ParentIsObject                func(ctx context.Context, bucket, parent string) bool // Used to verify if parent is an object.
+
+	// Only set in DeleteObjects calls, when set they override
+	// Versioned and VersionSuspended for each object.
+	PrefixEnabledFn   func(prefix string) bool
+	PrefixSuspendedFn func(prefix string) bool
 }
 
 // BucketOptions represents bucket options for ObjectLayer bucket operations
@@ -24,7 +24,6 @@ import (
 	"fmt"
 	"io"
 	"net/http"
-	"net/http/httptest"
 	"net/url"
 	"sort"
 	"strconv"
@@ -231,39 +230,19 @@ func (api objectAPIHandlers) SelectObjectContentHandler(w http.ResponseWriter, r
 
 	s3Select, err := s3select.NewS3Select(r.Body)
 	if err != nil {
-		if serr, ok := err.(s3select.SelectError); ok {
-			encodedErrorResponse := encodeResponse(APIErrorResponse{
-				Code:       serr.ErrorCode(),
-				Message:    serr.ErrorMessage(),
-				BucketName: bucket,
-				Key:        object,
-				Resource:   r.URL.Path,
-				RequestID:  w.Header().Get(xhttp.AmzRequestID),
-				HostID:     globalDeploymentID,
-			})
-			writeResponse(w, serr.HTTPStatusCode(), encodedErrorResponse, mimeXML)
-		} else {
-			writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL, guessIsBrowserReq(r))
-		}
+		writeSelectErrorResponse(ctx, w, r, bucket, object, err)
 		return
 	}
 	defer s3Select.Close()
 
+	if actualSize, err := objInfo.GetActualSize(); err == nil {
+		s3Select.SetObjectSize(actualSize)
+	}
+	// Objects compressed by the server are read decompressed.
+	s3Select.SetServerCompressed(objInfo.IsCompressed())
+
 	if err = s3Select.Open(getObject); err != nil {
-		if serr, ok := err.(s3select.SelectError); ok {
-			encodedErrorResponse := encodeResponse(APIErrorResponse{
-				Code:       serr.ErrorCode(),
-				Message:    serr.ErrorMessage(),
-				BucketName: bucket,
-				Key:        object,
-				Resource:   r.URL.Path,
-				RequestID:  w.Header().Get(xhttp.AmzRequestID),
-				HostID:     globalDeploymentID,
-			})
-			writeResponse(w, serr.HTTPStatusCode(), encodedErrorResponse, mimeXML)
-		} else {
-			writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL, guessIsBrowserReq(r))
-		}
+		writeSelectErrorResponse(ctx, w, r, bucket, object, err)
 		return
 	}
 
@@ -495,7 +474,7 @@ func (api objectAPIHandlers) GetObjectHandler(w http.ResponseWriter, r *http.Req
 	setHeadGetRespHeaders(w, r.URL.Query())
 
 	statusCodeWritten := false
-	httpWriter := ioutil.WriteOnClose(w)
+	httpWriter := ioutil.WriteOnClose(newClientBandwidthWriter(ctx, bucket, getReqAccessCred(r, globalServerRegion), w))
 	if rs != nil || opts.PartNumber > 0 {
 		statusCodeWritten = true
 		w.WriteHeader(http.StatusPartialContent)
@@ -1035,8 +1014,13 @@ func (api objectAPIHandlers) CopyObjectHandler(w http.ResponseWriter, r *http.Re
 	}
 	length := actualSize
 
+	cred := getReqAccessCred(r, globalServerRegion)
 	if !cpSrcDstSame {
-		if err := enforceBucketQuota(ctx, dstBucket, actualSize); err != nil {
+		if err := enforceBucketQuota(ctx, dstBucket, dstObject, actualSize); err != nil {
+			writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL, guessIsBrowserReq(r))
+			return
+		}
+		if err := enforceUserQuota(ctx, cred, actualSize); err != nil {
 			writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL, guessIsBrowserReq(r))
 			return
 		}
@@ -1194,12 +1178,22 @@ func (api objectAPIHandlers) CopyObjectHandler(w http.ResponseWriter, r *http.Re
 
 	srcInfo.PutObjReader = pReader
 
+	srcOwner := srcInfo.UserDefined[objectOwnerKey]
 	srcInfo.UserDefined, err = getCpObjMetadataFromHeader(ctx, r, srcInfo.UserDefined)
 	if err != nil {
 		writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL, guessIsBrowserReq(r))
 		return
 	}
 
+	// The copy is owned by the requester, unless only the
+	// metadata of the object is updated.
+	delete(srcInfo.UserDefined, objectOwnerKey)
+	if cpSrcDstSame && srcOwner != "" {
+		srcInfo.UserDefined[objectOwnerKey] = srcOwner
+	} else {
+		setObjectOwner(srcInfo.UserDefined, cred)
+	}
+
 	objTags := srcInfo.UserTags
 	// If x-amz-tagging-directive header is REPLACE, get passed tags.
 	if isDirectiveReplace(r.Header.Get(xhttp.AmzTagDirective)) {
@@ -1496,11 +1490,19 @@ func (api objectAPIHandlers) PutObjectHandler(w http.ResponseWriter, r *http.Req
 		}
 	}
 
-	if err := enforceBucketQuota(ctx, bucket, size); err != nil {
+	if err := enforceBucketQuota(ctx, bucket, object, size); err != nil {
 		writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL, guessIsBrowserReq(r))
 		return
 	}
 
+	cred := getReqAccessCred(r, globalServerRegion)
+	if err := enforceUserQuota(ctx, cred, size); err != nil {
+		writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL, guessIsBrowserReq(r))
+		return
+	}
+	setObjectOwner(metadata, cred)
+	reader = newClientBandwidthReader(ctx, bucket, cred, reader)
+
 	// Check if bucket encryption is enabled
 	_, err = globalBucketSSEConfigSys.Get(bucket)
 	// This request header needs to be set prior to setting ObjectOptions
@@ -1739,6 +1741,7 @@ func (api objectAPIHandlers) NewMultipartUploadHandler(w http.ResponseWriter, r
 		writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL, guessIsBrowserReq(r))
 		return
 	}
+	setObjectOwner(metadata, getReqAccessCred(r, globalServerRegion))
 
 	retPerms := isPutActionAllowed(ctx, getRequestAuthType(r), bucket, object, r, iampolicy.PutObjectRetentionAction)
 	holdPerms := isPutActionAllowed(ctx, getRequestAuthType(r), bucket, object, r, iampolicy.PutObjectLegalHoldAction)
@@ -1960,7 +1963,7 @@ func (api objectAPIHandlers) CopyObjectPartHandler(w http.ResponseWriter, r *htt
 		}
 	}
 
-	if err := enforceBucketQuota(ctx, dstBucket, actualPartSize); err != nil {
+	if err := enforceBucketQuota(ctx, dstBucket, dstObject, actualPartSize); err != nil {
 		writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL, guessIsBrowserReq(r))
 		return
 	}
@@ -2244,10 +2247,11 @@ func (api objectAPIHandlers) PutObjectPartHandler(w http.ResponseWriter, r *http
 		}
 	}
 
-	if err := enforceBucketQuota(ctx, bucket, size); err != nil {
+	if err := enforceBucketQuota(ctx, bucket, object, size); err != nil {
 		writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL, guessIsBrowserReq(r))
 		return
 	}
+	reader = newClientBandwidthReader(ctx, bucket, getReqAccessCred(r, globalServerRegion), reader)
 
 	actualSize := size
 
@@ -2656,6 +2660,19 @@ func (api objectAPIHandlers) CompleteMultipartUploadHandler(w http.ResponseWrite
 		}
 	}
 
+	cred := getReqAccessCred(r, globalServerRegion)
+	if globalUserQuotaSys.enforced(objectAPI, cred) {
+		size, err := completedPartsSize(ctx, objectAPI, bucket, object, uploadID, complMultipartUpload.Parts)
+		if err != nil {
+			writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL, guessIsBrowserReq(r))
+			return
+		}
+		if err = enforceUserQuota(ctx, cred, size); err != nil {
+			writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL, guessIsBrowserReq(r))
+			return
+		}
+	}
+
 	// Complete parts.
 	completeParts := make([]CompletePart, 0, len(complMultipartUpload.Parts))
 	for _, part := range complMultipartUpload.Parts {
@@ -2713,6 +2730,7 @@ func (api objectAPIHandlers) CompleteMultipartUploadHandler(w http.ResponseWrite
 		}
 		return
 	}
+	invalidateCachedObject(bucket, object)
 
 	// Get object location.
 	location := getObjectLocation(r, globalDomainNames, bucket, object)
@@ -3008,6 +3026,7 @@ func (api objectAPIHandlers) PutObjectLegalHoldHandler(w http.ResponseWriter, r
 		writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL, guessIsBrowserReq(r))
 		return
 	}
+	invalidateCachedObject(bucket, object)
 	if replicate {
 		scheduleReplication(ctx, objInfo.Clone(), objectAPI, sync)
 	}
@@ -3181,6 +3200,7 @@ func (api objectAPIHandlers) PutObjectRetentionHandler(w http.ResponseWriter, r
 		writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL, guessIsBrowserReq(r))
 		return
 	}
+	invalidateCachedObject(bucket, object)
 	if replicate {
 		scheduleReplication(ctx, objInfo.Clone(), objectAPI, sync)
 	}
@@ -3363,6 +3383,7 @@ func (api objectAPIHandlers) PutObjectTaggingHandler(w http.ResponseWriter, r *h
 		writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL, guessIsBrowserReq(r))
 		return
 	}
+	invalidateCachedObject(bucket, object)
 
 	if replicate {
 		scheduleReplication(ctx, objInfo.Clone(), objAPI, sync)
@@ -3437,6 +3458,7 @@ func (api objectAPIHandlers) DeleteObjectTaggingHandler(w http.ResponseWriter, r
 		writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL, guessIsBrowserReq(r))
 		return
 	}
+	invalidateCachedObject(bucket, object)
 
 	if replicate {
 		scheduleReplication(ctx, oi.Clone(), objAPI, sync)
@@ -3519,6 +3541,16 @@ func (api objectAPIHandlers) PostRestoreObjectHandler(w http.ResponseWriter, r *
 		writeErrorResponse(ctx, w, apiErr, r.URL, guessIsBrowserReq(r))
 		return
 	}
+	restoreObject := mustGetUUID()
+	// SELECT results are written to the output location with the
+	// credentials of this request, make sure they are allowed to.
+	if rreq.Type == SelectRestoreRequest {
+		outputObject := pathJoin(rreq.OutputLocation.S3.Prefix, restoreObject)
+		if s3Error := isPutActionAllowed(ctx, getRequestAuthType(r), rreq.OutputLocation.S3.BucketName, outputObject, r, iampolicy.PutObjectAction); s3Error != ErrNone {
+			writeErrorResponse(ctx, w, errorCodes.ToAPIErr(s3Error), r.URL, guessIsBrowserReq(r))
+			return
+		}
+	}
 	statusCode := http.StatusOK
 	alreadyRestored := false
 	if err == nil {
@@ -3549,6 +3581,8 @@ func (api objectAPIHandlers) PostRestoreObjectHandler(w http.ResponseWriter, r *
 		} else {
 			metadata[xhttp.AmzRestore] = fmt.Sprintf("ongoing-request=%t", ongoingReq)
 		}
+		// clear the failure of any previous restore attempt
+		delete(metadata, xhttp.MinIORestoreFailure)
 		objInfo.UserDefined = metadata
 		if _, err := objectAPI.CopyObject(GlobalContext, bucket, object, bucket, object, objInfo, ObjectOptions{
 			VersionID: objInfo.VersionID,
@@ -3559,13 +3593,13 @@ func (api objectAPIHandlers) PostRestoreObjectHandler(w http.ResponseWriter, r *
 			writeErrorResponse(ctx, w, errorCodes.ToAPIErr(ErrInvalidObjectState), r.URL, guessIsBrowserReq(r))
 			return
 		}
+		invalidateCachedObject(bucket, object)
 		// for previously restored object, just update the restore expiry
 		if alreadyRestored {
 			return
 		}
 	}
 
-	restoreObject := mustGetUUID()
 	if rreq.OutputLocation.S3.BucketName != "" {
 		w.Header()[xhttp.AmzRestoreOutputPath] = []string{pathJoin(rreq.OutputLocation.S3.BucketName, rreq.OutputLocation.S3.Prefix, restoreObject)}
 	}
@@ -3581,50 +3615,20 @@ func (api objectAPIHandlers) PostRestoreObjectHandler(w http.ResponseWriter, r *
 	})
 	// now process the restore in background
 	go func() {
-		rctx := GlobalContext
+		// The request context is canceled once the response is sent.
+		rctx := logger.SetReqInfo(GlobalContext, logger.GetReqInfo(ctx))
+		defer invalidateCachedObject(bucket, object)
 		if !rreq.SelectParameters.IsEmpty() {
-			getObject := func(offset, length int64) (rc io.ReadCloser, err error) {
-				isSuffixLength := false
-				if offset < 0 {
-					isSuffixLength = true
-				}
-
-				rs := &HTTPRangeSpec{
-					IsSuffixLength: isSuffixLength,
-					Start:          offset,
-					End:            offset + length,
-				}
-
-				return getTransitionedObjectReader(rctx, bucket, object, rs, r.Header, objInfo, ObjectOptions{
-					VersionID: objInfo.VersionID,
-				})
-			}
-			if err = rreq.SelectParameters.Open(getObject); err != nil {
-				if serr, ok := err.(s3select.SelectError); ok {
-					encodedErrorResponse := encodeResponse(APIErrorResponse{
-						Code:       serr.ErrorCode(),
-						Message:    serr.ErrorMessage(),
-						BucketName: bucket,
-						Key:        object,
-						Resource:   r.URL.Path,
-						RequestID:  w.Header().Get(xhttp.AmzRequestID),
-						HostID:     globalDeploymentID,
-					})
-					writeResponse(w, serr.HTTPStatusCode(), encodedErrorResponse, mimeXML)
-				} else {
-					writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL, guessIsBrowserReq(r))
-				}
+			outputObject := pathJoin(rreq.OutputLocation.S3.Prefix, restoreObject)
+			if err := restoreSelectTransitionedObject(rctx, bucket, object, objectAPI, objInfo, rreq, outputObject, r.Header); err != nil {
+				logger.LogIf(rctx, fmt.Errorf("Unable to restore SELECT results of %s/%s to %s/%s: %w",
+					bucket, object, rreq.OutputLocation.S3.BucketName, outputObject, err))
+				logger.LogIf(rctx, recordRestoreFailure(rctx, bucket, object, objectAPI, objInfo, err))
 				return
 			}
-			nr := httptest.NewRecorder()
-			rw := logger.NewResponseWriter(nr)
-			rw.LogErrBody = true
-			rw.LogAllBody = true
-			rreq.SelectParameters.Evaluate(rw)
-			rreq.SelectParameters.Close()
-			return
-		}
-		if err := restoreTransitionedObject(rctx, bucket, object, objectAPI, objInfo, rreq, restoreExpiry); err != nil {
+		} else if err := restoreTransitionedObject(rctx, bucket, object, objectAPI, objInfo, rreq, restoreExpiry); err != nil {
+			logger.LogIf(rctx, fmt.Errorf("Unable to restore transitioned object %s/%s: %w", bucket, object, err))
+			logger.LogIf(rctx, recordRestoreFailure(rctx, bucket, object, objectAPI, objInfo, err))
 			return
 		}
 
@@ -458,6 +458,21 @@ func (client *peerRESTClient) DeleteBucketMetadata(bucket string) error {
 	return nil
 }
 
+// UpdateMemCache - sends invalidations and cached objects of the
+// in-memory cache to the peer.
+func (client *peerRESTClient) UpdateMemCache(update memCacheUpdate) error {
+	var reader bytes.Buffer
+	if err := gob.NewEncoder(&reader).Encode(update); err != nil {
+		return err
+	}
+	respBody, err := client.call(peerRESTMethodUpdateMemCache, nil, &reader, -1)
+	if err != nil {
+		return err
+	}
+	defer http.DrainBody(respBody)
+	return nil
+}
+
 // cycleServerBloomFilter will cycle the bloom filter to start recording to index y if not already.
 // The response will contain a bloom filter starting at index x up to, but not including index y.
 // If y is 0, the response will not update y, but return the currently recorded information
@@ -17,7 +17,7 @@
 package cmd
 
 const (
-	peerRESTVersion       = "v12"
+	peerRESTVersion       = "v13"
 	peerRESTVersionPrefix = SlashSeparator + peerRESTVersion
 	peerRESTPrefix        = minioReservedBucketPath + "/peer"
 	peerRESTPath          = peerRESTPrefix + peerRESTVersionPrefix
@@ -59,6 +59,7 @@ const (
 	peerRESTMethodGetMetacacheListing    = "/getmetacache"
 	peerRESTMethodUpdateMetacacheListing = "/updatemetacache"
 	peerRESTMethodGetPeerMetrics         = "/peermetrics"
+	peerRESTMethodUpdateMemCache         = "/updatememcache"
 )
 
 const (
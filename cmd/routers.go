@@ -69,6 +69,8 @@ var globalHandlers = []mux.MiddlewareFunc{
 	setHTTPStatsHandler,
 	// Validate all the incoming requests.
 	setRequestValidityHandler,
+	// Rate limit S3 API requests by access key, bucket or source IP.
+	setRateLimitHandler,
 	// Forward path style requests to actual host in a bucket federated setup.
 	setBucketForwardingHandler,
 	// set HTTP security headers such as Content-Security-Policy.
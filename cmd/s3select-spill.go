@@ -0,0 +1,40 @@
+/*
+ * MinIO Cloud Storage, (C) 2021 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import "github.com/minio/minio/pkg/s3select/sql"
+
+// initSelectSpillDir makes S3 Select write the temporary files of large
+// GROUP BY and ORDER BY queries to the tmp directory of a local drive,
+// which is cleaned up on restart.
+func initSelectSpillDir(objAPI ObjectLayer) {
+	switch z := objAPI.(type) {
+	case *erasureServerPools:
+		getDisks := localOnlineDisks(z)
+		sql.SpillDir = func() string {
+			if disks := getDisks(); len(disks) > 0 {
+				return pathJoin(disks[0].Endpoint().Path, minioMetaTmpBucket)
+			}
+			return ""
+		}
+	case *FSObjects:
+		dir := pathJoin(z.fsPath, minioMetaTmpBucket)
+		sql.SpillDir = func() string {
+			return dir
+		}
+	}
+}
@@ -226,6 +226,15 @@ func newAllSubsystems() {
 	// Create new bucket quota subsystem
 	globalBucketQuotaSys = NewBucketQuotaSys()
 
+	// Create new user quota subsystem
+	globalUserQuotaSys = NewUserQuotaSys()
+
+	// Create new batch jobs subsystem
+	globalBatchJobsSys = NewBatchJobsSys()
+
+	// Create new bandwidth limits subsystem
+	globalBandwidthLimitsSys = NewBandwidthLimitsSys()
+
 	// Create new bucket versioning subsystem
 	if globalBucketVersioningSys == nil {
 		globalBucketVersioningSys = NewBucketVersioningSys()
@@ -502,6 +511,8 @@ func serverMain(ctx *cli.Context) {
 
 	logger.SetDeploymentID(globalDeploymentID)
 
+	initSelectSpillDir(newObject)
+
 	// Enable background operations for erasure coding
 	if globalIsErasure {
 		initAutoHeal(GlobalContext, newObject)
@@ -528,6 +539,10 @@ func serverMain(ctx *cli.Context) {
 	if globalIsErasure { // to be done after config init
 		initBackgroundReplication(GlobalContext, newObject)
 	}
+
+	// Resume the batch jobs interrupted by a restart.
+	globalBatchJobsSys.Init(GlobalContext, newObject)
+
 	if globalCacheConfig.Enabled {
 		// initialize the new disk cache objects.
 		var cacheAPI CacheObjectLayer
@@ -537,6 +552,11 @@ func serverMain(ctx *cli.Context) {
 		setCacheObjectLayer(cacheAPI)
 	}
 
+	if globalCacheConfig.MemorySize > 0 {
+		// initialize the in-memory cache in front of the disk cache, if any.
+		setCacheObjectLayer(newMemCacheObjects(globalCacheConfig, newCachedObjectLayerFn()))
+	}
+
 	// Initialize users credentials and policies in background right after config has initialized.
 	go globalIAMSys.Init(GlobalContext, newObject)
 
@@ -1441,6 +1441,15 @@ func getBucketLifecycleURL(endPoint, bucketName string) (ret string) {
 	return makeTestTargetURL(endPoint, bucketName, "", queryValue)
 }
 
+func getBucketInventoryURL(endPoint, bucketName, id string) (ret string) {
+	queryValue := url.Values{}
+	queryValue.Set("inventory", "")
+	if id != "" {
+		queryValue.Set("id", id)
+	}
+	return makeTestTargetURL(endPoint, bucketName, "", queryValue)
+}
+
 // return URL for listing objects in the bucket with V1 legacy API.
 func getListObjectsV1URL(endPoint, bucketName, prefix, maxKeys, encodingType string) string {
 	queryValue := url.Values{}
@@ -2043,12 +2052,22 @@ func registerBucketLevelFunc(bucket *mux.Router, api objectAPIHandlers, apiFunct
 		case "GetBucketPolicy":
 			// Register Get Bucket policy HTTP Handler.
 			bucket.Methods(http.MethodGet).HandlerFunc(api.GetBucketPolicyHandler).Queries("policy", "")
+		case "SelectObjectsContent":
+			bucket.Methods(http.MethodPost).HandlerFunc(api.SelectObjectsContentHandler).Queries("select", "").Queries("select-type", "2")
 		case "GetBucketLifecycle":
 			bucket.Methods(http.MethodGet).HandlerFunc(api.GetBucketLifecycleHandler).Queries("lifecycle", "")
 		case "PutBucketLifecycle":
 			bucket.Methods(http.MethodPut).HandlerFunc(api.PutBucketLifecycleHandler).Queries("lifecycle", "")
 		case "DeleteBucketLifecycle":
 			bucket.Methods(http.MethodDelete).HandlerFunc(api.DeleteBucketLifecycleHandler).Queries("lifecycle", "")
+		case "GetBucketInventoryConfiguration":
+			bucket.Methods(http.MethodGet).HandlerFunc(api.GetBucketInventoryConfigurationHandler).Queries("inventory", "", "id", "{id:.*}")
+		case "ListBucketInventoryConfigurations":
+			bucket.Methods(http.MethodGet).HandlerFunc(api.ListBucketInventoryConfigurationsHandler).Queries("inventory", "")
+		case "PutBucketInventoryConfiguration":
+			bucket.Methods(http.MethodPut).HandlerFunc(api.PutBucketInventoryConfigurationHandler).Queries("inventory", "")
+		case "DeleteBucketInventoryConfiguration":
+			bucket.Methods(http.MethodDelete).HandlerFunc(api.DeleteBucketInventoryConfigurationHandler).Queries("inventory", "")
 		case "GetBucketLocation":
 			// Register GetBucketLocation handler.
 			bucket.Methods(http.MethodGet).HandlerFunc(api.GetBucketLocationHandler).Queries("location", "")
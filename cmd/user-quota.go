@@ -0,0 +1,302 @@
+/*
+ * MinIO Cloud Storage, (C) 2021 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/minio/minio/pkg/auth"
+	"github.com/minio/minio/pkg/madmin"
+)
+
+const (
+	// Owner of an object, the parent user of the credentials it was
+	// uploaded with. The scanner accumulates the size of the objects
+	// of each owner to enforce user and group quotas.
+	objectOwnerKey = ReservedMetadataPrefix + "owner"
+
+	// User and group quotas are saved in a single entry of the IAM
+	// store, they are shared with the users and groups in etcd.
+	userQuotaConfigFile = iamConfigPrefix + SlashSeparator + "quotas.json"
+
+	userQuotaConfigVersion = 1
+
+	// TTL of the quotas loaded from the backend, quotas set on
+	// another node are enforced on this node after at most this long.
+	userQuotaConfigCacheTTL = 10 * time.Second
+)
+
+// userQuotaConfig holds the quotas of the users and groups, in bytes.
+type userQuotaConfig struct {
+	Version int               `json:"version"`
+	Users   map[string]uint64 `json:"users,omitempty"`
+	Groups  map[string]uint64 `json:"groups,omitempty"`
+}
+
+func (c userQuotaConfig) quota(name string, isGroup bool) uint64 {
+	if isGroup {
+		return c.Groups[name]
+	}
+	return c.Users[name]
+}
+
+// UserQuotaSys enforces the quotas of users and groups, on the size
+// of the objects they own across all the buckets.
+type UserQuotaSys struct {
+	mu          sync.Mutex // serializes updates of the quotas.
+	configCache timedValue
+	usageCache  timedValue
+}
+
+// NewUserQuotaSys returns initialized UserQuotaSys
+func NewUserQuotaSys() *UserQuotaSys {
+	return &UserQuotaSys{}
+}
+
+func loadUserQuotaConfig(ctx context.Context, objAPI ObjectLayer) (cfg userQuotaConfig, err error) {
+	if err = newIAMStore(objAPI).loadIAMConfig(ctx, &cfg, userQuotaConfigFile); err != nil {
+		if errors.Is(err, errConfigNotFound) {
+			return userQuotaConfig{Version: userQuotaConfigVersion}, nil
+		}
+		return cfg, err
+	}
+	return cfg, nil
+}
+
+func (sys *UserQuotaSys) config(objAPI ObjectLayer) (userQuotaConfig, error) {
+	sys.configCache.Once.Do(func() {
+		sys.configCache.TTL = userQuotaConfigCacheTTL
+		sys.configCache.Update = func() (interface{}, error) {
+			ctx, done := context.WithTimeout(context.Background(), 5*time.Second)
+			defer done()
+			return loadUserQuotaConfig(ctx, objAPI)
+		}
+	})
+	v, err := sys.configCache.Get()
+	if err != nil {
+		return userQuotaConfig{}, err
+	}
+	return v.(userQuotaConfig), nil
+}
+
+// Get returns the quota of the user or group.
+func (sys *UserQuotaSys) Get(objAPI ObjectLayer, name string, isGroup bool) (madmin.UserQuota, error) {
+	cfg, err := sys.config(objAPI)
+	if err != nil {
+		return madmin.UserQuota{}, err
+	}
+	return madmin.UserQuota{Quota: cfg.quota(name, isGroup)}, nil
+}
+
+// Set sets the quota of the user or group, a quota of 0 removes it.
+func (sys *UserQuotaSys) Set(ctx context.Context, objAPI ObjectLayer, name string, isGroup bool, quota madmin.UserQuota) error {
+	sys.mu.Lock()
+	defer sys.mu.Unlock()
+
+	cfg, err := loadUserQuotaConfig(ctx, objAPI)
+	if err != nil {
+		return err
+	}
+	quotas := cfg.Users
+	if isGroup {
+		quotas = cfg.Groups
+	}
+	// Copy the quotas, the cached config must not be modified.
+	updated := make(map[string]uint64, len(quotas)+1)
+	for k, v := range quotas {
+		updated[k] = v
+	}
+	if quota.Quota > 0 {
+		updated[name] = quota.Quota
+	} else {
+		delete(updated, name)
+	}
+	if isGroup {
+		cfg.Groups = updated
+	} else {
+		cfg.Users = updated
+	}
+	cfg.Version = userQuotaConfigVersion
+
+	if err = newIAMStore(objAPI).saveIAMConfig(ctx, cfg, userQuotaConfigFile); err != nil {
+		return err
+	}
+	sys.configCache.update(cfg)
+	return nil
+}
+
+func (sys *UserQuotaSys) dataUsage(objAPI ObjectLayer) (DataUsageInfo, error) {
+	sys.usageCache.Once.Do(func() {
+		sys.usageCache.TTL = 1 * time.Second
+		sys.usageCache.Update = func() (interface{}, error) {
+			ctx, done := context.WithTimeout(context.Background(), 5*time.Second)
+			defer done()
+			return loadDataUsageFromBackend(ctx, objAPI)
+		}
+	})
+	v, err := sys.usageCache.Get()
+	if err != nil {
+		return DataUsageInfo{}, err
+	}
+	return v.(DataUsageInfo), nil
+}
+
+// Usage returns the size of the objects owned by the user, or by
+// the members of the group, as of the last scan.
+func (sys *UserQuotaSys) Usage(objAPI ObjectLayer, name string, isGroup bool) (size uint64, lastUpdate time.Time, err error) {
+	dui, err := sys.dataUsage(objAPI)
+	if err != nil {
+		return 0, lastUpdate, err
+	}
+	if !isGroup {
+		return dui.OwnersUsage[name], dui.LastUpdate, nil
+	}
+	gd, err := globalIAMSys.GetGroupDescription(name)
+	if err != nil {
+		return 0, lastUpdate, err
+	}
+	for _, member := range gd.Members {
+		size += dui.OwnersUsage[member]
+	}
+	return size, dui.LastUpdate, nil
+}
+
+// objectOwner returns the owner of the objects uploaded with the
+// credentials, service accounts and temporary credentials upload
+// objects on behalf of their parent user.
+func objectOwner(cred auth.Credentials) string {
+	if cred.ParentUser != "" {
+		return cred.ParentUser
+	}
+	return cred.AccessKey
+}
+
+// setObjectOwner records the owner of the credentials in the
+// metadata of the object being uploaded.
+func setObjectOwner(metadata map[string]string, cred auth.Credentials) {
+	if owner := objectOwner(cred); owner != "" {
+		metadata[objectOwnerKey] = owner
+	}
+}
+
+// ownerGroups returns the groups the owner of the credentials is a member of.
+func ownerGroups(owner string, cred auth.Credentials) []string {
+	groups := append([]string{}, cred.Groups...)
+	if u, err := globalIAMSys.GetUserInfo(owner); err == nil {
+		groups = append(groups, u.MemberOf...)
+	}
+	return groups
+}
+
+// enforced returns true if uploads with the credentials may be
+// rejected by a user or group quota.
+func (sys *UserQuotaSys) enforced(objAPI ObjectLayer, cred auth.Credentials) bool {
+	if globalIsGateway || objectOwner(cred) == "" {
+		return false
+	}
+	cfg, err := sys.config(objAPI)
+	if err != nil {
+		// Let the check report the error.
+		return true
+	}
+	return len(cfg.Users) > 0 || len(cfg.Groups) > 0
+}
+
+func (sys *UserQuotaSys) check(ctx context.Context, cred auth.Credentials, size int64) error {
+	objAPI := newObjectLayerFn()
+	if objAPI == nil {
+		return errServerNotInitialized
+	}
+
+	if !sys.enforced(objAPI, cred) {
+		return nil
+	}
+	owner := objectOwner(cred)
+	cfg, err := sys.config(objAPI)
+	if err != nil {
+		return err
+	}
+
+	if q := cfg.Users[owner]; q > 0 {
+		used, _, err := sys.Usage(objAPI, owner, false)
+		if err != nil {
+			return err
+		}
+		if used+uint64(size) >= q {
+			return UserQuotaExceeded{Name: owner}
+		}
+	}
+	if len(cfg.Groups) == 0 {
+		return nil
+	}
+	for _, group := range ownerGroups(owner, cred) {
+		q := cfg.Groups[group]
+		if q == 0 {
+			continue
+		}
+		used, _, err := sys.Usage(objAPI, group, true)
+		if err != nil {
+			// Groups of external identity providers have no
+			// known members, their usage cannot be computed.
+			if errors.Is(err, errNoSuchGroup) {
+				continue
+			}
+			return err
+		}
+		if used+uint64(size) >= q {
+			return UserQuotaExceeded{Name: group, IsGroup: true}
+		}
+	}
+	return nil
+}
+
+// completedPartsSize returns the size of the object the parts of the
+// multipart upload are completed into.
+func completedPartsSize(ctx context.Context, objAPI ObjectLayer, bucket, object, uploadID string, parts []CompletePart) (size int64, err error) {
+	completed := make(map[int]struct{}, len(parts))
+	for _, part := range parts {
+		completed[part.PartNumber] = struct{}{}
+	}
+	listPartsInfo, err := objAPI.ListObjectParts(ctx, bucket, object, uploadID, 0, maxPartsList, ObjectOptions{})
+	if err != nil {
+		return 0, err
+	}
+	for _, part := range listPartsInfo.Parts {
+		if _, ok := completed[part.PartNumber]; !ok {
+			continue
+		}
+		if part.ActualSize > 0 {
+			size += part.ActualSize
+		} else {
+			size += part.Size
+		}
+	}
+	return size, nil
+}
+
+// enforceUserQuota rejects an upload of size bytes by the owner of the
+// credentials if it exceeds the quota of the owner or of its groups.
+func enforceUserQuota(ctx context.Context, cred auth.Credentials, size int64) error {
+	if size < 0 {
+		return nil
+	}
+
+	return globalUserQuotaSys.check(ctx, cred, size)
+}
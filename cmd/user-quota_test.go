@@ -0,0 +1,107 @@
+/*
+ * MinIO Cloud Storage, (C) 2021 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/minio/minio/pkg/auth"
+	"github.com/minio/minio/pkg/madmin"
+)
+
+func TestDataUsageEntryOwners(t *testing.T) {
+	var cache dataUsageCache
+	cache.replace("bucket", "", dataUsageEntry{Size: 10, Owners: map[string]int64{"alice": 10}})
+	cache.replace("bucket/dir", "bucket", dataUsageEntry{Size: 30, Owners: map[string]int64{"alice": 10, "bob": 20}})
+
+	flat := cache.sizeRecursive("bucket")
+	if flat.Owners["alice"] != 20 || flat.Owners["bob"] != 20 {
+		t.Fatalf("unexpected owners %v", flat.Owners)
+	}
+	// Flattening must not modify the cached entries.
+	if e := cache.find("bucket"); e.Owners["alice"] != 10 || len(e.Owners) != 1 {
+		t.Fatalf("cached owners modified: %v", e.Owners)
+	}
+
+	var s sizeSummary
+	s.addOwnerSize(ObjectInfo{UserDefined: map[string]string{objectOwnerKey: "alice"}}, 5)
+	s.addOwnerSize(ObjectInfo{}, 5)
+	var e dataUsageEntry
+	e.addSizes(s)
+	if len(e.Owners) != 1 || e.Owners["alice"] != 5 {
+		t.Fatalf("unexpected owners %v", e.Owners)
+	}
+}
+
+func TestUserQuota(t *testing.T) {
+	objAPI, fsDir, err := prepareFS()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(fsDir)
+	newAllSubsystems()
+	setObjectLayer(objAPI)
+	defer setObjectLayer(nil)
+
+	ctx := context.Background()
+	sys := NewUserQuotaSys()
+	if err = sys.Set(ctx, objAPI, "alice", false, madmin.UserQuota{Quota: 100}); err != nil {
+		t.Fatal(err)
+	}
+	if err = sys.Set(ctx, objAPI, "bob", false, madmin.UserQuota{Quota: 100}); err != nil {
+		t.Fatal(err)
+	}
+	if err = sys.Set(ctx, objAPI, "bob", false, madmin.UserQuota{}); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := loadUserQuotaConfig(ctx, objAPI)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.quota("alice", false) != 100 || cfg.quota("bob", false) != 0 {
+		t.Fatalf("unexpected quotas %v", cfg.Users)
+	}
+
+	sys.usageCache.Once.Do(func() {})
+	sys.usageCache.TTL = time.Hour
+	sys.usageCache.update(DataUsageInfo{OwnersUsage: map[string]uint64{"alice": 90, "bob": 1000}})
+
+	testCases := []struct {
+		cred     auth.Credentials
+		size     int64
+		exceeded bool
+	}{
+		{auth.Credentials{AccessKey: "alice"}, 5, false},
+		{auth.Credentials{AccessKey: "alice"}, 10, true},
+		// Service accounts upload on behalf of their parent user.
+		{auth.Credentials{AccessKey: "svc", ParentUser: "alice"}, 10, true},
+		{auth.Credentials{AccessKey: "bob"}, 10, false},
+		{auth.Credentials{}, 1000, false},
+	}
+	for i, tc := range testCases {
+		err := sys.check(ctx, tc.cred, tc.size)
+		var qerr UserQuotaExceeded
+		if exceeded := errors.As(err, &qerr); exceeded != tc.exceeded {
+			t.Errorf("Test %d: expected exceeded %v, got %v", i+1, tc.exceeded, err)
+		}
+	}
+}
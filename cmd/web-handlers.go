@@ -709,6 +709,12 @@ func (web *webAPIHandlers) RemoveObject(r *http.Request, args *RemoveObjectArgs,
 	opts := ObjectOptions{
 		Versioned:        globalBucketVersioningSys.Enabled(args.BucketName),
 		VersionSuspended: globalBucketVersioningSys.Suspended(args.BucketName),
+		PrefixEnabledFn: func(prefix string) bool {
+			return globalBucketVersioningSys.PrefixEnabled(args.BucketName, prefix)
+		},
+		PrefixSuspendedFn: func(prefix string) bool {
+			return globalBucketVersioningSys.PrefixSuspended(args.BucketName, prefix)
+		},
 	}
 	var (
 		err           error
@@ -1217,7 +1223,13 @@ func (web *webAPIHandlers) Upload(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := enforceBucketQuota(ctx, bucket, size); err != nil {
+	if err := enforceBucketQuota(ctx, bucket, object, size); err != nil {
+		writeWebErrorResponse(w, err)
+		return
+	}
+
+	cred := getReqAccessCred(r, globalServerRegion)
+	if err := enforceUserQuota(ctx, cred, size); err != nil {
 		writeWebErrorResponse(w, err)
 		return
 	}
@@ -1228,9 +1240,10 @@ func (web *webAPIHandlers) Upload(w http.ResponseWriter, r *http.Request) {
 		writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL, guessIsBrowserReq(r))
 		return
 	}
+	setObjectOwner(metadata, cred)
 
 	var pReader *PutObjReader
-	var reader io.Reader = r.Body
+	var reader io.Reader = newClientBandwidthReader(ctx, bucket, cred, r.Body)
 	actualSize := size
 
 	hashReader, err := hash.NewReader(reader, size, "", "", actualSize, globalCLIContext.StrictS3Compat)
@@ -1505,7 +1518,14 @@ func (web *webAPIHandlers) Download(w http.ResponseWriter, r *http.Request) {
 
 	setHeadGetRespHeaders(w, r.URL.Query())
 
-	httpWriter := ioutil.WriteOnClose(w)
+	var cred auth.Credentials
+	switch {
+	case owner:
+		cred = globalActiveCred
+	case authErr == nil:
+		cred, _ = globalIAMSys.GetUser(claims.AccessKey)
+	}
+	httpWriter := ioutil.WriteOnClose(newClientBandwidthWriter(ctx, bucket, cred, w))
 
 	// Write object content to response body
 	if _, err = io.Copy(httpWriter, gr); err != nil {
@@ -2394,6 +2414,10 @@ func toWebAPIError(ctx context.Context, err error) APIError {
 		return getAPIError(ErrStorageFull)
 	case BucketQuotaExceeded:
 		return getAPIError(ErrAdminBucketQuotaExceeded)
+	case PrefixQuotaExceeded:
+		return getAPIError(ErrAdminPrefixQuotaExceeded)
+	case UserQuotaExceeded:
+		return getAPIError(ErrAdminUserQuotaExceeded)
 	case BucketNotFound:
 		return getAPIError(ErrNoSuchBucket)
 	case BucketNotEmpty:
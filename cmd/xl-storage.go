@@ -381,10 +381,12 @@ func (s *xlStorage) CrawlAndGetDataUsage(ctx context.Context, cache dataUsageCac
 		for _, version := range fivs.Versions {
 			oi := version.ToObjectInfo(item.bucket, item.objectPath())
 			if objAPI != nil {
-				totalSize += item.applyActions(ctx, objAPI, actionMeta{
+				size := item.applyActions(ctx, objAPI, actionMeta{
 					oi:         oi,
 					bitRotScan: healOpts.Bitrot,
 				})
+				totalSize += size
+				sizeS.addOwnerSize(oi, size)
 				item.healReplication(ctx, objAPI, oi.Clone(), &sizeS)
 			}
 		}
@@ -20,9 +20,20 @@ package bandwidth
 type Details struct {
 	LimitInBytesPerSecond            int64   `json:"limitInBits"`
 	CurrentBandwidthInBytesPerSecond float64 `json:"currentBandwidth"`
+	// Bandwidth details of each replication target of the bucket, by ARN.
+	TargetStats map[string]Details `json:"targetStats,omitempty"`
+}
+
+// ClientDetails for the measured bandwidth of the client traffic
+type ClientDetails struct {
+	Get Details `json:"get"` // Data downloaded by clients
+	Put Details `json:"put"` // Data uploaded by clients
 }
 
 // Report captures the details for all buckets.
 type Report struct {
 	BucketStats map[string]Details `json:"bucketStats,omitempty"`
+	// Bandwidth details of the client traffic of each bucket and user.
+	BucketClientStats map[string]ClientDetails `json:"bucketClientStats,omitempty"`
+	UserClientStats   map[string]ClientDetails `json:"userClientStats,omitempty"`
 }
@@ -0,0 +1,186 @@
+/*
+ * MinIO Cloud Storage, (C) 2021 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package bandwidth
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+// clientIdleTimeout is how long the client traffic of a bucket or of a
+// user is monitored without requests or transferred bytes.
+const clientIdleTimeout = 5 * time.Minute
+
+// clientKey identifies the client traffic of a bucket or of a user,
+// in one direction.
+type clientKey struct {
+	name string
+	user bool
+	put  bool
+}
+
+// ClientLimit is the bandwidth limit of the client traffic of a
+// bucket or of a user, in one direction.
+type ClientLimit struct {
+	BytesPerSecond   int64 // Limit of this node, 0 for no limit
+	ClusterBandwidth int64 // Limit of the cluster, for reporting
+}
+
+// ClientTraffic describes the data transferred by a client request.
+type ClientTraffic struct {
+	Bucket      string
+	User        string      // Empty for anonymous requests
+	Put         bool        // Data is uploaded, otherwise it is downloaded
+	BucketLimit ClientLimit // Limit of the bucket in the direction of the traffic
+	UserLimit   ClientLimit // Limit of the user in the direction of the traffic
+}
+
+// clientTracker measures and throttles the traffic of a client request.
+type clientTracker struct {
+	measurements []*bucketMeasurement
+	throttles    []*throttle
+}
+
+// throttleClient gets the throttle of the client traffic with the configured value
+// must be called with the lock held.
+func (m *Monitor) throttleClient(ctx context.Context, key clientKey, limit ClientLimit) *throttle {
+	throttle, ok := m.clientThrottle[key]
+	if !ok || (throttle.cond == nil && limit.BytesPerSecond > 0) {
+		// Throttles without a limit cannot be given one.
+		throttle = newThrottle(ctx, limit.BytesPerSecond, limit.ClusterBandwidth)
+		m.clientThrottle[key] = throttle
+		return throttle
+	}
+	throttle.SetBandwidth(limit.BytesPerSecond, limit.ClusterBandwidth)
+	return throttle
+}
+
+// trackClient returns the measurements and throttles of the client traffic,
+// ctx bounds the lifetime of the throttles shared by all the requests.
+func (m *Monitor) trackClient(ctx context.Context, traffic ClientTraffic, timeNow time.Time) *clientTracker {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.startProcessing.Do(func() {
+		go m.process(m.doneCh)
+	})
+
+	t := &clientTracker{}
+	track := func(key clientKey, limit ClientLimit) {
+		measurement, ok := m.activeClients[key]
+		if !ok {
+			measurement = newBucketMeasurement(timeNow)
+			m.activeClients[key] = measurement
+		}
+		m.clientLastActive[key] = timeNow
+		t.measurements = append(t.measurements, measurement)
+		t.throttles = append(t.throttles, m.throttleClient(ctx, key, limit))
+	}
+	track(clientKey{name: traffic.Bucket, put: traffic.Put}, traffic.BucketLimit)
+	if traffic.User != "" {
+		track(clientKey{name: traffic.User, user: true, put: traffic.Put}, traffic.UserLimit)
+	}
+	return t
+}
+
+// limit returns the bytes that are possible to transfer within the limits
+// of all the throttles, at least one byte is always returned.
+func (t *clientTracker) limit(want int) int {
+	allowed := int64(want)
+	granted := make([]int64, len(t.throttles))
+	for i, throttle := range t.throttles {
+		allowed = throttle.GetLimitForBytes(allowed)
+		granted[i] = allowed
+	}
+	// Release what was granted above the most restrictive throttle.
+	for i, throttle := range t.throttles {
+		if unused := granted[i] - allowed; unused > 0 {
+			throttle.ReleaseUnusedBandwidth(unused)
+		}
+	}
+	return int(allowed)
+}
+
+// transferred reports n bytes transferred out of the allowed bytes.
+func (t *clientTracker) transferred(allowed, n int) {
+	for _, measurement := range t.measurements {
+		measurement.incrementBytes(uint64(n))
+	}
+	if unused := allowed - n; unused > 0 {
+		for _, throttle := range t.throttles {
+			throttle.ReleaseUnusedBandwidth(int64(unused))
+		}
+	}
+}
+
+type clientReader struct {
+	reader  io.Reader
+	tracker *clientTracker
+}
+
+func (r *clientReader) Read(p []byte) (n int, err error) {
+	if len(p) == 0 {
+		return r.reader.Read(p)
+	}
+	p = p[:r.tracker.limit(len(p))]
+	n, err = r.reader.Read(p)
+	r.tracker.transferred(len(p), n)
+	return n, err
+}
+
+// NewClientReader returns a reader that reports the bandwidth of the data
+// uploaded by a client request, throttled by the limits of its bucket and user.
+// ctx bounds the lifetime of the throttles, it must outlive the request.
+func NewClientReader(ctx context.Context, monitor *Monitor, traffic ClientTraffic, reader io.Reader) io.Reader {
+	traffic.Put = true
+	return &clientReader{
+		reader:  reader,
+		tracker: monitor.trackClient(ctx, traffic, time.Now()),
+	}
+}
+
+type clientWriter struct {
+	writer  io.Writer
+	tracker *clientTracker
+}
+
+func (w *clientWriter) Write(p []byte) (n int, err error) {
+	for len(p) > 0 {
+		allowed := w.tracker.limit(len(p))
+		var written int
+		written, err = w.writer.Write(p[:allowed])
+		w.tracker.transferred(allowed, written)
+		n += written
+		if err != nil {
+			return n, err
+		}
+		p = p[written:]
+	}
+	return n, nil
+}
+
+// NewClientWriter returns a writer that reports the bandwidth of the data
+// downloaded by a client request, throttled by the limits of its bucket and user.
+// ctx bounds the lifetime of the throttles, it must outlive the request.
+func NewClientWriter(ctx context.Context, monitor *Monitor, traffic ClientTraffic, writer io.Writer) io.Writer {
+	traffic.Put = false
+	return &clientWriter{
+		writer:  writer,
+		tracker: monitor.trackClient(ctx, traffic, time.Now()),
+	}
+}
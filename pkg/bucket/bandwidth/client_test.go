@@ -0,0 +1,162 @@
+/*
+ * MinIO Cloud Storage, (C) 2021 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package bandwidth
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"testing"
+	"time"
+)
+
+func TestClientTrackerLimit(t *testing.T) {
+	// Bandwidth is not generated once the context is done.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	bucket := newThrottle(ctx, 4000, 4000) // 1000 bytes per interval
+	user := newThrottle(ctx, 400, 400)     // 100 bytes per interval
+	tracker := &clientTracker{throttles: []*throttle{bucket, &throttle{}, user}}
+
+	if got := tracker.limit(500); got != 100 {
+		t.Fatalf("expected the most restrictive limit of 100 bytes, got %d", got)
+	}
+	if bucket.freeBytes != 900 || user.freeBytes != 0 {
+		t.Fatalf("unexpected free bytes %d and %d", bucket.freeBytes, user.freeBytes)
+	}
+	tracker.transferred(100, 60)
+	if bucket.freeBytes != 940 || user.freeBytes != 40 {
+		t.Fatalf("expected the unused bytes to be released, got %d and %d", bucket.freeBytes, user.freeBytes)
+	}
+}
+
+func TestClientReport(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	m := NewMonitor(ctx.Done())
+	traffic := ClientTraffic{
+		Bucket:      "bucket",
+		User:        "alice",
+		BucketLimit: ClientLimit{BytesPerSecond: 4000, ClusterBandwidth: 8000},
+	}
+
+	data := make([]byte, 1500)
+	start := time.Now()
+	n, err := io.Copy(ioutil.Discard, NewClientReader(ctx, m, traffic, bytes.NewReader(data)))
+	if err != nil || n != int64(len(data)) {
+		t.Fatalf("unexpected copy result %d, %v", n, err)
+	}
+	// 1000 bytes are available per interval of 250ms.
+	if elapsed := time.Since(start); elapsed < throttleInternal {
+		t.Fatalf("expected the upload to be throttled, took %s", elapsed)
+	}
+	var buf bytes.Buffer
+	if _, err = NewClientWriter(ctx, m, traffic, &buf).Write(data[:10]); err != nil || buf.Len() != 10 {
+		t.Fatalf("unexpected write result %d, %v", buf.Len(), err)
+	}
+
+	report := m.GetReport(SelectBuckets())
+	if report.BucketClientStats["bucket"].Put.LimitInBytesPerSecond != 8000 {
+		t.Fatalf("unexpected bucket stats %v", report.BucketClientStats)
+	}
+	if _, ok := report.UserClientStats["alice"]; !ok {
+		t.Fatalf("expected user stats, got %v", report.UserClientStats)
+	}
+	// Users are only reported along with all the buckets.
+	if report = m.GetReport(SelectBuckets("bucket")); report.UserClientStats != nil {
+		t.Fatalf("unexpected user stats %v", report.UserClientStats)
+	}
+
+	m.DeleteBucket("bucket")
+	report = m.GetReport(SelectBuckets())
+	if _, ok := report.BucketClientStats["bucket"]; ok {
+		t.Fatal("expected the client stats of a deleted bucket to be removed")
+	}
+	if _, ok := report.UserClientStats["alice"]; !ok {
+		t.Fatal("expected the client stats of users to be kept")
+	}
+}
+
+func TestClientIdleEviction(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	m := NewMonitor(ctx.Done())
+	traffic := ClientTraffic{
+		Bucket:      "bucket",
+		User:        "alice",
+		BucketLimit: ClientLimit{BytesPerSecond: 4000, ClusterBandwidth: 8000},
+	}
+	if _, err := io.Copy(ioutil.Discard, NewClientReader(ctx, m, traffic, bytes.NewReader(make([]byte, 10)))); err != nil {
+		t.Fatal(err)
+	}
+	busy := clientKey{name: "bucket", put: true}
+
+	// Clients with transfers in the last window are kept.
+	m.lock.Lock()
+	for key := range m.clientLastActive {
+		m.clientLastActive[key] = time.Now().Add(-2 * clientIdleTimeout)
+	}
+	m.lock.Unlock()
+	m.processAvg()
+	if len(m.activeClients) != 2 || len(m.clientThrottle) != 2 {
+		t.Fatalf("expected active clients to be kept, got %v", m.activeClients)
+	}
+
+	// Idle clients are evicted, the others are kept.
+	m.lock.Lock()
+	m.clientLastActive[busy] = time.Now().Add(-2 * clientIdleTimeout)
+	m.lock.Unlock()
+	m.processAvg()
+	if _, ok := m.activeClients[busy]; ok || len(m.activeClients) != 1 || len(m.clientThrottle) != 1 || len(m.clientLastActive) != 1 {
+		t.Fatalf("expected the idle client to be evicted, got %v", m.activeClients)
+	}
+}
+
+func TestThrottleSetBandwidth(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Limits below a byte per interval still limit.
+	throttle := newThrottle(ctx, 1, 1)
+	if throttle.bytesPerInterval != 1 {
+		t.Fatalf("expected 1 byte per interval, got %d", throttle.bytesPerInterval)
+	}
+	if got := throttle.GetLimitForBytes(100); got != 1 {
+		t.Fatalf("expected 1 byte, got %d", got)
+	}
+
+	// The cluster bandwidth is updated while it is reported.
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := int64(1); i <= 100; i++ {
+			throttle.SetBandwidth(i, i*2)
+		}
+	}()
+	for i := 0; i < 100; i++ {
+		throttle.getClusterBandwidth()
+	}
+	<-done
+	if throttle.getClusterBandwidth() != 200 {
+		t.Fatalf("unexpected cluster bandwidth %d", throttle.getClusterBandwidth())
+	}
+}
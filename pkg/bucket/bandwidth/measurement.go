@@ -47,8 +47,9 @@ func (m *bucketMeasurement) incrementBytes(bytes uint64) {
 	atomic.AddUint64(&m.bytesSinceLastWindow, bytes)
 }
 
-// updateExponentialMovingAverage processes the measurements captured so far.
-func (m *bucketMeasurement) updateExponentialMovingAverage(endTime time.Time) {
+// updateExponentialMovingAverage processes the measurements captured so far,
+// it returns the bytes transferred since the last window.
+func (m *bucketMeasurement) updateExponentialMovingAverage(endTime time.Time) (bytesSinceLastWindow uint64) {
 	// Calculate aggregate avg bandwidth and exp window avg
 	m.lock.Lock()
 	defer func() {
@@ -62,16 +63,17 @@ func (m *bucketMeasurement) updateExponentialMovingAverage(endTime time.Time) {
 
 	duration := endTime.Sub(m.startTime)
 
-	bytesSinceLastWindow := atomic.SwapUint64(&m.bytesSinceLastWindow, 0)
+	bytesSinceLastWindow = atomic.SwapUint64(&m.bytesSinceLastWindow, 0)
 
 	if m.expMovingAvg == 0 {
 		// Should address initial calculation and should be fine for resuming from 0
 		m.expMovingAvg = float64(bytesSinceLastWindow) / duration.Seconds()
-		return
+		return bytesSinceLastWindow
 	}
 
 	increment := float64(bytesSinceLastWindow) / duration.Seconds()
 	m.expMovingAvg = exponentialMovingAverage(betaBucket, m.expMovingAvg, increment)
+	return bytesSinceLastWindow
 }
 
 // exponentialMovingAverage calculates the exponential moving average
@@ -39,6 +39,20 @@ func (m *Monitor) throttleBandwidth(ctx context.Context, bucket string, bandwidt
 	return throttle
 }
 
+// throttleTargetBandwidth gets the throttle for the replication target with the configured value
+func (m *Monitor) throttleTargetBandwidth(ctx context.Context, arn string, bandwidthBytesPerSecond int64, clusterBandwidth int64) *throttle {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	throttle, ok := m.targetThrottle[arn]
+	if !ok {
+		throttle = newThrottle(ctx, bandwidthBytesPerSecond, clusterBandwidth)
+		m.targetThrottle[arn] = throttle
+		return throttle
+	}
+	throttle.SetBandwidth(bandwidthBytesPerSecond, clusterBandwidth)
+	return throttle
+}
+
 // SubscribeToBuckets subscribes to buckets. Empty array for monitoring all buckets.
 func (m *Monitor) SubscribeToBuckets(subCh chan interface{}, doneCh <-chan struct{}, buckets []string) {
 	m.pubsub.Subscribe(subCh, doneCh, func(f interface{}) bool {
@@ -71,6 +85,14 @@ type Monitor struct {
 
 	bucketThrottle map[string]*throttle
 
+	activeTargets  map[string]*bucketMeasurement // Replication targets with objects in flight, by ARN
+	targetThrottle map[string]*throttle          // Throttle of each replication target, by ARN
+	targetBucket   map[string]string             // Source bucket of each replication target, by ARN
+
+	activeClients    map[clientKey]*bucketMeasurement // Client traffic of buckets and users
+	clientThrottle   map[clientKey]*throttle          // Throttle of the client traffic of buckets and users
+	clientLastActive map[clientKey]time.Time          // Last request or transfer of the client traffic
+
 	startProcessing sync.Once
 
 	doneCh <-chan struct{}
@@ -83,6 +105,12 @@ func NewMonitor(doneCh <-chan struct{}) *Monitor {
 		bucketMovingAvgTicker: time.NewTicker(2 * time.Second),
 		pubsub:                pubsub.New(),
 		bucketThrottle:        make(map[string]*throttle),
+		activeTargets:         make(map[string]*bucketMeasurement),
+		targetThrottle:        make(map[string]*throttle),
+		targetBucket:          make(map[string]string),
+		activeClients:         make(map[clientKey]*bucketMeasurement),
+		clientThrottle:        make(map[clientKey]*throttle),
+		clientLastActive:      make(map[clientKey]time.Time),
 		doneCh:                doneCh,
 	}
 	return m
@@ -123,10 +151,59 @@ func (m *Monitor) getReport(selectBucket SelectionFunction) *bandwidth.Report {
 		if !selectBucket(bucket) {
 			continue
 		}
-		report.BucketStats[bucket] = bandwidth.Details{
-			LimitInBytesPerSecond:            m.bucketThrottle[bucket].clusterBandwidth,
+		details := bandwidth.Details{
 			CurrentBandwidthInBytesPerSecond: bucketMeasurement.getExpMovingAvgBytesPerSecond(),
 		}
+		if throttle, ok := m.bucketThrottle[bucket]; ok {
+			details.LimitInBytesPerSecond = throttle.getClusterBandwidth()
+		}
+		for arn, targetMeasurement := range m.activeTargets {
+			if m.targetBucket[arn] != bucket {
+				continue
+			}
+			if details.TargetStats == nil {
+				details.TargetStats = make(map[string]bandwidth.Details)
+			}
+			var limit int64
+			if throttle, ok := m.targetThrottle[arn]; ok {
+				limit = throttle.getClusterBandwidth()
+			}
+			details.TargetStats[arn] = bandwidth.Details{
+				LimitInBytesPerSecond:            limit,
+				CurrentBandwidthInBytesPerSecond: targetMeasurement.getExpMovingAvgBytesPerSecond(),
+			}
+			if _, ok := m.bucketThrottle[bucket]; !ok {
+				// Bucket is only throttled by its targets.
+				details.LimitInBytesPerSecond += limit
+			}
+		}
+		report.BucketStats[bucket] = details
+	}
+	for key, clientMeasurement := range m.activeClients {
+		// Users are only reported along with all the buckets.
+		if (key.user && !selectBucket("")) || (!key.user && !selectBucket(key.name)) {
+			continue
+		}
+		details := bandwidth.Details{
+			CurrentBandwidthInBytesPerSecond: clientMeasurement.getExpMovingAvgBytesPerSecond(),
+		}
+		if throttle, ok := m.clientThrottle[key]; ok {
+			details.LimitInBytesPerSecond = throttle.getClusterBandwidth()
+		}
+		stats := &report.BucketClientStats
+		if key.user {
+			stats = &report.UserClientStats
+		}
+		if *stats == nil {
+			*stats = make(map[string]bandwidth.ClientDetails)
+		}
+		clientDetails := (*stats)[key.name]
+		if key.put {
+			clientDetails.Put = details
+		} else {
+			clientDetails.Get = details
+		}
+		(*stats)[key.name] = clientDetails
 	}
 	return report
 }
@@ -157,6 +234,17 @@ func (m *Monitor) processAvg() {
 	for _, bucketMeasurement := range m.activeBuckets {
 		bucketMeasurement.updateExponentialMovingAverage(time.Now())
 	}
+	for _, targetMeasurement := range m.activeTargets {
+		targetMeasurement.updateExponentialMovingAverage(time.Now())
+	}
+	now := time.Now()
+	for key, clientMeasurement := range m.activeClients {
+		if clientMeasurement.updateExponentialMovingAverage(now) > 0 {
+			m.clientLastActive[key] = now
+		} else if now.Sub(m.clientLastActive[key]) > clientIdleTimeout {
+			m.deleteClient(key)
+		}
+	}
 	m.pubsub.Publish(m.getReport(SelectBuckets()))
 }
 
@@ -171,10 +259,43 @@ func (m *Monitor) track(bucket string, object string, timeNow time.Time) *bucket
 	return b
 }
 
+// trackTarget returns the measurement object for the replication target
+func (m *Monitor) trackTarget(bucket string, arn string, timeNow time.Time) *bucketMeasurement {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	t, ok := m.activeTargets[arn]
+	if !ok {
+		t = newBucketMeasurement(timeNow)
+		m.activeTargets[arn] = t
+		m.targetBucket[arn] = bucket
+	}
+	return t
+}
+
 // DeleteBucket deletes monitoring the 'bucket'
 func (m *Monitor) DeleteBucket(bucket string) {
 	m.lock.Lock()
 	defer m.lock.Unlock()
 	delete(m.activeBuckets, bucket)
 	delete(m.bucketThrottle, bucket)
+	for arn, b := range m.targetBucket {
+		if b == bucket {
+			delete(m.activeTargets, arn)
+			delete(m.targetThrottle, arn)
+			delete(m.targetBucket, arn)
+		}
+	}
+	for key := range m.activeClients {
+		if !key.user && key.name == bucket {
+			m.deleteClient(key)
+		}
+	}
+}
+
+// deleteClient stops monitoring the client traffic, must be called with
+// the lock held.
+func (m *Monitor) deleteClient(key clientKey) {
+	delete(m.activeClients, key)
+	delete(m.clientThrottle, key)
+	delete(m.clientLastActive, key)
 }
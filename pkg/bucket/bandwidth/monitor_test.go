@@ -156,3 +156,45 @@ func TestMonitor_GetReport(t *testing.T) {
 		})
 	}
 }
+
+func TestMonitor_GetTargetReport(t *testing.T) {
+	start := time.Now()
+	target := newBucketMeasurement(start)
+	target.incrementBytes(oneMiB)
+	target.updateExponentialMovingAverage(start.Add(1 * time.Second))
+	bucket := newBucketMeasurement(start)
+	bucket.incrementBytes(oneMiB)
+	bucket.updateExponentialMovingAverage(start.Add(1 * time.Second))
+
+	m := &Monitor{
+		activeBuckets:  map[string]*bucketMeasurement{"bucket": bucket},
+		bucketThrottle: map[string]*throttle{},
+		activeTargets:  map[string]*bucketMeasurement{"arn1": target, "arn2": newBucketMeasurement(start)},
+		targetThrottle: map[string]*throttle{
+			"arn1": {bytesPerSecond: 1024 * 1024, clusterBandwidth: 1024 * 1024},
+			"arn2": {bytesPerSecond: 2 * 1024 * 1024, clusterBandwidth: 2 * 1024 * 1024},
+		},
+		targetBucket: map[string]string{"arn1": "bucket", "arn2": "other"},
+	}
+	want := &bandwidth.Report{
+		BucketStats: map[string]bandwidth.Details{"bucket": {
+			LimitInBytesPerSecond:            1024 * 1024,
+			CurrentBandwidthInBytesPerSecond: float64(oneMiB),
+			TargetStats: map[string]bandwidth.Details{"arn1": {
+				LimitInBytesPerSecond:            1024 * 1024,
+				CurrentBandwidthInBytesPerSecond: float64(oneMiB),
+			}},
+		}},
+	}
+	if got := m.GetReport(SelectBuckets()); !reflect.DeepEqual(got, want) {
+		t.Errorf("GetReport() = %v, want %v", got, want)
+	}
+
+	m.DeleteBucket("bucket")
+	if _, ok := m.activeTargets["arn1"]; ok {
+		t.Error("expected the targets of a deleted bucket to be removed")
+	}
+	if _, ok := m.activeTargets["arn2"]; !ok {
+		t.Error("expected the targets of other buckets to be kept")
+	}
+}
@@ -27,6 +27,7 @@ import (
 type MonitoredReader struct {
 	bucket            string             // Token to track bucket
 	bucketMeasurement *bucketMeasurement // bucket measurement object
+	targetMeasurement *bucketMeasurement // replication target measurement object, if any
 	object            string             // Token to track object
 	reader            io.ReadCloser      // Reader to wrap
 	lastStop          time.Time          // Last timestamp for a measurement
@@ -53,6 +54,25 @@ func NewMonitoredReader(ctx context.Context, monitor *Monitor, bucket string, ob
 	}
 }
 
+// NewMonitoredTargetReader returns a io.ReadCloser that reports bandwidth details
+// of the bucket and of its replication target, it is throttled by the bandwidth
+// limit of the target. The supplied reader will be closed.
+func NewMonitoredTargetReader(ctx context.Context, monitor *Monitor, bucket string, arn string, object string, reader io.ReadCloser, headerSize int, bandwidthBytesPerSecond int64, clusterBandwidth int64) *MonitoredReader {
+	timeNow := time.Now()
+	b := monitor.track(bucket, object, timeNow)
+	return &MonitoredReader{
+		bucket:            bucket,
+		object:            object,
+		bucketMeasurement: b,
+		targetMeasurement: monitor.trackTarget(bucket, arn, timeNow),
+		reader:            reader,
+		lastStop:          timeNow,
+		headerSize:        headerSize,
+		throttle:          monitor.throttleTargetBandwidth(ctx, arn, bandwidthBytesPerSecond, clusterBandwidth),
+		monitor:           monitor,
+	}
+}
+
 // Read wraps the read reader
 func (m *MonitoredReader) Read(p []byte) (n int, err error) {
 	if m.closed {
@@ -66,6 +86,9 @@ func (m *MonitoredReader) Read(p []byte) (n int, err error) {
 	update := uint64(n + m.headerSize)
 
 	m.bucketMeasurement.incrementBytes(update)
+	if m.targetMeasurement != nil {
+		m.targetMeasurement.incrementBytes(update)
+	}
 	m.lastStop = stop
 	unused := len(p) - (n + m.headerSize)
 	m.headerSize = 0 // Set to 0 post first read
@@ -67,6 +67,10 @@ func (t *throttle) GetLimitForBytes(want int64) int64 {
 	t.cond.L.Lock()
 	defer t.cond.L.Unlock()
 	for {
+		if atomic.LoadInt64(&t.bytesPerInterval) == 0 {
+			// The limit was removed while waiting.
+			return want
+		}
 		var send int64
 		freeBytes := atomic.LoadInt64(&t.freeBytes)
 		send = want
@@ -90,7 +94,17 @@ func (t *throttle) GetLimitForBytes(want int64) int64 {
 // SetBandwidth sets a new bandwidth limit in bytes per second.
 func (t *throttle) SetBandwidth(bandwidthBiPS int64, clusterBandwidth int64) {
 	bpi := int64(throttleInternal) * bandwidthBiPS / int64(time.Second)
+	if bandwidthBiPS > 0 && bpi < 1 {
+		// A limit below a byte per interval is not the absence of a limit.
+		bpi = 1
+	}
 	atomic.StoreInt64(&t.bytesPerInterval, bpi)
+	atomic.StoreInt64(&t.clusterBandwidth, clusterBandwidth)
+}
+
+// getClusterBandwidth returns the cluster wide bandwidth for reporting.
+func (t *throttle) getClusterBandwidth() int64 {
+	return atomic.LoadInt64(&t.clusterBandwidth)
 }
 
 // ReleaseUnusedBandwidth releases bandwidth that was allocated for a user
@@ -0,0 +1,351 @@
+/*
+ * MinIO Cloud Storage, (C) 2021 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package inventory
+
+import (
+	"encoding/xml"
+	"io"
+	"strings"
+	"time"
+)
+
+// DestinationARNPrefix - destination bucket ARN prefix as per AWS S3 specification.
+const DestinationARNPrefix = "arn:aws:s3:::"
+
+// MaxConfigs - maximum number of inventory configurations of a bucket.
+const MaxConfigs = 1000
+
+var (
+	errInventoryTooManyConfigs = Errorf("Inventory configuration allows a maximum of %d configurations per bucket", MaxConfigs)
+	errInventoryMissingID      = Errorf("Inventory configuration must have an Id")
+	errInventoryInvalidID      = Errorf("Inventory configuration Id must be at most 64 characters long")
+	errInventoryIDMismatch     = Errorf("Inventory configuration Id does not match the id of the request")
+	errInventoryEncryption     = Errorf("Encryption of the inventory reports must be SSE-S3 or SSE-KMS")
+	errInventoryEncryptionKMS  = Errorf("SSE-KMS encryption of the inventory reports is not supported")
+)
+
+// Format - format of the inventory report data files.
+type Format string
+
+// Supported formats, ORC is not supported.
+const (
+	CSV     Format = "CSV"
+	JSON    Format = "JSON"
+	Parquet Format = "Parquet"
+)
+
+// Frequency - how often the inventory reports are generated.
+type Frequency string
+
+// Supported frequencies.
+const (
+	Daily  Frequency = "Daily"
+	Weekly Frequency = "Weekly"
+)
+
+// Interval returns the time between two reports.
+func (f Frequency) Interval() time.Duration {
+	if f == Weekly {
+		return 7 * 24 * time.Hour
+	}
+	return 24 * time.Hour
+}
+
+// Versions - object versions listed by the inventory reports.
+type Versions string
+
+// Supported object versions.
+const (
+	AllVersions     Versions = "All"
+	CurrentVersions Versions = "Current"
+)
+
+// Field - field of the records of the inventory reports.
+type Field string
+
+// Fields always listed in the reports.
+const (
+	BucketField         Field = "Bucket"
+	KeyField            Field = "Key"
+	VersionIDField      Field = "VersionId"
+	IsLatestField       Field = "IsLatest"
+	IsDeleteMarkerField Field = "IsDeleteMarker"
+)
+
+// Optional fields.
+const (
+	SizeField                      Field = "Size"
+	LastModifiedDateField          Field = "LastModifiedDate"
+	StorageClassField              Field = "StorageClass"
+	ETagField                      Field = "ETag"
+	IsMultipartUploadedField       Field = "IsMultipartUploaded"
+	ReplicationStatusField         Field = "ReplicationStatus"
+	EncryptionStatusField          Field = "EncryptionStatus"
+	ObjectLockRetainUntilDateField Field = "ObjectLockRetainUntilDate"
+	ObjectLockModeField            Field = "ObjectLockMode"
+	ObjectLockLegalHoldStatusField Field = "ObjectLockLegalHoldStatus"
+)
+
+// optionalFields in the order of the columns of the reports.
+var optionalFields = []Field{
+	SizeField,
+	LastModifiedDateField,
+	StorageClassField,
+	ETagField,
+	IsMultipartUploadedField,
+	ReplicationStatusField,
+	EncryptionStatusField,
+	ObjectLockRetainUntilDateField,
+	ObjectLockModeField,
+	ObjectLockLegalHoldStatusField,
+}
+
+// Filter - limits the objects listed by the inventory reports.
+type Filter struct {
+	Prefix string `xml:"Prefix"`
+}
+
+// S3BucketDestination - bucket the inventory reports are written to.
+type S3BucketDestination struct {
+	AccountID string `xml:"AccountId,omitempty"`
+	// ARN of the bucket, arn:aws:s3:::<bucket>
+	Bucket     string      `xml:"Bucket"`
+	Format     Format      `xml:"Format"`
+	Prefix     string      `xml:"Prefix,omitempty"`
+	Encryption *Encryption `xml:"Encryption,omitempty"`
+}
+
+// Encryption - server-side encryption of the inventory reports, only
+// SSE-S3 is supported.
+type Encryption struct {
+	SSES3  *struct{} `xml:"SSE-S3,omitempty"`
+	SSEKMS *SSEKMS   `xml:"SSE-KMS,omitempty"`
+}
+
+// SSEKMS - key of the SSE-KMS encryption of the inventory reports.
+type SSEKMS struct {
+	KeyID string `xml:"KeyId"`
+}
+
+// Destination - destination of the inventory reports.
+type Destination struct {
+	S3BucketDestination S3BucketDestination `xml:"S3BucketDestination"`
+}
+
+// Schedule - schedule of the inventory reports.
+type Schedule struct {
+	Frequency Frequency `xml:"Frequency"`
+}
+
+// Config - inventory configuration of a bucket.
+type Config struct {
+	XMLNS                  string      `xml:"xmlns,attr,omitempty"`
+	XMLName                xml.Name    `xml:"InventoryConfiguration"`
+	ID                     string      `xml:"Id"`
+	IsEnabled              bool        `xml:"IsEnabled"`
+	Filter                 *Filter     `xml:"Filter,omitempty"`
+	Destination            Destination `xml:"Destination"`
+	Schedule               Schedule    `xml:"Schedule"`
+	IncludedObjectVersions Versions    `xml:"IncludedObjectVersions"`
+	OptionalFields         []Field     `xml:"OptionalFields>Field,omitempty"`
+}
+
+// ParseConfig - parses data in given reader to Config.
+func ParseConfig(reader io.Reader) (*Config, error) {
+	var config Config
+	if err := xml.NewDecoder(reader).Decode(&config); err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
+// Validate - validates the inventory configuration.
+func (c Config) Validate() error {
+	if c.ID == "" {
+		return errInventoryMissingID
+	}
+	if len(c.ID) > 64 {
+		return errInventoryInvalidID
+	}
+
+	dest := c.Destination.S3BucketDestination
+	if dest.DestinationBucket() == "" {
+		return Errorf("Invalid destination bucket '%s', expected an ARN of the form %s<bucket>", dest.Bucket, DestinationARNPrefix)
+	}
+	switch dest.Format {
+	case CSV, JSON, Parquet:
+	default:
+		return Errorf("Unsupported inventory format '%s'", dest.Format)
+	}
+	if enc := dest.Encryption; enc != nil {
+		if enc.SSEKMS != nil {
+			return errInventoryEncryptionKMS
+		}
+		if enc.SSES3 == nil {
+			return errInventoryEncryption
+		}
+	}
+
+	switch c.Schedule.Frequency {
+	case Daily, Weekly:
+	default:
+		return Errorf("Unsupported inventory frequency '%s'", c.Schedule.Frequency)
+	}
+
+	switch c.IncludedObjectVersions {
+	case AllVersions, CurrentVersions:
+	default:
+		return Errorf("Unsupported included object versions '%s'", c.IncludedObjectVersions)
+	}
+
+	seen := make(map[Field]bool, len(c.OptionalFields))
+	for _, field := range c.OptionalFields {
+		if !field.isOptional() {
+			return Errorf("Unsupported inventory optional field '%s'", field)
+		}
+		if seen[field] {
+			return Errorf("Inventory optional field '%s' is listed more than once", field)
+		}
+		seen[field] = true
+	}
+	return nil
+}
+
+// ValidateID - validates the configuration is sent for the id of the request.
+func (c Config) ValidateID(id string) error {
+	if c.ID != id {
+		return errInventoryIDMismatch
+	}
+	return nil
+}
+
+func (f Field) isOptional() bool {
+	for _, field := range optionalFields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+// DestinationBucket returns the name of the destination bucket,
+// it is empty if the ARN of the bucket is invalid.
+func (d S3BucketDestination) DestinationBucket() string {
+	if !strings.HasPrefix(d.Bucket, DestinationARNPrefix) {
+		return ""
+	}
+	return strings.TrimPrefix(d.Bucket, DestinationARNPrefix)
+}
+
+// IsEncrypted returns true if the reports are encrypted with SSE-S3.
+func (d S3BucketDestination) IsEncrypted() bool {
+	return d.Encryption != nil && d.Encryption.SSES3 != nil
+}
+
+// Prefix returns the prefix of the objects listed by the reports.
+func (c Config) Prefix() string {
+	if c.Filter == nil {
+		return ""
+	}
+	return c.Filter.Prefix
+}
+
+// Fields returns the fields of the records of the reports, in order.
+func (c Config) Fields() []Field {
+	fields := []Field{BucketField, KeyField}
+	if c.IncludedObjectVersions == AllVersions {
+		fields = append(fields, VersionIDField, IsLatestField, IsDeleteMarkerField)
+	}
+	selected := make(map[Field]bool, len(c.OptionalFields))
+	for _, field := range c.OptionalFields {
+		selected[field] = true
+	}
+	for _, field := range optionalFields {
+		if selected[field] {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+// Due returns true if a report is due at now, the previous
+// report was generated at lastRun.
+func (c Config) Due(lastRun, now time.Time) bool {
+	if !c.IsEnabled {
+		return false
+	}
+	return lastRun.IsZero() || now.Sub(lastRun) >= c.Schedule.Frequency.Interval()
+}
+
+// Configurations - all the inventory configurations of a bucket.
+type Configurations struct {
+	XMLName xml.Name `xml:"InventoryConfigurations"`
+	Configs []Config `xml:"InventoryConfiguration"`
+}
+
+// ParseConfigurations - parses data in given reader to Configurations.
+func ParseConfigurations(reader io.Reader) (*Configurations, error) {
+	var configs Configurations
+	if err := xml.NewDecoder(reader).Decode(&configs); err != nil {
+		return nil, err
+	}
+	return &configs, nil
+}
+
+// Get returns the configuration with the id.
+func (c Configurations) Get(id string) (Config, bool) {
+	for _, config := range c.Configs {
+		if config.ID == id {
+			return config, true
+		}
+	}
+	return Config{}, false
+}
+
+// Set returns the configurations with the configuration added,
+// or replacing the configuration with the same id.
+func (c Configurations) Set(config Config) (Configurations, error) {
+	configs := make([]Config, 0, len(c.Configs)+1)
+	replaced := false
+	for _, existing := range c.Configs {
+		if existing.ID == config.ID {
+			existing = config
+			replaced = true
+		}
+		configs = append(configs, existing)
+	}
+	if !replaced {
+		if len(configs) >= MaxConfigs {
+			return c, errInventoryTooManyConfigs
+		}
+		configs = append(configs, config)
+	}
+	return Configurations{Configs: configs}, nil
+}
+
+// Delete returns the configurations without the configuration with
+// the id, ok is false if there is no such configuration.
+func (c Configurations) Delete(id string) (configs Configurations, ok bool) {
+	for _, config := range c.Configs {
+		if config.ID == id {
+			ok = true
+			continue
+		}
+		configs.Configs = append(configs.Configs, config)
+	}
+	return configs, ok
+}
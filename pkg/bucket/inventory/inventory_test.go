@@ -0,0 +1,192 @@
+/*
+ * MinIO Cloud Storage, (C) 2021 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package inventory
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func inventoryXML(id, bucket, format, frequency, versions, fields string) string {
+	return fmt.Sprintf(`<InventoryConfiguration xmlns="http://s3.amazonaws.com/doc/2006-03-01/">
+<Id>%s</Id><IsEnabled>true</IsEnabled><Filter><Prefix>logs/</Prefix></Filter>
+<Destination><S3BucketDestination><Bucket>%s</Bucket><Format>%s</Format><Prefix>reports</Prefix></S3BucketDestination></Destination>
+<Schedule><Frequency>%s</Frequency></Schedule>
+<IncludedObjectVersions>%s</IncludedObjectVersions>
+<OptionalFields>%s</OptionalFields>
+</InventoryConfiguration>`, id, bucket, format, frequency, versions, fields)
+}
+
+func TestParseAndValidateConfig(t *testing.T) {
+	testCases := []struct {
+		input       string
+		expectedErr bool
+	}{
+		// Valid configurations
+		{inventoryXML("report", "arn:aws:s3:::dest", "CSV", "Daily", "All", "<Field>Size</Field><Field>ETag</Field>"), false},
+		{inventoryXML("report", "arn:aws:s3:::dest", "JSON", "Weekly", "Current", ""), false},
+		{inventoryXML("report", "arn:aws:s3:::dest", "Parquet", "Daily", "All", "<Field>ObjectLockMode</Field>"), false},
+		// Missing id
+		{inventoryXML("", "arn:aws:s3:::dest", "CSV", "Daily", "All", ""), true},
+		// Invalid destination bucket ARN
+		{inventoryXML("report", "dest", "CSV", "Daily", "All", ""), true},
+		// Unsupported format
+		{inventoryXML("report", "arn:aws:s3:::dest", "ORC", "Daily", "All", ""), true},
+		// Unsupported frequency
+		{inventoryXML("report", "arn:aws:s3:::dest", "CSV", "Hourly", "All", ""), true},
+		// Unsupported versions
+		{inventoryXML("report", "arn:aws:s3:::dest", "CSV", "Daily", "Some", ""), true},
+		// Unsupported optional field
+		{inventoryXML("report", "arn:aws:s3:::dest", "CSV", "Daily", "All", "<Field>Owner</Field>"), true},
+		// Duplicate optional field
+		{inventoryXML("report", "arn:aws:s3:::dest", "CSV", "Daily", "All", "<Field>Size</Field><Field>Size</Field>"), true},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("Test %d", i+1), func(t *testing.T) {
+			config, err := ParseConfig(strings.NewReader(tc.input))
+			if err != nil {
+				t.Fatalf("unexpected parse error: %v", err)
+			}
+			err = config.Validate()
+			if tc.expectedErr && err == nil {
+				t.Fatal("expected an error, got none")
+			}
+			if !tc.expectedErr && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+
+	for _, tc := range []struct {
+		encryption  string
+		expectedErr error
+		encrypted   bool
+	}{
+		{"<Encryption><SSE-S3/></Encryption>", nil, true},
+		{"<Encryption><SSE-KMS><KeyId>arn:aws:kms:us-east-1:1234:key/abcd</KeyId></SSE-KMS></Encryption>", errInventoryEncryptionKMS, false},
+		{"<Encryption></Encryption>", errInventoryEncryption, false},
+	} {
+		encrypted := strings.Replace(inventoryXML("report", "arn:aws:s3:::dest", "CSV", "Daily", "All", ""),
+			"<Prefix>reports</Prefix>", "<Prefix>reports</Prefix>"+tc.encryption, 1)
+		config, err := ParseConfig(strings.NewReader(encrypted))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = config.Validate(); err != tc.expectedErr {
+			t.Fatalf("%s: expected %v, got %v", tc.encryption, tc.expectedErr, err)
+		}
+		if got := config.Destination.S3BucketDestination.IsEncrypted(); got != tc.encrypted {
+			t.Fatalf("%s: expected encrypted %v, got %v", tc.encryption, tc.encrypted, got)
+		}
+	}
+}
+
+func TestConfigFields(t *testing.T) {
+	config, err := ParseConfig(strings.NewReader(inventoryXML("report", "arn:aws:s3:::dest", "CSV", "Daily", "All",
+		"<Field>ETag</Field><Field>Size</Field>")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := config.Destination.S3BucketDestination.DestinationBucket(); got != "dest" {
+		t.Fatalf("expected destination bucket dest, got %s", got)
+	}
+	if got := config.Prefix(); got != "logs/" {
+		t.Fatalf("expected prefix logs/, got %s", got)
+	}
+	want := []Field{BucketField, KeyField, VersionIDField, IsLatestField, IsDeleteMarkerField, SizeField, ETagField}
+	if got := config.Fields(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected fields %v, got %v", want, got)
+	}
+
+	config.IncludedObjectVersions = CurrentVersions
+	config.OptionalFields = nil
+	want = []Field{BucketField, KeyField}
+	if got := config.Fields(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected fields %v, got %v", want, got)
+	}
+}
+
+func TestConfigDue(t *testing.T) {
+	now := time.Now()
+	config := Config{IsEnabled: true, Schedule: Schedule{Frequency: Weekly}}
+	if !config.Due(time.Time{}, now) {
+		t.Fatal("expected a first report to be due")
+	}
+	if config.Due(now.Add(-6*24*time.Hour), now) {
+		t.Fatal("expected a weekly report not to be due after 6 days")
+	}
+	if !config.Due(now.Add(-7*24*time.Hour), now) {
+		t.Fatal("expected a weekly report to be due after 7 days")
+	}
+	config.IsEnabled = false
+	if config.Due(time.Time{}, now) {
+		t.Fatal("expected a disabled report not to be due")
+	}
+}
+
+func TestConfigurations(t *testing.T) {
+	var configs Configurations
+	var err error
+	for _, id := range []string{"a", "b", "c"} {
+		if configs, err = configs.Set(Config{ID: id}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if configs, err = configs.Set(Config{ID: "b", IsEnabled: true}); err != nil {
+		t.Fatal(err)
+	}
+	if len(configs.Configs) != 3 {
+		t.Fatalf("expected 3 configurations, got %d", len(configs.Configs))
+	}
+	if config, ok := configs.Get("b"); !ok || !config.IsEnabled {
+		t.Fatalf("expected configuration b to be replaced, got %v", config)
+	}
+
+	configs, ok := configs.Delete("a")
+	if !ok {
+		t.Fatal("expected configuration a to be deleted")
+	}
+	if _, ok = configs.Delete("a"); ok {
+		t.Fatal("expected no configuration a")
+	}
+
+	data, err := xml.Marshal(configs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := ParseConfigurations(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(parsed.Configs) != 2 || parsed.Configs[0].ID != "b" || parsed.Configs[1].ID != "c" {
+		t.Fatalf("unexpected configurations %v", parsed.Configs)
+	}
+
+	configs = Configurations{}
+	for i := 0; i < MaxConfigs; i++ {
+		configs.Configs = append(configs.Configs, Config{ID: fmt.Sprint(i)})
+	}
+	if _, err = configs.Set(Config{ID: "new"}); err != errInventoryTooManyConfigs {
+		t.Fatalf("expected %v, got %v", errInventoryTooManyConfigs, err)
+	}
+}
@@ -51,7 +51,10 @@ func (f binaryEqualsFunc) evaluate(values map[string][]string) bool {
 		requestValue = values[f.k.Name()]
 	}
 
-	fvalues := f.values.ApplyFunc(substFuncFromValues(values))
+	fvalues, ok := substituteValues(f.values, values)
+	if !ok {
+		return false
+	}
 	return !fvalues.Intersection(set.CreateStringSet(requestValue...)).IsEmpty()
 }
 
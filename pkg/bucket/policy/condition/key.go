@@ -20,6 +20,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"strings"
+
+	"github.com/minio/minio-go/v7/pkg/set"
+	"github.com/minio/minio/pkg/wildcard"
 )
 
 // Key - conditional key which is used to fetch values for any condition.
@@ -84,6 +87,17 @@ const (
 	// Enables enforcement of the specified object legal hold status
 	S3ObjectLockLegalHold Key = "s3:object-lock-legal-hold"
 
+	// S3ExistingObjectTag - key representing the value of a tag of an existing object, it is
+	// used with the tag key as "s3:ExistingObjectTag/<tag-key>".
+	S3ExistingObjectTag Key = "s3:ExistingObjectTag"
+
+	// S3RequestObjectTag - key representing the value of a tag set by the request, it is
+	// used with the tag key as "s3:RequestObjectTag/<tag-key>".
+	S3RequestObjectTag Key = "s3:RequestObjectTag"
+
+	// S3RequestObjectTagKeys - key representing the keys of the tags set by the request.
+	S3RequestObjectTagKeys Key = "s3:RequestObjectTagKeys"
+
 	// AWSReferer - key representing Referer header of any API.
 	AWSReferer Key = "aws:Referer"
 
@@ -129,6 +143,9 @@ var AllSupportedKeys = append([]Key{
 	S3ObjectLockMode,
 	S3ObjectLockLegalHold,
 	S3ObjectLockRetainUntilDate,
+	S3ExistingObjectTag,
+	S3RequestObjectTag,
+	S3RequestObjectTagKeys,
 	AWSReferer,
 	AWSSourceIP,
 	AWSUserAgent,
@@ -158,20 +175,180 @@ var CommonKeys = append([]Key{
 	LDAPUser,
 }, JWTKeys...)
 
-func substFuncFromValues(values map[string][]string) func(string) string {
-	return func(v string) string {
-		for _, key := range CommonKeys {
+// variableEscapes - special policy variables standing for characters
+// which otherwise have a special meaning in policies.
+var variableEscapes = map[string]string{
+	"*": "*",
+	"?": "?",
+	"$": "$",
+}
+
+// substituteVariables - replaces policy variables in s by their values,
+// literal reports the runes coming from substituted values or escapes.
+// Returns false if a variable has no value.
+func substituteVariables(s string, values map[string][]string) (runes []rune, literal []bool, ok bool) {
+	appendRunes := func(s string, isLiteral bool) {
+		for _, r := range s {
+			runes = append(runes, r)
+			literal = append(literal, isLiteral)
+		}
+	}
+
+	for {
+		start := strings.Index(s, "${")
+		if start < 0 {
+			break
+		}
+		end := strings.IndexByte(s[start:], '}')
+		if end < 0 {
+			break
+		}
+		end += start
+
+		appendRunes(s[:start], false)
+		name := s[start+2 : end]
+		value, isEscape := variableEscapes[name]
+		if !isEscape {
+			key := Key(name)
+			rvalues := values[key.Name()]
 			// Empty values are not supported for policy variables.
-			if rvalues, ok := values[key.Name()]; ok && rvalues[0] != "" {
-				v = strings.Replace(v, key.VarName(), rvalues[0], -1)
+			if !key.IsValid() || len(rvalues) == 0 || rvalues[0] == "" {
+				return nil, nil, false
+			}
+			value = rvalues[0]
+		}
+		appendRunes(value, true)
+		s = s[end+1:]
+	}
+	appendRunes(s, false)
+	return runes, literal, true
+}
+
+// SubstituteVariables - replaces policy variables such as "${aws:username}"
+// in s by their values, and the escapes "${*}", "${?}" and "${$}" by their
+// character. Substituted values are never substituted again. Returns false
+// if a variable has no value, s must then not match anything.
+func SubstituteVariables(s string, values map[string][]string) (string, bool) {
+	if !strings.Contains(s, "${") {
+		return s, true
+	}
+
+	runes, _, ok := substituteVariables(s, values)
+	if !ok {
+		return "", false
+	}
+	return string(runes), true
+}
+
+// MatchVariables - matches name with the wildcard pattern after replacing
+// its policy variables like SubstituteVariables. Substituted values and
+// escaped characters are matched as they are, even '*' and '?'.
+func MatchVariables(pattern, name string, values map[string][]string) bool {
+	if !strings.Contains(pattern, "${") {
+		return wildcard.Match(pattern, name)
+	}
+
+	runes, literal, ok := substituteVariables(pattern, values)
+	if !ok {
+		return false
+	}
+	return matchRunes([]rune(name), runes, literal)
+}
+
+func matchRunes(str, pattern []rune, literal []bool) bool {
+	for len(pattern) > 0 {
+		switch {
+		case literal[0] || (pattern[0] != '*' && pattern[0] != '?'):
+			if len(str) == 0 || str[0] != pattern[0] {
+				return false
+			}
+		case pattern[0] == '?':
+			if len(str) == 0 {
+				return false
 			}
+		default:
+			return matchRunes(str, pattern[1:], literal[1:]) ||
+				(len(str) > 0 && matchRunes(str[1:], pattern, literal))
+		}
+		str, pattern, literal = str[1:], pattern[1:], literal[1:]
+	}
+	return len(str) == 0
+}
+
+// ValidateVariables - checks whether all policy variables in s are
+// supported condition keys or escapes.
+func ValidateVariables(s string) error {
+	for {
+		start := strings.Index(s, "${")
+		if start < 0 {
+			return nil
+		}
+		end := strings.IndexByte(s[start:], '}')
+		if end < 0 {
+			return fmt.Errorf("unterminated policy variable in '%v'", s)
+		}
+		end += start
+
+		name := s[start+2 : end]
+		if _, ok := variableEscapes[name]; !ok && !Key(name).IsValid() {
+			return fmt.Errorf("invalid policy variable '%v'", Key(name).VarName())
+		}
+		s = s[end+1:]
+	}
+}
+
+// variablesResolved - checks whether all policy variables in the
+// condition values have a value.
+func variablesResolved(fvalues set.StringSet, values map[string][]string) bool {
+	_, ok := substituteValues(fvalues, values)
+	return ok
+}
+
+// substituteValues - returns the condition values with their policy
+// variables substituted, false if a variable has no value.
+func substituteValues(fvalues set.StringSet, values map[string][]string) (set.StringSet, bool) {
+	svalues := set.NewStringSet()
+	for v := range fvalues {
+		sv, ok := SubstituteVariables(v, values)
+		if !ok {
+			return nil, false
+		}
+		svalues.Add(sv)
+	}
+	return svalues, true
+}
+
+// ObjectTagKeys - is list of keys used with a tag key, such as
+// "s3:ExistingObjectTag/<tag-key>".
+var ObjectTagKeys = []Key{
+	S3ExistingObjectTag,
+	S3RequestObjectTag,
+}
+
+// objectTagKey - returns the key without its tag key, if key is used
+// with a tag key.
+func (key Key) objectTagKey() (Key, bool) {
+	for _, tagKey := range ObjectTagKeys {
+		if strings.HasPrefix(string(key), string(tagKey)+"/") {
+			return tagKey, true
 		}
-		return v
 	}
+	return key, false
 }
 
 // IsValid - checks if key is valid or not.
 func (key Key) IsValid() bool {
+	if tagKey, ok := key.objectTagKey(); ok {
+		return len(key) > len(tagKey)+1
+	}
+
+	for _, tagKey := range ObjectTagKeys {
+		// These keys are only valid with a tag key.
+		if key == tagKey {
+			return false
+		}
+	}
+
 	for _, supKey := range AllSupportedKeys {
 		if supKey == key {
 			return true
@@ -251,6 +428,8 @@ func (set KeySet) Merge(mset KeySet) {
 }
 
 // Difference - returns a key set contains difference of two keys.
+// Keys used with a tag key are in sset if their key without the tag
+// key is.
 // Example:
 //     keySet1 := ["one", "two", "three"]
 //     keySet2 := ["two", "four", "three"]
@@ -259,14 +438,31 @@ func (set KeySet) Difference(sset KeySet) KeySet {
 	nset := make(KeySet)
 
 	for k := range set {
-		if _, ok := sset[k]; !ok {
-			nset.Add(k)
+		if _, ok := sset[k]; ok {
+			continue
+		}
+		if tagKey, ok := k.objectTagKey(); ok {
+			if _, ok = sset[tagKey]; ok {
+				continue
+			}
 		}
+		nset.Add(k)
 	}
 
 	return nset
 }
 
+// HasObjectTagKey - returns whether key set contains tagKey used with a
+// tag key, e.g. "s3:ExistingObjectTag/<tag-key>".
+func (set KeySet) HasObjectTagKey(tagKey Key) bool {
+	for k := range set {
+		if key, ok := k.objectTagKey(); ok && key == tagKey {
+			return true
+		}
+	}
+	return false
+}
+
 // IsEmpty - returns whether key set is empty or not.
 func (set KeySet) IsEmpty() bool {
 	return len(set) == 0
@@ -50,7 +50,10 @@ func (f stringEqualsFunc) evaluate(values map[string][]string) bool {
 		requestValue = values[f.k.Name()]
 	}
 
-	fvalues := f.values.ApplyFunc(substFuncFromValues(values))
+	fvalues, ok := substituteValues(f.values, values)
+	if !ok {
+		return false
+	}
 	return !fvalues.Intersection(set.CreateStringSet(requestValue...)).IsEmpty()
 }
 
@@ -96,7 +99,8 @@ type stringNotEqualsFunc struct {
 // evaluate() - evaluates to check whether value by Key in given values is NOT in
 // condition values.
 func (f stringNotEqualsFunc) evaluate(values map[string][]string) bool {
-	return !f.stringEqualsFunc.evaluate(values)
+	// Conditions with variables without a value never match.
+	return variablesResolved(f.values, values) && !f.stringEqualsFunc.evaluate(values)
 }
 
 // name() - returns "StringNotEquals" condition name.
@@ -125,6 +129,9 @@ func valuesToStringSlice(n name, values ValueSet) ([]string, error) {
 
 func validateStringEqualsValues(n name, key Key, values set.StringSet) error {
 	for _, s := range values.ToSlice() {
+		if err := ValidateVariables(s); err != nil {
+			return fmt.Errorf("%v for '%v' for %v condition", err, key, n)
+		}
 		switch key {
 		case S3XAmzCopySource:
 			bucket, object := path2BucketAndObject(s)
@@ -42,6 +42,11 @@ func TestStringEqualsFuncEvaluate(t *testing.T) {
 		t.Fatalf("unexpected error. %v\n", err)
 	}
 
+	case5Function, err := newStringEqualsFunc(S3Prefix, NewValueSet(NewStringValue("home/${aws:username}/")))
+	if err != nil {
+		t.Fatalf("unexpected error. %v\n", err)
+	}
+
 	testCases := []struct {
 		function       Function
 		values         map[string][]string
@@ -65,6 +70,10 @@ func TestStringEqualsFuncEvaluate(t *testing.T) {
 		{case4Function, map[string][]string{"LocationConstraint": {"us-east-1"}}, false},
 		{case4Function, map[string][]string{}, false},
 		{case4Function, map[string][]string{"delimiter": {"/"}}, false},
+
+		{case5Function, map[string][]string{"prefix": {"home/alice/"}, "username": {"alice"}}, true},
+		{case5Function, map[string][]string{"prefix": {"home/bob/"}, "username": {"alice"}}, false},
+		{case5Function, map[string][]string{"prefix": {"home/${aws:username}/"}}, false},
 	}
 
 	for i, testCase := range testCases {
@@ -264,6 +273,11 @@ func TestStringNotEqualsFuncEvaluate(t *testing.T) {
 		t.Fatalf("unexpected error. %v\n", err)
 	}
 
+	case5Function, err := newStringNotEqualsFunc(S3Prefix, NewValueSet(NewStringValue("home/${aws:username}/")))
+	if err != nil {
+		t.Fatalf("unexpected error. %v\n", err)
+	}
+
 	testCases := []struct {
 		function       Function
 		values         map[string][]string
@@ -287,6 +301,10 @@ func TestStringNotEqualsFuncEvaluate(t *testing.T) {
 		{case4Function, map[string][]string{"LocationConstraint": {"us-east-1"}}, true},
 		{case4Function, map[string][]string{}, true},
 		{case4Function, map[string][]string{"delimiter": {"/"}}, true},
+
+		{case5Function, map[string][]string{"prefix": {"home/alice/"}, "username": {"alice"}}, false},
+		{case5Function, map[string][]string{"prefix": {"home/bob/"}, "username": {"alice"}}, true},
+		{case5Function, map[string][]string{"prefix": {"home/bob/"}}, false},
 	}
 
 	for i, testCase := range testCases {
@@ -566,6 +584,7 @@ func TestNewStringEqualsFunc(t *testing.T) {
 
 		// Invalid value error.
 		{S3XAmzCopySource, NewValueSet(NewStringValue("mybucket")), nil, true},
+		{AWSUsername, NewValueSet(NewStringValue("${aws:unknown}")), nil, true},
 		{S3XAmzServerSideEncryption, NewValueSet(NewStringValue("SSE-C")), nil, true},
 		{S3XAmzMetadataDirective, NewValueSet(NewStringValue("DUPLICATE")), nil, true},
 	}
@@ -687,6 +706,7 @@ func TestNewStringNotEqualsFunc(t *testing.T) {
 
 		// Invalid value error.
 		{S3XAmzCopySource, NewValueSet(NewStringValue("mybucket")), nil, true},
+		{AWSUsername, NewValueSet(NewStringValue("${aws:unknown}")), nil, true},
 		{S3XAmzServerSideEncryption, NewValueSet(NewStringValue("SSE-C")), nil, true},
 		{S3XAmzMetadataDirective, NewValueSet(NewStringValue("DUPLICATE")), nil, true},
 	}
@@ -50,7 +50,10 @@ func (f stringEqualsIgnoreCaseFunc) evaluate(values map[string][]string) bool {
 		requestValue = values[f.k.Name()]
 	}
 
-	fvalues := f.values.ApplyFunc(substFuncFromValues(values))
+	fvalues, ok := substituteValues(f.values, values)
+	if !ok {
+		return false
+	}
 
 	for _, v := range requestValue {
 		if !fvalues.FuncMatch(strings.EqualFold, v).IsEmpty() {
@@ -103,7 +106,8 @@ type stringNotEqualsIgnoreCaseFunc struct {
 // evaluate() - evaluates to check whether value by Key in given values is NOT in
 // condition values.
 func (f stringNotEqualsIgnoreCaseFunc) evaluate(values map[string][]string) bool {
-	return !f.stringEqualsIgnoreCaseFunc.evaluate(values)
+	// Conditions with variables without a value never match.
+	return variablesResolved(f.values, values) && !f.stringEqualsIgnoreCaseFunc.evaluate(values)
 }
 
 // name() - returns "StringNotEqualsIgnoreCase" condition name.
@@ -23,7 +23,6 @@ import (
 
 	"github.com/minio/minio-go/v7/pkg/s3utils"
 	"github.com/minio/minio-go/v7/pkg/set"
-	"github.com/minio/minio/pkg/wildcard"
 )
 
 func toStringLikeFuncString(n name, key Key, values set.StringSet) string {
@@ -51,11 +50,15 @@ func (f stringLikeFunc) evaluate(values map[string][]string) bool {
 		requestValue = values[f.k.Name()]
 	}
 
-	fvalues := f.values.ApplyFunc(substFuncFromValues(values))
+	if !variablesResolved(f.values, values) {
+		return false
+	}
 
 	for _, v := range requestValue {
-		if !fvalues.FuncMatch(wildcard.Match, v).IsEmpty() {
-			return true
+		for pattern := range f.values {
+			if MatchVariables(pattern, v, values) {
+				return true
+			}
 		}
 	}
 
@@ -104,7 +107,8 @@ type stringNotLikeFunc struct {
 // evaluate() - evaluates to check whether value by Key in given values is NOT wildcard
 // matching in condition values.
 func (f stringNotLikeFunc) evaluate(values map[string][]string) bool {
-	return !f.stringLikeFunc.evaluate(values)
+	// Conditions with variables without a value never match.
+	return variablesResolved(f.values, values) && !f.stringLikeFunc.evaluate(values)
 }
 
 // name() - returns "StringNotLike" function name.
@@ -118,6 +122,9 @@ func (f stringNotLikeFunc) String() string {
 
 func validateStringLikeValues(n name, key Key, values set.StringSet) error {
 	for _, s := range values.ToSlice() {
+		if err := ValidateVariables(s); err != nil {
+			return fmt.Errorf("%v for '%v' for %v condition", err, key, n)
+		}
 		switch key {
 		case S3XAmzCopySource:
 			bucket, object := path2BucketAndObject(s)
@@ -62,6 +62,11 @@ func TestStringLikeFuncEvaluate(t *testing.T) {
 		t.Fatalf("unexpected error. %v\n", err)
 	}
 
+	case9Function, err := newStringLikeFunc(S3Prefix, NewValueSet(NewStringValue("home/${aws:username}/${*}*")))
+	if err != nil {
+		t.Fatalf("unexpected error. %v\n", err)
+	}
+
 	testCases := []struct {
 		function       Function
 		values         map[string][]string
@@ -112,6 +117,10 @@ func TestStringLikeFuncEvaluate(t *testing.T) {
 		{case8Function, map[string][]string{"LocationConstraint": {"us-east-1"}}, false},
 		{case8Function, map[string][]string{}, false},
 		{case8Function, map[string][]string{"delimiter": {"/"}}, false},
+
+		{case9Function, map[string][]string{"prefix": {"home/alice/*photos"}, "username": {"alice"}}, true},
+		{case9Function, map[string][]string{"prefix": {"home/alice/photos"}, "username": {"alice"}}, false},
+		{case9Function, map[string][]string{"prefix": {"home/${aws:username}/*"}}, false},
 	}
 
 	for i, testCase := range testCases {
@@ -331,6 +340,11 @@ func TestStringNotLikeFuncEvaluate(t *testing.T) {
 		t.Fatalf("unexpected error. %v\n", err)
 	}
 
+	case9Function, err := newStringNotLikeFunc(S3Prefix, NewValueSet(NewStringValue("home/${aws:username}/*")))
+	if err != nil {
+		t.Fatalf("unexpected error. %v\n", err)
+	}
+
 	testCases := []struct {
 		function       Function
 		values         map[string][]string
@@ -381,6 +395,10 @@ func TestStringNotLikeFuncEvaluate(t *testing.T) {
 		{case8Function, map[string][]string{"LocationConstraint": {"us-east-1"}}, true},
 		{case8Function, map[string][]string{}, true},
 		{case8Function, map[string][]string{"delimiter": {"/"}}, true},
+
+		{case9Function, map[string][]string{"prefix": {"home/alice/photos"}, "username": {"alice"}}, false},
+		{case9Function, map[string][]string{"prefix": {"home/bob/photos"}, "username": {"alice"}}, true},
+		{case9Function, map[string][]string{"prefix": {"home/bob/photos"}}, false},
 	}
 
 	for i, testCase := range testCases {
@@ -660,6 +678,7 @@ func TestNewStringLikeFunc(t *testing.T) {
 
 		// Invalid value error.
 		{S3XAmzCopySource, NewValueSet(NewStringValue("mybucket")), nil, true},
+		{AWSUsername, NewValueSet(NewStringValue("${aws:unknown}")), nil, true},
 	}
 
 	for i, testCase := range testCases {
@@ -779,6 +798,7 @@ func TestNewStringNotLikeFunc(t *testing.T) {
 
 		// Invalid value error.
 		{S3XAmzCopySource, NewValueSet(NewStringValue("mybucket")), nil, true},
+		{AWSUsername, NewValueSet(NewStringValue("${aws:unknown}")), nil, true},
 	}
 
 	for i, testCase := range testCases {
@@ -19,6 +19,8 @@ package policy
 import (
 	"encoding/json"
 	"io"
+
+	"github.com/minio/minio/pkg/bucket/policy/condition"
 )
 
 // DefaultVersion - default policy version as per AWS S3 specification.
@@ -69,6 +71,17 @@ func (policy Policy) IsAllowed(args Args) bool {
 	return false
 }
 
+// HasObjectTagKey - returns whether any statement of the policy has a
+// condition on tagKey used with a tag key.
+func (policy Policy) HasObjectTagKey(tagKey condition.Key) bool {
+	for _, statement := range policy.Statements {
+		if statement.Conditions.Keys().HasObjectTagKey(tagKey) {
+			return true
+		}
+	}
+	return false
+}
+
 // IsEmpty - returns whether policy is empty or not.
 func (policy Policy) IsEmpty() bool {
 	return len(policy.Statements) == 0
@@ -112,14 +125,26 @@ redo:
 				continue
 			}
 
+			if !policy.Statements[i].NotPrincipal.Equals(statement.NotPrincipal) {
+				continue
+			}
+
 			if !policy.Statements[i].Actions.Equals(statement.Actions) {
 				continue
 			}
 
+			if !policy.Statements[i].NotActions.Equals(statement.NotActions) {
+				continue
+			}
+
 			if !policy.Statements[i].Resources.Equals(statement.Resources) {
 				continue
 			}
 
+			if !policy.Statements[i].NotResources.Equals(statement.NotResources) {
+				continue
+			}
+
 			if policy.Statements[i].Conditions.String() != statement.Conditions.String() {
 				continue
 			}
@@ -132,6 +132,38 @@ func TestResourceMatch(t *testing.T) {
 	}
 }
 
+func TestResourceMatchVariables(t *testing.T) {
+	conditionValues := map[string][]string{
+		"username": {"alice"},
+		"sub":      {"${aws:username}"},
+	}
+
+	testCases := []struct {
+		resource       Resource
+		objectName     string
+		expectedResult bool
+	}{
+		{NewResource("mybucket", "home/${aws:username}/*"), "mybucket/home/alice/photo.jpg", true},
+		{NewResource("mybucket", "home/${aws:username}/*"), "mybucket/home/bob/photo.jpg", false},
+		// Substituted values are not substituted again.
+		{NewResource("mybucket", "home/${jwt:sub}/*"), "mybucket/home/alice/photo.jpg", false},
+		{NewResource("mybucket", "home/${jwt:sub}/*"), "mybucket/home/${aws:username}/photo.jpg", true},
+		// Resources with variables without a value never match.
+		{NewResource("mybucket", "home/${ldap:user}/*"), "mybucket/home/alice/photo.jpg", false},
+		{NewResource("mybucket", "home/${ldap:user}/*"), "mybucket/home/${ldap:user}/photo.jpg", false},
+		// Escaped characters are matched literally.
+		{NewResource("mybucket", "home/${*}/*"), "mybucket/home/*/photo.jpg", true},
+		{NewResource("mybucket", "home/${*}/*"), "mybucket/home/alice/photo.jpg", false},
+	}
+
+	for i, testCase := range testCases {
+		result := testCase.resource.Match(testCase.objectName, conditionValues)
+		if result != testCase.expectedResult {
+			t.Errorf("case %v: expected: %v, got: %v", i+1, testCase.expectedResult, result)
+		}
+	}
+}
+
 func TestResourceMarshalJSON(t *testing.T) {
 	testCases := []struct {
 		resource       Resource
@@ -179,7 +211,10 @@ func TestResourceUnmarshalJSON(t *testing.T) {
 		{[]byte(`"arn:aws:s3:::mybucket*/myobject"`), NewResource("mybucket*", "myobject"), false},
 		{[]byte(`"arn:aws:s3:::mybucket?0/2010/photos/*"`), NewResource("mybucket?0", "/2010/photos/*"), false},
 		{[]byte(`"mybucket/myobject*"`), Resource{}, true},
+		{[]byte(`"arn:aws:s3:::mybucket/home/${aws:username}/*"`), NewResource("mybucket", "home/${aws:username}/*"), false},
 		{[]byte(`"arn:aws:s3:::/*"`), Resource{}, true},
+		{[]byte(`"arn:aws:s3:::mybucket/home/${aws:unknown}/*"`), Resource{}, true},
+		{[]byte(`"arn:aws:s3:::mybucket/home/${aws:username/*"`), Resource{}, true},
 	}
 
 	for i, testCase := range testCases {
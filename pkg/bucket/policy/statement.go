@@ -25,22 +25,33 @@ import (
 
 // Statement - policy statement.
 type Statement struct {
-	SID        ID                  `json:"Sid,omitempty"`
-	Effect     Effect              `json:"Effect"`
-	Principal  Principal           `json:"Principal"`
-	Actions    ActionSet           `json:"Action"`
-	Resources  ResourceSet         `json:"Resource"`
-	Conditions condition.Functions `json:"Condition,omitempty"`
+	SID          ID                  `json:"Sid,omitempty"`
+	Effect       Effect              `json:"Effect"`
+	Principal    Principal           `json:"Principal"`
+	NotPrincipal Principal           `json:"NotPrincipal"`
+	Actions      ActionSet           `json:"Action,omitempty"`
+	NotActions   ActionSet           `json:"NotAction,omitempty"`
+	Resources    ResourceSet         `json:"Resource,omitempty"`
+	NotResources ResourceSet         `json:"NotResource,omitempty"`
+	Conditions   condition.Functions `json:"Condition,omitempty"`
 }
 
 // IsAllowed - checks given policy args is allowed to continue the Rest API.
 func (statement Statement) IsAllowed(args Args) bool {
 	check := func() bool {
-		if !statement.Principal.Match(args.AccountName) {
+		if statement.NotPrincipal.IsValid() {
+			if statement.NotPrincipal.Match(args.AccountName) {
+				return false
+			}
+		} else if !statement.Principal.Match(args.AccountName) {
 			return false
 		}
 
-		if !statement.Actions.Contains(args.Action) {
+		if len(statement.NotActions) > 0 {
+			if statement.NotActions.Contains(args.Action) {
+				return false
+			}
+		} else if !statement.Actions.Contains(args.Action) {
 			return false
 		}
 
@@ -53,7 +64,11 @@ func (statement Statement) IsAllowed(args Args) bool {
 			resource += args.ObjectName
 		}
 
-		if !statement.Resources.Match(resource, args.ConditionValues) {
+		if len(statement.NotResources) > 0 {
+			if statement.NotResources.Match(resource, args.ConditionValues) {
+				return false
+			}
+		} else if !statement.Resources.Match(resource, args.ConditionValues) {
 			return false
 		}
 
@@ -69,36 +84,66 @@ func (statement Statement) isValid() error {
 		return Errorf("invalid Effect %v", statement.Effect)
 	}
 
-	if !statement.Principal.IsValid() {
+	if statement.Principal.IsValid() && statement.NotPrincipal.IsValid() {
+		return Errorf("Principal and NotPrincipal must not be used together")
+	}
+
+	if !statement.Principal.IsValid() && !statement.NotPrincipal.IsValid() {
 		return Errorf("invalid Principal %v", statement.Principal)
 	}
 
-	if len(statement.Actions) == 0 {
+	if len(statement.Actions) != 0 && len(statement.NotActions) != 0 {
+		return Errorf("Action and NotAction must not be used together")
+	}
+
+	if len(statement.Actions) == 0 && len(statement.NotActions) == 0 {
 		return Errorf("Action must not be empty")
 	}
 
-	if len(statement.Resources) == 0 {
+	if len(statement.Resources) != 0 && len(statement.NotResources) != 0 {
+		return Errorf("Resource and NotResource must not be used together")
+	}
+
+	if len(statement.Resources) == 0 && len(statement.NotResources) == 0 {
 		return Errorf("Resource must not be empty")
 	}
 
+	keys := statement.Conditions.Keys()
 	for action := range statement.Actions {
-		if action.isObjectAction() {
-			if !statement.Resources.objectResourceExists() {
-				return Errorf("unsupported Resource found %v for action %v", statement.Resources, action)
-			}
-		} else {
-			if !statement.Resources.bucketResourceExists() {
-				return Errorf("unsupported Resource found %v for action %v", statement.Resources, action)
+		// NotResource matches both bucket and object resources.
+		if len(statement.Resources) != 0 {
+			if action.isObjectAction() {
+				if !statement.Resources.objectResourceExists() {
+					return Errorf("unsupported Resource found %v for action %v", statement.Resources, action)
+				}
+			} else {
+				if !statement.Resources.bucketResourceExists() {
+					return Errorf("unsupported Resource found %v for action %v", statement.Resources, action)
+				}
 			}
 		}
 
-		keys := statement.Conditions.Keys()
 		keyDiff := keys.Difference(actionConditionKeyMap[action])
 		if !keyDiff.IsEmpty() {
 			return Errorf("unsupported condition keys '%v' used for action '%v'", keyDiff, action)
 		}
 	}
 
+	if len(statement.NotActions) != 0 {
+		// Condition keys must be supported by any of the actions
+		// which are not excluded.
+		supportedKeys := condition.NewKeySet()
+		for action, actionKeys := range actionConditionKeyMap {
+			if !statement.NotActions.Contains(action) {
+				supportedKeys.Merge(actionKeys)
+			}
+		}
+		keyDiff := keys.Difference(supportedKeys)
+		if !keyDiff.IsEmpty() {
+			return Errorf("unsupported condition keys '%v' used for NotAction '%v'", keyDiff, statement.NotActions)
+		}
+	}
+
 	return nil
 }
 
@@ -108,10 +153,35 @@ func (statement Statement) MarshalJSON() ([]byte, error) {
 		return nil, err
 	}
 
-	// subtype to avoid recursive call to MarshalJSON()
-	type subStatement Statement
-	ss := subStatement(statement)
-	return json.Marshal(ss)
+	// Only one of Principal and NotPrincipal is encoded.
+	var principal, notPrincipal *Principal
+	if statement.NotPrincipal.IsValid() {
+		notPrincipal = &statement.NotPrincipal
+	} else {
+		principal = &statement.Principal
+	}
+
+	return json.Marshal(struct {
+		SID          ID                  `json:"Sid,omitempty"`
+		Effect       Effect              `json:"Effect"`
+		Principal    *Principal          `json:"Principal,omitempty"`
+		NotPrincipal *Principal          `json:"NotPrincipal,omitempty"`
+		Actions      ActionSet           `json:"Action,omitempty"`
+		NotActions   ActionSet           `json:"NotAction,omitempty"`
+		Resources    ResourceSet         `json:"Resource,omitempty"`
+		NotResources ResourceSet         `json:"NotResource,omitempty"`
+		Conditions   condition.Functions `json:"Condition,omitempty"`
+	}{
+		SID:          statement.SID,
+		Effect:       statement.Effect,
+		Principal:    principal,
+		NotPrincipal: notPrincipal,
+		Actions:      statement.Actions,
+		NotActions:   statement.NotActions,
+		Resources:    statement.Resources,
+		NotResources: statement.NotResources,
+		Conditions:   statement.Conditions,
+	})
 }
 
 // UnmarshalJSON - decodes JSON data to Statement.
@@ -140,7 +210,11 @@ func (statement Statement) Validate(bucketName string) error {
 		return err
 	}
 
-	return statement.Resources.Validate(bucketName)
+	if err := statement.Resources.Validate(bucketName); err != nil {
+		return err
+	}
+
+	return statement.NotResources.Validate(bucketName)
 }
 
 // NewStatement - creates new statement.
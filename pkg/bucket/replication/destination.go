@@ -27,11 +27,18 @@ import (
 // DestinationARNPrefix - destination ARN prefix as per AWS S3 specification.
 const DestinationARNPrefix = "arn:aws:s3:::"
 
+// DestinationARNMinIOPrefix - destination ARN prefix for a MinIO replication target.
+const DestinationARNMinIOPrefix = "arn:minio:replication:"
+
 // Destination - destination in ReplicationConfiguration.
 type Destination struct {
 	XMLName      xml.Name `xml:"Destination" json:"Destination"`
 	Bucket       string   `xml:"Bucket" json:"Bucket"`
 	StorageClass string   `xml:"StorageClass" json:"StorageClass"`
+	// ARN of the MinIO replication target, set when the destination
+	// bucket is specified as a MinIO replication target ARN instead of
+	// an AWS bucket ARN.
+	ARN string `xml:"-" json:"ARN,omitempty"`
 	//EncryptionConfiguration TODO: not needed for MinIO
 }
 
@@ -48,6 +55,9 @@ func (d Destination) IsValid() bool {
 }
 
 func (d Destination) String() string {
+	if d.ARN != "" {
+		return d.ARN
+	}
 	return DestinationARNPrefix + d.Bucket
 }
 
@@ -106,6 +116,17 @@ func (d Destination) Validate(bucketName string) error {
 
 // parseDestination - parses string to Destination.
 func parseDestination(s string) (Destination, error) {
+	if strings.HasPrefix(s, DestinationARNMinIOPrefix) {
+		// ARN is of the form arn:minio:replication:<region>:<id>:<bucket>
+		tokens := strings.Split(s, ":")
+		if len(tokens) != 6 || tokens[4] == "" || tokens[5] == "" {
+			return Destination{}, Errorf("invalid destination '%v'", s)
+		}
+		return Destination{
+			Bucket: tokens[5],
+			ARN:    s,
+		}, nil
+	}
 	if !strings.HasPrefix(s, DestinationARNPrefix) {
 		return Destination{}, Errorf("invalid destination '%v'", s)
 	}
@@ -0,0 +1,88 @@
+/*
+ * MinIO Cloud Storage, (C) 2021 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package replication
+
+import (
+	"encoding/xml"
+	"testing"
+)
+
+func TestParseDestination(t *testing.T) {
+	testCases := []struct {
+		input       string
+		expected    Destination
+		expectedErr bool
+	}{
+		{"arn:aws:s3:::destination", Destination{Bucket: "destination"}, false},
+		{"arn:minio:replication:us-east-1:id:destination", Destination{Bucket: "destination", ARN: "arn:minio:replication:us-east-1:id:destination"}, false},
+		{"arn:minio:replication::id:destination", Destination{Bucket: "destination", ARN: "arn:minio:replication::id:destination"}, false},
+		// Missing target id or bucket
+		{"arn:minio:replication:us-east-1::destination", Destination{}, true},
+		{"arn:minio:replication:us-east-1:id:", Destination{}, true},
+		{"arn:minio:replication:us-east-1:id", Destination{}, true},
+		{"arn:minio:replication:us-east-1:id:destination:extra", Destination{}, true},
+		{"destination", Destination{}, true},
+		{"arn:aws:sqs:::destination", Destination{}, true},
+	}
+	for i, testCase := range testCases {
+		dest, err := parseDestination(testCase.input)
+		if testCase.expectedErr && err == nil {
+			t.Errorf("Test %d: expected an error, got none", i+1)
+		}
+		if !testCase.expectedErr && err != nil {
+			t.Errorf("Test %d: unexpected error: %v", i+1, err)
+		}
+		if dest != testCase.expected {
+			t.Errorf("Test %d: expected %v, got %v", i+1, testCase.expected, dest)
+		}
+	}
+}
+
+func TestDestinationXML(t *testing.T) {
+	testCases := []struct {
+		input       string
+		expected    string
+		expectedErr bool
+	}{
+		{`<Destination><Bucket>arn:aws:s3:::destination</Bucket></Destination>`,
+			`<Destination><Bucket>arn:aws:s3:::destination</Bucket></Destination>`, false},
+		{`<Destination><Bucket>arn:minio:replication::id:destination</Bucket><StorageClass>STANDARD</StorageClass></Destination>`,
+			`<Destination><Bucket>arn:minio:replication::id:destination</Bucket><StorageClass>STANDARD</StorageClass></Destination>`, false},
+		{`<Destination><Bucket>arn:minio:replication::id:destination</Bucket><StorageClass>GLACIER</StorageClass></Destination>`, "", true},
+		{`<Destination><Bucket>arn:minio:replication:::destination</Bucket></Destination>`, "", true},
+	}
+	for i, testCase := range testCases {
+		var dest Destination
+		err := xml.Unmarshal([]byte(testCase.input), &dest)
+		if testCase.expectedErr {
+			if err == nil {
+				t.Errorf("Test %d: expected an error, got none", i+1)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("Test %d: unexpected error: %v", i+1, err)
+		}
+		data, err := xml.Marshal(dest)
+		if err != nil {
+			t.Fatalf("Test %d: unexpected error: %v", i+1, err)
+		}
+		if string(data) != testCase.expected {
+			t.Errorf("Test %d: expected %s, got %s", i+1, testCase.expected, data)
+		}
+	}
+}
@@ -57,6 +57,7 @@ var (
 	errReplicationUniquePriority      = Errorf("Replication configuration has duplicate priority")
 	errReplicationDestinationMismatch = Errorf("The destination bucket must be same for all rules")
 	errRoleArnMissing                 = Errorf("Missing required parameter `Role` in ReplicationConfiguration")
+	errRoleArnWithDestinationArn      = Errorf("`Role` must not be set when rules specify the ARN of their replication target as destination")
 )
 
 // Config - replication configuration specified in
@@ -64,8 +65,10 @@ var (
 type Config struct {
 	XMLName xml.Name `xml:"ReplicationConfiguration" json:"-"`
 	Rules   []Rule   `xml:"Rule" json:"Rules"`
-	// RoleArn is being reused for MinIO replication ARN
-	RoleArn string `xml:"Role" json:"Role"`
+	// RoleArn is being reused for MinIO replication ARN, it is the
+	// target of all the rules. It is empty when every rule specifies
+	// the ARN of its own replication target as destination.
+	RoleArn string `xml:"Role,omitempty" json:"Role,omitempty"`
 }
 
 // Maximum 2MiB size per replication config.
@@ -90,18 +93,25 @@ func (c Config) Validate(bucket string, sameTarget bool) error {
 	if len(c.Rules) == 0 {
 		return errReplicationNoRule
 	}
-	if c.RoleArn == "" {
-		return errRoleArnMissing
-	}
 	// Validate all the rules in the replication config
 	targetMap := make(map[string]struct{})
 	priorityMap := make(map[string]struct{})
 	for _, r := range c.Rules {
-		if len(targetMap) == 0 {
-			targetMap[r.Destination.Bucket] = struct{}{}
+		if c.RoleArn == "" && r.Destination.ARN == "" {
+			return errRoleArnMissing
 		}
-		if _, ok := targetMap[r.Destination.Bucket]; !ok {
-			return errReplicationDestinationMismatch
+		if c.RoleArn != "" && r.Destination.ARN != "" {
+			return errRoleArnWithDestinationArn
+		}
+		// All the rules replicating to the target of the
+		// config must have the same destination bucket.
+		if c.RoleArn != "" {
+			if len(targetMap) == 0 {
+				targetMap[r.Destination.Bucket] = struct{}{}
+			}
+			if _, ok := targetMap[r.Destination.Bucket]; !ok {
+				return errReplicationDestinationMismatch
+			}
 		}
 		if err := r.Validate(bucket, sameTarget); err != nil {
 			return err
@@ -123,6 +133,12 @@ type ObjectOpts struct {
 	IsLatest     bool
 	DeleteMarker bool
 	SSEC         bool
+	// ExistingObject is set for objects written before the
+	// replication rules were configured.
+	ExistingObject bool
+	// TargetArn restricts the evaluation to the rules replicating
+	// to this target, all the rules are evaluated if empty.
+	TargetArn string
 }
 
 // FilterActionableRules returns the rules actions that need to be executed
@@ -136,6 +152,9 @@ func (c Config) FilterActionableRules(obj ObjectOpts) []Rule {
 		if rule.Status == Disabled {
 			continue
 		}
+		if obj.TargetArn != "" && c.RuleTargetArn(rule) != obj.TargetArn {
+			continue
+		}
 		if !strings.HasPrefix(obj.Name, rule.Prefix()) {
 			continue
 		}
@@ -157,6 +176,54 @@ func (c Config) GetDestination() Destination {
 	return Destination{}
 }
 
+// RuleTargetArn returns the ARN of the replication target of the rule.
+func (c Config) RuleTargetArn(rule Rule) string {
+	if rule.Destination.ARN != "" {
+		return rule.Destination.ARN
+	}
+	return c.RoleArn
+}
+
+// GetTargetDestination returns destination bucket and storage class
+// of the rules replicating to the target.
+func (c Config) GetTargetDestination(arn string) Destination {
+	for _, rule := range c.Rules {
+		if c.RuleTargetArn(rule) == arn {
+			return rule.Destination
+		}
+	}
+	return Destination{}
+}
+
+// TargetArns returns the ARNs of all the replication targets of the
+// rules, in the order of their first rule.
+func (c Config) TargetArns() []string {
+	var arns []string
+	seen := make(map[string]struct{})
+	for _, rule := range c.Rules {
+		arn := c.RuleTargetArn(rule)
+		if _, ok := seen[arn]; ok {
+			continue
+		}
+		seen[arn] = struct{}{}
+		arns = append(arns, arn)
+	}
+	return arns
+}
+
+// FilterTargetArns returns the ARNs of the replication targets the
+// object should be replicated to.
+func (c Config) FilterTargetArns(obj ObjectOpts) []string {
+	var arns []string
+	for _, arn := range c.TargetArns() {
+		obj.TargetArn = arn
+		if c.Replicate(obj) {
+			arns = append(arns, arn)
+		}
+	}
+	return arns
+}
+
 // Replicate returns true if the object should be replicated.
 func (c Config) Replicate(obj ObjectOpts) bool {
 
@@ -173,6 +240,9 @@ func (c Config) Replicate(obj ObjectOpts) bool {
 		if obj.SSEC {
 			return false
 		}
+		if obj.ExistingObject && rule.ExistingObjectReplication.Status != Enabled {
+			return false
+		}
 		if rule.Status == Disabled {
 			continue
 		}
@@ -181,6 +251,17 @@ func (c Config) Replicate(obj ObjectOpts) bool {
 	return false
 }
 
+// HasExistingObjectReplication - returns whether any active rule replicates
+// existing objects.
+func (c Config) HasExistingObjectReplication() bool {
+	for _, rule := range c.Rules {
+		if rule.Status == Enabled && rule.ExistingObjectReplication.Status == Enabled {
+			return true
+		}
+	}
+	return false
+}
+
 // HasActiveRules - returns whether replication policy has active rules
 // Optionally a prefix can be supplied.
 // If recursive is specified the function will also return true if any level below the
@@ -0,0 +1,172 @@
+/*
+ * MinIO Cloud Storage, (C) 2021 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package replication
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+const testRuleFmt = `<Rule><Status>%s</Status><Priority>1</Priority>` +
+	`<DeleteMarkerReplication><Status>Disabled</Status></DeleteMarkerReplication>` +
+	`<DeleteReplication><Status>Disabled</Status></DeleteReplication>%s` +
+	`<Destination><Bucket>arn:aws:s3:::destination</Bucket></Destination></Rule>`
+
+func testConfigXML(status, existing string) string {
+	return `<ReplicationConfiguration><Role>arn:minio:replication::id:destination</Role>` +
+		fmt.Sprintf(testRuleFmt, status, existing) +
+		`</ReplicationConfiguration>`
+}
+
+func TestParseAndValidateExistingObjectReplication(t *testing.T) {
+	testCases := []struct {
+		existing        string
+		expectedErr     error
+		expectedEnabled bool
+	}{
+		{"", nil, false},
+		{`<ExistingObjectReplication><Status>Enabled</Status></ExistingObjectReplication>`, nil, true},
+		{`<ExistingObjectReplication><Status>Disabled</Status></ExistingObjectReplication>`, nil, false},
+		{`<ExistingObjectReplication><Status>Unknown</Status></ExistingObjectReplication>`, errInvalidExistingObjectReplicationStatus, false},
+	}
+	for i, testCase := range testCases {
+		cfg, err := ParseConfig(strings.NewReader(testConfigXML("Enabled", testCase.existing)))
+		if err != nil {
+			t.Fatalf("Test %d: unexpected parse error: %v", i+1, err)
+		}
+		if err = cfg.Validate("source", true); err != testCase.expectedErr {
+			t.Errorf("Test %d: expected error %v, got %v", i+1, testCase.expectedErr, err)
+		}
+		if enabled := cfg.Rules[0].ExistingObjectReplication.Status == Enabled; enabled != testCase.expectedEnabled {
+			t.Errorf("Test %d: expected existing object replication enabled %v, got %v", i+1, testCase.expectedEnabled, enabled)
+		}
+	}
+}
+
+func TestHasExistingObjectReplication(t *testing.T) {
+	testCases := []struct {
+		status   Status
+		existing Status
+		expected bool
+	}{
+		{Enabled, "", false},
+		{Enabled, Disabled, false},
+		{Enabled, Enabled, true},
+		{Disabled, Enabled, false},
+	}
+	for i, testCase := range testCases {
+		cfg := Config{Rules: []Rule{{
+			Status:                    testCase.status,
+			ExistingObjectReplication: ExistingObjectReplication{Status: testCase.existing},
+		}}}
+		if got := cfg.HasExistingObjectReplication(); got != testCase.expected {
+			t.Errorf("Test %d: expected %v, got %v", i+1, testCase.expected, got)
+		}
+	}
+}
+
+func TestReplicate(t *testing.T) {
+	const arn1, arn2 = "arn:minio:replication::id1:bucket1", "arn:minio:replication::id2:bucket2"
+	cfg := Config{Rules: []Rule{
+		{
+			Status:                    Enabled,
+			Priority:                  2,
+			DeleteMarkerReplication:   DeleteMarkerReplication{Status: Enabled},
+			DeleteReplication:         DeleteReplication{Status: Disabled},
+			ExistingObjectReplication: ExistingObjectReplication{Status: Enabled},
+			Destination:               Destination{Bucket: "bucket1", ARN: arn1},
+			Filter:                    Filter{Prefix: "existing/"},
+		},
+		{
+			Status:                  Enabled,
+			Priority:                1,
+			DeleteMarkerReplication: DeleteMarkerReplication{Status: Disabled},
+			DeleteReplication:       DeleteReplication{Status: Enabled},
+			Destination:             Destination{Bucket: "bucket2", ARN: arn2},
+		},
+		{
+			Status:                    Disabled,
+			Priority:                  3,
+			ExistingObjectReplication: ExistingObjectReplication{Status: Enabled},
+			Destination:               Destination{Bucket: "bucket2", ARN: arn2},
+			Filter:                    Filter{Prefix: "disabled/"},
+		},
+	}}
+	testCases := []struct {
+		opts     ObjectOpts
+		expected bool
+	}{
+		{ObjectOpts{}, false},
+		{ObjectOpts{Name: "object"}, true},
+		{ObjectOpts{Name: "object", SSEC: true}, false},
+		// Existing objects are only replicated by rules enabling it.
+		{ObjectOpts{Name: "object", ExistingObject: true}, false},
+		{ObjectOpts{Name: "existing/object", ExistingObject: true}, true},
+		{ObjectOpts{Name: "disabled/object", ExistingObject: true}, false},
+		// Delete markers and versioned deletes.
+		{ObjectOpts{Name: "existing/object", DeleteMarker: true}, true},
+		{ObjectOpts{Name: "object", DeleteMarker: true}, false},
+		{ObjectOpts{Name: "object", VersionID: "v1"}, true},
+		{ObjectOpts{Name: "existing/object", VersionID: "v1"}, false},
+		// Rules are restricted to the target.
+		{ObjectOpts{Name: "existing/object", ExistingObject: true, TargetArn: arn1}, true},
+		{ObjectOpts{Name: "existing/object", ExistingObject: true, TargetArn: arn2}, false},
+		{ObjectOpts{Name: "object", TargetArn: arn1}, false},
+		{ObjectOpts{Name: "object", TargetArn: arn2}, true},
+		{ObjectOpts{Name: "existing/object", DeleteMarker: true, TargetArn: arn2}, false},
+	}
+	for i, testCase := range testCases {
+		if got := cfg.Replicate(testCase.opts); got != testCase.expected {
+			t.Errorf("Test %d: expected %v, got %v", i+1, testCase.expected, got)
+		}
+	}
+}
+
+func TestValidateTargetArns(t *testing.T) {
+	rule := func(priority int, bucket string) string {
+		return fmt.Sprintf(`<Rule><Status>Enabled</Status><Priority>%d</Priority>`+
+			`<DeleteMarkerReplication><Status>Disabled</Status></DeleteMarkerReplication>`+
+			`<DeleteReplication><Status>Disabled</Status></DeleteReplication>`+
+			`<Destination><Bucket>%s</Bucket></Destination></Rule>`, priority, bucket)
+	}
+	const role = `<Role>arn:minio:replication::id:destination</Role>`
+	testCases := []struct {
+		input       string
+		expectedErr error
+	}{
+		// All the rules replicate to the target of the config.
+		{role + rule(1, "arn:aws:s3:::destination") + rule(2, "arn:aws:s3:::destination"), nil},
+		{role + rule(1, "arn:aws:s3:::destination") + rule(2, "arn:aws:s3:::other"), errReplicationDestinationMismatch},
+		// Every rule replicates to its own target.
+		{rule(1, "arn:minio:replication::id1:destination") + rule(2, "arn:minio:replication::id2:other"), nil},
+		{rule(1, "arn:minio:replication::id1:destination") + rule(2, "arn:aws:s3:::other"), errRoleArnMissing},
+		{rule(1, "arn:aws:s3:::destination"), errRoleArnMissing},
+		{role + rule(1, "arn:minio:replication::id1:destination"), errRoleArnWithDestinationArn},
+		{rule(1, "arn:minio:replication::id1:source"), errDestinationSourceIdentical},
+		{rule(1, "arn:minio:replication::id1:destination") + rule(1, "arn:minio:replication::id2:other"), errReplicationUniquePriority},
+	}
+	for i, testCase := range testCases {
+		cfg, err := ParseConfig(strings.NewReader(`<ReplicationConfiguration>` + testCase.input + `</ReplicationConfiguration>`))
+		if err != nil {
+			t.Fatalf("Test %d: unexpected parse error: %v", i+1, err)
+		}
+		if err = cfg.Validate("source", true); err != testCase.expectedErr {
+			t.Errorf("Test %d: expected error %v, got %v", i+1, testCase.expectedErr, err)
+		}
+	}
+}
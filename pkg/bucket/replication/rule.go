@@ -89,6 +89,38 @@ func (d *DeleteReplication) UnmarshalXML(dec *xml.Decoder, start xml.StartElemen
 	return nil
 }
 
+// ExistingObjectReplication - whether existing objects, written before the
+// rule was configured, are replicated - https://docs.aws.amazon.com/AmazonS3/latest/dev/replication-what-is-isnot-replicated.html
+type ExistingObjectReplication struct {
+	Status Status `xml:"Status"` // should be set to "Disabled" by default
+}
+
+// IsEmpty returns true if ExistingObjectReplication is not set
+func (e ExistingObjectReplication) IsEmpty() bool {
+	return len(e.Status) == 0
+}
+
+// Validate validates whether the status is disabled.
+func (e ExistingObjectReplication) Validate() error {
+	if e.IsEmpty() {
+		return nil
+	}
+	if e.Status != Disabled && e.Status != Enabled {
+		return errInvalidExistingObjectReplicationStatus
+	}
+	return nil
+}
+
+// MarshalXML - encodes to XML data, the element is optional and
+// omitted when not set.
+func (e ExistingObjectReplication) MarshalXML(enc *xml.Encoder, start xml.StartElement) error {
+	if e.IsEmpty() {
+		return nil
+	}
+	type existingObjectReplication ExistingObjectReplication
+	return enc.EncodeElement(existingObjectReplication(e), start)
+}
+
 // Rule - a rule for replication configuration.
 type Rule struct {
 	XMLName                 xml.Name                `xml:"Rule" json:"Rule"`
@@ -97,21 +129,23 @@ type Rule struct {
 	Priority                int                     `xml:"Priority" json:"Priority"`
 	DeleteMarkerReplication DeleteMarkerReplication `xml:"DeleteMarkerReplication" json:"DeleteMarkerReplication"`
 	// MinIO extension to replicate versioned deletes
-	DeleteReplication DeleteReplication `xml:"DeleteReplication" json:"DeleteReplication"`
-	Destination       Destination       `xml:"Destination" json:"Destination"`
-	Filter            Filter            `xml:"Filter" json:"Filter"`
+	DeleteReplication         DeleteReplication         `xml:"DeleteReplication" json:"DeleteReplication"`
+	ExistingObjectReplication ExistingObjectReplication `xml:"ExistingObjectReplication" json:"ExistingObjectReplication"`
+	Destination               Destination               `xml:"Destination" json:"Destination"`
+	Filter                    Filter                    `xml:"Filter" json:"Filter"`
 }
 
 var (
-	errInvalidRuleID                        = Errorf("ID must be less than 255 characters")
-	errEmptyRuleStatus                      = Errorf("Status should not be empty")
-	errInvalidRuleStatus                    = Errorf("Status must be set to either Enabled or Disabled")
-	errDeleteMarkerReplicationMissing       = Errorf("DeleteMarkerReplication must be specified")
-	errPriorityMissing                      = Errorf("Priority must be specified")
-	errInvalidDeleteMarkerReplicationStatus = Errorf("Delete marker replication is currently not supported")
-	errDestinationSourceIdentical           = Errorf("Destination bucket cannot be the same as the source bucket.")
-	errDeleteReplicationMissing             = Errorf("Delete replication must be specified")
-	errInvalidDeleteReplicationStatus       = Errorf("Delete replication is either enable|disable")
+	errInvalidRuleID                          = Errorf("ID must be less than 255 characters")
+	errEmptyRuleStatus                        = Errorf("Status should not be empty")
+	errInvalidRuleStatus                      = Errorf("Status must be set to either Enabled or Disabled")
+	errDeleteMarkerReplicationMissing         = Errorf("DeleteMarkerReplication must be specified")
+	errPriorityMissing                        = Errorf("Priority must be specified")
+	errInvalidDeleteMarkerReplicationStatus   = Errorf("Delete marker replication is currently not supported")
+	errDestinationSourceIdentical             = Errorf("Destination bucket cannot be the same as the source bucket.")
+	errDeleteReplicationMissing               = Errorf("Delete replication must be specified")
+	errInvalidDeleteReplicationStatus         = Errorf("Delete replication is either enable|disable")
+	errInvalidExistingObjectReplicationStatus = Errorf("Existing object replication status must be set to either Enabled or Disabled")
 )
 
 // validateID - checks if ID is valid or not.
@@ -191,6 +225,9 @@ func (r Rule) Validate(bucket string, sameTarget bool) error {
 	if err := r.DeleteReplication.Validate(); err != nil {
 		return err
 	}
+	if err := r.ExistingObjectReplication.Validate(); err != nil {
+		return err
+	}
 	if r.Priority < 0 {
 		return errPriorityMissing
 	}
@@ -19,6 +19,9 @@ package versioning
 import (
 	"encoding/xml"
 	"io"
+	"strings"
+
+	"github.com/minio/minio/pkg/wildcard"
 )
 
 // State - enabled/disabled/suspended states
@@ -32,12 +35,33 @@ const (
 	Suspended State = "Suspended"
 )
 
+var (
+	errExcludedPrefixNotSupported = Errorf("excluded prefixes extension supported only when versioning is enabled")
+	errTooManyExcludedPrefixes    = Errorf("too many excluded prefixes")
+	errEmptyExcludedPrefix        = Errorf("excluded prefix cannot be empty")
+)
+
+// maxExcludedPrefixes - maximum number of excluded prefixes
+// in a versioning configuration.
+const maxExcludedPrefixes = 10
+
+// ExcludedPrefix - holds individual prefixes excluded from being versioned.
+type ExcludedPrefix struct {
+	Prefix string
+}
+
 // Versioning - Configuration for bucket versioning.
 type Versioning struct {
 	XMLNS   string   `xml:"xmlns,attr,omitempty"`
 	XMLName xml.Name `xml:"VersioningConfiguration"`
 	// MFADelete State    `xml:"MFADelete,omitempty"` // not supported yet.
 	Status State `xml:"Status,omitempty"`
+	// MinIO extension - objects under these prefixes, which may
+	// contain '*' wildcards, are not versioned.
+	ExcludedPrefixes []ExcludedPrefix `xml:",omitempty"`
+	// MinIO extension - folder objects, i.e. objects whose name
+	// ends with '/', are not versioned.
+	ExcludeFolders bool `xml:",omitempty"`
 }
 
 // Validate - validates the versioning configuration
@@ -49,7 +73,19 @@ func (v Versioning) Validate() error {
 	// 	return Errorf("unsupported MFADelete state %s", v.MFADelete)
 	// }
 	switch v.Status {
-	case Enabled, Suspended:
+	case Enabled:
+		if len(v.ExcludedPrefixes) > maxExcludedPrefixes {
+			return errTooManyExcludedPrefixes
+		}
+		for _, p := range v.ExcludedPrefixes {
+			if p.Prefix == "" {
+				return errEmptyExcludedPrefix
+			}
+		}
+	case Suspended:
+		if len(v.ExcludedPrefixes) > 0 || v.ExcludeFolders {
+			return errExcludedPrefixNotSupported
+		}
 	default:
 		return Errorf("unsupported Versioning status %s", v.Status)
 	}
@@ -66,6 +102,50 @@ func (v Versioning) Suspended() bool {
 	return v.Status == Suspended
 }
 
+// PrefixesExcluded - returns true if some objects of an enabled
+// bucket are not versioned.
+func (v Versioning) PrefixesExcluded() bool {
+	return v.Enabled() && (len(v.ExcludedPrefixes) > 0 || v.ExcludeFolders)
+}
+
+// excluded - returns true if the object is excluded from versioning.
+func (v Versioning) excluded(object string) bool {
+	if v.ExcludeFolders && strings.HasSuffix(object, "/") {
+		return true
+	}
+	for _, p := range v.ExcludedPrefixes {
+		if wildcard.MatchSimple(p.Prefix+"*", object) {
+			return true
+		}
+	}
+	return false
+}
+
+// PrefixEnabled - returns true if versioning is enabled for the object,
+// an empty object name checks the bucket as a whole.
+func (v Versioning) PrefixEnabled(object string) bool {
+	if !v.Enabled() {
+		return false
+	}
+	if object == "" {
+		return true
+	}
+	return !v.excluded(object)
+}
+
+// PrefixSuspended - returns true if versioning is suspended for the
+// object, objects excluded from an enabled bucket behave as in a
+// suspended bucket.
+func (v Versioning) PrefixSuspended(object string) bool {
+	if v.Suspended() {
+		return true
+	}
+	if !v.Enabled() || object == "" {
+		return false
+	}
+	return v.excluded(object)
+}
+
 // ParseConfig - parses data in given reader to VersioningConfiguration.
 func ParseConfig(reader io.Reader) (*Versioning, error) {
 	var v Versioning
@@ -0,0 +1,94 @@
+/*
+ * MinIO Cloud Storage, (C) 2021 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package versioning
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseConfig(t *testing.T) {
+	testCases := []struct {
+		input       string
+		expectedErr bool
+	}{
+		{`<VersioningConfiguration><Status>Enabled</Status></VersioningConfiguration>`, false},
+		{`<VersioningConfiguration><Status>Suspended</Status></VersioningConfiguration>`, false},
+		{`<VersioningConfiguration><Status>Enabled</Status><ExcludeFolders>true</ExcludeFolders>
+<ExcludedPrefixes><Prefix>*/_temporary</Prefix></ExcludedPrefixes>
+<ExcludedPrefixes><Prefix>staging/</Prefix></ExcludedPrefixes></VersioningConfiguration>`, false},
+		// Unsupported status
+		{`<VersioningConfiguration><Status>Disabled</Status></VersioningConfiguration>`, true},
+		// Excluded prefixes with versioning suspended
+		{`<VersioningConfiguration><Status>Suspended</Status>
+<ExcludedPrefixes><Prefix>staging/</Prefix></ExcludedPrefixes></VersioningConfiguration>`, true},
+		{`<VersioningConfiguration><Status>Suspended</Status><ExcludeFolders>true</ExcludeFolders></VersioningConfiguration>`, true},
+		// Empty excluded prefix
+		{`<VersioningConfiguration><Status>Enabled</Status>
+<ExcludedPrefixes><Prefix></Prefix></ExcludedPrefixes></VersioningConfiguration>`, true},
+		// Too many excluded prefixes
+		{`<VersioningConfiguration><Status>Enabled</Status>` +
+			strings.Repeat(`<ExcludedPrefixes><Prefix>tmp/</Prefix></ExcludedPrefixes>`, maxExcludedPrefixes+1) +
+			`</VersioningConfiguration>`, true},
+	}
+
+	for i, testCase := range testCases {
+		_, err := ParseConfig(strings.NewReader(testCase.input))
+		if testCase.expectedErr && err == nil {
+			t.Errorf("Test %d: expected an error, got none", i+1)
+		}
+		if !testCase.expectedErr && err != nil {
+			t.Errorf("Test %d: unexpected error: %v", i+1, err)
+		}
+	}
+}
+
+func TestPrefixEnabled(t *testing.T) {
+	v := Versioning{
+		Status:           Enabled,
+		ExcludedPrefixes: []ExcludedPrefix{{Prefix: "*/_temporary"}, {Prefix: "staging/"}},
+		ExcludeFolders:   true,
+	}
+	testCases := []struct {
+		object            string
+		expectedEnabled   bool
+		expectedSuspended bool
+	}{
+		{"", true, false},
+		{"data/part-0000.parquet", true, false},
+		{"data/_temporary/0/part-0000.parquet", false, true},
+		{"staging/file", false, true},
+		{"data/staging/file", true, false},
+		{"data/", false, true},
+	}
+	for i, testCase := range testCases {
+		if enabled := v.PrefixEnabled(testCase.object); enabled != testCase.expectedEnabled {
+			t.Errorf("Test %d: expected PrefixEnabled %v, got %v", i+1, testCase.expectedEnabled, enabled)
+		}
+		if suspended := v.PrefixSuspended(testCase.object); suspended != testCase.expectedSuspended {
+			t.Errorf("Test %d: expected PrefixSuspended %v, got %v", i+1, testCase.expectedSuspended, suspended)
+		}
+	}
+	if !v.PrefixesExcluded() {
+		t.Error("expected prefixes to be excluded")
+	}
+
+	v = Versioning{Status: Suspended}
+	if v.PrefixEnabled("data/file") || !v.PrefixSuspended("data/file") || v.PrefixesExcluded() {
+		t.Error("expected versioning to be suspended for all objects")
+	}
+}
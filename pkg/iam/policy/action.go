@@ -90,6 +90,14 @@ const (
 	// GetBucketLifecycleAction - GetBucketLifecycle Rest API action.
 	GetBucketLifecycleAction = "s3:GetLifecycleConfiguration"
 
+	// PutBucketInventoryAction - PutBucketInventoryConfiguration and
+	// DeleteBucketInventoryConfiguration Rest API action.
+	PutBucketInventoryAction = "s3:PutInventoryConfiguration"
+
+	// GetBucketInventoryAction - GetBucketInventoryConfiguration and
+	// ListBucketInventoryConfigurations Rest API action.
+	GetBucketInventoryAction = "s3:GetInventoryConfiguration"
+
 	// PutBucketNotificationAction - PutObjectNotification Rest API action.
 	PutBucketNotificationAction = "s3:PutBucketNotification"
 
@@ -204,6 +212,8 @@ var supportedActions = map[Action]struct{}{
 	ListMultipartUploadPartsAction:         {},
 	PutBucketLifecycleAction:               {},
 	GetBucketLifecycleAction:               {},
+	PutBucketInventoryAction:               {},
+	GetBucketInventoryAction:               {},
 	PutBucketNotificationAction:            {},
 	PutBucketPolicyAction:                  {},
 	PutObjectAction:                        {},
@@ -310,6 +320,7 @@ var iamActionConditionKeyMap = actionConditionKeyMap{
 			condition.S3XAmzServerSideEncryption,
 			condition.S3XAmzServerSideEncryptionCustomerAlgorithm,
 			condition.S3VersionID,
+			condition.S3ExistingObjectTag,
 		}, condition.CommonKeys...)...),
 
 	ListBucketAction: condition.NewKeySet(
@@ -342,6 +353,8 @@ var iamActionConditionKeyMap = actionConditionKeyMap{
 			condition.S3ObjectLockRetainUntilDate,
 			condition.S3ObjectLockMode,
 			condition.S3ObjectLockLegalHold,
+			condition.S3RequestObjectTag,
+			condition.S3RequestObjectTagKeys,
 		}, condition.CommonKeys...)...),
 
 	// https://docs.aws.amazon.com/AmazonS3/latest/dev/list_amazons3.html
@@ -385,27 +398,37 @@ var iamActionConditionKeyMap = actionConditionKeyMap{
 	PutObjectTaggingAction: condition.NewKeySet(
 		append([]condition.Key{
 			condition.S3VersionID,
+			condition.S3ExistingObjectTag,
+			condition.S3RequestObjectTag,
+			condition.S3RequestObjectTagKeys,
 		}, condition.CommonKeys...)...),
 	GetObjectTaggingAction: condition.NewKeySet(
 		append([]condition.Key{
 			condition.S3VersionID,
+			condition.S3ExistingObjectTag,
 		}, condition.CommonKeys...)...),
 	DeleteObjectTaggingAction: condition.NewKeySet(
 		append([]condition.Key{
 			condition.S3VersionID,
+			condition.S3ExistingObjectTag,
 		}, condition.CommonKeys...)...),
 
 	PutObjectVersionTaggingAction: condition.NewKeySet(
 		append([]condition.Key{
 			condition.S3VersionID,
+			condition.S3ExistingObjectTag,
+			condition.S3RequestObjectTag,
+			condition.S3RequestObjectTagKeys,
 		}, condition.CommonKeys...)...),
 	GetObjectVersionAction: condition.NewKeySet(
 		append([]condition.Key{
 			condition.S3VersionID,
+			condition.S3ExistingObjectTag,
 		}, condition.CommonKeys...)...),
 	GetObjectVersionTaggingAction: condition.NewKeySet(
 		append([]condition.Key{
 			condition.S3VersionID,
+			condition.S3ExistingObjectTag,
 		}, condition.CommonKeys...)...),
 	DeleteObjectVersionAction: condition.NewKeySet(
 		append([]condition.Key{
@@ -414,6 +437,7 @@ var iamActionConditionKeyMap = actionConditionKeyMap{
 	DeleteObjectVersionTaggingAction: condition.NewKeySet(
 		append([]condition.Key{
 			condition.S3VersionID,
+			condition.S3ExistingObjectTag,
 		}, condition.CommonKeys...)...),
 	ReplicateObjectAction: condition.NewKeySet(
 		append([]condition.Key{
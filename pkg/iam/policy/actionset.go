@@ -57,6 +57,19 @@ func (actionSet ActionSet) Match(action Action) bool {
 	return false
 }
 
+// matchExcluded - matches the action with the action patterns of a
+// NotAction set. Unlike Match, excluding GetObjectVersion does not
+// exclude GetObject.
+func (actionSet ActionSet) matchExcluded(action Action) bool {
+	for r := range actionSet {
+		if r.Match(action) {
+			return true
+		}
+	}
+
+	return false
+}
+
 // Equals - checks whether given action set is equal to current action set or not.
 func (actionSet ActionSet) Equals(sactionSet ActionSet) bool {
 	// If length of set is not equal to length of given set, the
@@ -53,6 +53,10 @@ const (
 	HealthInfoAdminAction = "admin:OBDInfo"
 	// BandwidthMonitorAction - allow monitoring bandwidth usage
 	BandwidthMonitorAction = "admin:BandwidthMonitor"
+	// SetBandwidthLimitAdminAction - allow setting bucket and user bandwidth limits
+	SetBandwidthLimitAdminAction = "admin:SetBandwidthLimit"
+	// GetBandwidthLimitAdminAction - allow getting bucket and user bandwidth limits
+	GetBandwidthLimitAdminAction = "admin:GetBandwidthLimit"
 
 	// ServerUpdateAdminAction - allow MinIO binary update
 	ServerUpdateAdminAction = "admin:ServerUpdate"
@@ -112,12 +116,32 @@ const (
 	// GetBucketQuotaAdminAction - allow getting bucket quota
 	GetBucketQuotaAdminAction = "admin:GetBucketQuota"
 
+	// User quota Actions
+
+	// SetUserQuotaAdminAction - allow setting user and group quotas
+	SetUserQuotaAdminAction = "admin:SetUserQuota"
+	// GetUserQuotaAdminAction - allow getting user and group quotas
+	GetUserQuotaAdminAction = "admin:GetUserQuota"
+
 	// Bucket Target admin Actions
 
 	// SetBucketTargetAction - allow setting bucket target
 	SetBucketTargetAction = "admin:SetBucketTarget"
 	// GetBucketTargetAction - allow getting bucket targets
 	GetBucketTargetAction = "admin:GetBucketTarget"
+	// ReplicateBucketMetadataAction - allow replication sources to update bucket configuration
+	ReplicateBucketMetadataAction = "admin:ReplicateBucketMetadata"
+
+	// Batch job Actions
+
+	// StartBatchJobAction - allow starting batch jobs
+	StartBatchJobAction = "admin:StartBatchJob"
+	// ListBatchJobsAction - allow listing batch jobs
+	ListBatchJobsAction = "admin:ListBatchJobs"
+	// DescribeBatchJobAction - allow getting the progress of batch jobs
+	DescribeBatchJobAction = "admin:DescribeBatchJob"
+	// CancelBatchJobAction - allow canceling batch jobs
+	CancelBatchJobAction = "admin:CancelBatchJob"
 
 	// AllAdminActions - provides all admin permissions
 	AllAdminActions = "admin:*"
@@ -136,6 +160,8 @@ var supportedAdminActions = map[AdminAction]struct{}{
 	ServerInfoAdminAction:          {},
 	HealthInfoAdminAction:          {},
 	BandwidthMonitorAction:         {},
+	SetBandwidthLimitAdminAction:   {},
+	GetBandwidthLimitAdminAction:   {},
 	ServerUpdateAdminAction:        {},
 	ServiceRestartAdminAction:      {},
 	ServiceStopAdminAction:         {},
@@ -159,8 +185,15 @@ var supportedAdminActions = map[AdminAction]struct{}{
 	ListUserPoliciesAdminAction:    {},
 	SetBucketQuotaAdminAction:      {},
 	GetBucketQuotaAdminAction:      {},
+	SetUserQuotaAdminAction:        {},
+	GetUserQuotaAdminAction:        {},
 	SetBucketTargetAction:          {},
 	GetBucketTargetAction:          {},
+	ReplicateBucketMetadataAction:  {},
+	StartBatchJobAction:            {},
+	ListBatchJobsAction:            {},
+	DescribeBatchJobAction:         {},
+	CancelBatchJobAction:           {},
 	AllAdminActions:                {},
 }
 
@@ -179,6 +212,8 @@ var adminActionConditionKeyMap = map[Action]condition.KeySet{
 	DataUsageInfoAdminAction:       condition.NewKeySet(condition.AllSupportedAdminKeys...),
 	HealthInfoAdminAction:          condition.NewKeySet(condition.AllSupportedAdminKeys...),
 	BandwidthMonitorAction:         condition.NewKeySet(condition.AllSupportedAdminKeys...),
+	SetBandwidthLimitAdminAction:   condition.NewKeySet(condition.AllSupportedAdminKeys...),
+	GetBandwidthLimitAdminAction:   condition.NewKeySet(condition.AllSupportedAdminKeys...),
 	TopLocksAdminAction:            condition.NewKeySet(condition.AllSupportedAdminKeys...),
 	ProfilingAdminAction:           condition.NewKeySet(condition.AllSupportedAdminKeys...),
 	TraceAdminAction:               condition.NewKeySet(condition.AllSupportedAdminKeys...),
@@ -206,6 +241,13 @@ var adminActionConditionKeyMap = map[Action]condition.KeySet{
 	ListUserPoliciesAdminAction:    condition.NewKeySet(condition.AllSupportedAdminKeys...),
 	SetBucketQuotaAdminAction:      condition.NewKeySet(condition.AllSupportedAdminKeys...),
 	GetBucketQuotaAdminAction:      condition.NewKeySet(condition.AllSupportedAdminKeys...),
+	SetUserQuotaAdminAction:        condition.NewKeySet(condition.AllSupportedAdminKeys...),
+	GetUserQuotaAdminAction:        condition.NewKeySet(condition.AllSupportedAdminKeys...),
 	SetBucketTargetAction:          condition.NewKeySet(condition.AllSupportedAdminKeys...),
 	GetBucketTargetAction:          condition.NewKeySet(condition.AllSupportedAdminKeys...),
+	ReplicateBucketMetadataAction:  condition.NewKeySet(condition.AllSupportedAdminKeys...),
+	StartBatchJobAction:            condition.NewKeySet(condition.AllSupportedAdminKeys...),
+	ListBatchJobsAction:            condition.NewKeySet(condition.AllSupportedAdminKeys...),
+	DescribeBatchJobAction:         condition.NewKeySet(condition.AllSupportedAdminKeys...),
+	CancelBatchJobAction:           condition.NewKeySet(condition.AllSupportedAdminKeys...),
 }
@@ -23,6 +23,7 @@ import (
 
 	"github.com/minio/minio-go/v7/pkg/set"
 	"github.com/minio/minio/pkg/bucket/policy"
+	"github.com/minio/minio/pkg/bucket/policy/condition"
 )
 
 // DefaultVersion - default policy version as per AWS S3 specification.
@@ -122,6 +123,17 @@ func (iamp Policy) IsAllowed(args Args) bool {
 	return false
 }
 
+// HasObjectTagKey - returns whether any statement of the policy has a
+// condition on tagKey used with a tag key.
+func (iamp Policy) HasObjectTagKey(tagKey condition.Key) bool {
+	for _, statement := range iamp.Statements {
+		if statement.Conditions.Keys().HasObjectTagKey(tagKey) {
+			return true
+		}
+	}
+	return false
+}
+
 // IsEmpty - returns whether policy is empty or not.
 func (iamp Policy) IsEmpty() bool {
 	return len(iamp.Statements) == 0
@@ -153,10 +165,18 @@ redo:
 				continue
 			}
 
+			if !iamp.Statements[i].NotActions.Equals(statement.NotActions) {
+				continue
+			}
+
 			if !iamp.Statements[i].Resources.Equals(statement.Resources) {
 				continue
 			}
 
+			if !iamp.Statements[i].NotResources.Equals(statement.NotResources) {
+				continue
+			}
+
 			if iamp.Statements[i].Conditions.String() != statement.Conditions.String() {
 				continue
 			}
@@ -22,7 +22,6 @@ import (
 	"strings"
 
 	"github.com/minio/minio/pkg/bucket/policy/condition"
-	"github.com/minio/minio/pkg/wildcard"
 )
 
 // ResourceARNPrefix - resource ARN prefix as per AWS S3 specification.
@@ -49,17 +48,10 @@ func (r Resource) IsValid() bool {
 
 // Match - matches object name with resource pattern.
 func (r Resource) Match(resource string, conditionValues map[string][]string) bool {
-	pattern := r.Pattern
-	for _, key := range condition.CommonKeys {
-		// Empty values are not supported for policy variables.
-		if rvalues, ok := conditionValues[key.Name()]; ok && rvalues[0] != "" {
-			pattern = strings.Replace(pattern, key.VarName(), rvalues[0], -1)
-		}
-	}
-	if path.Clean(resource) == pattern {
+	if pattern, ok := condition.SubstituteVariables(r.Pattern, conditionValues); ok && path.Clean(resource) == pattern {
 		return true
 	}
-	return wildcard.Match(pattern, resource)
+	return condition.MatchVariables(r.Pattern, resource, conditionValues)
 }
 
 // MarshalJSON - encodes Resource to JSON data.
@@ -112,6 +104,9 @@ func parseResource(s string) (Resource, error) {
 	if bucketName == "" {
 		return Resource{}, Errorf("invalid resource format '%v'", s)
 	}
+	if err := condition.ValidateVariables(pattern); err != nil {
+		return Resource{}, Errorf("invalid resource '%v': %v", s, err)
+	}
 
 	return Resource{
 		BucketName: bucketName,
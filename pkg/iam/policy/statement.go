@@ -25,17 +25,23 @@ import (
 
 // Statement - iam policy statement.
 type Statement struct {
-	SID        policy.ID           `json:"Sid,omitempty"`
-	Effect     policy.Effect       `json:"Effect"`
-	Actions    ActionSet           `json:"Action"`
-	Resources  ResourceSet         `json:"Resource,omitempty"`
-	Conditions condition.Functions `json:"Condition,omitempty"`
+	SID          policy.ID           `json:"Sid,omitempty"`
+	Effect       policy.Effect       `json:"Effect"`
+	Actions      ActionSet           `json:"Action,omitempty"`
+	NotActions   ActionSet           `json:"NotAction,omitempty"`
+	Resources    ResourceSet         `json:"Resource,omitempty"`
+	NotResources ResourceSet         `json:"NotResource,omitempty"`
+	Conditions   condition.Functions `json:"Condition,omitempty"`
 }
 
 // IsAllowed - checks given policy args is allowed to continue the Rest API.
 func (statement Statement) IsAllowed(args Args) bool {
 	check := func() bool {
-		if !statement.Actions.Match(args.Action) {
+		if len(statement.NotActions) > 0 {
+			if statement.NotActions.matchExcluded(args.Action) {
+				return false
+			}
+		} else if !statement.Actions.Match(args.Action) {
 			return false
 		}
 
@@ -51,8 +57,14 @@ func (statement Statement) IsAllowed(args Args) bool {
 		}
 
 		// For admin statements, resource match can be ignored.
-		if !statement.Resources.Match(resource, args.ConditionValues) && !statement.isAdmin() {
-			return false
+		if !statement.isAdmin() {
+			if len(statement.NotResources) > 0 {
+				if statement.NotResources.Match(resource, args.ConditionValues) {
+					return false
+				}
+			} else if !statement.Resources.Match(resource, args.ConditionValues) {
+				return false
+			}
 		}
 
 		return statement.Conditions.Evaluate(args.ConditionValues)
@@ -75,10 +87,18 @@ func (statement Statement) isValid() error {
 		return Errorf("invalid Effect %v", statement.Effect)
 	}
 
-	if len(statement.Actions) == 0 {
+	if len(statement.Actions) != 0 && len(statement.NotActions) != 0 {
+		return Errorf("Action and NotAction must not be used together")
+	}
+
+	if len(statement.Actions) == 0 && len(statement.NotActions) == 0 {
 		return Errorf("Action must not be empty")
 	}
 
+	if len(statement.Resources) != 0 && len(statement.NotResources) != 0 {
+		return Errorf("Resource and NotResource must not be used together")
+	}
+
 	if statement.isAdmin() {
 		if err := statement.Actions.ValidateAdmin(); err != nil {
 			return err
@@ -97,7 +117,7 @@ func (statement Statement) isValid() error {
 		return Errorf("invalid SID %v", statement.SID)
 	}
 
-	if len(statement.Resources) == 0 {
+	if len(statement.Resources) == 0 && len(statement.NotResources) == 0 {
 		return Errorf("Resource must not be empty")
 	}
 
@@ -105,12 +125,46 @@ func (statement Statement) isValid() error {
 		return err
 	}
 
+	if err := statement.NotResources.Validate(); err != nil {
+		return err
+	}
+
 	if err := statement.Actions.Validate(); err != nil {
 		return err
 	}
 
+	// NotAction may exclude admin actions as well, as it matches
+	// all actions which are not excluded.
+	for action := range statement.NotActions {
+		if !action.IsValid() && !AdminAction(action).IsValid() {
+			return Errorf("unsupported action '%v'", action)
+		}
+	}
+
+	if len(statement.NotActions) != 0 {
+		// Condition keys must be supported by any of the actions
+		// which are not excluded.
+		supportedKeys := condition.NewKeySet(condition.CommonKeys...)
+		for action := range supportedActions {
+			// s3:* stands for all the actions, some may be excluded.
+			if action != AllActions && !statement.NotActions.matchExcluded(action) {
+				supportedKeys.Merge(iamActionConditionKeyMap.Lookup(action))
+			}
+		}
+		for action := range supportedAdminActions {
+			if !statement.NotActions.matchExcluded(Action(action)) {
+				supportedKeys.Merge(adminActionConditionKeyMap[Action(action)])
+			}
+		}
+		keyDiff := statement.Conditions.Keys().Difference(supportedKeys)
+		if !keyDiff.IsEmpty() {
+			return Errorf("unsupported condition keys '%v' used for NotAction '%v'", keyDiff, statement.NotActions)
+		}
+	}
+
 	for action := range statement.Actions {
-		if !statement.Resources.objectResourceExists() && !statement.Resources.bucketResourceExists() {
+		// NotResource matches both bucket and object resources.
+		if len(statement.Resources) != 0 && !statement.Resources.objectResourceExists() && !statement.Resources.bucketResourceExists() {
 			return Errorf("unsupported Resource found %v for action %v", statement.Resources, action)
 		}
 
@@ -464,3 +464,85 @@ func TestStatementValidate(t *testing.T) {
 		}
 	}
 }
+
+func TestStatementNotElements(t *testing.T) {
+	testCases := []struct {
+		data      string
+		args      Args
+		expectErr bool
+		allowed   bool
+	}{
+		// NotAction matches all other actions.
+		{`{"Effect":"Allow","NotAction":["s3:DeleteObject"],"Resource":["arn:aws:s3:::mybucket/*"]}`,
+			Args{Action: GetObjectAction, BucketName: "mybucket", ObjectName: "myobject"}, false, true},
+		{`{"Effect":"Allow","NotAction":["s3:DeleteObject"],"Resource":["arn:aws:s3:::mybucket/*"]}`,
+			Args{Action: DeleteObjectAction, BucketName: "mybucket", ObjectName: "myobject"}, false, false},
+		{`{"Effect":"Deny","NotAction":["s3:Get*"],"Resource":["arn:aws:s3:::*"]}`,
+			Args{Action: PutObjectAction, BucketName: "mybucket", ObjectName: "myobject"}, false, false},
+		{`{"Effect":"Deny","NotAction":["s3:Get*"],"Resource":["arn:aws:s3:::*"]}`,
+			Args{Action: GetObjectAction, BucketName: "mybucket", ObjectName: "myobject"}, false, true},
+		{`{"Effect":"Allow","NotAction":["admin:ServerInfo"],"Resource":["arn:aws:s3:::*"]}`,
+			Args{Action: Action(ServerInfoAdminAction)}, false, false},
+		// Excluding GetObjectVersion does not exclude GetObject.
+		{`{"Effect":"Allow","NotAction":["s3:GetObjectVersion"],"Resource":["arn:aws:s3:::mybucket/*"]}`,
+			Args{Action: GetObjectAction, BucketName: "mybucket", ObjectName: "myobject"}, false, true},
+		{`{"Effect":"Allow","NotAction":["s3:GetObjectVersion"],"Resource":["arn:aws:s3:::mybucket/*"]}`,
+			Args{Action: GetObjectVersionAction, BucketName: "mybucket", ObjectName: "myobject"}, false, false},
+		// Condition keys must be supported by an action which is not excluded.
+		{`{"Effect":"Allow","NotAction":["s3:DeleteObject"],"Resource":["arn:aws:s3:::mybucket/*"],"Condition":{"StringEquals":{"s3:prefix":"public/"}}}`,
+			Args{Action: ListBucketAction, BucketName: "mybucket", ConditionValues: map[string][]string{"prefix": {"public/"}}}, false, true},
+		{`{"Effect":"Allow","NotAction":["s3:List*"],"Resource":["arn:aws:s3:::mybucket/*"],"Condition":{"StringEquals":{"s3:prefix":"public/"}}}`,
+			Args{}, true, false},
+		// NotResource matches all other resources.
+		{`{"Effect":"Allow","Action":["s3:GetObject"],"NotResource":["arn:aws:s3:::mybucket/private/*"]}`,
+			Args{Action: GetObjectAction, BucketName: "mybucket", ObjectName: "public/myobject"}, false, true},
+		{`{"Effect":"Allow","Action":["s3:GetObject"],"NotResource":["arn:aws:s3:::mybucket/private/*"]}`,
+			Args{Action: GetObjectAction, BucketName: "mybucket", ObjectName: "private/myobject"}, false, false},
+		{`{"Effect":"Allow","NotAction":["s3:DeleteObject"],"NotResource":["arn:aws:s3:::mybucket/private/*"]}`,
+			Args{Action: PutObjectAction, BucketName: "yourbucket", ObjectName: "myobject"}, false, true},
+		// Positive and negated elements can not be used together.
+		{`{"Effect":"Allow","Action":["s3:GetObject"],"NotAction":["s3:PutObject"],"Resource":["arn:aws:s3:::mybucket/*"]}`,
+			Args{}, true, false},
+		{`{"Effect":"Allow","Action":["s3:GetObject"],"Resource":["arn:aws:s3:::mybucket/*"],"NotResource":["arn:aws:s3:::mybucket/private/*"]}`,
+			Args{}, true, false},
+		// Invalid NotAction.
+		{`{"Effect":"Allow","NotAction":["s3:Unknown"],"Resource":["arn:aws:s3:::mybucket/*"]}`,
+			Args{}, true, false},
+		// NotAction needs a resource.
+		{`{"Effect":"Allow","NotAction":["s3:DeleteObject"]}`,
+			Args{}, true, false},
+	}
+
+	for i, testCase := range testCases {
+		var statement Statement
+		err := json.Unmarshal([]byte(testCase.data), &statement)
+		if err == nil {
+			err = statement.Validate()
+		}
+		expectErr := (err != nil)
+
+		if expectErr != testCase.expectErr {
+			t.Fatalf("case %v: error: expected: %v, got: %v, %v\n", i+1, testCase.expectErr, expectErr, err)
+		}
+		if testCase.expectErr {
+			continue
+		}
+
+		if result := statement.IsAllowed(testCase.args); result != testCase.allowed {
+			t.Fatalf("case %v: expected: %v, got: %v\n", i+1, testCase.allowed, result)
+		}
+
+		// Statement must be encoded with the same elements.
+		data, err := json.Marshal(statement)
+		if err != nil {
+			t.Fatalf("case %v: unexpected error. %v\n", i+1, err)
+		}
+		var decoded Statement
+		if err = json.Unmarshal(data, &decoded); err != nil {
+			t.Fatalf("case %v: unexpected error. %v\n", i+1, err)
+		}
+		if !reflect.DeepEqual(decoded, statement) {
+			t.Fatalf("case %v: expected: %v, got: %v\n", i+1, statement, decoded)
+		}
+	}
+}
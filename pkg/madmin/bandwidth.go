@@ -20,8 +20,10 @@ package madmin
 import (
 	"context"
 	"encoding/json"
+	"io/ioutil"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
 
 	"github.com/minio/minio/pkg/bandwidth"
@@ -33,8 +35,9 @@ type Report struct {
 	Err    error
 }
 
-// GetBucketBandwidth - Gets a channel reporting bandwidth measurements for replication buckets. If no buckets
-// generate replication traffic an empty map is returned in the report until traffic is seen.
+// GetBucketBandwidth - Gets a channel reporting bandwidth measurements for replication and client traffic of
+// buckets, and client traffic of users when no bucket is requested. If no buckets generate traffic an empty
+// map is returned in the report until traffic is seen.
 func (adm *AdminClient) GetBucketBandwidth(ctx context.Context, buckets ...string) <-chan Report {
 	queryValues := url.Values{}
 	ch := make(chan Report)
@@ -80,3 +83,79 @@ func (adm *AdminClient) GetBucketBandwidth(ctx context.Context, buckets ...strin
 	}(ctx, ch, resp)
 	return ch
 }
+
+// BandwidthLimit holds the bandwidth limits of the client traffic of a
+// bucket or of a user across the cluster, in bytes per second. A limit
+// of 0 disables it.
+type BandwidthLimit struct {
+	Get uint64 `json:"get,omitempty"` // Data downloaded by clients
+	Put uint64 `json:"put,omitempty"` // Data uploaded by clients
+}
+
+// BandwidthLimits holds the bandwidth limits of the buckets and users.
+type BandwidthLimits struct {
+	Buckets map[string]BandwidthLimit `json:"buckets,omitempty"`
+	Users   map[string]BandwidthLimit `json:"users,omitempty"`
+}
+
+// SetBandwidthLimit - sets the bandwidth limit of the client traffic of a
+// bucket or of a user, an empty limit removes it.
+func (adm *AdminClient) SetBandwidthLimit(ctx context.Context, name string, isUser bool, limit BandwidthLimit) error {
+	data, err := json.Marshal(limit)
+	if err != nil {
+		return err
+	}
+
+	queryValues := url.Values{}
+	queryValues.Set("name", name)
+	queryValues.Set("isUser", strconv.FormatBool(isUser))
+
+	reqData := requestData{
+		relPath:     adminAPIPrefix + "/set-bandwidth-limit",
+		queryValues: queryValues,
+		content:     data,
+	}
+
+	// Execute PUT on /minio/admin/v3/set-bandwidth-limit
+	resp, err := adm.executeMethod(ctx, http.MethodPut, reqData)
+
+	defer closeResponse(resp)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return httpRespToErrorResponse(resp)
+	}
+
+	return nil
+}
+
+// GetBandwidthLimits - returns the bandwidth limits of all the buckets and users.
+func (adm *AdminClient) GetBandwidthLimits(ctx context.Context) (l BandwidthLimits, err error) {
+	reqData := requestData{
+		relPath: adminAPIPrefix + "/get-bandwidth-limits",
+	}
+
+	// Execute GET on /minio/admin/v3/get-bandwidth-limits
+	resp, err := adm.executeMethod(ctx, http.MethodGet, reqData)
+
+	defer closeResponse(resp)
+	if err != nil {
+		return l, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return l, httpRespToErrorResponse(resp)
+	}
+
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return l, err
+	}
+	if err = json.Unmarshal(b, &l); err != nil {
+		return l, err
+	}
+
+	return l, nil
+}
@@ -0,0 +1,196 @@
+/*
+ * MinIO Cloud Storage, (C) 2021 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package madmin
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// BatchJobType - action applied by a batch job to the matching objects.
+type BatchJobType string
+
+const (
+	// BatchJobCopy - copies the objects to another bucket or to a remote target.
+	BatchJobCopy BatchJobType = "copy"
+	// BatchJobTag - sets tags on the objects.
+	BatchJobTag BatchJobType = "tag"
+	// BatchJobDelete - deletes the objects.
+	BatchJobDelete BatchJobType = "delete"
+	// BatchJobReencrypt - rewrites the objects encrypted with SSE-S3.
+	BatchJobReencrypt BatchJobType = "reencrypt"
+	// BatchJobReplicate - queues the objects for replication.
+	BatchJobReplicate BatchJobType = "replicate"
+)
+
+// BatchJobStatusType - status of a batch job.
+type BatchJobStatusType string
+
+const (
+	// BatchJobRunning - job is listing and processing objects.
+	BatchJobRunning BatchJobStatusType = "Running"
+	// BatchJobCompleted - all the matching objects were processed.
+	BatchJobCompleted BatchJobStatusType = "Completed"
+	// BatchJobFailed - job stopped before processing all objects.
+	BatchJobFailed BatchJobStatusType = "Failed"
+	// BatchJobCanceled - job was canceled.
+	BatchJobCanceled BatchJobStatusType = "Canceled"
+)
+
+// BatchJobStatus - progress of a batch job.
+type BatchJobStatus struct {
+	ID     string             `json:"id"`
+	Type   BatchJobType       `json:"type"`
+	Status BatchJobStatusType `json:"status"`
+	Bucket string             `json:"bucket"`
+	Prefix string             `json:"prefix,omitempty"`
+	// Server running the job.
+	Node       string    `json:"node"`
+	StartTime  time.Time `json:"startTime"`
+	LastUpdate time.Time `json:"lastUpdate"`
+
+	// Last object processed, the job resumes after it.
+	Marker string `json:"marker,omitempty"`
+
+	// Number of objects listed.
+	Scanned int64 `json:"scanned"`
+	// Number of objects matching the filters of the job.
+	Matched int64 `json:"matched"`
+	// Number and size of objects processed.
+	Succeeded     int64 `json:"succeeded"`
+	SucceededSize int64 `json:"succeededSize"`
+	// Number of objects which could not be processed.
+	Failed int64 `json:"failed"`
+	// Last error encountered, if any.
+	Error string `json:"error,omitempty"`
+}
+
+// StartBatchJobResult - result of StartBatchJob.
+type StartBatchJobResult struct {
+	ID string `json:"id"`
+}
+
+// StartBatchJob - starts a batch job described by the YAML or JSON
+// spec, returns the ID of the job.
+func (adm *AdminClient) StartBatchJob(ctx context.Context, spec []byte) (id string, err error) {
+	reqData := requestData{
+		relPath: adminAPIPrefix + "/batch/start-job",
+		content: spec,
+	}
+
+	// Execute POST on /minio/admin/v3/batch/start-job to start a batch job
+	resp, err := adm.executeMethod(ctx, http.MethodPost, reqData)
+	defer closeResponse(resp)
+	if err != nil {
+		return id, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return id, httpRespToErrorResponse(resp)
+	}
+
+	var result StartBatchJobResult
+	if err = json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return id, err
+	}
+	return result.ID, nil
+}
+
+// ListBatchJobs - returns the progress of all the batch jobs.
+func (adm *AdminClient) ListBatchJobs(ctx context.Context) (jobs []BatchJobStatus, err error) {
+	reqData := requestData{
+		relPath: adminAPIPrefix + "/batch/list-jobs",
+	}
+
+	// Execute GET on /minio/admin/v3/batch/list-jobs
+	resp, err := adm.executeMethod(ctx, http.MethodGet, reqData)
+	defer closeResponse(resp)
+	if err != nil {
+		return jobs, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return jobs, httpRespToErrorResponse(resp)
+	}
+
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return jobs, err
+	}
+	if err = json.Unmarshal(b, &jobs); err != nil {
+		return jobs, err
+	}
+	return jobs, nil
+}
+
+// DescribeBatchJob - returns the progress of a batch job.
+func (adm *AdminClient) DescribeBatchJob(ctx context.Context, id string) (job BatchJobStatus, err error) {
+	queryValues := url.Values{}
+	queryValues.Set("id", id)
+
+	reqData := requestData{
+		relPath:     adminAPIPrefix + "/batch/describe-job",
+		queryValues: queryValues,
+	}
+
+	// Execute GET on /minio/admin/v3/batch/describe-job
+	resp, err := adm.executeMethod(ctx, http.MethodGet, reqData)
+	defer closeResponse(resp)
+	if err != nil {
+		return job, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return job, httpRespToErrorResponse(resp)
+	}
+
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return job, err
+	}
+	if err = json.Unmarshal(b, &job); err != nil {
+		return job, err
+	}
+	return job, nil
+}
+
+// CancelBatchJob - cancels a running batch job.
+func (adm *AdminClient) CancelBatchJob(ctx context.Context, id string) error {
+	queryValues := url.Values{}
+	queryValues.Set("id", id)
+
+	reqData := requestData{
+		relPath:     adminAPIPrefix + "/batch/cancel-job",
+		queryValues: queryValues,
+	}
+
+	// Execute DELETE on /minio/admin/v3/batch/cancel-job to cancel a batch job
+	resp, err := adm.executeMethod(ctx, http.MethodDelete, reqData)
+	defer closeResponse(resp)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return httpRespToErrorResponse(resp)
+	}
+	return nil
+}
@@ -23,6 +23,9 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/url"
+	"strconv"
+	"strings"
+	"time"
 )
 
 // QuotaType represents bucket quota type
@@ -44,18 +47,66 @@ func (t QuotaType) IsValid() bool {
 type BucketQuota struct {
 	Quota uint64    `json:"quota"`
 	Type  QuotaType `json:"quotatype,omitempty"`
+
+	// Objects is the maximum number of objects in the bucket,
+	// it is always a hard quota.
+	Objects uint64 `json:"objects,omitempty"`
+
+	// Prefixes holds the quotas of prefixes of the bucket.
+	Prefixes []PrefixQuota `json:"prefixes,omitempty"`
+}
+
+// PrefixQuota holds the hard quota restrictions of a prefix of a bucket,
+// the prefix is a folder of the bucket with or without the trailing slash.
+type PrefixQuota struct {
+	Prefix  string `json:"prefix"`
+	Quota   uint64 `json:"quota,omitempty"`
+	Objects uint64 `json:"objects,omitempty"`
 }
 
 // IsValid returns false if quota is invalid
 // empty quota when Quota == 0 is always true.
 func (q BucketQuota) IsValid() bool {
-	if q.Quota > 0 {
-		return q.Type.IsValid()
+	if q.Quota > 0 && !q.Type.IsValid() {
+		return false
+	}
+	prefixes := make(map[string]struct{}, len(q.Prefixes))
+	for _, pq := range q.Prefixes {
+		prefix := strings.TrimSuffix(pq.Prefix, "/")
+		if prefix == "" || strings.HasPrefix(prefix, "/") {
+			return false
+		}
+		if pq.Quota == 0 && pq.Objects == 0 {
+			return false
+		}
+		if _, ok := prefixes[prefix]; ok {
+			return false
+		}
+		prefixes[prefix] = struct{}{}
 	}
 	// Empty configs are valid.
 	return true
 }
 
+// BucketQuotaUsage holds the usage of a bucket against its quotas,
+// as last computed by the data usage scanner.
+type BucketQuotaUsage struct {
+	Bucket     string      `json:"bucket"`
+	Quota      BucketQuota `json:"quota"`
+	LastUpdate time.Time   `json:"lastUpdate"`
+	Size       uint64      `json:"size"`
+	Objects    uint64      `json:"objects"`
+
+	Prefixes []PrefixQuotaUsage `json:"prefixes,omitempty"`
+}
+
+// PrefixQuotaUsage holds the usage of a prefix with a quota.
+type PrefixQuotaUsage struct {
+	Prefix  string `json:"prefix"`
+	Size    uint64 `json:"size"`
+	Objects uint64 `json:"objects"`
+}
+
 // GetBucketQuota - get info on a user
 func (adm *AdminClient) GetBucketQuota(ctx context.Context, bucket string) (q BucketQuota, err error) {
 	queryValues := url.Values{}
@@ -120,3 +171,116 @@ func (adm *AdminClient) SetBucketQuota(ctx context.Context, bucket string, quota
 
 	return nil
 }
+
+// GetBucketQuotaUsage - returns the usage of a bucket against its quotas.
+func (adm *AdminClient) GetBucketQuotaUsage(ctx context.Context, bucket string) (u BucketQuotaUsage, err error) {
+	queryValues := url.Values{}
+	queryValues.Set("bucket", bucket)
+
+	reqData := requestData{
+		relPath:     adminAPIPrefix + "/get-bucket-quota-usage",
+		queryValues: queryValues,
+	}
+
+	// Execute GET on /minio/admin/v3/get-bucket-quota-usage
+	resp, err := adm.executeMethod(ctx, http.MethodGet, reqData)
+
+	defer closeResponse(resp)
+	if err != nil {
+		return u, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return u, httpRespToErrorResponse(resp)
+	}
+
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return u, err
+	}
+	if err = json.Unmarshal(b, &u); err != nil {
+		return u, err
+	}
+	return u, nil
+}
+
+// UserQuota holds the quota of a user or group, the maximum size of
+// the objects they may store across all the buckets.
+type UserQuota struct {
+	Quota uint64 `json:"quota"`
+}
+
+// UserQuotaUsage holds the usage of a user or group against its quota.
+type UserQuotaUsage struct {
+	Name       string    `json:"name"`
+	IsGroup    bool      `json:"isGroup"`
+	Quota      uint64    `json:"quota"`
+	LastUpdate time.Time `json:"lastUpdate"`
+	Size       uint64    `json:"size"`
+}
+
+// SetUserQuota - sets the quota of a user or group, if quota is
+// set to '0' quota is disabled.
+func (adm *AdminClient) SetUserQuota(ctx context.Context, name string, isGroup bool, quota UserQuota) error {
+	data, err := json.Marshal(quota)
+	if err != nil {
+		return err
+	}
+
+	queryValues := url.Values{}
+	queryValues.Set("name", name)
+	queryValues.Set("isGroup", strconv.FormatBool(isGroup))
+
+	reqData := requestData{
+		relPath:     adminAPIPrefix + "/set-user-quota",
+		queryValues: queryValues,
+		content:     data,
+	}
+
+	// Execute PUT on /minio/admin/v3/set-user-quota to set quota for a user or group.
+	resp, err := adm.executeMethod(ctx, http.MethodPut, reqData)
+
+	defer closeResponse(resp)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return httpRespToErrorResponse(resp)
+	}
+
+	return nil
+}
+
+// GetUserQuota - returns the quota of a user or group and its usage.
+func (adm *AdminClient) GetUserQuota(ctx context.Context, name string, isGroup bool) (u UserQuotaUsage, err error) {
+	queryValues := url.Values{}
+	queryValues.Set("name", name)
+	queryValues.Set("isGroup", strconv.FormatBool(isGroup))
+
+	reqData := requestData{
+		relPath:     adminAPIPrefix + "/get-user-quota",
+		queryValues: queryValues,
+	}
+
+	// Execute GET on /minio/admin/v3/get-user-quota
+	resp, err := adm.executeMethod(ctx, http.MethodGet, reqData)
+
+	defer closeResponse(resp)
+	if err != nil {
+		return u, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return u, httpRespToErrorResponse(resp)
+	}
+
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return u, err
+	}
+	if err = json.Unmarshal(b, &u); err != nil {
+		return u, err
+	}
+	return u, nil
+}
@@ -101,6 +101,8 @@ type BucketTarget struct {
 	BandwidthLimit      int64             `json:"bandwidthlimit,omitempty"`
 	ReplicationSync     bool              `json:"replicationSync"`
 	HealthCheckDuration time.Duration     `json:"healthCheckDuration,omitempty"`
+	DisableProxy        bool              `json:"disableProxy"`
+	ReplicateMetadata   bool              `json:"replicateMetadata"`
 }
 
 // Clone returns shallow clone of BucketTarget without secret key in credentials
@@ -120,6 +122,8 @@ func (t *BucketTarget) Clone() BucketTarget {
 		BandwidthLimit:      t.BandwidthLimit,
 		ReplicationSync:     t.ReplicationSync,
 		HealthCheckDuration: t.HealthCheckDuration,
+		DisableProxy:        t.DisableProxy,
+		ReplicateMetadata:   t.ReplicateMetadata,
 	}
 }
 
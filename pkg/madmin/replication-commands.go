@@ -0,0 +1,182 @@
+/*
+ * MinIO Cloud Storage, (C) 2021 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package madmin
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// ResyncStatusType status of a replication resync
+type ResyncStatusType string
+
+const (
+	// ResyncOngoing - resync is walking the bucket or replicating versions.
+	ResyncOngoing ResyncStatusType = "Ongoing"
+	// ResyncCompleted - all the versions of the bucket were checked
+	// and the missing ones were queued for replication.
+	ResyncCompleted ResyncStatusType = "Completed"
+	// ResyncFailed - resync stopped before checking all versions.
+	ResyncFailed ResyncStatusType = "Failed"
+)
+
+// ResyncTargetStatus - progress of the resync of a bucket to a replication target.
+type ResyncTargetStatus struct {
+	Arn        string           `json:"arn"`
+	Status     ResyncStatusType `json:"status"`
+	StartTime  time.Time        `json:"startTime"`
+	LastUpdate time.Time        `json:"lastUpdate"`
+
+	// Number of versions checked against the target.
+	Scanned int64 `json:"scanned"`
+	// Number of versions not matching any rule with existing object replication.
+	Skipped int64 `json:"skipped"`
+	// Number of versions already present on the target.
+	Present int64 `json:"present"`
+	// Number and size of versions queued for replication.
+	Queued     int64 `json:"queued"`
+	QueuedSize int64 `json:"queuedSize"`
+	// Number and size of queued versions replicated to the target.
+	Replicated     int64 `json:"replicated"`
+	ReplicatedSize int64 `json:"replicatedSize"`
+	// Number of versions which could not be checked or replicated.
+	Failed int64 `json:"failed"`
+	// Last error encountered, if any.
+	Error string `json:"error,omitempty"`
+}
+
+// ResyncStatus - progress of the resyncs of a bucket, per replication target.
+type ResyncStatus struct {
+	Bucket  string               `json:"bucket"`
+	Targets []ResyncTargetStatus `json:"targets"`
+}
+
+// StartReplicationResync - starts replicating all versions of the bucket
+// which are not yet present on the replication target with the given ARN.
+func (adm *AdminClient) StartReplicationResync(ctx context.Context, bucket, arn string) error {
+	queryValues := url.Values{}
+	queryValues.Set("bucket", bucket)
+	queryValues.Set("arn", arn)
+
+	reqData := requestData{
+		relPath:     adminAPIPrefix + "/replication/resync",
+		queryValues: queryValues,
+	}
+
+	// Execute POST on /minio/admin/v3/replication/resync to start a resync to this target
+	resp, err := adm.executeMethod(ctx, http.MethodPost, reqData)
+	defer closeResponse(resp)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return httpRespToErrorResponse(resp)
+	}
+	return nil
+}
+
+// GetReplicationResyncStatus - returns the progress of the resyncs of a bucket.
+func (adm *AdminClient) GetReplicationResyncStatus(ctx context.Context, bucket string) (rs ResyncStatus, err error) {
+	queryValues := url.Values{}
+	queryValues.Set("bucket", bucket)
+
+	reqData := requestData{
+		relPath:     adminAPIPrefix + "/replication/resync-status",
+		queryValues: queryValues,
+	}
+
+	// Execute GET on /minio/admin/v3/replication/resync-status
+	resp, err := adm.executeMethod(ctx, http.MethodGet, reqData)
+	defer closeResponse(resp)
+	if err != nil {
+		return rs, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return rs, httpRespToErrorResponse(resp)
+	}
+
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return rs, err
+	}
+	if err = json.Unmarshal(b, &rs); err != nil {
+		return rs, err
+	}
+	return rs, nil
+}
+
+// BucketMetadataType - bucket configuration replicated to the
+// replication targets of a bucket.
+type BucketMetadataType string
+
+const (
+	// BucketPolicyMetadata - bucket policy.
+	BucketPolicyMetadata BucketMetadataType = "policy"
+	// BucketTaggingMetadata - bucket tags.
+	BucketTaggingMetadata BucketMetadataType = "tagging"
+	// BucketSSEMetadata - bucket encryption configuration.
+	BucketSSEMetadata BucketMetadataType = "sse"
+	// BucketObjectLockMetadata - bucket object lock configuration.
+	BucketObjectLockMetadata BucketMetadataType = "object-lock"
+	// BucketLifecycleMetadata - bucket lifecycle configuration.
+	BucketLifecycleMetadata BucketMetadataType = "lifecycle"
+)
+
+// BucketMetadataUpdate - a change of a bucket configuration, the
+// configuration was removed if Data is empty.
+type BucketMetadataUpdate struct {
+	Type      BucketMetadataType `json:"type"`
+	Data      []byte             `json:"data,omitempty"`
+	UpdatedAt time.Time          `json:"updatedAt"`
+}
+
+// ReplicateBucketMetadata - applies a change of a bucket configuration made on
+// a replication source. The change is rejected if the configuration was updated
+// after it on this site.
+func (adm *AdminClient) ReplicateBucketMetadata(ctx context.Context, bucket string, update BucketMetadataUpdate) error {
+	data, err := json.Marshal(update)
+	if err != nil {
+		return err
+	}
+
+	queryValues := url.Values{}
+	queryValues.Set("bucket", bucket)
+
+	reqData := requestData{
+		relPath:     adminAPIPrefix + "/replication/bucket-metadata",
+		queryValues: queryValues,
+		content:     data,
+	}
+
+	// Execute PUT on /minio/admin/v3/replication/bucket-metadata to apply the change
+	resp, err := adm.executeMethod(ctx, http.MethodPut, reqData)
+	defer closeResponse(resp)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return httpRespToErrorResponse(resp)
+	}
+	return nil
+}
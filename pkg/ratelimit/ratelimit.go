@@ -0,0 +1,143 @@
+/*
+ * MinIO Cloud Storage, (C) 2021 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package ratelimit implements token bucket rate limiters, optionally
+// keyed by an arbitrary string such as an access key or a bucket name.
+package ratelimit
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// Limiter is a token bucket, it is refilled at a constant rate up to
+// its burst size.
+type Limiter struct {
+	mu     sync.Mutex
+	rate   float64 // tokens added per second.
+	burst  float64 // maximum number of tokens.
+	tokens float64 // may be negative, after Charge.
+	last   time.Time
+}
+
+// NewLimiter returns a full token bucket refilled with rate tokens per
+// second, holding at most burst tokens.
+func NewLimiter(rate, burst float64, now time.Time) *Limiter {
+	return &Limiter{
+		rate:   rate,
+		burst:  burst,
+		tokens: burst,
+		last:   now,
+	}
+}
+
+// refill must be called with the lock held.
+func (l *Limiter) refill(now time.Time) {
+	if elapsed := now.Sub(l.last); elapsed > 0 {
+		l.tokens += elapsed.Seconds() * l.rate
+		if l.tokens > l.burst {
+			l.tokens = l.burst
+		}
+		l.last = now
+	}
+}
+
+// Take consumes n tokens if they are available. Otherwise no token is
+// consumed, and the time until n tokens are available is returned.
+// Taking 0 tokens returns the time until the balance is positive again.
+func (l *Limiter) Take(now time.Time, n float64) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.refill(now)
+	if l.tokens >= n {
+		l.tokens -= n
+		return 0
+	}
+	if l.rate <= 0 {
+		return time.Duration(math.MaxInt64)
+	}
+	return time.Duration((n - l.tokens) / l.rate * float64(time.Second))
+}
+
+// Charge consumes n tokens regardless of the balance, which may become
+// negative, the tokens are then owed until the bucket is refilled.
+func (l *Limiter) Charge(now time.Time, n float64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.refill(now)
+	l.tokens -= n
+}
+
+// full returns true if the bucket has been refilled up to its burst size.
+func (l *Limiter) full(now time.Time) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.refill(now)
+	return l.tokens >= l.burst
+}
+
+// Interval at which the limiters of a KeyedLimiter refilled
+// up to their burst size are dropped.
+const sweepInterval = time.Minute
+
+// KeyedLimiter holds a token bucket per key, all with the same rate
+// and burst size.
+type KeyedLimiter struct {
+	mu        sync.Mutex
+	rate      float64
+	burst     float64
+	limiters  map[string]*Limiter
+	lastSweep time.Time
+}
+
+// NewKeyedLimiter returns a KeyedLimiter whose token buckets are
+// refilled with rate tokens per second, and hold at most burst tokens.
+func NewKeyedLimiter(rate, burst float64, now time.Time) *KeyedLimiter {
+	return &KeyedLimiter{
+		rate:      rate,
+		burst:     burst,
+		limiters:  make(map[string]*Limiter),
+		lastSweep: now,
+	}
+}
+
+// Get returns the token bucket of the key.
+func (k *KeyedLimiter) Get(key string, now time.Time) *Limiter {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if now.Sub(k.lastSweep) > sweepInterval {
+		// A full bucket is the same as a new one, drop
+		// them to not grow with every key ever seen.
+		for key, l := range k.limiters {
+			if l.full(now) {
+				delete(k.limiters, key)
+			}
+		}
+		k.lastSweep = now
+	}
+
+	l, ok := k.limiters[key]
+	if !ok {
+		l = NewLimiter(k.rate, k.burst, now)
+		k.limiters[key] = l
+	}
+	return l
+}
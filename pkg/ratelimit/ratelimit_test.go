@@ -0,0 +1,83 @@
+/*
+ * MinIO Cloud Storage, (C) 2021 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ratelimit
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLimiterTake(t *testing.T) {
+	now := time.Now()
+	l := NewLimiter(2, 2, now)
+
+	for i := 0; i < 2; i++ {
+		if wait := l.Take(now, 1); wait != 0 {
+			t.Fatalf("Take %d: expected a token to be available, wait %s", i+1, wait)
+		}
+	}
+	if wait := l.Take(now, 1); wait != 500*time.Millisecond {
+		t.Fatalf("expected to wait 500ms, got %s", wait)
+	}
+	now = now.Add(500 * time.Millisecond)
+	if wait := l.Take(now, 1); wait != 0 {
+		t.Fatalf("expected a token after the wait, wait %s", wait)
+	}
+
+	// The bucket is never refilled above its burst size.
+	now = now.Add(time.Hour)
+	l.Take(now, 2)
+	if wait := l.Take(now, 1); wait == 0 {
+		t.Fatal("expected the bucket to be empty")
+	}
+}
+
+func TestLimiterCharge(t *testing.T) {
+	now := time.Now()
+	l := NewLimiter(100, 100, now)
+
+	l.Charge(now, 300)
+	if wait := l.Take(now, 0); wait != 2*time.Second {
+		t.Fatalf("expected to wait 2s for the charged tokens, got %s", wait)
+	}
+	now = now.Add(2 * time.Second)
+	if wait := l.Take(now, 0); wait != 0 {
+		t.Fatalf("expected the charged tokens to be refilled, wait %s", wait)
+	}
+}
+
+func TestKeyedLimiter(t *testing.T) {
+	now := time.Now()
+	k := NewKeyedLimiter(1, 1, now)
+
+	if wait := k.Get("a", now).Take(now, 1); wait != 0 {
+		t.Fatalf("expected a token for key a, wait %s", wait)
+	}
+	if wait := k.Get("a", now).Take(now, 1); wait == 0 {
+		t.Fatal("expected no token left for key a")
+	}
+	if wait := k.Get("b", now).Take(now, 1); wait != 0 {
+		t.Fatalf("expected keys to be limited independently, wait %s", wait)
+	}
+
+	// Refilled buckets are dropped.
+	now = now.Add(2 * sweepInterval)
+	k.Get("c", now)
+	if len(k.limiters) != 1 {
+		t.Fatalf("expected the full buckets to be dropped, got %d buckets", len(k.limiters))
+	}
+}
@@ -0,0 +1,184 @@
+/*
+ * MinIO Cloud Storage, (C) 2021 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package arrow
+
+import "encoding/binary"
+
+// builder builds a flatbuffer back to front like the flatbuffers
+// libraries do, objects must be complete before they are referred
+// to. Only what the Arrow IPC messages need is supported.
+type builder struct {
+	// End of the buffer built so far, positions of objects are
+	// counted from the end of the buffer.
+	buf      []byte
+	minAlign int
+
+	// Positions of the fields of the table being built.
+	fields   []int
+	tableEnd int
+}
+
+func (b *builder) offset() int {
+	return len(b.buf)
+}
+
+func (b *builder) prepend(p []byte) {
+	buf := make([]byte, len(p)+len(b.buf))
+	copy(buf, p)
+	copy(buf[len(p):], b.buf)
+	b.buf = buf
+}
+
+// prep pads the buffer so that a value of size bytes is aligned
+// after additional bytes are prepended.
+func (b *builder) prep(size, additional int) {
+	if size > b.minAlign {
+		b.minAlign = size
+	}
+	if n := -(len(b.buf) + additional) & (size - 1); n > 0 {
+		b.prepend(make([]byte, n))
+	}
+}
+
+func (b *builder) prependUint8(v uint8) {
+	b.prep(1, 0)
+	b.prepend([]byte{v})
+}
+
+func (b *builder) prependUint16(v uint16) {
+	var p [2]byte
+	binary.LittleEndian.PutUint16(p[:], v)
+	b.prep(2, 0)
+	b.prepend(p[:])
+}
+
+func (b *builder) prependUint32(v uint32) {
+	var p [4]byte
+	binary.LittleEndian.PutUint32(p[:], v)
+	b.prep(4, 0)
+	b.prepend(p[:])
+}
+
+func (b *builder) prependUint64(v uint64) {
+	var p [8]byte
+	binary.LittleEndian.PutUint64(p[:], v)
+	b.prep(8, 0)
+	b.prepend(p[:])
+}
+
+// prependOffset prepends a reference to the object at off.
+func (b *builder) prependOffset(off int) {
+	b.prep(4, 0)
+	b.prependUint32(uint32(b.offset() - off + 4))
+}
+
+func (b *builder) createString(s string) int {
+	b.prep(4, len(s)+1)
+	b.prepend(append([]byte(s), 0))
+	b.prependUint32(uint32(len(s)))
+	return b.offset()
+}
+
+// createOffsetVector creates a vector of references to objects.
+func (b *builder) createOffsetVector(offs []int) int {
+	b.prep(4, 4*len(offs))
+	for i := len(offs) - 1; i >= 0; i-- {
+		b.prependOffset(offs[i])
+	}
+	b.prependUint32(uint32(len(offs)))
+	return b.offset()
+}
+
+// createPairVector creates a vector of structs of two longs, the
+// layout of both FieldNode and Buffer.
+func (b *builder) createPairVector(pairs [][2]int64) int {
+	b.prep(4, 16*len(pairs))
+	b.prep(8, 16*len(pairs))
+	for i := len(pairs) - 1; i >= 0; i-- {
+		b.prependUint64(uint64(pairs[i][1]))
+		b.prependUint64(uint64(pairs[i][0]))
+	}
+	b.prependUint32(uint32(len(pairs)))
+	return b.offset()
+}
+
+func (b *builder) startTable(numFields int) {
+	b.fields = make([]int, numFields)
+	b.tableEnd = b.offset()
+}
+
+func (b *builder) addUint8(slot int, v uint8) {
+	b.prependUint8(v)
+	b.fields[slot] = b.offset()
+}
+
+func (b *builder) addBool(slot int, v bool) {
+	var u uint8
+	if v {
+		u = 1
+	}
+	b.addUint8(slot, u)
+}
+
+func (b *builder) addInt16(slot int, v int16) {
+	b.prependUint16(uint16(v))
+	b.fields[slot] = b.offset()
+}
+
+func (b *builder) addInt32(slot int, v int32) {
+	b.prependUint32(uint32(v))
+	b.fields[slot] = b.offset()
+}
+
+func (b *builder) addInt64(slot int, v int64) {
+	b.prependUint64(uint64(v))
+	b.fields[slot] = b.offset()
+}
+
+func (b *builder) addOffset(slot int, off int) {
+	b.prependOffset(off)
+	b.fields[slot] = b.offset()
+}
+
+// endTable writes the vtable of the table being built and returns
+// the position of the table.
+func (b *builder) endTable() int {
+	// Placeholder of the reference to the vtable.
+	b.prependUint32(0)
+	table := b.offset()
+	for i := len(b.fields) - 1; i >= 0; i-- {
+		var off uint16
+		if b.fields[i] != 0 {
+			off = uint16(table - b.fields[i])
+		}
+		b.prependUint16(off)
+	}
+	b.prependUint16(uint16(table - b.tableEnd))
+	b.prependUint16(uint16(4 + 2*len(b.fields)))
+
+	// The table refers to its vtable by the signed distance to it.
+	binary.LittleEndian.PutUint32(b.buf[len(b.buf)-table:], uint32(int32(b.offset()-table)))
+	b.fields = nil
+	return table
+}
+
+// finish completes the buffer with the reference to its root table.
+func (b *builder) finish(root int) []byte {
+	b.prep(b.minAlign, 4)
+	b.prependOffset(root)
+	return b.buf
+}
@@ -0,0 +1,302 @@
+/*
+ * MinIO Cloud Storage, (C) 2021 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package arrow
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"time"
+)
+
+// ColumnType - type of the values of a column.
+type ColumnType int
+
+// Supported column types.
+const (
+	String    ColumnType = iota // string values
+	Int64                       // int64 values
+	Bool                        // bool values
+	Float64                     // float64 values
+	Timestamp                   // time.Time values, stored as microseconds since the epoch in UTC
+)
+
+// Column - column of the records written by Writer.
+type Column struct {
+	Name string
+	Type ColumnType
+}
+
+// Flatbuffer enum values of the Arrow IPC format, see Schema.fbs
+// and Message.fbs in the Arrow repository.
+const (
+	metadataVersionV5 = 4
+
+	headerSchema      = 1
+	headerRecordBatch = 3
+
+	typeInt           = 2
+	typeFloatingPoint = 3
+	typeUtf8          = 5
+	typeBool          = 6
+	typeTimestamp     = 10
+
+	precisionDouble  = 2
+	unitMicrosecond  = 2
+	continuationMark = 0xFFFFFFFF
+)
+
+// Writer - writes flat records of nullable values as an Arrow IPC
+// stream, records are written in record batches.
+type Writer struct {
+	writer    io.Writer
+	columns   []Column
+	batchSize int
+	rows      [][]interface{}
+}
+
+// NewWriter - creates new Arrow IPC stream writer of records with the
+// columns and writes the schema, batchSize records are buffered in
+// memory before they are written to w as a record batch.
+func NewWriter(w io.Writer, columns []Column, batchSize int) (*Writer, error) {
+	for _, column := range columns {
+		switch column.Type {
+		case String, Int64, Bool, Float64, Timestamp:
+		default:
+			return nil, fmt.Errorf("unsupported type of column %s", column.Name)
+		}
+	}
+
+	writer := &Writer{
+		writer:    w,
+		columns:   columns,
+		batchSize: batchSize,
+	}
+	if err := writer.writeMessage(writer.schemaMessage(), nil); err != nil {
+		return nil, err
+	}
+	return writer, nil
+}
+
+// Write - writes a record, values are in the order of the columns and nil values are null.
+func (w *Writer) Write(values ...interface{}) error {
+	if len(values) != len(w.columns) {
+		return fmt.Errorf("expected %d values, got %d", len(w.columns), len(values))
+	}
+	for i, column := range w.columns {
+		var ok bool
+		switch values[i].(type) {
+		case nil:
+			ok = true
+		case string:
+			ok = column.Type == String
+		case int64:
+			ok = column.Type == Int64
+		case bool:
+			ok = column.Type == Bool
+		case float64:
+			ok = column.Type == Float64
+		case time.Time:
+			ok = column.Type == Timestamp
+		}
+		if !ok {
+			return fmt.Errorf("unsupported value %T of column %s", values[i], column.Name)
+		}
+	}
+
+	w.rows = append(w.rows, append([]interface{}(nil), values...))
+	if len(w.rows) >= w.batchSize {
+		return w.flush()
+	}
+	return nil
+}
+
+// Close - writes the pending records and the end of the stream, the
+// underlying writer is not closed.
+func (w *Writer) Close() error {
+	if err := w.flush(); err != nil {
+		return err
+	}
+	var eos [8]byte
+	binary.LittleEndian.PutUint32(eos[:], continuationMark)
+	_, err := w.writer.Write(eos[:])
+	return err
+}
+
+// flush writes the pending records as a record batch.
+func (w *Writer) flush() error {
+	if len(w.rows) == 0 {
+		return nil
+	}
+
+	var (
+		body    []byte
+		nodes   [][2]int64
+		buffers [][2]int64
+	)
+	addBuffer := func(buf []byte) {
+		buffers = append(buffers, [2]int64{int64(len(body)), int64(len(buf))})
+		body = append(body, buf...)
+		// Buffers start at multiples of 8 bytes.
+		body = append(body, make([]byte, -len(body)&7)...)
+	}
+
+	n := len(w.rows)
+	for i, column := range w.columns {
+		validity := make([]byte, (n+7)/8)
+		nulls := 0
+		for j, row := range w.rows {
+			if row[i] == nil {
+				nulls++
+			} else {
+				validity[j/8] |= 1 << uint(j%8)
+			}
+		}
+		nodes = append(nodes, [2]int64{int64(n), int64(nulls)})
+		addBuffer(validity)
+
+		switch column.Type {
+		case String:
+			offsets := make([]byte, 4*(n+1))
+			var data []byte
+			for j, row := range w.rows {
+				if s, ok := row[i].(string); ok {
+					data = append(data, s...)
+				}
+				binary.LittleEndian.PutUint32(offsets[4*(j+1):], uint32(len(data)))
+			}
+			if len(data) > math.MaxInt32 {
+				return fmt.Errorf("values of column %s exceed the maximum size of a record batch", column.Name)
+			}
+			addBuffer(offsets)
+			addBuffer(data)
+		case Bool:
+			values := make([]byte, (n+7)/8)
+			for j, row := range w.rows {
+				if v, ok := row[i].(bool); ok && v {
+					values[j/8] |= 1 << uint(j%8)
+				}
+			}
+			addBuffer(values)
+		default:
+			values := make([]byte, 8*n)
+			for j, row := range w.rows {
+				var u uint64
+				switch v := row[i].(type) {
+				case int64:
+					u = uint64(v)
+				case float64:
+					u = math.Float64bits(v)
+				case time.Time:
+					u = uint64(v.UnixNano() / int64(time.Microsecond))
+				}
+				binary.LittleEndian.PutUint64(values[8*j:], u)
+			}
+			addBuffer(values)
+		}
+	}
+
+	b := &builder{}
+	nodesVec := b.createPairVector(nodes)
+	buffersVec := b.createPairVector(buffers)
+	b.startTable(3)
+	b.addInt64(0, int64(n))
+	b.addOffset(1, nodesVec)
+	b.addOffset(2, buffersVec)
+	recordBatch := b.endTable()
+
+	w.rows = w.rows[:0]
+	return w.writeMessage(finishMessage(b, headerRecordBatch, recordBatch, int64(len(body))), body)
+}
+
+// schemaMessage returns the metadata of the schema message.
+func (w *Writer) schemaMessage() []byte {
+	b := &builder{}
+	fields := make([]int, len(w.columns))
+	for i, column := range w.columns {
+		name := b.createString(column.Name)
+		typeType, typ := buildType(b, column.Type)
+		children := b.createOffsetVector(nil)
+		b.startTable(7)
+		b.addOffset(0, name)
+		b.addBool(1, true)
+		b.addUint8(2, typeType)
+		b.addOffset(3, typ)
+		b.addOffset(5, children)
+		fields[i] = b.endTable()
+	}
+	fieldsVec := b.createOffsetVector(fields)
+	b.startTable(4)
+	// Little endian.
+	b.addInt16(0, 0)
+	b.addOffset(1, fieldsVec)
+	schema := b.endTable()
+	return finishMessage(b, headerSchema, schema, 0)
+}
+
+// buildType builds the type table of a column and returns its type
+// in the Type union.
+func buildType(b *builder, columnType ColumnType) (uint8, int) {
+	switch columnType {
+	case Int64:
+		b.startTable(2)
+		b.addInt32(0, 64)
+		b.addBool(1, true)
+		return typeInt, b.endTable()
+	case Bool:
+		b.startTable(0)
+		return typeBool, b.endTable()
+	case Float64:
+		b.startTable(1)
+		b.addInt16(0, precisionDouble)
+		return typeFloatingPoint, b.endTable()
+	case Timestamp:
+		timezone := b.createString("UTC")
+		b.startTable(2)
+		b.addInt16(0, unitMicrosecond)
+		b.addOffset(1, timezone)
+		return typeTimestamp, b.endTable()
+	}
+	b.startTable(0)
+	return typeUtf8, b.endTable()
+}
+
+// finishMessage completes the metadata of a message with the header.
+func finishMessage(b *builder, headerType uint8, header int, bodyLength int64) []byte {
+	b.startTable(5)
+	b.addInt64(3, bodyLength)
+	b.addOffset(2, header)
+	b.addInt16(0, metadataVersionV5)
+	b.addUint8(1, headerType)
+	return b.finish(b.endTable())
+}
+
+// writeMessage writes an encapsulated message, the metadata is padded
+// to a multiple of 8 bytes.
+func (w *Writer) writeMessage(metadata, body []byte) error {
+	padding := -len(metadata) & 7
+	prefix := make([]byte, 8)
+	binary.LittleEndian.PutUint32(prefix, continuationMark)
+	binary.LittleEndian.PutUint32(prefix[4:], uint32(len(metadata)+padding))
+
+	msg := append(prefix, metadata...)
+	msg = append(msg, make([]byte, padding)...)
+	msg = append(msg, body...)
+	_, err := w.writer.Write(msg)
+	return err
+}
@@ -0,0 +1,281 @@
+/*
+ * MinIO Cloud Storage, (C) 2021 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package arrow
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// table reads the fields of a flatbuffer table.
+type table struct {
+	buf []byte
+	pos int
+}
+
+func rootTable(buf []byte) table {
+	return table{buf, int(binary.LittleEndian.Uint32(buf))}
+}
+
+// field returns the position of the field in slot, 0 if it is absent.
+func (t table) field(slot int) int {
+	vtable := t.pos - int(int32(binary.LittleEndian.Uint32(t.buf[t.pos:])))
+	if 4+2*slot >= int(binary.LittleEndian.Uint16(t.buf[vtable:])) {
+		return 0
+	}
+	if off := int(binary.LittleEndian.Uint16(t.buf[vtable+4+2*slot:])); off != 0 {
+		return t.pos + off
+	}
+	return 0
+}
+
+func (t table) uint8(slot int) uint8 {
+	if p := t.field(slot); p != 0 {
+		return t.buf[p]
+	}
+	return 0
+}
+
+func (t table) int16(slot int) int16 {
+	if p := t.field(slot); p != 0 {
+		return int16(binary.LittleEndian.Uint16(t.buf[p:]))
+	}
+	return 0
+}
+
+func (t table) int32(slot int) int32 {
+	if p := t.field(slot); p != 0 {
+		return int32(binary.LittleEndian.Uint32(t.buf[p:]))
+	}
+	return 0
+}
+
+func (t table) int64(slot int) int64 {
+	if p := t.field(slot); p != 0 {
+		return int64(binary.LittleEndian.Uint64(t.buf[p:]))
+	}
+	return 0
+}
+
+func (t table) deref(slot int) int {
+	p := t.field(slot)
+	return p + int(binary.LittleEndian.Uint32(t.buf[p:]))
+}
+
+func (t table) table(slot int) table {
+	return table{t.buf, t.deref(slot)}
+}
+
+func (t table) string(slot int) string {
+	p := t.deref(slot)
+	n := int(binary.LittleEndian.Uint32(t.buf[p:]))
+	return string(t.buf[p+4 : p+4+n])
+}
+
+func (t table) tables(slot int) []table {
+	p := t.deref(slot)
+	n := int(binary.LittleEndian.Uint32(t.buf[p:]))
+	tables := make([]table, n)
+	for i := range tables {
+		e := p + 4 + 4*i
+		tables[i] = table{t.buf, e + int(binary.LittleEndian.Uint32(t.buf[e:]))}
+	}
+	return tables
+}
+
+func (t table) pairs(slot int) [][2]int64 {
+	p := t.deref(slot)
+	n := int(binary.LittleEndian.Uint32(t.buf[p:]))
+	pairs := make([][2]int64, n)
+	for i := range pairs {
+		e := p + 4 + 16*i
+		pairs[i] = [2]int64{int64(binary.LittleEndian.Uint64(t.buf[e:])), int64(binary.LittleEndian.Uint64(t.buf[e+8:]))}
+	}
+	return pairs
+}
+
+// readStream decodes the schema and the records of an Arrow IPC stream.
+func readStream(t *testing.T, r io.Reader) (columns []Column, records [][]interface{}) {
+	t.Helper()
+
+	for {
+		var prefix [8]byte
+		if _, err := io.ReadFull(r, prefix[:]); err != nil {
+			t.Fatal(err)
+		}
+		if binary.LittleEndian.Uint32(prefix[:]) != continuationMark {
+			t.Fatalf("unexpected continuation marker %x", prefix[:4])
+		}
+		size := int(binary.LittleEndian.Uint32(prefix[4:]))
+		if size == 0 {
+			return columns, records
+		}
+		if size%8 != 0 {
+			t.Fatalf("metadata of %d bytes is not padded", size)
+		}
+		metadata := make([]byte, size)
+		if _, err := io.ReadFull(r, metadata); err != nil {
+			t.Fatal(err)
+		}
+		msg := rootTable(metadata)
+		if v := msg.int16(0); v != metadataVersionV5 {
+			t.Fatalf("unexpected metadata version %d", v)
+		}
+		body := make([]byte, msg.int64(3))
+		if _, err := io.ReadFull(r, body); err != nil {
+			t.Fatal(err)
+		}
+
+		switch msg.uint8(1) {
+		case headerSchema:
+			for _, field := range msg.table(2).tables(1) {
+				if field.uint8(1) != 1 {
+					t.Fatal("fields must be nullable")
+				}
+				column := Column{Name: field.string(0)}
+				typ := field.table(3)
+				switch field.uint8(2) {
+				case typeUtf8:
+					column.Type = String
+				case typeInt:
+					if typ.int32(0) != 64 || typ.uint8(1) != 1 {
+						t.Fatal("unexpected Int type")
+					}
+					column.Type = Int64
+				case typeBool:
+					column.Type = Bool
+				case typeFloatingPoint:
+					if typ.int16(0) != precisionDouble {
+						t.Fatal("unexpected FloatingPoint type")
+					}
+					column.Type = Float64
+				case typeTimestamp:
+					if typ.int16(0) != unitMicrosecond || typ.string(1) != "UTC" {
+						t.Fatal("unexpected Timestamp type")
+					}
+					column.Type = Timestamp
+				default:
+					t.Fatalf("unexpected type %d", field.uint8(2))
+				}
+				columns = append(columns, column)
+			}
+
+		case headerRecordBatch:
+			batch := msg.table(2)
+			n := int(batch.int64(0))
+			nodes, buffers := batch.pairs(1), batch.pairs(2)
+			if len(nodes) != len(columns) {
+				t.Fatalf("expected %d field nodes, got %d", len(columns), len(nodes))
+			}
+			next := func() []byte {
+				b := buffers[0]
+				buffers = buffers[1:]
+				if b[0]%8 != 0 {
+					t.Fatalf("buffer at %d is not aligned", b[0])
+				}
+				return body[b[0] : b[0]+b[1]]
+			}
+			rows := make([][]interface{}, n)
+			for i := range rows {
+				rows[i] = make([]interface{}, len(columns))
+			}
+			for i, column := range columns {
+				validity := next()
+				var offsets, values []byte
+				if column.Type == String {
+					offsets = next()
+				}
+				values = next()
+				for j := range rows {
+					if validity[j/8]&(1<<uint(j%8)) == 0 {
+						continue
+					}
+					switch column.Type {
+					case String:
+						start := binary.LittleEndian.Uint32(offsets[4*j:])
+						end := binary.LittleEndian.Uint32(offsets[4*j+4:])
+						rows[j][i] = string(values[start:end])
+					case Int64:
+						rows[j][i] = int64(binary.LittleEndian.Uint64(values[8*j:]))
+					case Bool:
+						rows[j][i] = values[j/8]&(1<<uint(j%8)) != 0
+					case Float64:
+						rows[j][i] = math.Float64frombits(binary.LittleEndian.Uint64(values[8*j:]))
+					case Timestamp:
+						micros := int64(binary.LittleEndian.Uint64(values[8*j:]))
+						rows[j][i] = time.Unix(0, micros*int64(time.Microsecond)).UTC()
+					}
+				}
+			}
+			records = append(records, rows...)
+
+		default:
+			t.Fatalf("unexpected message type %d", msg.uint8(1))
+		}
+	}
+}
+
+func TestWriter(t *testing.T) {
+	var buf bytes.Buffer
+	columns := []Column{
+		{Name: "key", Type: String},
+		{Name: "size", Type: Int64},
+		{Name: "latest", Type: Bool},
+		{Name: "ratio", Type: Float64},
+		{Name: "created", Type: Timestamp},
+	}
+	w, err := NewWriter(&buf, columns, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	created := time.Date(2021, 1, 1, 0, 0, 1, 2000, time.UTC)
+	records := [][]interface{}{
+		{"a", int64(1), true, 0.5, created},
+		{"", nil, false, nil, nil},
+		{"ccc", int64(-3), nil, -1.25, created},
+	}
+	for _, record := range records {
+		if err = w.Write(record...); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err = w.Write("d"); err == nil {
+		t.Fatal("expected an error for a record with missing values")
+	}
+	if err = w.Write("d", "1", nil, nil, nil); err == nil {
+		t.Fatal("expected an error for a value of the wrong type")
+	}
+	if err = w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	gotColumns, gotRecords := readStream(t, &buf)
+	if !reflect.DeepEqual(gotColumns, columns) {
+		t.Fatalf("expected columns %v, got %v", columns, gotColumns)
+	}
+	if !reflect.DeepEqual(gotRecords, records) {
+		t.Fatalf("expected records %v, got %v", records, gotRecords)
+	}
+	if buf.Len() != 0 {
+		t.Fatalf("%d bytes after the end of the stream", buf.Len())
+	}
+}
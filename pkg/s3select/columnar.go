@@ -0,0 +1,443 @@
+/*
+ * MinIO Cloud Storage, (C) 2021 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package s3select
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bcicen/jstream"
+	"github.com/minio/minio/pkg/s3select/arrow"
+	"github.com/minio/minio/pkg/s3select/parquet"
+	"github.com/minio/minio/pkg/s3select/sql"
+)
+
+// Number of records in a Parquet row group or an Arrow record batch.
+const columnarBatchSize = 10000
+
+var errColumnRecordUnsupported = errors.New("operation is not supported on columnar output records")
+
+// columnRecord - output record of the columnar formats, it keeps the
+// values of the projected columns in order. Values are held as
+// string, int64, float64, bool or time.Time, untyped values of the
+// input are strings and arrays are JSON encoded.
+type columnRecord struct {
+	names  []string
+	values []interface{}
+}
+
+func (r *columnRecord) Get(name string) (*sql.Value, error) {
+	for i := range r.names {
+		if r.names[i] != name {
+			continue
+		}
+		switch v := r.values[i].(type) {
+		case string:
+			return sql.FromString(v), nil
+		case int64:
+			return sql.FromInt(v), nil
+		case float64:
+			return sql.FromFloat(v), nil
+		case bool:
+			return sql.FromBool(v), nil
+		case time.Time:
+			return sql.FromTimestamp(v), nil
+		}
+		return sql.FromNull(), nil
+	}
+	return nil, fmt.Errorf("column %v not found", name)
+}
+
+func (r *columnRecord) Set(name string, value *sql.Value) (sql.Record, error) {
+	var v interface{}
+	if i, ok := value.ToInt(); ok {
+		v = i
+	} else if f, ok := value.ToFloat(); ok {
+		v = f
+	} else if b, ok := value.ToBool(); ok {
+		v = b
+	} else if t, ok := value.ToTimestamp(); ok {
+		v = t
+	} else if s, ok := value.ToString(); ok {
+		v = s
+	} else if b, ok := value.ToBytes(); ok {
+		v = string(b)
+	} else if value.IsArray() {
+		b, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		v = string(b)
+	}
+	r.names = append(r.names, name)
+	r.values = append(r.values, v)
+	return r, nil
+}
+
+func (r *columnRecord) WriteCSV(writer io.Writer, opts sql.WriteCSVOpts) error {
+	return errColumnRecordUnsupported
+}
+
+func (r *columnRecord) WriteJSON(writer io.Writer) error {
+	kvs := make(jstream.KVS, len(r.names))
+	for i := range r.names {
+		kvs[i] = jstream.KV{Key: r.names[i], Value: r.values[i]}
+	}
+	return json.NewEncoder(writer).Encode(kvs)
+}
+
+func (r *columnRecord) Clone(dst sql.Record) sql.Record {
+	other, ok := dst.(*columnRecord)
+	if !ok {
+		other = &columnRecord{}
+	}
+	other.names = append(other.names[:0], r.names...)
+	other.values = append(other.values[:0], r.values...)
+	return other
+}
+
+func (r *columnRecord) Reset() {
+	r.names = r.names[:0]
+	r.values = r.values[:0]
+}
+
+func (r *columnRecord) Raw() (sql.SelectObjectFormat, interface{}) {
+	return sql.SelectFmtUnknown, r
+}
+
+func (r *columnRecord) Replace(k interface{}) error {
+	return errColumnRecordUnsupported
+}
+
+// columnValues returns the columns of an output record. Records of
+// SELECT * queries are of the input format and are read from their
+// JSON encoding.
+func columnValues(record sql.Record) (names []string, values []interface{}, err error) {
+	if r, ok := record.(*columnRecord); ok {
+		return r.names, r.values, nil
+	}
+
+	var buf bytes.Buffer
+	if err = record.WriteJSON(&buf); err != nil {
+		return nil, nil, err
+	}
+	d := json.NewDecoder(&buf)
+	if t, err := d.Token(); err != nil || t != json.Delim('{') {
+		return nil, nil, fmt.Errorf("record is not a JSON object")
+	}
+	for d.More() {
+		t, err := d.Token()
+		if err != nil {
+			return nil, nil, err
+		}
+		var raw json.RawMessage
+		if err = d.Decode(&raw); err != nil {
+			return nil, nil, err
+		}
+		var v interface{}
+		switch raw[0] {
+		case '{', '[':
+			v = string(raw)
+		case '"', 't', 'f', 'n':
+			if err = json.Unmarshal(raw, &v); err != nil {
+				return nil, nil, err
+			}
+		default:
+			n := json.Number(raw)
+			if v, err = n.Int64(); err != nil {
+				if v, err = n.Float64(); err != nil {
+					return nil, nil, err
+				}
+			}
+		}
+		names = append(names, t.(string))
+		values = append(values, v)
+	}
+	return names, values, nil
+}
+
+// columnarWriter - encodes output records as Parquet or as an Arrow
+// IPC stream. The columns and their types follow from the projection
+// of the statement, the types of columns whose expression does not
+// determine one are inferred from the first batch of records. The
+// encoded output is appended to the payload passed to write and
+// finish.
+type columnarWriter struct {
+	format string
+	names  []string
+	known  []sql.ValueType
+	types  []arrow.ColumnType
+	index  map[string]int
+	writer interface {
+		Write(values ...interface{}) error
+		Close() error
+	}
+	payload *bytes.Buffer
+}
+
+// newColumnarWriter returns a writer of the output columns of a
+// statement, names and types are nil for SELECT * whose columns are
+// those of the first record.
+func newColumnarWriter(format string, names []string, types []sql.ValueType) (*columnarWriter, error) {
+	c := &columnarWriter{format: format, known: types}
+	if names != nil {
+		if err := c.setColumns(names); err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// Write appends encoded output to the current payload.
+func (c *columnarWriter) Write(p []byte) (int, error) {
+	return c.payload.Write(p)
+}
+
+// Close is called by the Parquet writer once the footer is written.
+func (c *columnarWriter) Close() error {
+	return nil
+}
+
+// setColumns sets the column names of the output.
+func (c *columnarWriter) setColumns(names []string) error {
+	c.names = append([]string(nil), names...)
+	c.index = make(map[string]int, len(names))
+	for i, name := range names {
+		if _, ok := c.index[name]; ok {
+			return fmt.Errorf("duplicate column %s", name)
+		}
+		c.index[name] = i
+	}
+	return nil
+}
+
+// inferTypes sets the column types. Columns of a known type get the
+// column type of it, the others are float columns if the values of the
+// first records are numbers and string columns otherwise, so that
+// later records of other types still fit.
+func (c *columnarWriter) inferTypes(rows [][]interface{}) {
+	c.types = make([]arrow.ColumnType, len(c.names))
+	for i := range c.names {
+		var known sql.ValueType
+		if i < len(c.known) {
+			known = c.known[i]
+		}
+		switch known {
+		case sql.TypeInt:
+			c.types[i] = arrow.Int64
+			continue
+		case sql.TypeNumber:
+			c.types[i] = arrow.Float64
+			continue
+		case sql.TypeBool:
+			c.types[i] = arrow.Bool
+			continue
+		case sql.TypeTimestamp:
+			c.types[i] = arrow.Timestamp
+			continue
+		case sql.TypeString:
+			c.types[i] = arrow.String
+			continue
+		}
+
+		numeric, other := false, false
+		for _, row := range rows {
+			switch row[i].(type) {
+			case nil:
+			case int64, float64:
+				numeric = true
+			default:
+				other = true
+			}
+		}
+		if numeric && !other {
+			c.types[i] = arrow.Float64
+		} else {
+			c.types[i] = arrow.String
+		}
+	}
+}
+
+// open creates the writer of the format, which writes the header.
+func (c *columnarWriter) open() (err error) {
+	switch c.format {
+	case parquetFormat:
+		columns := make([]parquet.Column, len(c.names))
+		for i := range c.names {
+			columns[i] = parquet.Column{Name: c.names[i]}
+			switch c.types[i] {
+			case arrow.Int64:
+				columns[i].Type = parquet.Int64
+			case arrow.Float64:
+				columns[i].Type = parquet.Float64
+			case arrow.Bool:
+				columns[i].Type = parquet.Bool
+			case arrow.Timestamp:
+				columns[i].Type = parquet.Timestamp
+			default:
+				columns[i].Type = parquet.String
+			}
+		}
+		c.writer, err = parquet.NewWriter(c, columns, columnarBatchSize)
+	default:
+		columns := make([]arrow.Column, len(c.names))
+		for i := range c.names {
+			columns[i] = arrow.Column{Name: c.names[i], Type: c.types[i]}
+		}
+		c.writer, err = arrow.NewWriter(c, columns, columnarBatchSize)
+	}
+	return err
+}
+
+// convert converts a value to the type of column i, values that do
+// not convert are null.
+func (c *columnarWriter) convert(i int, v interface{}) interface{} {
+	switch c.types[i] {
+	case arrow.Int64:
+		switch x := v.(type) {
+		case int64:
+			return x
+		case float64:
+			if n, frac := math.Modf(x); frac == 0 && n >= math.MinInt64 && n < math.MaxInt64 {
+				return int64(n)
+			}
+		case string:
+			if n, err := strconv.ParseInt(strings.TrimSpace(x), 10, 64); err == nil {
+				return n
+			}
+		}
+	case arrow.Float64:
+		switch x := v.(type) {
+		case int64:
+			return float64(x)
+		case float64:
+			return x
+		case string:
+			if f, err := strconv.ParseFloat(strings.TrimSpace(x), 64); err == nil {
+				return f
+			}
+		}
+	case arrow.Bool:
+		switch x := v.(type) {
+		case bool:
+			return x
+		case string:
+			if b, err := strconv.ParseBool(strings.TrimSpace(x)); err == nil {
+				return b
+			}
+		}
+	case arrow.Timestamp:
+		switch x := v.(type) {
+		case time.Time:
+			return x
+		case string:
+			if t, err := time.Parse(time.RFC3339Nano, strings.TrimSpace(x)); err == nil {
+				return t
+			}
+		}
+	default:
+		switch x := v.(type) {
+		case string:
+			return x
+		case int64:
+			return strconv.FormatInt(x, 10)
+		case float64:
+			return strconv.FormatFloat(x, 'g', -1, 64)
+		case bool:
+			return strconv.FormatBool(x)
+		case time.Time:
+			return x.Format(time.RFC3339Nano)
+		}
+	}
+	return nil
+}
+
+// write encodes the records, the encoded output is appended to payload.
+func (c *columnarWriter) write(records []sql.Record, payload *bytes.Buffer) error {
+	c.payload = payload
+	defer func() {
+		c.payload = nil
+	}()
+
+	rows := make([][]interface{}, 0, len(records))
+	for _, record := range records {
+		if record == nil {
+			continue
+		}
+		names, values, err := columnValues(record)
+		if err != nil {
+			return err
+		}
+		if c.index == nil {
+			if err = c.setColumns(names); err != nil {
+				return err
+			}
+		}
+		// Match the columns by name, the columns of SELECT * are
+		// those of the first record and other fields are dropped.
+		row := make([]interface{}, len(c.names))
+		for i, name := range names {
+			if j, ok := c.index[name]; ok {
+				row[j] = values[i]
+			}
+		}
+		rows = append(rows, row)
+	}
+
+	if c.writer == nil {
+		if len(rows) == 0 {
+			return nil
+		}
+		c.inferTypes(rows)
+		if err := c.open(); err != nil {
+			return err
+		}
+	}
+	for _, row := range rows {
+		for i := range row {
+			row[i] = c.convert(i, row[i])
+		}
+		if err := c.writer.Write(row...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// finish encodes the pending records and the trailer of the output,
+// an output of SELECT * without records has no columns.
+func (c *columnarWriter) finish(payload *bytes.Buffer) error {
+	c.payload = payload
+	defer func() {
+		c.payload = nil
+	}()
+
+	if c.writer == nil {
+		c.inferTypes(nil)
+		if err := c.open(); err != nil {
+			return err
+		}
+	}
+	return c.writer.Close()
+}
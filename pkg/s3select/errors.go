@@ -65,7 +65,7 @@ func errMalformedXML(err error) *s3Error {
 func errInvalidCompressionFormat(err error) *s3Error {
 	return &s3Error{
 		code:       "InvalidCompressionFormat",
-		message:    "The file is not in a supported compression format. Only GZIP and BZIP2 are supported.",
+		message:    "The file is not in a supported compression format. Only GZIP, BZIP2, ZSTD, SNAPPY and S2 are supported.",
 		statusCode: 400,
 		cause:      err,
 	}
@@ -89,6 +89,33 @@ func errInvalidGZIPCompressionFormat(err error) *s3Error {
 	}
 }
 
+func errInvalidZSTDCompressionFormat(err error) *s3Error {
+	return &s3Error{
+		code:       "InvalidCompressionFormat",
+		message:    "ZSTD is not applicable to the queried object. Please correct the request and try again.",
+		statusCode: 400,
+		cause:      err,
+	}
+}
+
+func errInvalidSnappyCompressionFormat(err error) *s3Error {
+	return &s3Error{
+		code:       "InvalidCompressionFormat",
+		message:    "SNAPPY is not applicable to the queried object. Please correct the request and try again.",
+		statusCode: 400,
+		cause:      err,
+	}
+}
+
+func errInvalidS2CompressionFormat(err error) *s3Error {
+	return &s3Error{
+		code:       "InvalidCompressionFormat",
+		message:    "S2 is not applicable to the queried object. Please correct the request and try again.",
+		statusCode: 400,
+		cause:      err,
+	}
+}
+
 func errInvalidDataSource(err error) *s3Error {
 	return &s3Error{
 		code:       "InvalidDataSource",
@@ -110,7 +137,7 @@ func errInvalidRequestParameter(err error) *s3Error {
 func errObjectSerializationConflict(err error) *s3Error {
 	return &s3Error{
 		code:       "ObjectSerializationConflict",
-		message:    "InputSerialization specifies more than one format (CSV, JSON, or Parquet), or OutputSerialization specifies more than one format (CSV or JSON). InputSerialization and OutputSerialization can only specify one format each.",
+		message:    "InputSerialization specifies more than one format (CSV, JSON, or Parquet), or OutputSerialization specifies more than one format (CSV, JSON, Parquet, or Arrow). InputSerialization and OutputSerialization can only specify one format each.",
 		statusCode: 400,
 		cause:      err,
 	}
@@ -139,6 +139,42 @@ func genStatsHeader() {
 	fmt.Println(buf.Bytes())
 }
 
+// ObjectStats Message
+// ===================
+// Header specification
+// --------------------
+// ObjectStats messages contain three headers, as follows:
+// :message-type "event", :content-type "text/xml" and :event-type "ObjectStats".
+//
+// Payload specification
+// ---------------------
+// ObjectStats messages are a MinIO extension, the payload is an XML
+// document with the key and the stats of one object of a select over
+// many objects.
+func genObjectStatsHeader() {
+	buf := new(bytes.Buffer)
+
+	buf.WriteByte(13)
+	buf.WriteString(":message-type")
+	buf.WriteByte(7)
+	buf.Write([]byte{0, 5})
+	buf.WriteString("event")
+
+	buf.WriteByte(13)
+	buf.WriteString(":content-type")
+	buf.WriteByte(7)
+	buf.Write([]byte{0, 8})
+	buf.WriteString("text/xml")
+
+	buf.WriteByte(11)
+	buf.WriteString(":event-type")
+	buf.WriteByte(7)
+	buf.Write([]byte{0, 11})
+	buf.WriteString("ObjectStats")
+
+	fmt.Println(buf.Bytes())
+}
+
 // End Message
 // ===========
 // Header specification
@@ -31,13 +31,18 @@ type Reader struct {
 	args       *ReaderArgs
 	decoder    *jstream.Decoder
 	valueCh    chan *jstream.MetaValue
-	readCloser io.ReadCloser
+	readCloser *syncReadCloser
 }
 
 // Read - reads single record.
 func (r *Reader) Read(dst sql.Record) (sql.Record, error) {
 	v, ok := <-r.valueCh
 	if !ok {
+		// Errors reading the input, like decompression errors, end
+		// the stream early and take precedence over parsing errors.
+		if err := r.readCloser.readErr(); err != nil {
+			return nil, errJSONParsingError(err)
+		}
 		if err := r.decoder.Err(); err != nil {
 			return nil, errJSONParsingError(err)
 		}
@@ -79,13 +84,13 @@ func (r *Reader) Close() error {
 
 // NewReader - creates new JSON reader using readCloser.
 func NewReader(readCloser io.ReadCloser, args *ReaderArgs) *Reader {
-	readCloser = &syncReadCloser{rc: readCloser}
-	d := jstream.NewDecoder(readCloser, 0).ObjectAsKVS()
+	src := &syncReadCloser{rc: readCloser}
+	d := jstream.NewDecoder(src, 0).ObjectAsKVS()
 	return &Reader{
 		args:       args,
 		decoder:    d,
 		valueCh:    d.Stream(),
-		readCloser: readCloser,
+		readCloser: src,
 	}
 }
 
@@ -119,6 +124,17 @@ func (pr *syncReadCloser) Read(p []byte) (n int, err error) {
 
 var errClosed = errors.New("read after close")
 
+// readErr returns the error that ended reading the input, if it
+// was not the end of the input.
+func (pr *syncReadCloser) readErr() error {
+	pr.errMu.Lock()
+	defer pr.errMu.Unlock()
+	if pr.err == io.EOF || pr.err == errClosed {
+		return nil
+	}
+	return pr.err
+}
+
 func (pr *syncReadCloser) Close() error {
 	pr.errMu.Lock()
 	defer pr.errMu.Unlock()
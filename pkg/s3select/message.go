@@ -19,8 +19,10 @@ package s3select
 import (
 	"bytes"
 	"encoding/binary"
+	"encoding/xml"
 	"fmt"
 	"hash/crc32"
+	"io"
 	"net/http"
 	"strconv"
 	"sync/atomic"
@@ -190,6 +192,34 @@ func newStatsMessage(bytesScanned, bytesProcessed, bytesReturned int64) []byte {
 	return genMessage(statsHeader, payload)
 }
 
+// Refer genObjectStatsHeader().
+var objectStatsHeader = []byte{
+	13, ':', 'm', 'e', 's', 's', 'a', 'g', 'e', '-', 't', 'y', 'p', 'e', 7, 0, 5, 'e', 'v', 'e', 'n', 't',
+	13, ':', 'c', 'o', 'n', 't', 'e', 'n', 't', '-', 't', 'y', 'p', 'e', 7, 0, 8, 't', 'e', 'x', 't', '/', 'x', 'm', 'l',
+	11, ':', 'e', 'v', 'e', 'n', 't', '-', 't', 'y', 'p', 'e', 7, 0, 11, 'O', 'b', 'j', 'e', 'c', 't', 'S', 't', 'a', 't', 's',
+}
+
+// newObjectStatsMessage - creates new ObjectStats Message. This message
+// is a MinIO extension sent by a select over many objects once all
+// records of an object are read, after the records of the object.
+//
+// Example:
+//
+// <?xml version="1.0" encoding="UTF-8"?>
+// <ObjectStats>
+//      <Key>data/2021/01.csv</Key>
+//      <BytesScanned>512</BytesScanned>
+//      <BytesProcessed>1024</BytesProcessed>
+// </ObjectStats>
+func newObjectStatsMessage(stats ObjectStats) []byte {
+	payload, err := xml.Marshal(stats)
+	if err != nil {
+		// Cannot fail for ObjectStats.
+		panic(err)
+	}
+	return genMessage(objectStatsHeader, append([]byte(`<?xml version="1.0" encoding="UTF-8"?>`), payload...))
+}
+
 // endMessage - indicates that the request is complete, and no more messages will be sent.
 // You should not assume that the request is complete until the client receives an End message.
 //
@@ -251,8 +281,9 @@ type messageWriter struct {
 
 	finBytesScanned, finBytesProcessed int64
 
-	errCh  chan []byte
-	doneCh chan struct{}
+	eventCh chan []byte
+	errCh   chan []byte
+	doneCh  chan struct{}
 }
 
 func (writer *messageWriter) write(data []byte) bool {
@@ -297,6 +328,12 @@ func (writer *messageWriter) start() {
 			}
 			writer.write(data)
 
+		case data := <-writer.eventCh:
+			// Flush collected records before sending the event
+			if !writer.flushRecords() || !writer.write(data) {
+				quitFlag = true
+			}
+
 		case payload, ok := <-writer.payloadCh:
 			if !ok {
 				// payloadCh is closed by caller to
@@ -374,6 +411,14 @@ func (writer *messageWriter) SendRecord(payload *bytes.Buffer) error {
 	}
 }
 
+// Sends the stats of an object of a select over many objects.
+func (writer *messageWriter) sendObjectStats(stats ObjectStats) {
+	select {
+	case writer.eventCh <- newObjectStatsMessage(stats):
+	case <-writer.doneCh:
+	}
+}
+
 func (writer *messageWriter) flushRecords() bool {
 	if writer.payloadBufferIndex == 0 {
 		return true
@@ -424,9 +469,47 @@ func newMessageWriter(w http.ResponseWriter, getProgressFunc func() (bytesScanne
 		payloadBuffer: make([]byte, bufLength),
 		payloadCh:     make(chan *bytes.Buffer, 1),
 
-		errCh:  make(chan []byte),
-		doneCh: make(chan struct{}),
+		eventCh: make(chan []byte),
+		errCh:   make(chan []byte),
+		doneCh:  make(chan struct{}),
 	}
 	go writer.start()
 	return writer
 }
+
+// rawWriter writes record payloads as they are, without any message
+// framing, progress or stats.
+type rawWriter struct {
+	writer io.Writer
+	err    error
+}
+
+// SendRecord writes a single whole record.
+func (writer *rawWriter) SendRecord(payload *bytes.Buffer) error {
+	if writer.err != nil {
+		return writer.err
+	}
+	if _, writer.err = payload.WriteTo(writer.writer); writer.err != nil {
+		return writer.err
+	}
+	bufPool.Put(payload)
+	return nil
+}
+
+// Finish returns the first error seen while writing records, if any.
+func (writer *rawWriter) Finish(bytesScanned, bytesProcessed int64) error {
+	return writer.err
+}
+
+// FinishWithError records the error that terminated the evaluation.
+func (writer *rawWriter) FinishWithError(errorCode, errorMessage string) error {
+	if writer.err == nil {
+		writer.err = fmt.Errorf("%s: %s", errorCode, errorMessage)
+	}
+	return nil
+}
+
+// newRawWriter creates a writer that writes the serialized records to w.
+func newRawWriter(w io.Writer) *rawWriter {
+	return &rawWriter{writer: w}
+}
@@ -0,0 +1,266 @@
+/*
+ * MinIO Cloud Storage, (C) 2021 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package s3select
+
+import (
+	"encoding/xml"
+	"errors"
+	"io"
+	"sync"
+
+	"github.com/minio/minio/pkg/s3select/sql"
+)
+
+// SelectObject - an object queried by a select over many objects.
+type SelectObject struct {
+	Name string
+	// Size of the object content, -1 if not known.
+	Size int64
+	// Whether the object is stored compressed by the server.
+	Compressed bool
+	// Returns a reader of the object content, as the callback
+	// passed to Open.
+	GetReader func(offset, length int64) (io.ReadCloser, error)
+
+	// Set when the object cannot be queried, the object is
+	// skipped and the error is reported in its stats.
+	ErrorCode    string
+	ErrorMessage string
+}
+
+// ObjectStats - represents the statistics of an object of a select
+// over many objects, sent in an ObjectStats event once all records of
+// the object are read.
+type ObjectStats struct {
+	XMLName        xml.Name `xml:"ObjectStats"`
+	Key            string   `xml:"Key"`
+	BytesScanned   int64    `xml:"BytesScanned"`
+	BytesProcessed int64    `xml:"BytesProcessed"`
+	ErrorCode      string   `xml:"ErrorCode,omitempty"`
+	ErrorMessage   string   `xml:"ErrorMessage,omitempty"`
+}
+
+// objectRecord is a record read from an object, the stats of an
+// object once it is done or an error ending the select.
+type objectRecord struct {
+	record sql.Record
+	stats  *ObjectStats
+	err    error
+}
+
+// multiObjectReader - reads the records of many objects, objects are
+// read in parallel and their records are returned as they are read.
+// Records read without a destination record are not reused by the
+// readers of the input formats, so they can be read ahead.
+type multiObjectReader struct {
+	records chan objectRecord
+	doneCh  chan struct{}
+	wg      sync.WaitGroup
+	once    sync.Once
+
+	// Called with the stats of each object once all its records
+	// have been returned.
+	onObjectDone func(stats ObjectStats)
+
+	mu sync.Mutex
+	// Objects being read.
+	active map[*S3Select]struct{}
+	// Totals of the objects done.
+	bytesScanned, bytesProcessed int64
+}
+
+// OpenObjects - opens the objects returned by next for a select over
+// many objects, next returns io.EOF after the last object. At most
+// parallel objects are read at once, the statement is evaluated over
+// the records of all objects so aggregations and ordering apply to the
+// whole result.
+func (s3Select *S3Select) OpenObjects(next func() (*SelectObject, error), parallel int) error {
+	if !s3Select.ScanRange.IsEmpty() {
+		return errInvalidRequestParameter(errors.New("ScanRange is not supported for a select over many objects"))
+	}
+	if parallel < 1 {
+		parallel = 1
+	}
+
+	r := &multiObjectReader{
+		records: make(chan objectRecord, 100*parallel),
+		doneCh:  make(chan struct{}),
+		active:  make(map[*S3Select]struct{}),
+	}
+	objects := make(chan *SelectObject)
+
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		defer close(objects)
+		for {
+			object, err := next()
+			if err == io.EOF {
+				return
+			}
+			if err != nil {
+				r.send(objectRecord{err: err})
+				return
+			}
+			select {
+			case objects <- object:
+			case <-r.doneCh:
+				return
+			}
+		}
+	}()
+
+	for i := 0; i < parallel; i++ {
+		r.wg.Add(1)
+		go func() {
+			defer r.wg.Done()
+			for object := range objects {
+				if !r.readObject(s3Select, object) {
+					return
+				}
+			}
+		}()
+	}
+
+	go func() {
+		r.wg.Wait()
+		close(r.records)
+	}()
+
+	s3Select.recordReader = r
+	return nil
+}
+
+// send queues a record, it returns false once the reader is closed.
+func (r *multiObjectReader) send(rec objectRecord) bool {
+	select {
+	case r.records <- rec:
+		return true
+	case <-r.doneCh:
+		return false
+	}
+}
+
+// readObject queues the records and the stats of an object, it returns
+// false once the reader is closed.
+func (r *multiObjectReader) readObject(s3Select *S3Select, object *SelectObject) bool {
+	stats := ObjectStats{
+		Key:          object.Name,
+		ErrorCode:    object.ErrorCode,
+		ErrorMessage: object.ErrorMessage,
+	}
+	if stats.ErrorCode == "" {
+		objSelect := &S3Select{
+			Input:            s3Select.Input,
+			objectSize:       object.Size,
+			serverCompressed: object.Compressed,
+		}
+		err := objSelect.Open(object.GetReader)
+		if err == nil {
+			r.mu.Lock()
+			r.active[objSelect] = struct{}{}
+			r.mu.Unlock()
+
+			var closed bool
+			closed, err = r.readRecords(objSelect)
+			stats.BytesScanned, stats.BytesProcessed = objSelect.getProgress()
+			objSelect.Close()
+
+			r.mu.Lock()
+			delete(r.active, objSelect)
+			addProgress(&r.bytesScanned, &r.bytesProcessed, stats.BytesScanned, stats.BytesProcessed)
+			r.mu.Unlock()
+			if closed {
+				return false
+			}
+		}
+		if err != nil {
+			stats.ErrorCode, stats.ErrorMessage = "InternalError", err.Error()
+			if serr, ok := err.(SelectError); ok {
+				stats.ErrorCode, stats.ErrorMessage = serr.ErrorCode(), serr.ErrorMessage()
+			}
+		}
+	}
+	return r.send(objectRecord{stats: &stats})
+}
+
+// readRecords queues the records of an opened object, closed is set
+// when the reader is closed.
+func (r *multiObjectReader) readRecords(objSelect *S3Select) (closed bool, err error) {
+	for {
+		rec, err := objSelect.recordReader.Read(nil)
+		if err == io.EOF {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		if !r.send(objectRecord{record: rec}) {
+			return true, nil
+		}
+	}
+}
+
+// addProgress adds the progress of an object to the totals, progress
+// is not known for Parquet objects.
+func addProgress(bytesScanned, bytesProcessed *int64, objScanned, objProcessed int64) {
+	if objScanned > 0 {
+		*bytesScanned += objScanned
+	}
+	if objProcessed > 0 {
+		*bytesProcessed += objProcessed
+	}
+}
+
+// Stats returns the progress over all objects.
+func (r *multiObjectReader) Stats() (bytesScanned, bytesProcessed int64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	bytesScanned, bytesProcessed = r.bytesScanned, r.bytesProcessed
+	for objSelect := range r.active {
+		s, p := objSelect.getProgress()
+		addProgress(&bytesScanned, &bytesProcessed, s, p)
+	}
+	return bytesScanned, bytesProcessed
+}
+
+func (r *multiObjectReader) Read(dst sql.Record) (sql.Record, error) {
+	for rec := range r.records {
+		switch {
+		case rec.err != nil:
+			return nil, rec.err
+		case rec.stats != nil:
+			if r.onObjectDone != nil {
+				r.onObjectDone(*rec.stats)
+			}
+		default:
+			return rec.record, nil
+		}
+	}
+	return nil, io.EOF
+}
+
+// Close stops reading the objects and waits for the readers to be
+// closed.
+func (r *multiObjectReader) Close() error {
+	r.once.Do(func() {
+		close(r.doneCh)
+	})
+	r.wg.Wait()
+	return nil
+}
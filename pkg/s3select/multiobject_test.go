@@ -0,0 +1,250 @@
+/*
+ * MinIO Cloud Storage, (C) 2021 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package s3select
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// readEvents returns the event types and payloads of an event stream.
+func readEvents(t *testing.T, stream []byte) (types []string, payloads [][]byte) {
+	t.Helper()
+	for len(stream) > 0 {
+		if len(stream) < 16 {
+			t.Fatalf("truncated message")
+		}
+		totalLength := int(binary.BigEndian.Uint32(stream[0:4]))
+		headerLength := int(binary.BigEndian.Uint32(stream[4:8]))
+		header := stream[12 : 12+headerLength]
+		payload := stream[12+headerLength : totalLength-4]
+		eventType := ""
+		for len(header) > 0 {
+			nameLength := int(header[0])
+			name := string(header[1 : 1+nameLength])
+			valueLength := int(binary.BigEndian.Uint16(header[2+nameLength : 4+nameLength]))
+			value := string(header[4+nameLength : 4+nameLength+valueLength])
+			header = header[4+nameLength+valueLength:]
+			if name == ":event-type" {
+				eventType = value
+			}
+		}
+		types = append(types, eventType)
+		payloads = append(payloads, payload)
+		stream = stream[totalLength:]
+	}
+	return types, payloads
+}
+
+func TestOpenObjects(t *testing.T) {
+	objects := []struct {
+		name    string
+		content string
+		errCode string
+	}{
+		{name: "data/1.csv", content: "name,dept,salary\nalice,eng,100\nbob,eng,80\n"},
+		{name: "data/2.csv", content: "name,dept,salary\ncarol,sales,70\n"},
+		{name: "data/3.csv", content: "name,dept,salary\ndave,sales,90\nerin,hr,60\n"},
+		{name: "data/denied.csv", errCode: "AccessDenied"},
+		{name: "data/broken.csv"},
+	}
+
+	var testTable = []struct {
+		name       string
+		query      string
+		wantResult string
+	}{
+		{
+			name:       "aggregate",
+			query:      `SELECT COUNT(*), SUM(s.salary), MIN(s.salary), MAX(s.salary) FROM S3Object s`,
+			wantResult: "5,400,60,100\n",
+		},
+		{
+			name:       "group-by-order-by",
+			query:      `SELECT s.dept, COUNT(*) AS n FROM S3Object s GROUP BY s.dept ORDER BY n DESC, s.dept`,
+			wantResult: "eng,2\nsales,2\nhr,1\n",
+		},
+		{
+			name:       "order-by-limit",
+			query:      `SELECT s.name FROM S3Object s ORDER BY s.salary DESC LIMIT 2`,
+			wantResult: "alice\ndave\n",
+		},
+		{
+			name:       "where",
+			query:      `SELECT s.name FROM S3Object s WHERE s.dept = 'sales' ORDER BY s.name`,
+			wantResult: "carol\ndave\n",
+		},
+	}
+
+	defRequest := `<?xml version="1.0" encoding="UTF-8"?>
+<SelectObjectContentRequest>
+    <Expression>%s</Expression>
+    <ExpressionType>SQL</ExpressionType>
+    <InputSerialization>
+        <CompressionType>NONE</CompressionType>
+        <CSV>
+        	<FileHeaderInfo>USE</FileHeaderInfo>
+        </CSV>
+    </InputSerialization>
+    <OutputSerialization>
+        <CSV>
+        </CSV>
+    </OutputSerialization>
+</SelectObjectContentRequest>`
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			s3Select, err := NewS3Select(strings.NewReader(fmt.Sprintf(defRequest, testCase.query)))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			i := 0
+			next := func() (*SelectObject, error) {
+				if i == len(objects) {
+					return nil, io.EOF
+				}
+				object := objects[i]
+				i++
+				return &SelectObject{
+					Name:      object.name,
+					Size:      int64(len(object.content)),
+					ErrorCode: object.errCode,
+					GetReader: func(offset, length int64) (io.ReadCloser, error) {
+						if object.content == "" {
+							return nil, errors.New("object is broken")
+						}
+						return ioutil.NopCloser(strings.NewReader(object.content)), nil
+					},
+				}, nil
+			}
+			if err = s3Select.OpenObjects(next, 2); err != nil {
+				t.Fatal(err)
+			}
+
+			w := &testResponseWriter{}
+			s3Select.Evaluate(w)
+			s3Select.Close()
+
+			types, payloads := readEvents(t, w.response)
+			var records bytes.Buffer
+			stats := make(map[string]ObjectStats)
+			for i, eventType := range types {
+				switch eventType {
+				case "Records":
+					records.Write(payloads[i])
+				case "ObjectStats":
+					var objStats ObjectStats
+					if err = xml.Unmarshal(payloads[i], &objStats); err != nil {
+						t.Fatal(err)
+					}
+					stats[objStats.Key] = objStats
+				case "Cont", "Stats", "End":
+				default:
+					t.Fatalf("unexpected event %s: %s", eventType, payloads[i])
+				}
+			}
+			if types[len(types)-1] != "End" {
+				t.Fatalf("expected End event last, got %v", types)
+			}
+			if records.String() != testCase.wantResult {
+				t.Errorf("received output does not match. Query: %s\ngot: %q\nwant: %q", testCase.query, records.String(), testCase.wantResult)
+			}
+
+			var keys []string
+			for key := range stats {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+			if len(keys) != len(objects) {
+				t.Fatalf("expected stats of %d objects, got %v", len(objects), keys)
+			}
+			var totalScanned int64
+			for _, object := range objects {
+				objStats := stats[object.name]
+				switch object.name {
+				case "data/denied.csv":
+					if objStats.ErrorCode != "AccessDenied" {
+						t.Errorf("%s: expected AccessDenied, got %+v", object.name, objStats)
+					}
+				case "data/broken.csv":
+					if objStats.ErrorCode != "InternalError" || objStats.ErrorMessage != "object is broken" {
+						t.Errorf("%s: expected InternalError, got %+v", object.name, objStats)
+					}
+				default:
+					if objStats.ErrorCode != "" || objStats.BytesScanned != int64(len(object.content)) {
+						t.Errorf("%s: unexpected stats %+v", object.name, objStats)
+					}
+					totalScanned += objStats.BytesScanned
+				}
+			}
+			if scanned, _ := s3Select.getProgress(); scanned != totalScanned {
+				t.Errorf("expected %d bytes scanned, got %d", totalScanned, scanned)
+			}
+		})
+	}
+}
+
+func TestOpenObjectsErrors(t *testing.T) {
+	request := `<?xml version="1.0" encoding="UTF-8"?>
+<SelectObjectContentRequest>
+    <Expression>SELECT * FROM S3Object</Expression>
+    <ExpressionType>SQL</ExpressionType>
+    <InputSerialization>
+        <CSV/>
+    </InputSerialization>
+    <OutputSerialization>
+        <CSV/>
+    </OutputSerialization>
+    %s
+</SelectObjectContentRequest>`
+
+	s3Select, err := NewS3Select(strings.NewReader(fmt.Sprintf(request, "<ScanRange><Start>1</Start></ScanRange>")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = s3Select.OpenObjects(func() (*SelectObject, error) {
+		return nil, io.EOF
+	}, 1); err == nil {
+		t.Fatal("expected ScanRange to be rejected")
+	}
+
+	// A listing error ends the request with an error.
+	s3Select, err = NewS3Select(strings.NewReader(fmt.Sprintf(request, "")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = s3Select.OpenObjects(func() (*SelectObject, error) {
+		return nil, errors.New("listing failed")
+	}, 1); err != nil {
+		t.Fatal(err)
+	}
+	var got bytes.Buffer
+	err = s3Select.EvaluateTo(&got)
+	s3Select.Close()
+	if err == nil || !strings.Contains(err.Error(), "listing failed") {
+		t.Fatalf("expected listing error, got %v", err)
+	}
+}
@@ -0,0 +1,144 @@
+/*
+ * MinIO Cloud Storage, (C) 2021 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package parquet
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	parquetgo "github.com/minio/minio/pkg/s3select/internal/parquet-go"
+	"github.com/minio/minio/pkg/s3select/internal/parquet-go/data"
+	parquetgen "github.com/minio/minio/pkg/s3select/internal/parquet-go/gen-go/parquet"
+	"github.com/minio/minio/pkg/s3select/internal/parquet-go/schema"
+)
+
+// ColumnType - type of the values of a column.
+type ColumnType int
+
+// Supported column types.
+const (
+	String    ColumnType = iota // string values
+	Int64                       // int64 values
+	Bool                        // bool values
+	Float64                     // float64 values
+	Timestamp                   // time.Time values, stored as microseconds since the epoch
+)
+
+// Column - column of the records written by Writer.
+type Column struct {
+	Name string
+	Type ColumnType
+}
+
+// Writer - writes flat records of optional values into a Parquet file.
+type Writer struct {
+	columns []Column
+	writer  *parquetgo.Writer
+}
+
+// NewWriter - creates new Parquet writer of records with the columns, rowGroupCount
+// records are buffered in memory before they are written to writeCloser.
+func NewWriter(writeCloser io.WriteCloser, columns []Column, rowGroupCount int) (*Writer, error) {
+	schemaTree := schema.NewTree()
+	for _, column := range columns {
+		var elementType parquetgen.Type
+		var convertedType *parquetgen.ConvertedType
+		switch column.Type {
+		case String:
+			elementType = parquetgen.Type_BYTE_ARRAY
+			convertedType = parquetgen.ConvertedTypePtr(parquetgen.ConvertedType_UTF8)
+		case Int64:
+			elementType = parquetgen.Type_INT64
+		case Bool:
+			elementType = parquetgen.Type_BOOLEAN
+		case Float64:
+			elementType = parquetgen.Type_DOUBLE
+		case Timestamp:
+			elementType = parquetgen.Type_INT64
+			convertedType = parquetgen.ConvertedTypePtr(parquetgen.ConvertedType_TIMESTAMP_MICROS)
+		default:
+			return nil, fmt.Errorf("unsupported type of column %s", column.Name)
+		}
+		// Values are PLAIN encoded, dictionary encoded pages cannot be read by Reader.
+		element, err := schema.NewElement(column.Name, parquetgen.FieldRepetitionType_OPTIONAL,
+			parquetgen.TypePtr(elementType), convertedType,
+			parquetgen.EncodingPtr(parquetgen.Encoding_PLAIN), nil, nil)
+		if err != nil {
+			return nil, err
+		}
+		if err = schemaTree.Set(column.Name, element); err != nil {
+			return nil, err
+		}
+	}
+
+	writer, err := parquetgo.NewWriter(writeCloser, schemaTree, rowGroupCount)
+	if err != nil {
+		return nil, err
+	}
+	return &Writer{
+		columns: columns,
+		writer:  writer,
+	}, nil
+}
+
+// Write - writes a record, values are in the order of the columns and nil values are null.
+func (w *Writer) Write(values ...interface{}) error {
+	if len(values) != len(w.columns) {
+		return fmt.Errorf("expected %d values, got %d", len(w.columns), len(values))
+	}
+
+	record := make(map[string]*data.Column, len(w.columns))
+	for i, column := range w.columns {
+		var c *data.Column
+		switch column.Type {
+		case String:
+			c = data.NewColumn(parquetgen.Type_BYTE_ARRAY)
+		case Bool:
+			c = data.NewColumn(parquetgen.Type_BOOLEAN)
+		case Float64:
+			c = data.NewColumn(parquetgen.Type_DOUBLE)
+		default:
+			c = data.NewColumn(parquetgen.Type_INT64)
+		}
+
+		// Values of optional columns are at definition level 1.
+		switch v := values[i].(type) {
+		case nil:
+			c.AddNull(0, 0)
+		case string:
+			c.AddByteArray([]byte(v), 1, 0)
+		case int64:
+			c.AddInt64(v, 1, 0)
+		case bool:
+			c.AddBoolean(v, 1, 0)
+		case float64:
+			c.AddDouble(v, 1, 0)
+		case time.Time:
+			c.AddInt64(v.UnixNano()/int64(time.Microsecond), 1, 0)
+		default:
+			return fmt.Errorf("unsupported value %T of column %s", v, column.Name)
+		}
+		record[column.Name] = c
+	}
+	return w.writer.Write(record)
+}
+
+// Close - writes the pending records and the footer, and closes the underlying writer.
+func (w *Writer) Close() error {
+	return w.writer.Close()
+}
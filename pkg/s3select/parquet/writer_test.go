@@ -0,0 +1,102 @@
+/*
+ * MinIO Cloud Storage, (C) 2021 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package parquet
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/bcicen/jstream"
+	jsonfmt "github.com/minio/minio/pkg/s3select/json"
+)
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error { return nil }
+
+func TestWriter(t *testing.T) {
+	var buf bytes.Buffer
+	columns := []Column{
+		{Name: "key", Type: String},
+		{Name: "size", Type: Int64},
+		{Name: "latest", Type: Bool},
+		{Name: "modified", Type: String},
+		{Name: "ratio", Type: Float64},
+		{Name: "created", Type: Timestamp},
+	}
+	w, err := NewWriter(nopWriteCloser{&buf}, columns, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	modTime := "2021-01-01T00:00:00.000Z"
+	created := time.Date(2021, 1, 1, 0, 0, 1, 2000, time.UTC)
+	records := [][]interface{}{
+		{"a", int64(1), true, modTime, 0.5, created},
+		{"b", nil, false, nil, nil, nil},
+		{"c", int64(3), nil, modTime, -1.25, created},
+	}
+	for _, record := range records {
+		if err = w.Write(record...); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err = w.Write("d"); err == nil {
+		t.Fatal("expected an error for a record with missing values")
+	}
+	if err = w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	data := buf.Bytes()
+	r, err := NewReader(func(offset, length int64) (io.ReadCloser, error) {
+		if offset < 0 {
+			offset = int64(len(data)) + offset
+		}
+		return ioutil.NopCloser(bytes.NewReader(data[offset:])), nil
+	}, &ReaderArgs{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	want := []jstream.KVS{
+		{{Key: "key", Value: "a"}, {Key: "size", Value: int64(1)}, {Key: "latest", Value: true}, {Key: "modified", Value: modTime},
+			{Key: "ratio", Value: 0.5}, {Key: "created", Value: int64(1609459201000002)}},
+		{{Key: "key", Value: "b"}, {Key: "size", Value: nil}, {Key: "latest", Value: false}, {Key: "modified", Value: nil},
+			{Key: "ratio", Value: nil}, {Key: "created", Value: nil}},
+		{{Key: "key", Value: "c"}, {Key: "size", Value: int64(3)}, {Key: "latest", Value: nil}, {Key: "modified", Value: modTime},
+			{Key: "ratio", Value: -1.25}, {Key: "created", Value: int64(1609459201000002)}},
+	}
+	for i := range want {
+		rec, err := r.Read(nil)
+		if err != nil {
+			t.Fatalf("record %d: %v", i, err)
+		}
+		if got := rec.(*jsonfmt.Record).KVS; !reflect.DeepEqual(got, want[i]) {
+			t.Fatalf("record %d: expected %v, got %v", i, want[i], got)
+		}
+	}
+	if _, err = r.Read(nil); err != io.EOF {
+		t.Fatalf("expected EOF, got %v", err)
+	}
+}
@@ -17,6 +17,8 @@
 package s3select
 
 import (
+	"bufio"
+	"bytes"
 	"compress/bzip2"
 	"errors"
 	"fmt"
@@ -24,9 +26,22 @@ import (
 	"sync"
 	"sync/atomic"
 
+	"github.com/klauspost/compress/s2"
+	"github.com/klauspost/compress/snappy"
+	"github.com/klauspost/compress/zstd"
 	gzip "github.com/klauspost/pgzip"
 )
 
+// zstdMaxWindowSize caps the memory used to decode a ZSTD stream,
+// streams requiring a larger window are rejected.
+const zstdMaxWindowSize = 64 << 20
+
+// Stream identifier chunks starting S2 and Snappy framed streams.
+var (
+	s2StreamIdentifier     = []byte("\xff\x06\x00\x00S2sTwO")
+	snappyStreamIdentifier = []byte("\xff\x06\x00\x00sNaPpY")
+)
+
 type countUpReader struct {
 	reader    io.Reader
 	bytesRead int64
@@ -58,6 +73,7 @@ type progressReader struct {
 
 	closedMu sync.Mutex
 	gzr      *gzip.Reader
+	zr       *zstd.Decoder
 	closed   bool
 }
 
@@ -82,6 +98,9 @@ func (pr *progressReader) Close() error {
 	if pr.gzr != nil {
 		pr.gzr.Close()
 	}
+	if pr.zr != nil {
+		pr.zr.Close()
+	}
 	return pr.rc.Close()
 }
 
@@ -118,6 +137,19 @@ func newProgressReader(rc io.ReadCloser, compType CompressionType) (*progressRea
 		r = pr.gzr
 	case bzip2Type:
 		r = bzip2.NewReader(scannedReader)
+	case zstdType:
+		// A single goroutine decodes ahead of the reader.
+		pr.zr, err = zstd.NewReader(scannedReader, zstd.WithDecoderConcurrency(1),
+			zstd.WithDecoderMaxMemory(zstdMaxWindowSize))
+		if err != nil {
+			return nil, err
+		}
+		r = pr.zr
+	case snappyType:
+		r = snappy.NewReader(scannedReader)
+	case s2Type:
+		// S2 readers decode Snappy framed streams too.
+		r = s2.NewReader(scannedReader)
 	default:
 		return nil, errInvalidCompressionFormat(fmt.Errorf("unknown compression type '%v'", compType))
 	}
@@ -125,3 +157,15 @@ func newProgressReader(rc io.ReadCloser, compType CompressionType) (*progressRea
 
 	return &pr, nil
 }
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// isFramedStream returns whether the stream read by r starts with
+// the stream identifier of an S2 or Snappy framed stream.
+func isFramedStream(r *bufio.Reader) bool {
+	b, _ := r.Peek(len(s2StreamIdentifier))
+	return bytes.Equal(b, s2StreamIdentifier) || bytes.Equal(b, snappyStreamIdentifier)
+}
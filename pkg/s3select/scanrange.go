@@ -0,0 +1,263 @@
+/*
+ * MinIO Cloud Storage, (C) 2021 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package s3select
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ScanRange - represents elements inside <ScanRange/> in request XML.
+// Only the records whose first byte lies within the range are
+// processed, a record starting in the range is read to its end even
+// beyond the range.
+type ScanRange struct {
+	// First byte of the range, defaults to 0.
+	Start *int64 `xml:"Start"`
+	// Last byte of the range, inclusive. Without Start it is the
+	// number of bytes at the end of the object to scan.
+	End *int64 `xml:"End"`
+}
+
+// IsEmpty - returns whether no scan range is set.
+func (r ScanRange) IsEmpty() bool {
+	return r.Start == nil && r.End == nil
+}
+
+// validate checks the scan range against the input serialization.
+func (r ScanRange) validate(input *InputSerialization) error {
+	if r.IsEmpty() {
+		return nil
+	}
+	if (r.Start != nil && *r.Start < 0) || (r.End != nil && *r.End < 0) {
+		return errInvalidRequestParameter(errors.New("ScanRange Start and End must not be negative"))
+	}
+	if r.Start != nil && r.End != nil && *r.End < *r.Start {
+		return errInvalidRequestParameter(errors.New("ScanRange End must not be smaller than Start"))
+	}
+	if input.CompressionType != noneType {
+		return errInvalidRequestParameter(errors.New("ScanRange is not supported for compressed input"))
+	}
+	switch input.format {
+	case csvFormat:
+		if input.CSVArgs.AllowQuotedRecordDelimiter {
+			return errInvalidRequestParameter(errors.New("ScanRange is not supported with AllowQuotedRecordDelimiter"))
+		}
+	case jsonFormat:
+		if !strings.EqualFold(input.JSONArgs.ContentType, "lines") {
+			return errInvalidRequestParameter(errors.New("ScanRange is only supported for JSON of type LINES"))
+		}
+	default:
+		return errInvalidRequestParameter(fmt.Errorf("ScanRange is not supported for %s input", input.format))
+	}
+	return nil
+}
+
+// openScanRange returns a reader of the records starting within the
+// scan range. With header set the first record of the object is read
+// first if it lies before the range.
+func (s3Select *S3Select) openScanRange(getReader func(offset, length int64) (io.ReadCloser, error), delim []byte, header bool) (io.ReadCloser, error) {
+	r := s3Select.ScanRange
+	start, end := int64(0), int64(-1)
+	switch {
+	case r.Start != nil:
+		start = *r.Start
+		if r.End != nil {
+			end = *r.End
+		}
+	default:
+		// Scan the last End bytes.
+		if s3Select.objectSize < 0 {
+			return nil, errInvalidRequestParameter(errors.New("ScanRange without Start requires the object size"))
+		}
+		if start = s3Select.objectSize - *r.End; start < 0 {
+			start = 0
+		}
+	}
+
+	var headerRecord []byte
+	if header && start > 0 {
+		rc, err := getReader(0, -1)
+		if err != nil {
+			return nil, err
+		}
+		var buf bytes.Buffer
+		_, err = readRecord(bufio.NewReader(io.LimitReader(rc, maxRecordSize)), delim, &buf)
+		rc.Close()
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+		if err == io.EOF && buf.Len() >= maxRecordSize {
+			return nil, errors.New("the header record is larger than the maximum record size")
+		}
+		headerRecord = buf.Bytes()
+		if err == io.EOF {
+			// A single record without delimiter.
+			headerRecord = append(headerRecord, delim...)
+		}
+	}
+
+	// Read from the bytes preceding the range, a record starts at
+	// the range when they are a record delimiter.
+	offset := start - int64(len(delim))
+	if offset < 0 {
+		offset = 0
+	}
+	rc, err := getReader(offset, -1)
+	if err != nil {
+		return nil, err
+	}
+	sr := &scanRangeReader{
+		rc:      rc,
+		r:       bufio.NewReader(rc),
+		delim:   delim,
+		pos:     offset,
+		end:     end,
+		pending: headerRecord,
+	}
+	if start > 0 {
+		n, err := readRecord(sr.r, delim, nil)
+		sr.pos += n
+		if err == io.EOF {
+			sr.done = true
+		} else if err != nil {
+			rc.Close()
+			return nil, err
+		}
+	}
+	if end >= 0 && sr.pos > end {
+		// No record starts within the range.
+		sr.done = true
+	}
+	return sr, nil
+}
+
+// readRecord reads up to and including the next record delimiter,
+// the bytes are written to w if set. It returns the number of bytes
+// read.
+func readRecord(r *bufio.Reader, delim []byte, w io.Writer) (n int64, err error) {
+	last := delim[len(delim)-1]
+	tail := make([]byte, 0, 2*len(delim))
+	for {
+		chunk, err := r.ReadSlice(last)
+		n += int64(len(chunk))
+		if w != nil {
+			if _, werr := w.Write(chunk); werr != nil {
+				return n, werr
+			}
+		}
+		tail = appendTail(tail, chunk, len(delim))
+		switch err {
+		case nil:
+			if bytes.HasSuffix(tail, delim) {
+				return n, nil
+			}
+		case bufio.ErrBufferFull:
+		default:
+			return n, err
+		}
+	}
+}
+
+// appendTail appends b to tail keeping only the last n bytes.
+func appendTail(tail, b []byte, n int) []byte {
+	if len(b) > n {
+		b = b[len(b)-n:]
+	}
+	tail = append(tail, b...)
+	if len(tail) > n {
+		tail = append(tail[:0], tail[len(tail)-n:]...)
+	}
+	return tail
+}
+
+// scanRangeReader returns the records of the object starting up to
+// end, the underlying reader is positioned at the start of the first
+// record of the range.
+type scanRangeReader struct {
+	rc    io.ReadCloser
+	r     *bufio.Reader
+	delim []byte
+
+	// Offset in the object of the next byte of r.
+	pos int64
+	// Last offset at which a record may start, -1 for the whole
+	// remainder of the object.
+	end int64
+	// Last bytes returned, to detect a record delimiter at end.
+	tail []byte
+
+	pending []byte
+	done    bool
+}
+
+func (s *scanRangeReader) Read(p []byte) (n int, err error) {
+	for {
+		if len(s.pending) > 0 {
+			n = copy(p, s.pending)
+			s.pending = s.pending[n:]
+			return n, nil
+		}
+		if s.done {
+			return 0, io.EOF
+		}
+
+		switch {
+		case s.end < 0 || s.pos <= s.end:
+			if s.end >= 0 && int64(len(p)) > s.end-s.pos+1 {
+				p = p[:s.end-s.pos+1]
+			}
+			n, err = s.r.Read(p)
+			s.pos += int64(n)
+			s.tail = appendTail(s.tail, p[:n], len(s.delim))
+			if err == io.EOF {
+				s.done = true
+				if n > 0 {
+					err = nil
+				}
+			}
+			return n, err
+
+		case bytes.HasSuffix(s.tail, s.delim):
+			// The next record starts after the range.
+			s.done = true
+
+		default:
+			// Read the remainder of the last record.
+			var chunk []byte
+			chunk, err = s.r.ReadSlice(s.delim[len(s.delim)-1])
+			s.pos += int64(len(chunk))
+			s.tail = appendTail(s.tail, chunk, len(s.delim))
+			s.pending = append(s.pending[:0], chunk...)
+			switch err {
+			case nil, bufio.ErrBufferFull:
+			case io.EOF:
+				s.done = true
+			default:
+				return 0, err
+			}
+		}
+	}
+}
+
+func (s *scanRangeReader) Close() error {
+	return s.rc.Close()
+}
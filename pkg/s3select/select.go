@@ -30,6 +30,9 @@ import (
 	"strings"
 	"sync"
 
+	"github.com/klauspost/compress/s2"
+	"github.com/klauspost/compress/zstd"
+	"github.com/minio/minio/pkg/s3select/arrow"
 	"github.com/minio/minio/pkg/s3select/csv"
 	"github.com/minio/minio/pkg/s3select/json"
 	"github.com/minio/minio/pkg/s3select/parquet"
@@ -49,15 +52,19 @@ const (
 	csvFormat     = "csv"
 	jsonFormat    = "json"
 	parquetFormat = "parquet"
+	arrowFormat   = "arrow"
 )
 
 // CompressionType - represents value inside <CompressionType/> in request XML.
 type CompressionType string
 
 const (
-	noneType  CompressionType = "none"
-	gzipType  CompressionType = "gzip"
-	bzip2Type CompressionType = "bzip2"
+	noneType   CompressionType = "none"
+	gzipType   CompressionType = "gzip"
+	bzip2Type  CompressionType = "bzip2"
+	zstdType   CompressionType = "zstd"
+	snappyType CompressionType = "snappy"
+	s2Type     CompressionType = "s2"
 )
 
 const (
@@ -92,7 +99,7 @@ func (c *CompressionType) UnmarshalXML(d *xml.Decoder, start xml.StartElement) e
 	}
 
 	switch parsedType {
-	case noneType, gzipType, bzip2Type:
+	case noneType, gzipType, bzip2Type, zstdType, snappyType, s2Type:
 	default:
 		return errInvalidCompressionFormat(fmt.Errorf("invalid compression format '%v'", s))
 	}
@@ -159,8 +166,10 @@ func (input *InputSerialization) UnmarshalXML(d *xml.Decoder, start xml.StartEle
 
 // OutputSerialization - represents elements inside <OutputSerialization/> in request XML.
 type OutputSerialization struct {
-	CSVArgs     csv.WriterArgs  `xml:"CSV"`
-	JSONArgs    json.WriterArgs `xml:"JSON"`
+	CSVArgs     csv.WriterArgs     `xml:"CSV"`
+	JSONArgs    json.WriterArgs    `xml:"JSON"`
+	ParquetArgs parquet.WriterArgs `xml:"Parquet"`
+	ArrowArgs   arrow.WriterArgs   `xml:"Arrow"`
 	unmarshaled bool
 	format      string
 }
@@ -188,8 +197,16 @@ func (output *OutputSerialization) UnmarshalXML(d *xml.Decoder, start xml.StartE
 		parsedOutput.format = jsonFormat
 		found++
 	}
+	if !parsedOutput.ParquetArgs.IsEmpty() {
+		parsedOutput.format = parquetFormat
+		found++
+	}
+	if !parsedOutput.ArrowArgs.IsEmpty() {
+		parsedOutput.format = arrowFormat
+		found++
+	}
 	if found != 1 {
-		return errObjectSerializationConflict(fmt.Errorf("either CSV or JSON should be present in OutputSerialization"))
+		return errObjectSerializationConflict(fmt.Errorf("one of CSV, JSON, Parquet or Arrow should be present in OutputSerialization"))
 	}
 
 	*output = OutputSerialization(parsedOutput)
@@ -212,10 +229,16 @@ type S3Select struct {
 	Input          InputSerialization  `xml:"InputSerialization"`
 	Output         OutputSerialization `xml:"OutputSerialization"`
 	Progress       RequestProgress     `xml:"RequestProgress"`
+	ScanRange      ScanRange           `xml:"ScanRange"`
 
 	statement      *sql.SelectStatement
+	columnar       *columnarWriter
 	progressReader *progressReader
 	recordReader   recordReader
+	objectSize     int64
+	// Whether the object is stored compressed by the server, its
+	// reader returns the decompressed content.
+	serverCompressed bool
 }
 
 var (
@@ -256,12 +279,30 @@ func (s3Select *S3Select) UnmarshalXML(d *xml.Decoder, start xml.StartElement) e
 		return errMissingRequiredParameter(fmt.Errorf("OutputSerialization must be provided"))
 	}
 
+	if err := parsedS3Select.ScanRange.validate(&parsedS3Select.Input); err != nil {
+		return err
+	}
+
 	statement, err := sql.ParseSelectStatement(parsedS3Select.Expression)
 	if err != nil {
 		return err
 	}
 
 	parsedS3Select.statement = &statement
+	parsedS3Select.objectSize = -1
+
+	// The columns of Parquet and Arrow output are those of the
+	// projection, the keys of JSON records vary from record to record.
+	switch parsedS3Select.Output.format {
+	case parquetFormat, arrowFormat:
+		names, types := statement.OutputColumns()
+		if names == nil && parsedS3Select.Input.format == jsonFormat {
+			return errInvalidRequestParameter(fmt.Errorf("SELECT * of JSON input is not supported for Parquet or Arrow output"))
+		}
+		if parsedS3Select.columnar, err = newColumnarWriter(parsedS3Select.Output.format, names, types); err != nil {
+			return errInvalidRequestParameter(err)
+		}
+	}
 
 	*s3Select = S3Select(parsedS3Select)
 	return nil
@@ -273,12 +314,17 @@ func (s3Select *S3Select) outputRecord() sql.Record {
 		return csv.NewRecord()
 	case jsonFormat:
 		return json.NewRecord(sql.SelectFmtJSON)
+	case parquetFormat, arrowFormat:
+		return &columnRecord{}
 	}
 
 	panic(fmt.Errorf("unknown output format '%v'", s3Select.Output.format))
 }
 
 func (s3Select *S3Select) getProgress() (bytesScanned, bytesProcessed int64) {
+	if r, ok := s3Select.recordReader.(*multiObjectReader); ok {
+		return r.Stats()
+	}
 	if s3Select.progressReader != nil {
 		return s3Select.progressReader.Stats()
 	}
@@ -286,17 +332,78 @@ func (s3Select *S3Select) getProgress() (bytesScanned, bytesProcessed int64) {
 	return -1, -1
 }
 
+// SetObjectSize - sets the size of the queried object, it is needed
+// to resolve a ScanRange with only an End.
+func (s3Select *S3Select) SetObjectSize(size int64) {
+	s3Select.objectSize = size
+}
+
+// SetServerCompressed - marks the queried object as stored compressed
+// by the server. Such objects are read decompressed, a request
+// declaring S2 compression for them reads the content directly unless
+// it is an S2 or Snappy stream itself.
+func (s3Select *S3Select) SetServerCompressed(compressed bool) {
+	s3Select.serverCompressed = compressed
+}
+
+// openProgressReader returns a reader of rc decompressed as declared
+// in the request.
+func (s3Select *S3Select) openProgressReader(rc io.ReadCloser) (*progressReader, error) {
+	compType := s3Select.Input.CompressionType
+	if s3Select.serverCompressed && compType == s2Type {
+		br := bufio.NewReader(rc)
+		if !isFramedStream(br) {
+			compType = noneType
+		}
+		rc = readCloser{Reader: br, Closer: rc}
+	}
+	return newProgressReader(rc, compType)
+}
+
+// decompressionError returns the error reporting that the input could
+// not be decompressed, if err was returned by a decompressor. Record
+// readers report decompression errors as their cause.
+func (s3Select *S3Select) decompressionError(err error) error {
+	cause := err
+	var causer interface{ Cause() error }
+	if errors.As(err, &causer) && causer.Cause() != nil {
+		cause = causer.Cause()
+	}
+	var stErr bzip2.StructuralError
+	switch {
+	case errors.As(cause, &stErr):
+		return errInvalidBZIP2CompressionFormat(cause)
+	case errors.Is(cause, zstd.ErrMagicMismatch), errors.Is(cause, zstd.ErrReservedBlockType),
+		errors.Is(cause, zstd.ErrWindowSizeExceeded), errors.Is(cause, zstd.ErrWindowSizeTooSmall),
+		errors.Is(cause, zstd.ErrDecoderSizeExceeded), errors.Is(cause, zstd.ErrCRCMismatch):
+		return errInvalidZSTDCompressionFormat(cause)
+	case errors.Is(cause, s2.ErrCorrupt), errors.Is(cause, s2.ErrUnsupported):
+		if s3Select.Input.CompressionType == s2Type {
+			return errInvalidS2CompressionFormat(cause)
+		}
+		return errInvalidSnappyCompressionFormat(cause)
+	}
+	return err
+}
+
 // Open - opens S3 object by using callback for SQL selection query.
 // Currently CSV, JSON and Apache Parquet formats are supported.
 func (s3Select *S3Select) Open(getReader func(offset, length int64) (io.ReadCloser, error)) error {
 	switch s3Select.Input.format {
 	case csvFormat:
-		rc, err := getReader(0, -1)
+		var rc io.ReadCloser
+		var err error
+		if s3Select.ScanRange.IsEmpty() {
+			rc, err = getReader(0, -1)
+		} else {
+			rc, err = s3Select.openScanRange(getReader, []byte(s3Select.Input.CSVArgs.RecordDelimiter),
+				s3Select.Input.CSVArgs.FileHeaderInfo != "none")
+		}
 		if err != nil {
 			return err
 		}
 
-		s3Select.progressReader, err = newProgressReader(rc, s3Select.Input.CompressionType)
+		s3Select.progressReader, err = s3Select.openProgressReader(rc)
 		if err != nil {
 			rc.Close()
 			return err
@@ -305,20 +412,22 @@ func (s3Select *S3Select) Open(getReader func(offset, length int64) (io.ReadClos
 		s3Select.recordReader, err = csv.NewReader(s3Select.progressReader, &s3Select.Input.CSVArgs)
 		if err != nil {
 			rc.Close()
-			var stErr bzip2.StructuralError
-			if errors.As(err, &stErr) {
-				return errInvalidBZIP2CompressionFormat(err)
-			}
-			return err
+			return s3Select.decompressionError(err)
 		}
 		return nil
 	case jsonFormat:
-		rc, err := getReader(0, -1)
+		var rc io.ReadCloser
+		var err error
+		if s3Select.ScanRange.IsEmpty() {
+			rc, err = getReader(0, -1)
+		} else {
+			rc, err = s3Select.openScanRange(getReader, []byte{'\n'}, false)
+		}
 		if err != nil {
 			return err
 		}
 
-		s3Select.progressReader, err = newProgressReader(rc, s3Select.Input.CompressionType)
+		s3Select.progressReader, err = s3Select.openProgressReader(rc)
 		if err != nil {
 			rc.Close()
 			return err
@@ -340,7 +449,10 @@ func (s3Select *S3Select) Open(getReader func(offset, length int64) (io.ReadClos
 		}
 		var err error
 		s3Select.recordReader, err = parquet.NewReader(getReader, &s3Select.Input.ParquetArgs)
-		return err
+		if err != nil {
+			return s3Select.decompressionError(err)
+		}
+		return nil
 	}
 
 	panic(fmt.Errorf("unknown input format '%v'", s3Select.Input.format))
@@ -394,6 +506,13 @@ func (s3Select *S3Select) marshal(buf *bytes.Buffer, record sql.Record) error {
 	panic(fmt.Errorf("unknown output format '%v'", s3Select.Output.format))
 }
 
+// recordWriter - receives the marshaled output of an evaluated select statement.
+type recordWriter interface {
+	SendRecord(payload *bytes.Buffer) error
+	Finish(bytesScanned, bytesProcessed int64) error
+	FinishWithError(errorCode, errorMessage string) error
+}
+
 // Evaluate - filters and sends records read from opened reader as per select statement to http response writer.
 func (s3Select *S3Select) Evaluate(w http.ResponseWriter) {
 	getProgressFunc := s3Select.getProgress
@@ -401,22 +520,43 @@ func (s3Select *S3Select) Evaluate(w http.ResponseWriter) {
 		getProgressFunc = nil
 	}
 	writer := newMessageWriter(w, getProgressFunc)
+	if r, ok := s3Select.recordReader.(*multiObjectReader); ok {
+		r.onObjectDone = writer.sendObjectStats
+	}
+	s3Select.evaluate(writer)
+}
 
-	var outputQueue []sql.Record
+// EvaluateTo - filters records read from opened reader as per select
+// statement and writes the serialized output records to w, without any
+// event stream framing. This is used when the results are persisted as
+// an object, for example by a restore request of type SELECT.
+func (s3Select *S3Select) EvaluateTo(w io.Writer) error {
+	writer := newRawWriter(w)
+	s3Select.evaluate(writer)
+	return writer.err
+}
 
-	// Create queue based on the type.
-	if s3Select.statement.IsAggregated() {
-		outputQueue = make([]sql.Record, 0, 1)
-	} else {
-		outputQueue = make([]sql.Record, 0, 100)
-	}
+func (s3Select *S3Select) evaluate(writer recordWriter) {
+	defer s3Select.statement.Close()
+
+	// Parquet and Arrow output is encoded in batches of records, the
+	// payloads are parts of a single file or stream.
+	columnar := s3Select.columnar
+
+	outputQueue := make([]sql.Record, 0, 100)
 	var err error
 	sendRecord := func() bool {
 		buf := bufPool.Get().(*bytes.Buffer)
 		buf.Reset()
 
+		if columnar != nil {
+			if err = columnar.write(outputQueue, buf); err != nil {
+				bufPool.Put(buf)
+				return false
+			}
+		}
 		for _, outputRecord := range outputQueue {
-			if outputRecord == nil {
+			if outputRecord == nil || columnar != nil {
 				continue
 			}
 			before := buf.Len()
@@ -441,11 +581,31 @@ func (s3Select *S3Select) Evaluate(w http.ResponseWriter) {
 		return true
 	}
 
+	// finishOutput sends the end of the columnar output.
+	finishOutput := func() bool {
+		if columnar == nil {
+			return true
+		}
+		buf := bufPool.Get().(*bytes.Buffer)
+		buf.Reset()
+		if err = columnar.finish(buf); err != nil {
+			bufPool.Put(buf)
+			return false
+		}
+		if err = writer.SendRecord(buf); err != nil {
+			// FIXME: log this error.
+			err = nil
+			bufPool.Put(buf)
+			return false
+		}
+		return true
+	}
+
 	var rec sql.Record
 OuterLoop:
 	for {
 		if s3Select.statement.LimitReached() {
-			if !sendRecord() {
+			if !sendRecord() || !finishOutput() {
 				break
 			}
 			if err = writer.Finish(s3Select.getProgress()); err != nil {
@@ -457,18 +617,26 @@ OuterLoop:
 
 		if rec, err = s3Select.recordReader.Read(rec); err != nil {
 			if err != io.EOF {
+				err = s3Select.decompressionError(err)
 				break
 			}
 
-			if s3Select.statement.IsAggregated() {
-				outputRecord := s3Select.outputRecord()
-				if err = s3Select.statement.AggregateResult(outputRecord); err != nil {
-					break
-				}
+			// Output the results of aggregation and ordered
+			// queries.
+			stopped := false
+			resultsErr := s3Select.statement.Results(s3Select.outputRecord, func(outputRecord sql.Record) bool {
 				outputQueue = append(outputQueue, outputRecord)
+				if len(outputQueue) < cap(outputQueue) || sendRecord() {
+					return true
+				}
+				stopped = true
+				return false
+			})
+			if resultsErr != nil {
+				err = resultsErr
+				break
 			}
-
-			if !sendRecord() {
+			if stopped || !sendRecord() || !finishOutput() {
 				break
 			}
 
@@ -528,7 +696,12 @@ OuterLoop:
 	}
 
 	if err != nil {
-		_ = writer.FinishWithError("InternalError", err.Error())
+		var serr SelectError
+		if errors.As(err, &serr) {
+			_ = writer.FinishWithError(serr.ErrorCode(), serr.ErrorMessage())
+		} else {
+			_ = writer.FinishWithError("InternalError", err.Error())
+		}
 	}
 }
 
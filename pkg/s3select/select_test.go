@@ -26,11 +26,20 @@ import (
 	"net/http"
 	"os"
 	"reflect"
+	"strconv"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/bcicen/jstream"
+	"github.com/klauspost/compress/s2"
+	"github.com/klauspost/compress/snappy"
+	"github.com/klauspost/compress/zstd"
 	"github.com/klauspost/cpuid"
 	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio/pkg/s3select/arrow"
+	"github.com/minio/minio/pkg/s3select/json"
+	"github.com/minio/minio/pkg/s3select/parquet"
 	"github.com/minio/simdjson-go"
 )
 
@@ -236,6 +245,14 @@ func TestJSONQueries(t *testing.T) {
 {"id":1, "value": true}
 {"id":2, "value": 42}
 {"id":3, "value": "true"}
+`,
+		},
+		{
+			name:       "keyword-keys",
+			query:      `SELECT s.end, s."Case" AS c from s3object s WHERE s.mod = 1 AND "position" > 0`,
+			wantResult: `{"end":2,"c":"x"}`,
+			withJSON: `{"end":2, "Case": "x", "mod": 1, "position": 3}
+{"end":4, "Case": "y", "mod": 0, "position": 3}
 `,
 		},
 		{
@@ -546,6 +563,771 @@ func TestCSVQueries(t *testing.T) {
 	}
 }
 
+func TestEvaluateTo(t *testing.T) {
+	input := `id,name,size
+1,alpha,100
+2,beta,200
+3,gamma,300`
+
+	var testTable = []struct {
+		name       string
+		query      string
+		wantResult string
+		wantErr    bool
+	}{
+		{
+			name:       "select-filter",
+			query:      `SELECT name FROM S3Object s WHERE CAST(s.size AS INT) > 150`,
+			wantResult: "beta\ngamma\n",
+		},
+		{
+			name:       "select-aggregate",
+			query:      `SELECT COUNT(*) FROM S3Object`,
+			wantResult: "3\n",
+		},
+		{
+			name:    "select-eval-error",
+			query:   `SELECT CAST(s.name AS INT) FROM S3Object s`,
+			wantErr: true,
+		},
+	}
+
+	defRequest := `<?xml version="1.0" encoding="UTF-8"?>
+<SelectObjectContentRequest>
+    <Expression>%s</Expression>
+    <ExpressionType>SQL</ExpressionType>
+    <InputSerialization>
+        <CompressionType>NONE</CompressionType>
+        <CSV>
+        	<FileHeaderInfo>USE</FileHeaderInfo>
+        </CSV>
+    </InputSerialization>
+    <OutputSerialization>
+        <CSV>
+        </CSV>
+    </OutputSerialization>
+</SelectObjectContentRequest>`
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			s3Select, err := NewS3Select(bytes.NewReader([]byte(fmt.Sprintf(defRequest, testCase.query))))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if err = s3Select.Open(func(offset, length int64) (io.ReadCloser, error) {
+				return ioutil.NopCloser(bytes.NewBufferString(input)), nil
+			}); err != nil {
+				t.Fatal(err)
+			}
+
+			var got bytes.Buffer
+			err = s3Select.EvaluateTo(&got)
+			s3Select.Close()
+			if testCase.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got output %q", got.String())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.String() != testCase.wantResult {
+				t.Errorf("received output does not match. Query: %s\ngot: %q\nwant: %q", testCase.query, got.String(), testCase.wantResult)
+			}
+		})
+	}
+}
+
+func TestGroupByOrderByQueries(t *testing.T) {
+	input := `name,dept,salary
+alice,eng,100
+bob,eng,80
+carol,sales,70
+dave,sales,90
+erin,hr,60`
+
+	var testTable = []struct {
+		name       string
+		query      string
+		wantResult string
+		wantErr    bool
+	}{
+		{
+			name:       "group-by-count",
+			query:      `SELECT s.dept, COUNT(*) FROM S3Object s GROUP BY s.dept`,
+			wantResult: "eng,2\nsales,2\nhr,1\n",
+		},
+		{
+			name:       "group-by-where",
+			query:      `SELECT s.dept, MIN(s.salary), MAX(s.salary) FROM S3Object s WHERE s.salary > 65 GROUP BY s.dept`,
+			wantResult: "eng,80,100\nsales,70,90\n",
+		},
+		{
+			name:       "group-by-having-order-by-alias",
+			query:      `SELECT s.dept, SUM(s.salary) AS total FROM S3Object s GROUP BY s.dept HAVING COUNT(*) > 1 ORDER BY total DESC`,
+			wantResult: "eng,180\nsales,160\n",
+		},
+		{
+			name:       "group-by-having-group-column",
+			query:      `SELECT COUNT(*) AS n FROM S3Object s GROUP BY s.dept HAVING s.dept != 'eng' AND COUNT(*) >= 1 ORDER BY s.dept`,
+			wantResult: "1\n2\n",
+		},
+		{
+			name:       "group-by-order-by-limit",
+			query:      `SELECT s.dept, AVG(s.salary) FROM S3Object s GROUP BY s.dept ORDER BY AVG(s.salary) LIMIT 2`,
+			wantResult: "hr,60\nsales,80\n",
+		},
+		{
+			name:       "group-by-expression",
+			query:      `SELECT UPPER(s.dept), COUNT(*) FROM S3Object s GROUP BY UPPER(s.dept) ORDER BY UPPER(s.dept)`,
+			wantResult: "ENG,2\nHR,1\nSALES,2\n",
+		},
+		{
+			name:       "order-by",
+			query:      `SELECT s.name, s.salary FROM S3Object s WHERE s.dept = 'eng' OR s.dept = 'hr' ORDER BY s.salary`,
+			wantResult: "erin,60\nbob,80\nalice,100\n",
+		},
+		{
+			name:       "order-by-multiple-limit",
+			query:      `SELECT s.name FROM S3Object s ORDER BY s.dept DESC, s.name LIMIT 3`,
+			wantResult: "carol\ndave\nerin\n",
+		},
+		{
+			name:       "aggregate-having",
+			query:      `SELECT COUNT(*) FROM S3Object s HAVING COUNT(*) > 10`,
+			wantResult: "",
+		},
+		{
+			name:    "group-by-ungrouped-column",
+			query:   `SELECT s.name, COUNT(*) FROM S3Object s GROUP BY s.dept`,
+			wantErr: true,
+		},
+		{
+			name:    "group-by-select-all",
+			query:   `SELECT * FROM S3Object s GROUP BY s.dept`,
+			wantErr: true,
+		},
+	}
+
+	defRequest := `<?xml version="1.0" encoding="UTF-8"?>
+<SelectObjectContentRequest>
+    <Expression>%s</Expression>
+    <ExpressionType>SQL</ExpressionType>
+    <InputSerialization>
+        <CompressionType>NONE</CompressionType>
+        <CSV>
+        	<FileHeaderInfo>USE</FileHeaderInfo>
+        </CSV>
+    </InputSerialization>
+    <OutputSerialization>
+        <CSV>
+        </CSV>
+    </OutputSerialization>
+</SelectObjectContentRequest>`
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			s3Select, err := NewS3Select(bytes.NewReader([]byte(fmt.Sprintf(defRequest, testCase.query))))
+			if err != nil {
+				if testCase.wantErr {
+					return
+				}
+				t.Fatal(err)
+			}
+
+			if err = s3Select.Open(func(offset, length int64) (io.ReadCloser, error) {
+				return ioutil.NopCloser(bytes.NewBufferString(input)), nil
+			}); err != nil {
+				t.Fatal(err)
+			}
+
+			var got bytes.Buffer
+			err = s3Select.EvaluateTo(&got)
+			s3Select.Close()
+			if testCase.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got output %q", got.String())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.String() != testCase.wantResult {
+				t.Errorf("received output does not match. Query: %s\ngot: %q\nwant: %q", testCase.query, got.String(), testCase.wantResult)
+			}
+		})
+	}
+}
+
+func TestScanRange(t *testing.T) {
+	csvInput := "a,b\n1,x\n22,y\n333,z\n"
+	csvCRLFInput := strings.Replace(csvInput, "\n", "\r\n", -1)
+	jsonInput := `{"a":1}` + "\n" + `{"a":22}` + "\n" + `{"a":333}` + "\n"
+
+	csvRequest := `<?xml version="1.0" encoding="UTF-8"?>
+<SelectObjectContentRequest>
+    <Expression>SELECT s.a FROM S3Object s</Expression>
+    <ExpressionType>SQL</ExpressionType>
+    <InputSerialization>
+        <CompressionType>NONE</CompressionType>
+        <CSV>
+        	<FileHeaderInfo>USE</FileHeaderInfo>
+        	<RecordDelimiter>%s</RecordDelimiter>
+        </CSV>
+    </InputSerialization>
+    <OutputSerialization>
+        <CSV>
+        </CSV>
+    </OutputSerialization>
+    <ScanRange>%s</ScanRange>
+</SelectObjectContentRequest>`
+
+	jsonRequest := `<?xml version="1.0" encoding="UTF-8"?>
+<SelectObjectContentRequest>
+    <Expression>SELECT s.a FROM S3Object s</Expression>
+    <ExpressionType>SQL</ExpressionType>
+    <InputSerialization>
+        <CompressionType>NONE</CompressionType>
+        <JSON>
+        	<Type>LINES</Type>
+        </JSON>
+    </InputSerialization>
+    <OutputSerialization>
+        <CSV>
+        </CSV>
+    </OutputSerialization>
+    <ScanRange>%s</ScanRange>
+</SelectObjectContentRequest>`
+
+	var testTable = []struct {
+		name       string
+		input      string
+		request    string
+		scanRange  string
+		wantResult string
+		wantErr    bool
+	}{
+		{"csv-all", csvInput, fmt.Sprintf(csvRequest, "\n", "%s"), `<Start>0</Start>`, "1\n22\n333\n", false},
+		{"csv-header-only", csvInput, fmt.Sprintf(csvRequest, "\n", "%s"), `<Start>0</Start><End>3</End>`, "", false},
+		{"csv-first-byte", csvInput, fmt.Sprintf(csvRequest, "\n", "%s"), `<Start>4</Start><End>4</End>`, "1\n", false},
+		{"csv-middle", csvInput, fmt.Sprintf(csvRequest, "\n", "%s"), `<Start>5</Start><End>9</End>`, "22\n", false},
+		{"csv-record-end", csvInput, fmt.Sprintf(csvRequest, "\n", "%s"), `<Start>1</Start><End>7</End>`, "1\n", false},
+		{"csv-start-only", csvInput, fmt.Sprintf(csvRequest, "\n", "%s"), `<Start>8</Start>`, "22\n333\n", false},
+		{"csv-end-only", csvInput, fmt.Sprintf(csvRequest, "\n", "%s"), `<End>6</End>`, "333\n", false},
+		{"csv-beyond-end", csvInput, fmt.Sprintf(csvRequest, "\n", "%s"), `<Start>100</Start>`, "", false},
+		{"csv-crlf", csvCRLFInput, fmt.Sprintf(csvRequest, "\r\n", "%s"), `<Start>9</Start><End>10</End>`, "22\n", false},
+		{"csv-crlf-boundary", csvCRLFInput, fmt.Sprintf(csvRequest, "\r\n", "%s"), `<Start>8</Start><End>9</End>`, "", false},
+		{"csv-custom-delimiter", strings.Replace(csvInput, "\n", ";", -1), fmt.Sprintf(csvRequest, ";", "%s"), `<Start>4</Start><End>8</End>`, "1\n22\n", false},
+		{"json-lines", jsonInput, jsonRequest, `<Start>1</Start><End>8</End>`, "22\n", false},
+		{"json-lines-end-only", jsonInput, jsonRequest, `<End>10</End>`, "333\n", false},
+		{"invalid-range", csvInput, fmt.Sprintf(csvRequest, "\n", "%s"), `<Start>5</Start><End>4</End>`, "", true},
+		{"invalid-json-document", jsonInput, strings.Replace(jsonRequest, "LINES", "DOCUMENT", 1), `<Start>0</Start>`, "", true},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			input := []byte(testCase.input)
+			s3Select, err := NewS3Select(strings.NewReader(fmt.Sprintf(testCase.request, testCase.scanRange)))
+			if err != nil {
+				if testCase.wantErr {
+					return
+				}
+				t.Fatal(err)
+			}
+			if testCase.wantErr {
+				t.Fatal("expected an error")
+			}
+			s3Select.SetObjectSize(int64(len(input)))
+
+			if err = s3Select.Open(func(offset, length int64) (io.ReadCloser, error) {
+				if offset < 0 {
+					offset += int64(len(input))
+				}
+				if offset > int64(len(input)) {
+					offset = int64(len(input))
+				}
+				return ioutil.NopCloser(bytes.NewReader(input[offset:])), nil
+			}); err != nil {
+				t.Fatal(err)
+			}
+
+			var got bytes.Buffer
+			err = s3Select.EvaluateTo(&got)
+			s3Select.Close()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.String() != testCase.wantResult {
+				t.Errorf("received output does not match. ScanRange: %s\ngot: %q\nwant: %q", testCase.scanRange, got.String(), testCase.wantResult)
+			}
+		})
+	}
+
+	// Consecutive ranges return every record exactly once.
+	for _, input := range []string{csvInput, csvCRLFInput} {
+		delim := "\n"
+		if strings.Contains(input, "\r") {
+			delim = "\r\n"
+		}
+		for size := 1; size <= len(input); size++ {
+			var got bytes.Buffer
+			for start := 0; start < len(input); start += size {
+				scanRange := fmt.Sprintf("<Start>%d</Start><End>%d</End>", start, start+size-1)
+				s3Select, err := NewS3Select(strings.NewReader(fmt.Sprintf(csvRequest, delim, scanRange)))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if err = s3Select.Open(func(offset, length int64) (io.ReadCloser, error) {
+					return ioutil.NopCloser(strings.NewReader(input[offset:])), nil
+				}); err != nil {
+					t.Fatal(err)
+				}
+				err = s3Select.EvaluateTo(&got)
+				s3Select.Close()
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+			if got.String() != "1\n22\n333\n" {
+				t.Errorf("ranges of %d bytes with delimiter %q returned %q", size, delim, got.String())
+			}
+		}
+	}
+}
+
+func TestCompressedInput(t *testing.T) {
+	input := []byte("a,b\n1,x\n22,y\n333,z\n")
+
+	requestXML := `<?xml version="1.0" encoding="UTF-8"?>
+<SelectObjectContentRequest>
+    <Expression>SELECT s.a FROM S3Object s</Expression>
+    <ExpressionType>SQL</ExpressionType>
+    <InputSerialization>
+        <CompressionType>%s</CompressionType>
+        <CSV>
+        	<FileHeaderInfo>USE</FileHeaderInfo>
+        </CSV>
+    </InputSerialization>
+    <OutputSerialization>
+        <CSV>
+        </CSV>
+    </OutputSerialization>
+</SelectObjectContentRequest>`
+
+	compress := func(newWriter func(w io.Writer) io.WriteCloser) []byte {
+		var buf bytes.Buffer
+		w := newWriter(&buf)
+		if _, err := w.Write(input); err != nil {
+			t.Fatal(err)
+		}
+		if err := w.Close(); err != nil {
+			t.Fatal(err)
+		}
+		return buf.Bytes()
+	}
+	zstdData := compress(func(w io.Writer) io.WriteCloser {
+		zw, err := zstd.NewWriter(w)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return zw
+	})
+	snappyData := compress(func(w io.Writer) io.WriteCloser { return snappy.NewBufferedWriter(w) })
+	s2Data := compress(func(w io.Writer) io.WriteCloser { return s2.NewWriter(w) })
+
+	var testTable = []struct {
+		name             string
+		compression      string
+		data             []byte
+		serverCompressed bool
+		wantErr          bool
+	}{
+		{"zstd", "ZSTD", zstdData, false, false},
+		{"snappy", "SNAPPY", snappyData, false, false},
+		{"s2", "S2", s2Data, false, false},
+		{"s2-snappy-stream", "S2", snappyData, false, false},
+		{"s2-server-compressed", "S2", input, true, false},
+		{"s2-server-compressed-stream", "S2", s2Data, true, false},
+		{"zstd-invalid", "ZSTD", input, false, true},
+		{"s2-invalid", "S2", input, false, true},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			s3Select, err := NewS3Select(strings.NewReader(fmt.Sprintf(requestXML, testCase.compression)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			s3Select.SetServerCompressed(testCase.serverCompressed)
+
+			err = s3Select.Open(func(offset, length int64) (io.ReadCloser, error) {
+				return ioutil.NopCloser(bytes.NewReader(testCase.data)), nil
+			})
+			if testCase.wantErr {
+				if serr, ok := err.(SelectError); !ok || serr.ErrorCode() != "InvalidCompressionFormat" {
+					t.Fatalf("expected InvalidCompressionFormat error, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got bytes.Buffer
+			err = s3Select.EvaluateTo(&got)
+			scanned, processed := s3Select.getProgress()
+			s3Select.Close()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.String() != "1\n22\n333\n" {
+				t.Errorf("received output does not match: %q", got.String())
+			}
+			if scanned != int64(len(testCase.data)) || processed != int64(len(input)) {
+				t.Errorf("got %d bytes scanned and %d processed, want %d and %d", scanned, processed, len(testCase.data), len(input))
+			}
+		})
+	}
+}
+
+func TestCompressedInputErrors(t *testing.T) {
+	requestXML := `<?xml version="1.0" encoding="UTF-8"?>
+<SelectObjectContentRequest>
+    <Expression>SELECT s.a FROM S3Object s</Expression>
+    <ExpressionType>SQL</ExpressionType>
+    <InputSerialization>
+        <CompressionType>%s</CompressionType>
+        <JSON>
+            <Type>%s</Type>
+        </JSON>
+    </InputSerialization>
+    <OutputSerialization>
+        <JSON>
+        </JSON>
+    </OutputSerialization>
+</SelectObjectContentRequest>`
+
+	input := []byte(`{"a":1}` + "\n" + `{"a":2}` + "\n")
+	// A ZSTD frame declaring a 128MiB window, holding the input as a
+	// single raw block.
+	largeWindowData := []byte{0x28, 0xb5, 0x2f, 0xfd, 0x00, (27 - 10) << 3}
+	blockHeader := uint32(len(input))<<3 | 1
+	largeWindowData = append(largeWindowData, byte(blockHeader), byte(blockHeader>>8), byte(blockHeader>>16))
+	largeWindowData = append(largeWindowData, input...)
+
+	var testTable = []struct {
+		name        string
+		compression string
+		jsonType    string
+		data        []byte
+	}{
+		{"zstd-document", "ZSTD", "DOCUMENT", input},
+		{"zstd-lines", "ZSTD", "LINES", input},
+		{"zstd-window-exceeded", "ZSTD", "LINES", largeWindowData},
+		{"s2-document", "S2", "DOCUMENT", input},
+		{"snappy-lines", "SNAPPY", "LINES", input},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			s3Select, err := NewS3Select(strings.NewReader(fmt.Sprintf(requestXML, testCase.compression, testCase.jsonType)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = s3Select.Open(func(offset, length int64) (io.ReadCloser, error) {
+				return ioutil.NopCloser(bytes.NewReader(testCase.data)), nil
+			})
+			if err == nil {
+				err = s3Select.EvaluateTo(ioutil.Discard)
+				s3Select.Close()
+			}
+			if err == nil || !strings.HasPrefix(err.Error(), "InvalidCompressionFormat") {
+				t.Fatalf("expected InvalidCompressionFormat error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestColumnarOutput(t *testing.T) {
+	input := `{"name":"a","n":1,"f":1.5,"ok":true,"tags":["x","y"]}
+{"name":"b","n":null,"f":2,"ok":false,"tags":null}
+{"name":"c","n":-3,"f":null,"ok":null,"tags":[]}
+`
+
+	requestXML := `<?xml version="1.0" encoding="UTF-8"?>
+<SelectObjectContentRequest>
+    <Expression>%s</Expression>
+    <ExpressionType>SQL</ExpressionType>
+    <InputSerialization>
+        <CompressionType>NONE</CompressionType>
+        <JSON>
+            <Type>LINES</Type>
+        </JSON>
+    </InputSerialization>
+    <OutputSerialization>
+        <%s/>
+    </OutputSerialization>
+    <RequestProgress>
+        <Enabled>FALSE</Enabled>
+    </RequestProgress>
+</SelectObjectContentRequest>`
+
+	evaluate := func(t *testing.T, query, format string, framed bool) []byte {
+		t.Helper()
+
+		s3Select, err := NewS3Select(strings.NewReader(fmt.Sprintf(requestXML, query, format)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = s3Select.Open(func(offset, length int64) (io.ReadCloser, error) {
+			return ioutil.NopCloser(strings.NewReader(input)), nil
+		}); err != nil {
+			t.Fatal(err)
+		}
+		defer s3Select.Close()
+
+		if !framed {
+			var got bytes.Buffer
+			if err = s3Select.EvaluateTo(&got); err != nil {
+				t.Fatal(err)
+			}
+			return got.Bytes()
+		}
+
+		w := &testResponseWriter{}
+		s3Select.Evaluate(w)
+		resp := http.Response{
+			StatusCode:    http.StatusOK,
+			Body:          ioutil.NopCloser(bytes.NewReader(w.response)),
+			ContentLength: int64(len(w.response)),
+		}
+		res, err := minio.NewSelectResults(&resp, "testbucket")
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := ioutil.ReadAll(res)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return got
+	}
+
+	const query = "SELECT s.name, s.n, s.f, s.ok, s.tags, CHAR_LENGTH(s.name) AS l, CAST('2021-01-02T03:04:05Z' AS TIMESTAMP) AS ts FROM S3Object s"
+	ts := time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	t.Run("parquet", func(t *testing.T) {
+		got := evaluate(t, query, "Parquet", false)
+		if framed := evaluate(t, query, "Parquet", true); !bytes.Equal(framed, got) {
+			t.Fatal("records of the event stream differ from the output")
+		}
+
+		r, err := parquet.NewReader(func(offset, length int64) (io.ReadCloser, error) {
+			if offset < 0 {
+				offset = int64(len(got)) + offset
+			}
+			return ioutil.NopCloser(bytes.NewReader(got[offset:])), nil
+		}, &parquet.ReaderArgs{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer r.Close()
+
+		micros := ts.UnixNano() / int64(time.Microsecond)
+		want := []jstream.KVS{
+			{{Key: "name", Value: "a"}, {Key: "n", Value: 1.0}, {Key: "f", Value: 1.5}, {Key: "ok", Value: "true"},
+				{Key: "tags", Value: `["x","y"]`}, {Key: "l", Value: int64(1)}, {Key: "ts", Value: micros}},
+			{{Key: "name", Value: "b"}, {Key: "n", Value: nil}, {Key: "f", Value: 2.0}, {Key: "ok", Value: "false"},
+				{Key: "tags", Value: nil}, {Key: "l", Value: int64(1)}, {Key: "ts", Value: micros}},
+			{{Key: "name", Value: "c"}, {Key: "n", Value: -3.0}, {Key: "f", Value: nil}, {Key: "ok", Value: nil},
+				{Key: "tags", Value: `[]`}, {Key: "l", Value: int64(1)}, {Key: "ts", Value: micros}},
+		}
+		for i := range want {
+			rec, err := r.Read(nil)
+			if err != nil {
+				t.Fatalf("record %d: %v", i, err)
+			}
+			if got := rec.(*json.Record).KVS; !reflect.DeepEqual(got, want[i]) {
+				t.Fatalf("record %d: expected %v, got %v", i, want[i], got)
+			}
+		}
+		if _, err = r.Read(nil); err != io.EOF {
+			t.Fatalf("expected EOF, got %v", err)
+		}
+	})
+
+	arrowStream := func(t *testing.T, columns []arrow.Column, records [][]interface{}) []byte {
+		t.Helper()
+
+		var buf bytes.Buffer
+		w, err := arrow.NewWriter(&buf, columns, columnarBatchSize)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, record := range records {
+			if err = w.Write(record...); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err = w.Close(); err != nil {
+			t.Fatal(err)
+		}
+		return buf.Bytes()
+	}
+
+	arrowCases := []struct {
+		name    string
+		query   string
+		columns []arrow.Column
+		records [][]interface{}
+	}{
+		{
+			name:  "projection",
+			query: query,
+			columns: []arrow.Column{
+				{Name: "name", Type: arrow.String},
+				{Name: "n", Type: arrow.Float64},
+				{Name: "f", Type: arrow.Float64},
+				{Name: "ok", Type: arrow.String},
+				{Name: "tags", Type: arrow.String},
+				{Name: "l", Type: arrow.Int64},
+				{Name: "ts", Type: arrow.Timestamp},
+			},
+			records: [][]interface{}{
+				{"a", 1.0, 1.5, "true", `["x","y"]`, int64(1), ts},
+				{"b", nil, 2.0, "false", nil, int64(1), ts},
+				{"c", -3.0, nil, nil, `[]`, int64(1), ts},
+			},
+		},
+		{
+			name:  "cast",
+			query: "SELECT CAST(s.name AS STRING) AS name, CAST(s.f AS INT) AS f FROM S3Object s LIMIT 2",
+			columns: []arrow.Column{
+				{Name: "name", Type: arrow.String},
+				{Name: "f", Type: arrow.Int64},
+			},
+			records: [][]interface{}{
+				{"a", int64(1)},
+				{"b", int64(2)},
+			},
+		},
+		{
+			name:  "aggregation",
+			query: "SELECT COUNT(*) AS total, MAX(s.f) FROM S3Object s",
+			columns: []arrow.Column{
+				{Name: "total", Type: arrow.Int64},
+				{Name: "_2", Type: arrow.Float64},
+			},
+			records: [][]interface{}{{int64(3), 2.0}},
+		},
+		{
+			name:  "empty",
+			query: "SELECT s.name FROM S3Object s WHERE s.name = 'd'",
+			columns: []arrow.Column{
+				{Name: "name", Type: arrow.String},
+			},
+		},
+	}
+	for _, testCase := range arrowCases {
+		t.Run("arrow-"+testCase.name, func(t *testing.T) {
+			want := arrowStream(t, testCase.columns, testCase.records)
+			if got := evaluate(t, testCase.query, "Arrow", false); !bytes.Equal(got, want) {
+				t.Fatalf("unexpected output\ngot:  %x\nwant: %x", got, want)
+			}
+			if got := evaluate(t, testCase.query, "Arrow", true); !bytes.Equal(got, want) {
+				t.Fatal("records of the event stream differ from the expected output")
+			}
+		})
+	}
+
+	t.Run("select-all", func(t *testing.T) {
+		query := "SELECT * FROM S3Object s"
+		if _, err := NewS3Select(strings.NewReader(fmt.Sprintf(requestXML, query, "Arrow"))); err == nil {
+			t.Fatal("expected SELECT * of JSON input to be rejected")
+		}
+	})
+}
+
+func TestColumnarOutputWidening(t *testing.T) {
+	// The values of the first batch of records are integers and do
+	// not have the key w, the later records are of other types.
+	var input strings.Builder
+	for i := 0; i < 150; i++ {
+		switch {
+		case i < 120:
+			fmt.Fprintf(&input, `{"v":%d}`+"\n", i)
+		case i%3 == 0:
+			fmt.Fprintf(&input, `{"v":%d.5,"w":true}`+"\n", i)
+		case i%3 == 1:
+			fmt.Fprintf(&input, `{"v":"x%d","w":%d}`+"\n", i, i)
+		default:
+			fmt.Fprintf(&input, `{"v":[%d],"w":"%d"}`+"\n", i, i)
+		}
+	}
+
+	requestXML := `<?xml version="1.0" encoding="UTF-8"?>
+<SelectRequest>
+    <Expression>SELECT s.v, s.w FROM S3Object s</Expression>
+    <ExpressionType>SQL</ExpressionType>
+    <InputSerialization>
+        <JSON>
+            <Type>LINES</Type>
+        </JSON>
+    </InputSerialization>
+    <OutputSerialization>
+        <Arrow/>
+    </OutputSerialization>
+</SelectRequest>`
+
+	columns := []arrow.Column{
+		{Name: "v", Type: arrow.Float64},
+		{Name: "w", Type: arrow.String},
+	}
+	var buf bytes.Buffer
+	w, err := arrow.NewWriter(&buf, columns, columnarBatchSize)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 150; i++ {
+		var record []interface{}
+		switch {
+		case i < 120:
+			record = []interface{}{float64(i), nil}
+		case i%3 == 0:
+			record = []interface{}{float64(i) + 0.5, "true"}
+		default:
+			record = []interface{}{nil, strconv.Itoa(i)}
+		}
+		if err = w.Write(record...); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err = w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	s3Select, err := NewS3Select(strings.NewReader(requestXML))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = s3Select.Open(func(offset, length int64) (io.ReadCloser, error) {
+		return ioutil.NopCloser(strings.NewReader(input.String())), nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	defer s3Select.Close()
+
+	var got bytes.Buffer
+	if err = s3Select.EvaluateTo(&got); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got.Bytes(), buf.Bytes()) {
+		t.Fatalf("unexpected output\ngot:  %x\nwant: %x", got.Bytes(), buf.Bytes())
+	}
+}
+
 func TestCSVQueries2(t *testing.T) {
 	input := `id,time,num,num2,text
 1,2010-01-01T,7867786,4565.908123,"a text, with comma"
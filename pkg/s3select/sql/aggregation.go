@@ -149,146 +149,38 @@ func (e *FuncExpr) evalAggregationNode(r Record) error {
 	return err
 }
 
-func (e *AliasedExpression) aggregateRow(r Record) error {
-	return e.Expression.aggregateRow(r)
-}
-
-func (e *Expression) aggregateRow(r Record) error {
-	for _, ex := range e.And {
-		err := ex.aggregateRow(r)
-		if err != nil {
-			return err
-		}
-	}
-	return nil
-}
-
-func (e *ListExpr) aggregateRow(r Record) error {
-	for _, ex := range e.Elements {
-		err := ex.aggregateRow(r)
-		if err != nil {
-			return err
-		}
-	}
-	return nil
-}
-
-func (e *AndCondition) aggregateRow(r Record) error {
-	for _, ex := range e.Condition {
-		err := ex.aggregateRow(r)
-		if err != nil {
-			return err
-		}
-	}
-	return nil
-}
-
-func (e *Condition) aggregateRow(r Record) error {
-	if e.Operand != nil {
-		return e.Operand.aggregateRow(r)
-	}
-	return e.Not.aggregateRow(r)
-}
-
-func (e *ConditionOperand) aggregateRow(r Record) error {
-	err := e.Operand.aggregateRow(r)
-	if err != nil {
-		return err
-	}
+// merge combines the partial aggregation o of the function fn into a.
+func (a *aggVal) merge(fn FuncName, o *aggVal) (err error) {
+	isFirst := !a.seen
+	a.seen = a.seen || o.seen
 
-	if e.ConditionRHS == nil {
-		return nil
-	}
+	switch fn {
+	case aggFnCount:
+		a.runningCount += o.runningCount
 
-	switch {
-	case e.ConditionRHS.Compare != nil:
-		return e.ConditionRHS.Compare.Operand.aggregateRow(r)
-	case e.ConditionRHS.Between != nil:
-		err = e.ConditionRHS.Between.Start.aggregateRow(r)
-		if err != nil {
-			return err
-		}
-		return e.ConditionRHS.Between.End.aggregateRow(r)
-	case e.ConditionRHS.In != nil:
-		elt := e.ConditionRHS.In.ListExpression
-		err = elt.aggregateRow(r)
-		if err != nil {
-			return err
-		}
-		return nil
-	case e.ConditionRHS.Like != nil:
-		err = e.ConditionRHS.Like.Pattern.aggregateRow(r)
-		if err != nil {
-			return err
-		}
-		return e.ConditionRHS.Like.EscapeChar.aggregateRow(r)
-	default:
-		return errInvalidASTNode
-	}
-}
+	case aggFnAvg, aggFnSum:
+		a.runningCount += o.runningCount
+		err = a.runningSum.arithOp(opPlus, o.runningSum)
 
-func (e *Operand) aggregateRow(r Record) error {
-	err := e.Left.aggregateRow(r)
-	if err != nil {
-		return err
-	}
-	for _, rt := range e.Right {
-		err = rt.Right.aggregateRow(r)
-		if err != nil {
-			return err
+	case aggFnMin:
+		if o.seen {
+			err = a.runningMin.minmax(o.runningMin, false, isFirst)
 		}
-	}
-	return nil
-}
 
-func (e *MultOp) aggregateRow(r Record) error {
-	err := e.Left.aggregateRow(r)
-	if err != nil {
-		return err
-	}
-	for _, rt := range e.Right {
-		err = rt.Right.aggregateRow(r)
-		if err != nil {
-			return err
+	case aggFnMax:
+		if o.seen {
+			err = a.runningMax.minmax(o.runningMax, true, isFirst)
 		}
-	}
-	return nil
-}
 
-func (e *UnaryTerm) aggregateRow(r Record) error {
-	if e.Negated != nil {
-		return e.Negated.Term.aggregateRow(r)
-	}
-	return e.Primary.aggregateRow(r)
-}
-
-func (e *PrimaryTerm) aggregateRow(r Record) error {
-	switch {
-	case e.ListExpr != nil:
-		return e.ListExpr.aggregateRow(r)
-	case e.SubExpression != nil:
-		return e.SubExpression.aggregateRow(r)
-	case e.FuncCall != nil:
-		return e.FuncCall.aggregateRow(r)
-	}
-	return nil
-}
-
-func (e *FuncExpr) aggregateRow(r Record) error {
-	switch e.getFunctionName() {
-	case aggFnAvg, aggFnSum, aggFnMax, aggFnMin, aggFnCount:
-		return e.evalAggregationNode(r)
 	default:
-		// TODO: traverse arguments and call aggregateRow on
-		// them if they could be an ancestor of an
-		// aggregation.
+		err = errInvalidAggregation
 	}
-	return nil
+	return err
 }
 
 // getAggregate() implementation for each AST node follows. This is
-// called after calling aggregateRow() on each input row, to calculate
-// the final aggregate result.
+// called after calling evalAggregationNode() on each input row, to
+// calculate the final aggregate result.
 
 func (e *FuncExpr) getAggregate() (*Value, error) {
 	switch e.getFunctionName() {
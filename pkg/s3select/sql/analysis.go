@@ -19,6 +19,8 @@ package sql
 import (
 	"errors"
 	"fmt"
+	"regexp"
+	"time"
 )
 
 // Query analysis - The query is analyzed to determine if it involves
@@ -149,6 +151,12 @@ func (e *Operand) analyze(s *Select) (result qProp) {
 	for _, r := range e.Right {
 		result.combine(r.Right.analyze(s))
 	}
+	for _, c := range e.Concat {
+		result.combine(c.Left.analyze(s))
+		for _, r := range c.Right {
+			result.combine(r.Right.analyze(s))
+		}
+	}
 	return
 }
 
@@ -182,7 +190,10 @@ func (e *PrimaryTerm) analyze(s *Select) (result qProp) {
 				return
 			}
 		}
-		result = qProp{isRowFunc: true}
+		// In grouped queries path expressions outside of
+		// aggregations refer to the columns of the group, so
+		// they may be combined with aggregations.
+		result = qProp{isRowFunc: len(s.GroupBy) == 0}
 
 	case e.ListExpr != nil:
 		result = e.ListExpr.analyze(s)
@@ -193,12 +204,94 @@ func (e *PrimaryTerm) analyze(s *Select) (result qProp) {
 	case e.FuncCall != nil:
 		result = e.FuncCall.analyze(s)
 
+	case e.Case != nil:
+		result = e.Case.analyze(s)
+
 	default:
 		result = qProp{err: errUnexpectedInvalidNode}
 	}
 	return
 }
 
+func (e *CaseExpr) analyze(s *Select) (result qProp) {
+	if e.Operand != nil {
+		result.combine(e.Operand.analyze(s))
+	}
+	for _, when := range e.When {
+		result.combine(when.Condition.analyze(s))
+		result.combine(when.Result.analyze(s))
+	}
+	if e.Else != nil {
+		result.combine(e.Else.analyze(s))
+	}
+	return
+}
+
+// Argument types checked for literal function arguments.
+type argType int
+
+const (
+	argAny argType = iota
+	argString
+	argNumeric
+	argInteger
+)
+
+// checkLiteralArg returns an error if the argument is a literal that
+// does not have the type expected by the function.
+func checkLiteralArg(funcName FuncName, lit *LitValue, t argType) error {
+	if lit == nil || lit.Null {
+		return nil
+	}
+	switch t {
+	case argString:
+		if lit.String == nil {
+			return fmt.Errorf("%s expects string arguments", funcName)
+		}
+	case argNumeric:
+		if lit.Number == nil {
+			return fmt.Errorf("%s expects numeric arguments", funcName)
+		}
+	case argInteger:
+		if lit.Number == nil || *lit.Number != float64(int64(*lit.Number)) {
+			return fmt.Errorf("%s expects an integer argument", funcName)
+		}
+	}
+	return nil
+}
+
+// analyzeArgs analyzes the arguments of a simple argument function
+// call, of which there must be between min and max, a negative max
+// allows any number. The literal arguments are checked against the
+// types, the last type applies to all remaining arguments.
+func (e *FuncExpr) analyzeArgs(s *Select, min, max int, types ...argType) (result qProp) {
+	funcName := e.getFunctionName()
+	args := e.SFunc.ArgsList
+	switch {
+	case len(args) < min && min == max:
+		return qProp{err: fmt.Errorf("%s needs exactly %d argument(s)", funcName, min)}
+	case len(args) < min:
+		return qProp{err: fmt.Errorf("%s needs at least %d argument(s)", funcName, min)}
+	case max >= 0 && len(args) > max:
+		return qProp{err: fmt.Errorf("%s takes at most %d argument(s)", funcName, max)}
+	}
+
+	for i, arg := range args {
+		t := argAny
+		if len(types) > 0 {
+			t = types[len(types)-1]
+			if i < len(types) {
+				t = types[i]
+			}
+		}
+		if err := checkLiteralArg(funcName, getLiteral(arg), t); err != nil {
+			return qProp{err: err}
+		}
+		result.combine(arg.analyze(s))
+	}
+	return result
+}
+
 func (e *FuncExpr) analyze(s *Select) (result qProp) {
 	funcName := e.getFunctionName()
 
@@ -222,6 +315,9 @@ func (e *FuncExpr) analyze(s *Select) (result qProp) {
 	// Handle aggregation function calls
 	case aggFnAvg, aggFnMax, aggFnMin, aggFnSum, aggFnCount:
 		// Initialize accumulator
+		if e.aggregate == nil {
+			s.aggregates = append(s.aggregates, e)
+		}
 		e.aggregate = newAggVal(funcName)
 
 		var exprA qProp
@@ -315,6 +411,56 @@ func (e *FuncExpr) analyze(s *Select) (result qProp) {
 			result.err = fmt.Errorf("%s() takes no arguments", string(funcName))
 		}
 		return result
+
+	case sqlFnConcat:
+		return e.analyzeArgs(s, 1, -1)
+
+	case sqlFnReplace:
+		return e.analyzeArgs(s, 3, 3, argString)
+
+	case sqlFnRegexpLike:
+		result = e.analyzeArgs(s, 2, 2, argString)
+		if result.err != nil {
+			return result
+		}
+		// Compile a constant pattern once.
+		if lit := getLiteral(e.SFunc.ArgsList[1]); lit != nil && lit.String != nil {
+			if e.pattern, result.err = regexp.Compile(string(*lit.String)); result.err != nil {
+				result.err = fmt.Errorf("Invalid pattern for %s: %w", funcName, result.err)
+			}
+		}
+		return result
+
+	case sqlFnPosition:
+		for _, arg := range []*Operand{e.Position.Substr, e.Position.Str} {
+			if err := checkLiteralArg(funcName, arg.literal(), argString); err != nil {
+				return qProp{err: err}
+			}
+			result.combine(arg.analyze(s))
+		}
+		return result
+
+	case sqlFnAbs, sqlFnFloor, sqlFnCeil, sqlFnCeiling:
+		return e.analyzeArgs(s, 1, 1, argNumeric)
+
+	case sqlFnRound:
+		return e.analyzeArgs(s, 1, 2, argNumeric, argInteger)
+
+	case sqlFnMod:
+		return e.analyzeArgs(s, 2, 2, argNumeric)
+
+	case sqlFnToString:
+		result = e.analyzeArgs(s, 2, 2, argAny, argString)
+		if result.err != nil {
+			return result
+		}
+		// Validate a constant format.
+		if lit := getLiteral(e.SFunc.ArgsList[1]); lit != nil && lit.String != nil {
+			if _, err := formatTimestamp(time.Time{}, string(*lit.String)); err != nil {
+				result.err = err
+			}
+		}
+		return result
 	}
 
 	// TODO: implement other functions
@@ -99,6 +99,15 @@ func errBadTableName(err error) *s3Error {
 	}
 }
 
+func errQueryTooLarge(err error) *s3Error {
+	return &s3Error{
+		code:       "QueryTooLarge",
+		message:    "The GROUP BY or ORDER BY clause of the SQL expression holds more data than the server allows.",
+		statusCode: 400,
+		cause:      err,
+	}
+}
+
 func errDataSource(err error) *s3Error {
 	return &s3Error{
 		code:       "DataSourcePathUnsupported",
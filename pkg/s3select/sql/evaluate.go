@@ -21,7 +21,6 @@ import (
 	"errors"
 	"fmt"
 	"math"
-	"strings"
 
 	"github.com/bcicen/jstream"
 	"github.com/minio/simdjson-go"
@@ -305,15 +304,33 @@ func (e *In) evalInNode(r Record, lhs *Value) (*Value, error) {
 }
 
 func (e *Operand) evalNode(r Record) (*Value, error) {
-	lval, lerr := e.Left.evalNode(r)
-	if lerr != nil || len(e.Right) == 0 {
+	lval, lerr := evalAdditive(r, e.Left, e.Right)
+	if lerr != nil || len(e.Concat) == 0 {
+		return lval, lerr
+	}
+
+	// Concatenate the terms separated by ||.
+	args := []*Value{lval}
+	for _, term := range e.Concat {
+		v, err := evalAdditive(r, term.Left, term.Right)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, v)
+	}
+	return concat(args)
+}
+
+func evalAdditive(r Record, left *MultOp, right []*OpFactor) (*Value, error) {
+	lval, lerr := left.evalNode(r)
+	if lerr != nil || len(right) == 0 {
 		return lval, lerr
 	}
 
 	// Process remaining child nodes - result must be
 	// numeric. This AST node is for terms separated by + or -
 	// symbols.
-	for _, rightTerm := range e.Right {
+	for _, rightTerm := range right {
 		op := rightTerm.Op
 		rval, rerr := rightTerm.Right.evalNode(r)
 		if rerr != nil {
@@ -370,13 +387,7 @@ func (e *UnaryTerm) evalNode(r Record) (*Value, error) {
 
 func (e *JSONPath) evalNode(r Record) (*Value, error) {
 	// Strip the table name from the keypath.
-	keypath := e.String()
-	if strings.Contains(keypath, ".") {
-		ps := strings.SplitN(keypath, ".", 2)
-		if len(ps) == 2 {
-			keypath = ps[1]
-		}
-	}
+	keypath := e.keypath()
 	_, rawVal := r.Raw()
 	switch rowVal := rawVal.(type) {
 	case jstream.KVS, simdjson.Object:
@@ -459,10 +470,59 @@ func (e *PrimaryTerm) evalNode(r Record) (res *Value, err error) {
 		return e.SubExpression.evalNode(r)
 	case e.FuncCall != nil:
 		return e.FuncCall.evalNode(r)
+	case e.Case != nil:
+		return e.Case.evalNode(r)
 	}
 	return nil, errInvalidASTNode
 }
 
+// evalNode returns the result of the first WHEN branch whose value
+// equals the operand, or whose condition is true without an operand.
+// Without a matching branch the ELSE result or NULL is returned.
+func (e *CaseExpr) evalNode(r Record) (*Value, error) {
+	var operand *Value
+	if e.Operand != nil {
+		var err error
+		if operand, err = e.Operand.evalNode(r); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, when := range e.When {
+		cond, err := when.Condition.evalNode(r)
+		if err != nil {
+			return nil, err
+		}
+
+		var matched bool
+		switch {
+		case operand != nil:
+			if !operand.IsNull() && !cond.IsNull() {
+				// Compare copies, the comparison infers
+				// the types of untyped values.
+				a, b := *operand, *cond
+				if matched, err = a.compareOp(opEq, &b); err != nil {
+					return nil, err
+				}
+			}
+		case !cond.IsNull():
+			var ok bool
+			if matched, ok = cond.ToBool(); !ok {
+				return nil, errExpectedBool
+			}
+		}
+
+		if matched {
+			return when.Result.evalNode(r)
+		}
+	}
+
+	if e.Else != nil {
+		return e.Else.evalNode(r)
+	}
+	return FromNull(), nil
+}
+
 func (e *FuncExpr) evalNode(r Record) (res *Value, err error) {
 	switch e.getFunctionName() {
 	case aggFnCount, aggFnAvg, aggFnMax, aggFnMin, aggFnSum:
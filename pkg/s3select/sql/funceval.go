@@ -19,6 +19,8 @@ package sql
 import (
 	"errors"
 	"fmt"
+	"math"
+	"regexp"
 	"strconv"
 	"strings"
 	"time"
@@ -51,6 +53,18 @@ const (
 	sqlFnSubstring       FuncName = "SUBSTRING"
 	sqlFnTrim            FuncName = "TRIM"
 	sqlFnUpper           FuncName = "UPPER"
+	sqlFnConcat          FuncName = "CONCAT"
+	sqlFnReplace         FuncName = "REPLACE"
+	sqlFnRegexpLike      FuncName = "REGEXP_LIKE"
+	sqlFnPosition        FuncName = "POSITION"
+
+	// Math
+	sqlFnAbs     FuncName = "ABS"
+	sqlFnRound   FuncName = "ROUND"
+	sqlFnFloor   FuncName = "FLOOR"
+	sqlFnCeil    FuncName = "CEIL"
+	sqlFnCeiling FuncName = "CEILING"
+	sqlFnMod     FuncName = "MOD"
 )
 
 var (
@@ -77,6 +91,8 @@ func (e *FuncExpr) getFunctionName() FuncName {
 		return sqlFnDateAdd
 	case e.DateDiff != nil:
 		return sqlFnDateDiff
+	case e.Position != nil:
+		return sqlFnPosition
 	default:
 		return ""
 	}
@@ -107,6 +123,9 @@ func (e *FuncExpr) evalSQLFnNode(r Record) (res *Value, err error) {
 	case sqlFnDateDiff:
 		return handleDateDiff(r, e.DateDiff)
 
+	case sqlFnPosition:
+		return handleSQLPosition(r, e.Position)
+
 	}
 
 	// For all simple argument functions, we evaluate the arguments here
@@ -137,7 +156,32 @@ func (e *FuncExpr) evalSQLFnNode(r Record) (res *Value, err error) {
 	case sqlFnUTCNow:
 		return handleUTCNow()
 
-	case sqlFnToString, sqlFnToTimestamp:
+	case sqlFnConcat:
+		return concat(argVals)
+
+	case sqlFnReplace:
+		return replace(argVals[0], argVals[1], argVals[2])
+
+	case sqlFnRegexpLike:
+		return e.regexpLike(argVals[0], argVals[1])
+
+	case sqlFnAbs, sqlFnFloor, sqlFnCeil, sqlFnCeiling:
+		return mathFunc(e.getFunctionName(), argVals[0])
+
+	case sqlFnRound:
+		digits := FromInt(0)
+		if len(argVals) > 1 {
+			digits = argVals[1]
+		}
+		return round(argVals[0], digits)
+
+	case sqlFnMod:
+		return mod(argVals[0], argVals[1])
+
+	case sqlFnToString:
+		return toString(argVals[0], argVals[1])
+
+	case sqlFnToTimestamp:
 		// TODO: implement
 		fallthrough
 
@@ -219,6 +263,194 @@ func upperCase(v *Value) (*Value, error) {
 	return FromString(strings.ToUpper(s)), nil
 }
 
+// concat concatenates the string representations of the values, the
+// result is NULL if any value is NULL.
+func concat(args []*Value) (*Value, error) {
+	var sb strings.Builder
+	for _, arg := range args {
+		if arg.IsNull() {
+			return FromNull(), nil
+		}
+		sb.WriteString(arg.CSVString())
+	}
+	return FromString(sb.String()), nil
+}
+
+// stringArgs returns the values as strings, ok is false if any value
+// is NULL.
+func stringArgs(fn FuncName, args ...*Value) (strs []string, ok bool, err error) {
+	strs = make([]string, len(args))
+	for i, arg := range args {
+		if arg.IsNull() {
+			return nil, false, nil
+		}
+		inferTypeAsString(arg)
+		if strs[i], ok = arg.ToString(); !ok {
+			err := fmt.Errorf("%s expects string arguments", fn)
+			return nil, false, errIncorrectSQLFunctionArgumentType(err)
+		}
+	}
+	return strs, true, nil
+}
+
+func replace(v, from, to *Value) (*Value, error) {
+	args, ok, err := stringArgs(sqlFnReplace, v, from, to)
+	if !ok {
+		return FromNull(), err
+	}
+	if args[1] == "" {
+		return FromString(args[0]), nil
+	}
+	return FromString(strings.Replace(args[0], args[1], args[2], -1)), nil
+}
+
+// regexpLike reports whether the pattern matches any part of v. The
+// compiled pattern is kept for the following rows.
+func (e *FuncExpr) regexpLike(v, pattern *Value) (*Value, error) {
+	args, ok, err := stringArgs(sqlFnRegexpLike, v, pattern)
+	if !ok {
+		return FromNull(), err
+	}
+	if e.pattern == nil || e.pattern.String() != args[1] {
+		if e.pattern, err = regexp.Compile(args[1]); err != nil {
+			return nil, errIncorrectSQLFunctionArgumentType(err)
+		}
+	}
+	return FromBool(e.pattern.MatchString(args[0])), nil
+}
+
+func handleSQLPosition(r Record, e *PositionFunc) (*Value, error) {
+	substr, err := e.Substr.evalNode(r)
+	if err != nil {
+		return nil, err
+	}
+	str, err := e.Str.evalNode(r)
+	if err != nil {
+		return nil, err
+	}
+	args, ok, err := stringArgs(sqlFnPosition, substr, str)
+	if !ok {
+		return FromNull(), err
+	}
+
+	// Positions count characters starting from 1, 0 if the
+	// string does not contain the substring.
+	idx := strings.Index(args[1], args[0])
+	if idx < 0 {
+		return FromInt(0), nil
+	}
+	return FromInt(int64(len([]rune(args[1][:idx])) + 1)), nil
+}
+
+// numericArg converts an untyped value to a number, ok is false if
+// the value is NULL.
+func numericArg(fn FuncName, v *Value) (ok bool, err error) {
+	if v.IsNull() {
+		return false, nil
+	}
+	if err = inferTypeForArithOp(v); err != nil {
+		return false, err
+	}
+	if !v.isNumeric() {
+		err = fmt.Errorf("%s expects numeric arguments", fn)
+		return false, errIncorrectSQLFunctionArgumentType(err)
+	}
+	return true, nil
+}
+
+// mathFunc evaluates ABS, FLOOR and CEIL, integers are returned
+// unchanged except for the sign.
+func mathFunc(fn FuncName, v *Value) (*Value, error) {
+	if ok, err := numericArg(fn, v); !ok {
+		return FromNull(), err
+	}
+	if i, ok := v.ToInt(); ok {
+		if fn == sqlFnAbs && i < 0 {
+			i = -i
+		}
+		return FromInt(i), nil
+	}
+
+	f, _ := v.ToFloat()
+	switch fn {
+	case sqlFnAbs:
+		return FromFloat(math.Abs(f)), nil
+	case sqlFnFloor:
+		return floatToValue(math.Floor(f)), nil
+	default:
+		return floatToValue(math.Ceil(f)), nil
+	}
+}
+
+// round rounds v half away from zero to the number of decimal
+// digits, negative digits round to powers of ten.
+func round(v, digits *Value) (*Value, error) {
+	if ok, err := numericArg(sqlFnRound, v); !ok {
+		return FromNull(), err
+	}
+	if ok, err := numericArg(sqlFnRound, digits); !ok {
+		return FromNull(), err
+	}
+	d, ok := digits.ToInt()
+	if !ok {
+		err := fmt.Errorf("%s expects an integer number of digits", sqlFnRound)
+		return nil, errIncorrectSQLFunctionArgumentType(err)
+	}
+
+	if i, ok := v.ToInt(); ok {
+		if d >= 0 {
+			return FromInt(i), nil
+		}
+		f, _ := v.ToFloat()
+		scale := math.Pow(10, float64(-d))
+		return floatToValue(math.Round(f/scale) * scale), nil
+	}
+
+	f, _ := v.ToFloat()
+	if d == 0 {
+		return floatToValue(math.Round(f)), nil
+	}
+	scale := math.Pow(10, float64(d))
+	return FromFloat(math.Round(f*scale) / scale), nil
+}
+
+func mod(v1, v2 *Value) (*Value, error) {
+	if ok, err := numericArg(sqlFnMod, v1); !ok {
+		return FromNull(), err
+	}
+	if ok, err := numericArg(sqlFnMod, v2); !ok {
+		return FromNull(), err
+	}
+	res := *v1
+	if err := res.arithOp(opModulo, v2); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+func toString(v, format *Value) (*Value, error) {
+	if v.IsNull() {
+		return FromNull(), nil
+	}
+	if err := inferTypeAsTimestamp(v); err != nil {
+		return nil, err
+	}
+	t, ok := v.ToTimestamp()
+	if !ok {
+		err := fmt.Errorf("%s expects a timestamp argument", sqlFnToString)
+		return nil, errIncorrectSQLFunctionArgumentType(err)
+	}
+	args, ok, err := stringArgs(sqlFnToString, format)
+	if !ok {
+		return FromNull(), err
+	}
+	s, err := formatTimestamp(t, args[0])
+	if err != nil {
+		return nil, err
+	}
+	return FromString(s), nil
+}
+
 func handleDateAdd(r Record, d *DateAddFunc) (*Value, error) {
 	q, err := d.Quantity.evalNode(r)
 	if err != nil {
@@ -0,0 +1,193 @@
+/*
+ * MinIO Cloud Storage, (C) 2021 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package sql
+
+import (
+	"reflect"
+	"testing"
+)
+
+func evalExpression(t *testing.T, expr string) (*Value, error) {
+	t.Helper()
+
+	stmt, err := ParseSelectStatement("SELECT " + expr + " FROM S3Object s")
+	if err != nil {
+		t.Fatalf("%s: %v", expr, err)
+	}
+	input := newGroupRecord()
+	input.Set("n", FromBytes([]byte("7")))
+	input.Set("neg", FromBytes([]byte("-4")))
+	input.Set("f", FromBytes([]byte("2.45")))
+	input.Set("name", FromBytes([]byte("foo")))
+	input.Set("pat", FromBytes([]byte("(")))
+	input.Set("ts", FromBytes([]byte("2021-01-02T03:04:05.5+01:30")))
+	input.Set("empty", FromNull())
+
+	output, err := stmt.Eval(input, newGroupRecord())
+	if err != nil {
+		return nil, err
+	}
+	return output.Get(stmt.columns[0])
+}
+
+func TestFunctionEvaluation(t *testing.T) {
+	cases := []struct {
+		expr string
+		want string
+	}{
+		// Concatenation
+		{"'a' || 'b' || s.n", `"ab7":STRING`},
+		{"s.n + 1 || 'x'", `"8x":STRING`},
+		{"'x' || 2 * 3", `"x6":STRING`},
+		{"CONCAT('a', 1, TRUE, 2.5)", `"a1true2.5":STRING`},
+		{"CONCAT(s.name)", `"foo":STRING`},
+		{"CONCAT('a', s.empty)", `:NULL`},
+		{"'a' || NULL", `:NULL`},
+
+		// String functions
+		{"REPLACE('abcabc', 'b', 'xy')", `"axycaxyc":STRING`},
+		{"REPLACE(s.name, '', 'x')", `"foo":STRING`},
+		{"REPLACE(s.empty, 'a', 'b')", `:NULL`},
+		{"REGEXP_LIKE('foo123', '^[a-z]+[0-9]+$')", `true:BOOL`},
+		{"REGEXP_LIKE(s.name, 'o{3}')", `false:BOOL`},
+		{"REGEXP_LIKE(s.name, 'f' || 'o+')", `true:BOOL`},
+		{"POSITION('c' IN 'abc')", `3:INT`},
+		{"POSITION('b' IN 'aéb')", `3:INT`},
+		{"POSITION('z' IN s.name)", `0:INT`},
+		{"POSITION('' IN s.name)", `1:INT`},
+		{"POSITION(s.empty IN s.name)", `:NULL`},
+
+		// Math functions
+		{"ABS(-3)", `3:INT`},
+		{"ABS(-2.5)", `2.5:FLOAT`},
+		{"ABS(s.neg)", `4:INT`},
+		{"ROUND(2.5)", `3:INT`},
+		{"ROUND(-2.5)", `-3:INT`},
+		{"ROUND(3.14159, 2)", `3.14:FLOAT`},
+		{"ROUND(s.f, 1)", `2.5:FLOAT`},
+		{"ROUND(1250, -2)", `1300:INT`},
+		{"ROUND(7, 2)", `7:INT`},
+		{"FLOOR(2.7)", `2:INT`},
+		{"FLOOR(-2.1)", `-3:INT`},
+		{"CEIL(2.1)", `3:INT`},
+		{"CEILING(-2.7)", `-2:INT`},
+		{"MOD(10, 3)", `1:INT`},
+		{"MOD(s.n, 4)", `3:INT`},
+		{"MOD(-7, 3)", `-1:INT`},
+		{"MOD(7.5, 2)", `1.5:FLOAT`},
+		{"ABS(s.empty)", `:NULL`},
+
+		// Timestamp formatting
+		{"TO_STRING(CAST('1969-07-20T20:18:04.123Z' AS TIMESTAMP), 'MMMM d, y')", `"July 20, 1969":STRING`},
+		{"TO_STRING(CAST('1969-07-20T20:18:04.123Z' AS TIMESTAMP), 'y-MM-dd''T''HH:mm:ss.SSSX')", `"1969-07-20T20:18:04.123Z":STRING`},
+		{"TO_STRING(CAST('1969-07-20T20:18:04.123Z' AS TIMESTAMP), 'yy MMM MMMMM h:m:s a n')", `"69 Jul J 8:18:4 PM 123000000":STRING`},
+		{"TO_STRING(CAST('1969-07-20T08:18:04Z' AS TIMESTAMP), 'hh a x xx xxx')", `"08 AM +00 +0000 +00:00":STRING`},
+		{"TO_STRING(s.ts, 'X XX XXX S')", `"+0130 +0130 +01:30 5":STRING`},
+		{"TO_STRING(s.ts, '''o''''clock'' H')", `"o'clock 3":STRING`},
+
+		// CASE expressions
+		{"CASE WHEN s.n > 5 THEN 'big' ELSE 'small' END", `"big":STRING`},
+		{"CASE WHEN s.n > 10 THEN 'big' WHEN s.n > 5 THEN 'medium' END", `"medium":STRING`},
+		{"CASE WHEN s.n < 0 THEN 'negative' END", `:NULL`},
+		{"CASE WHEN s.empty THEN 1 ELSE 2 END", `2:INT`},
+		{"CASE s.name WHEN 'bar' THEN 1 WHEN 'foo' THEN 2 END", `2:INT`},
+		{"CASE s.n WHEN 7 THEN 'seven' ELSE 'other' END", `"seven":STRING`},
+		{"CASE s.empty WHEN NULL THEN 1 ELSE 0 END", `0:INT`},
+		{"CASE WHEN s.n > 5 THEN s.n * 2 END + 1", `15:INT`},
+	}
+
+	for i, tc := range cases {
+		v, err := evalExpression(t, tc.expr)
+		if err != nil {
+			t.Errorf("Case %d: %s: unexpected error %v", i+1, tc.expr, err)
+			continue
+		}
+		if got := v.Repr(); got != tc.want {
+			t.Errorf("Case %d: %s: got %s, want %s", i+1, tc.expr, got, tc.want)
+		}
+	}
+}
+
+func TestFunctionEvaluationErrors(t *testing.T) {
+	cases := []string{
+		"ABS(s.name)",
+		"MOD(s.n, 0)",
+		"ROUND(s.f, s.f)",
+		"REGEXP_LIKE(s.name, s.pat)",
+		"TO_STRING(s.name, 'y')",
+		"CASE WHEN s.name THEN 1 END",
+		"CASE s.name WHEN 1 THEN 1 END",
+	}
+	for i, expr := range cases {
+		if _, err := evalExpression(t, expr); err == nil {
+			t.Errorf("Case %d: %s: expected an error", i+1, expr)
+		}
+	}
+}
+
+func TestFunctionAnalysis(t *testing.T) {
+	cases := []struct {
+		expr  string
+		valid bool
+	}{
+		{"CONCAT(s.a, 1)", true},
+		{"CONCAT()", false},
+		{"REPLACE(s.a, 'b', 'c')", true},
+		{"REPLACE(s.a, 'b')", false},
+		{"REPLACE(1, 'b', 'c')", false},
+		{"REGEXP_LIKE(s.a, '^a.*$')", true},
+		{"REGEXP_LIKE(s.a, '(')", false},
+		{"REGEXP_LIKE(s.a, 1)", false},
+		{"POSITION('a' IN s.a)", true},
+		{"POSITION(1 IN s.a)", false},
+		{"ABS(-1)", true},
+		{"ABS('x')", false},
+		{"ABS(1, 2)", false},
+		{"ROUND(1.5, 1)", true},
+		{"ROUND(1.5, 0.5)", false},
+		{"ROUND(1, 2, 3)", false},
+		{"FLOOR(TRUE)", false},
+		{"MOD(1)", false},
+		{"MOD(s.a, NULL)", true},
+		{"TO_STRING(s.a, 'yyyy-MM-dd')", true},
+		{"TO_STRING(s.a, 'yyyyQ')", false},
+		{"TO_STRING(s.a, '''abc')", false},
+		{"TO_STRING(s.a, 1)", false},
+		{"TO_STRING(s.a)", false},
+		{"CASE WHEN s.a > 1 THEN 'x' END", true},
+		{"CASE s.a WHEN 1 THEN 'x' WHEN 2 THEN 'y' ELSE 'z' END", true},
+		{"CASE WHEN s.a > 1 THEN 'x'", false},
+		{"CASE ELSE 1 END", false},
+		{"CASE WHEN s.a > 1 THEN COUNT(*) END", false},
+	}
+	for i, tc := range cases {
+		_, err := ParseSelectStatement("SELECT " + tc.expr + " FROM S3Object s")
+		if tc.valid && err != nil {
+			t.Errorf("Case %d: %s: unexpected error %v", i+1, tc.expr, err)
+		}
+		if !tc.valid && err == nil {
+			t.Errorf("Case %d: %s: expected an error", i+1, tc.expr)
+		}
+	}
+}
+
+func TestCaseAggregation(t *testing.T) {
+	got := runStatement(t, "SELECT SUM(CASE WHEN s.v >= 500 THEN 1 ELSE 0 END), COUNT(*) FROM S3Object s", 1000)
+	if want := []string{"500,1000"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,345 @@
+/*
+ * MinIO Cloud Storage, (C) 2021 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package sql
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// Approximate memory used by a group in addition to its key.
+const (
+	groupOverhead  = 64
+	aggValOverhead = 96
+)
+
+var (
+	errGroupRecordUnsupported = errors.New("operation is not supported on grouped rows")
+	errColumnNotGrouped       = func(name string) error {
+		return fmt.Errorf("column %s must appear in the GROUP BY clause or be used in an aggregate function", name)
+	}
+)
+
+// group holds the partial aggregations of the rows sharing a key.
+type group struct {
+	// Encoded values of the GROUP BY expressions.
+	key  string
+	aggs []*aggVal
+}
+
+// groupTable accumulates the aggregations of a grouped query per
+// group. When the groups held in memory exceed spillThreshold they
+// are written to a temporary file sorted by key, the runs are merged
+// once all rows have been aggregated.
+type groupTable struct {
+	aggregates []*FuncExpr
+
+	groups map[string]*group
+	// Groups in the order they were first seen.
+	order []*group
+	size  int64
+
+	runs   []*spillFile
+	usage  *spillUsage
+	keyBuf []byte
+}
+
+func newGroupTable(aggregates []*FuncExpr, usage *spillUsage) *groupTable {
+	return &groupTable{
+		aggregates: aggregates,
+		groups:     make(map[string]*group),
+		usage:      usage,
+	}
+}
+
+// aggregateRow aggregates the input row into the group of key.
+func (t *groupTable) aggregateRow(key []*Value, input Record) error {
+	t.keyBuf = appendValues(t.keyBuf[:0], key)
+	g, ok := t.groups[string(t.keyBuf)]
+	if !ok {
+		g = &group{
+			key:  string(t.keyBuf),
+			aggs: make([]*aggVal, len(t.aggregates)),
+		}
+		for i, fn := range t.aggregates {
+			g.aggs[i] = newAggVal(fn.getFunctionName())
+		}
+		t.groups[g.key] = g
+		t.order = append(t.order, g)
+		t.size += int64(len(g.key) + groupOverhead + len(g.aggs)*aggValOverhead)
+	}
+
+	for i, fn := range t.aggregates {
+		fn.aggregate = g.aggs[i]
+		if err := fn.evalAggregationNode(input); err != nil {
+			return err
+		}
+	}
+
+	if t.size >= spillThreshold {
+		return t.spill()
+	}
+	return nil
+}
+
+// spill writes the groups held in memory to a new run.
+func (t *groupTable) spill() error {
+	run, err := newSpillFile(t.usage)
+	if err != nil {
+		return err
+	}
+	t.runs = append(t.runs, run)
+
+	sort.Slice(t.order, func(i, j int) bool {
+		return t.order[i].key < t.order[j].key
+	})
+	var entry []byte
+	for _, g := range t.order {
+		entry = appendBytes(entry[:0], []byte(g.key))
+		for _, a := range g.aggs {
+			entry = a.appendTo(entry)
+		}
+		if err = run.write(entry); err != nil {
+			return err
+		}
+	}
+
+	t.groups = make(map[string]*group)
+	t.order = nil
+	t.size = 0
+	return nil
+}
+
+// iterate calls fn with the key and the aggregations of every group.
+// Without spilled runs the groups are visited in the order they were
+// first seen, otherwise in the order of their encoded keys.
+func (t *groupTable) iterate(fn func(key []*Value, aggs []*aggVal) error) error {
+	if len(t.runs) == 0 {
+		for _, g := range t.order {
+			key, err := decodeKey([]byte(g.key))
+			if err != nil {
+				return err
+			}
+			if err = fn(key, g.aggs); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if len(t.order) > 0 {
+		if err := t.spill(); err != nil {
+			return err
+		}
+	}
+	m, err := newRunMerger(t.runs, func(a, b []byte) bool {
+		ka, _, _ := readBytes(a)
+		kb, _, _ := readBytes(b)
+		return bytes.Compare(ka, kb) < 0
+	})
+	if err != nil {
+		return err
+	}
+
+	var (
+		curKey  []byte
+		curAggs []*aggVal
+	)
+	for {
+		entry, err := m.next()
+		if err != nil && err != io.EOF {
+			return err
+		}
+		var key []byte
+		if err == nil {
+			if key, entry, err = readBytes(entry); err != nil {
+				return err
+			}
+			if curAggs != nil && bytes.Equal(key, curKey) {
+				if err = t.mergeAggs(curAggs, entry); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+
+		// A new key or the end of the runs, pass on the
+		// current group.
+		if curAggs != nil {
+			values, err := decodeKey(curKey)
+			if err != nil {
+				return err
+			}
+			if err = fn(values, curAggs); err != nil {
+				return err
+			}
+		}
+		if key == nil {
+			return nil
+		}
+		curKey, curAggs = key, make([]*aggVal, len(t.aggregates))
+		for i, f := range t.aggregates {
+			curAggs[i] = newAggVal(f.getFunctionName())
+		}
+		if err = t.mergeAggs(curAggs, entry); err != nil {
+			return err
+		}
+	}
+}
+
+// mergeAggs merges the encoded aggregations of a spilled group into aggs.
+func (t *groupTable) mergeAggs(aggs []*aggVal, buf []byte) error {
+	for i, f := range t.aggregates {
+		var (
+			a   aggVal
+			err error
+		)
+		if buf, err = a.readFrom(buf); err != nil {
+			return err
+		}
+		if err = aggs[i].merge(f.getFunctionName(), &a); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close removes the spilled runs.
+func (t *groupTable) Close() error {
+	var err error
+	for _, run := range t.runs {
+		if cerr := run.Close(); err == nil {
+			err = cerr
+		}
+	}
+	t.runs = nil
+	return err
+}
+
+// decodeKey decodes all values of an encoded group key.
+func decodeKey(buf []byte) (values []*Value, err error) {
+	for len(buf) > 0 {
+		var v *Value
+		if v, buf, err = readValue(buf); err != nil {
+			return nil, err
+		}
+		values = append(values, v)
+	}
+	return values, nil
+}
+
+// appendTo appends the encoding of the partial aggregation to buf.
+func (a *aggVal) appendTo(buf []byte) []byte {
+	buf = appendVarint(buf, a.runningCount)
+	if a.seen {
+		buf = append(buf, 1)
+	} else {
+		buf = append(buf, 0)
+	}
+	for _, v := range []*Value{a.runningSum, a.runningMin, a.runningMax} {
+		if v == nil {
+			buf = append(buf, 0)
+			continue
+		}
+		buf = appendValue(append(buf, 1), v)
+	}
+	return buf
+}
+
+// readFrom decodes a partial aggregation encoded by appendTo and
+// returns the remainder of buf.
+func (a *aggVal) readFrom(buf []byte) ([]byte, error) {
+	var n int
+	if a.runningCount, n = binary.Varint(buf); n <= 0 || len(buf) == n {
+		return nil, errInvalidEncodedValue
+	}
+	a.seen = buf[n] == 1
+	buf = buf[n+1:]
+	for _, v := range []**Value{&a.runningSum, &a.runningMin, &a.runningMax} {
+		if len(buf) == 0 {
+			return nil, errInvalidEncodedValue
+		}
+		present := buf[0] == 1
+		buf = buf[1:]
+		if !present {
+			continue
+		}
+		var err error
+		if *v, buf, err = readValue(buf); err != nil {
+			return nil, err
+		}
+	}
+	return buf, nil
+}
+
+// groupRecord resolves the path expressions of a grouped query to
+// the values of the GROUP BY expressions of a group.
+type groupRecord struct {
+	columns map[string]*Value
+}
+
+func newGroupRecord() *groupRecord {
+	return &groupRecord{columns: make(map[string]*Value)}
+}
+
+// Get returns a copy of the value, evaluation may change the type of
+// untyped values.
+func (r *groupRecord) Get(name string) (*Value, error) {
+	v, ok := r.columns[name]
+	if !ok {
+		return nil, errColumnNotGrouped(name)
+	}
+	c := *v
+	return &c, nil
+}
+
+func (r *groupRecord) Set(name string, value *Value) (Record, error) {
+	r.columns[name] = value
+	return r, nil
+}
+
+func (r *groupRecord) WriteCSV(writer io.Writer, opts WriteCSVOpts) error {
+	return errGroupRecordUnsupported
+}
+
+func (r *groupRecord) WriteJSON(writer io.Writer) error {
+	return errGroupRecordUnsupported
+}
+
+func (r *groupRecord) Clone(dst Record) Record {
+	other := newGroupRecord()
+	for k, v := range r.columns {
+		other.columns[k] = v
+	}
+	return other
+}
+
+func (r *groupRecord) Reset() {
+	r.columns = make(map[string]*Value)
+}
+
+func (r *groupRecord) Raw() (SelectObjectFormat, interface{}) {
+	return SelectFmtUnknown, r.columns
+}
+
+func (r *groupRecord) Replace(k interface{}) error {
+	return errGroupRecordUnsupported
+}
@@ -0,0 +1,200 @@
+/*
+ * MinIO Cloud Storage, (C) 2021 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package sql
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func runStatement(t *testing.T, query string, rows int) []string {
+	t.Helper()
+
+	stmt, err := ParseSelectStatement(query)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < rows; i++ {
+		input := newGroupRecord()
+		input.Set("k", FromBytes([]byte(fmt.Sprintf("key-%02d", i%37))))
+		input.Set("v", FromBytes([]byte(strconv.Itoa(i))))
+		if stmt.IsAggregated() {
+			err = stmt.AggregateRow(input)
+		} else {
+			_, err = stmt.Eval(input, newGroupRecord())
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var got []string
+	err = stmt.Results(func() Record { return newGroupRecord() }, func(output Record) bool {
+		var row []string
+		for _, name := range stmt.columns {
+			v, err := output.Get(name)
+			if err != nil {
+				t.Fatal(err)
+			}
+			row = append(row, v.CSVString())
+		}
+		got = append(got, strings.Join(row, ","))
+		return true
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return got
+}
+
+func TestGroupBySpill(t *testing.T) {
+	queries := []string{
+		"SELECT s.k, COUNT(*), SUM(s.v), MIN(s.v), MAX(s.v), AVG(s.v) FROM S3Object s GROUP BY s.k ORDER BY s.k",
+		"SELECT s.k, COUNT(*) AS n FROM S3Object s WHERE s.v > 100 GROUP BY s.k HAVING MAX(s.v) > 950 ORDER BY n DESC, s.k LIMIT 5",
+		"SELECT s.v, s.k FROM S3Object s ORDER BY s.k DESC, s.v",
+		"SELECT s.v FROM S3Object s WHERE s.v < 500 ORDER BY s.k LIMIT 20",
+	}
+
+	defer func(threshold int64) {
+		spillThreshold = threshold
+	}(spillThreshold)
+
+	for i, query := range queries {
+		spillThreshold = 64 << 20
+		want := runStatement(t, query, 1000)
+		if len(want) == 0 {
+			t.Fatalf("Case %d: no results", i+1)
+		}
+
+		// Spill every few groups and rows.
+		spillThreshold = 1000
+		got := runStatement(t, query, 1000)
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("Case %d: results differ after spilling\ngot:  %v\nwant: %v", i+1, got, want)
+		}
+	}
+}
+
+func TestSpillLimits(t *testing.T) {
+	dir := t.TempDir()
+	defer func(threshold, size int64, runs int, spillDir func() string) {
+		spillThreshold, maxSpillSize, maxSpillRuns, SpillDir = threshold, size, runs, spillDir
+	}(spillThreshold, maxSpillSize, maxSpillRuns, SpillDir)
+	spillThreshold = 1000
+	SpillDir = func() string { return dir }
+
+	testCases := []struct {
+		size int64
+		runs int
+	}{
+		{size: 2000, runs: 1000},
+		{size: 1 << 20, runs: 2},
+	}
+	for i, testCase := range testCases {
+		maxSpillSize, maxSpillRuns = testCase.size, testCase.runs
+
+		stmt, err := ParseSelectStatement("SELECT s.v FROM S3Object s ORDER BY s.k")
+		if err != nil {
+			t.Fatal(err)
+		}
+		for j := 0; err == nil && j < 1000; j++ {
+			input := newGroupRecord()
+			input.Set("k", FromBytes([]byte(fmt.Sprintf("key-%04d", j))))
+			input.Set("v", FromBytes([]byte(strconv.Itoa(j))))
+			_, err = stmt.Eval(input, newGroupRecord())
+		}
+		var serr *s3Error
+		if !errors.As(err, &serr) || serr.ErrorCode() != "QueryTooLarge" {
+			t.Fatalf("Case %d: expected QueryTooLarge, got %v", i+1, err)
+		}
+
+		files, err := ioutil.ReadDir(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(files) == 0 {
+			t.Fatalf("Case %d: expected temporary files in %s", i+1, dir)
+		}
+		stmt.Close()
+		if files, err = ioutil.ReadDir(dir); err != nil || len(files) != 0 {
+			t.Fatalf("Case %d: expected temporary files to be removed, found %d: %v", i+1, len(files), err)
+		}
+	}
+}
+
+func TestGroupByAnalysis(t *testing.T) {
+	cases := []struct {
+		query string
+		valid bool
+	}{
+		{"SELECT s.a, COUNT(*) FROM S3Object s GROUP BY s.a", true},
+		{"SELECT COUNT(*) FROM S3Object s GROUP BY s.a, s.b HAVING SUM(s.c) > 1", true},
+		{"SELECT s.a FROM S3Object s ORDER BY s.b DESC, s.c ASC LIMIT 1", true},
+		{"SELECT s.a AS x FROM S3Object s ORDER BY x", true},
+		{"SELECT COUNT(*) FROM S3Object s GROUP BY COUNT(*)", false},
+		{"SELECT * FROM S3Object s GROUP BY s.a", false},
+		{"SELECT * FROM S3Object s ORDER BY s.a", false},
+		{"SELECT s.a FROM S3Object s HAVING s.a > 1", false},
+		{"SELECT COUNT(*) FROM S3Object s HAVING s.a > 1", false},
+		{"SELECT s.a FROM S3Object s ORDER BY MAX(s.a)", false},
+		{"SELECT s.a, COUNT(*) FROM S3Object s", false},
+	}
+	for i, tc := range cases {
+		_, err := ParseSelectStatement(tc.query)
+		if tc.valid && err != nil {
+			t.Errorf("Case %d: %s: unexpected error %v", i+1, tc.query, err)
+		}
+		if !tc.valid && err == nil {
+			t.Errorf("Case %d: %s: expected an error", i+1, tc.query)
+		}
+	}
+}
+
+func TestValueEncoding(t *testing.T) {
+	values := []*Value{
+		FromNull(),
+		FromBool(true),
+		FromInt(-42),
+		FromFloat(3.5),
+		FromString("string"),
+		FromBytes([]byte("bytes")),
+		FromTimestamp(time.Date(2021, 3, 4, 5, 6, 7, 8, time.FixedZone("", 3600))),
+		FromArray([]Value{*FromInt(1), *FromString("a")}),
+	}
+	buf := appendValues(nil, values)
+	got, rest, err := readValues(buf, len(values))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rest) != 0 {
+		t.Fatalf("%d bytes left after decoding", len(rest))
+	}
+	for i := range values {
+		if got[i].Repr() != values[i].Repr() {
+			t.Errorf("Case %d: got %s, want %s", i+1, got[i].Repr(), values[i].Repr())
+		}
+	}
+	if _, _, err = readValues(buf[:len(buf)-1], len(values)); err == nil {
+		t.Error("expected an error decoding a truncated value")
+	}
+}
@@ -0,0 +1,296 @@
+/*
+ * MinIO Cloud Storage, (C) 2021 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package sql
+
+import (
+	"bytes"
+	"io"
+	"sort"
+	"strings"
+	"time"
+)
+
+const sortDesc = "DESC"
+
+// Approximate memory used by a buffered row in addition to its
+// encoded values, and by each of its keys.
+const (
+	sortRowOverhead = 48
+	sortKeyOverhead = 32
+)
+
+// sortRow is a buffered output row with the values it is ordered by.
+type sortRow struct {
+	keys []*Value
+	// Encoded output values.
+	values []byte
+}
+
+// rowSorter orders the output rows of a query by the terms of the
+// ORDER BY clause. When the buffered rows exceed spillThreshold they
+// are sorted and written to a temporary file, the runs are merged
+// once all rows have been added. Rows with equal keys keep the order
+// in which they were added.
+type rowSorter struct {
+	desc []bool
+
+	rows  []sortRow
+	size  int64
+	runs  []*spillFile
+	usage *spillUsage
+}
+
+func newRowSorter(terms []*OrderByTerm, usage *spillUsage) *rowSorter {
+	desc := make([]bool, len(terms))
+	for i, term := range terms {
+		desc[i] = strings.ToUpper(term.Direction) == sortDesc
+	}
+	return &rowSorter{desc: desc, usage: usage}
+}
+
+// add buffers an output row ordered by keys.
+func (s *rowSorter) add(keys, values []*Value) error {
+	for _, k := range keys {
+		inferSortType(k)
+	}
+	row := sortRow{keys: keys, values: appendValues(nil, values)}
+	s.rows = append(s.rows, row)
+	s.size += int64(len(row.values) + len(keys)*sortKeyOverhead + sortRowOverhead)
+	if s.size >= spillThreshold {
+		return s.spill()
+	}
+	return nil
+}
+
+func (s *rowSorter) less(a, b []*Value) bool {
+	for i, desc := range s.desc {
+		c := compareValues(a[i], b[i])
+		if desc {
+			c = -c
+		}
+		if c != 0 {
+			return c < 0
+		}
+	}
+	return false
+}
+
+func (s *rowSorter) sort() {
+	sort.SliceStable(s.rows, func(i, j int) bool {
+		return s.less(s.rows[i].keys, s.rows[j].keys)
+	})
+}
+
+// spill writes the buffered rows to a new sorted run.
+func (s *rowSorter) spill() error {
+	run, err := newSpillFile(s.usage)
+	if err != nil {
+		return err
+	}
+	s.runs = append(s.runs, run)
+
+	s.sort()
+	var entry []byte
+	for _, row := range s.rows {
+		entry = append(appendValues(entry[:0], row.keys), row.values...)
+		if err = run.write(entry); err != nil {
+			return err
+		}
+	}
+	s.rows = nil
+	s.size = 0
+	return nil
+}
+
+// iterate calls fn with the output values of all rows in order.
+func (s *rowSorter) iterate(n int, fn func(values []*Value) error) error {
+	if len(s.runs) == 0 {
+		s.sort()
+		for _, row := range s.rows {
+			values, _, err := readValues(row.values, n)
+			if err != nil {
+				return err
+			}
+			if err = fn(values); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if len(s.rows) > 0 {
+		if err := s.spill(); err != nil {
+			return err
+		}
+	}
+	m, err := newRunMerger(s.runs, func(a, b []byte) bool {
+		ka, _, _ := readValues(a, len(s.desc))
+		kb, _, _ := readValues(b, len(s.desc))
+		return ka != nil && kb != nil && s.less(ka, kb)
+	})
+	if err != nil {
+		return err
+	}
+	for {
+		entry, err := m.next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		_, entry, err = readValues(entry, len(s.desc))
+		if err != nil {
+			return err
+		}
+		values, _, err := readValues(entry, n)
+		if err != nil {
+			return err
+		}
+		if err = fn(values); err != nil {
+			return err
+		}
+	}
+}
+
+// Close removes the spilled runs.
+func (s *rowSorter) Close() error {
+	var err error
+	for _, run := range s.runs {
+		if cerr := run.Close(); err == nil {
+			err = cerr
+		}
+	}
+	s.runs = nil
+	return err
+}
+
+// inferSortType converts an untyped value to a number if possible,
+// and to a string otherwise, so that all keys compare consistently.
+func inferSortType(v *Value) {
+	if _, ok := v.ToBytes(); !ok {
+		return
+	}
+	if i, ok := v.bytesToInt(); ok {
+		v.setInt(i)
+	} else if f, ok := v.bytesToFloat(); ok {
+		v.setFloat(f)
+	} else {
+		v.setString(v.bytesToString())
+	}
+}
+
+// sortRank orders values of different types, NULL is the smallest
+// value.
+func sortRank(v *Value) int {
+	switch v.value.(type) {
+	case nil:
+		return 0
+	case bool:
+		return 1
+	case int64, float64:
+		return 2
+	case time.Time:
+		return 3
+	case string, []byte:
+		return 4
+	case []Value:
+		return 5
+	}
+	return 6
+}
+
+// compareValues returns -1, 0 or 1 when a is smaller than, equal to
+// or larger than b.
+func compareValues(a, b *Value) int {
+	ra, rb := sortRank(a), sortRank(b)
+	switch {
+	case ra < rb:
+		return -1
+	case ra > rb:
+		return 1
+	}
+
+	switch x := a.value.(type) {
+	case bool:
+		y := b.value.(bool)
+		switch {
+		case x == y:
+			return 0
+		case !x:
+			return -1
+		}
+		return 1
+
+	case int64, float64:
+		if i, ok := a.ToInt(); ok {
+			if j, ok := b.ToInt(); ok {
+				switch {
+				case i < j:
+					return -1
+				case i > j:
+					return 1
+				}
+				return 0
+			}
+		}
+		f, _ := a.ToFloat()
+		g, _ := b.ToFloat()
+		return compareOrdered(f, g)
+
+	case time.Time:
+		y := b.value.(time.Time)
+		switch {
+		case x.Before(y):
+			return -1
+		case x.After(y):
+			return 1
+		}
+		return 0
+
+	case string, []byte:
+		return bytes.Compare(valueBytes(a), valueBytes(b))
+
+	case []Value:
+		y := b.value.([]Value)
+		for i := 0; i < len(x) && i < len(y); i++ {
+			if c := compareValues(&x[i], &y[i]); c != 0 {
+				return c
+			}
+		}
+		return compareOrdered(float64(len(x)), float64(len(y)))
+	}
+	return 0
+}
+
+func compareOrdered(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func valueBytes(v *Value) []byte {
+	if s, ok := v.ToString(); ok {
+		return []byte(s)
+	}
+	b, _ := v.ToBytes()
+	return b
+}
@@ -17,6 +17,8 @@
 package sql
 
 import (
+	"io"
+	"regexp"
 	"strings"
 
 	"github.com/alecthomas/participle"
@@ -88,7 +90,21 @@ type Select struct {
 	Expression *SelectExpression `parser:"\"SELECT\" @@"`
 	From       *TableExpression  `parser:"\"FROM\" @@"`
 	Where      *Expression       `parser:"( \"WHERE\" @@ )?"`
+	GroupBy    []*Expression     `parser:"( \"GROUP\" \"BY\" @@ { \",\" @@ } )?"`
+	Having     *Expression       `parser:"( \"HAVING\" @@ )?"`
+	OrderBy    []*OrderByTerm    `parser:"( \"ORDER\" \"BY\" @@ { \",\" @@ } )?"`
 	Limit      *LitValue         `parser:"( \"LIMIT\" @@ )?"`
+
+	// Aggregation function calls of the statement, collected during
+	// analysis.
+	aggregates []*FuncExpr
+}
+
+// OrderByTerm represents an expression of the ORDER BY clause and
+// its sort direction.
+type OrderByTerm struct {
+	Expression *Expression `parser:"@@"`
+	Direction  string      `parser:"@( \"ASC\" | \"DESC\" )?"`
 }
 
 // SelectExpression represents the items requested in the select
@@ -206,15 +222,24 @@ type In struct {
 
 // Grammar for Operand:
 //
-// operand → multOp ( ("-" | "+") multOp )*
-// multOp  → unary ( ("/" | "*" | "%") unary )*
-// unary   → "-" unary | primary
-// primary → Value | Variable | "(" expression ")"
+// operand  → additive ( "||" additive )*
+// additive → multOp ( ("-" | "+") multOp )*
+// multOp   → unary ( ("/" | "*" | "%") unary )*
+// unary    → "-" unary | primary
+// primary  → Value | Variable | "(" expression ")" | Case
 //
 
 // An Operand is a single term followed by an optional sequence of
-// terms separated by +/-
+// terms separated by +/-, optionally concatenated with further such
+// sequences by ||
 type Operand struct {
+	Left   *MultOp       `parser:"@@"`
+	Right  []*OpFactor   `parser:"(@@)*"`
+	Concat []*ConcatTerm `parser:"( \"||\" @@ )*"`
+}
+
+// ConcatTerm represents the right side of a || operation.
+type ConcatTerm struct {
 	Left  *MultOp     `parser:"@@"`
 	Right []*OpFactor `parser:"(@@)*"`
 }
@@ -249,8 +274,8 @@ type NegatedTerm struct {
 	Term *PrimaryTerm `parser:"\"-\" @@"`
 }
 
-// PrimaryTerm represents a Value, Path expression, a Sub-expression
-// or a function call.
+// PrimaryTerm represents a Value, Path expression, a Sub-expression,
+// a function call or a CASE expression.
 type PrimaryTerm struct {
 	Value         *LitValue   `parser:"  @@"`
 	JPathExpr     *JSONPath   `parser:"| @@"`
@@ -258,6 +283,23 @@ type PrimaryTerm struct {
 	SubExpression *Expression `parser:"| \"(\" @@ \")\""`
 	// Include function expressions here.
 	FuncCall *FuncExpr `parser:"| @@"`
+	Case     *CaseExpr `parser:"| @@"`
+}
+
+// CaseExpr represents a CASE expression. With an operand it is
+// compared to the WHEN values, otherwise the WHEN conditions are
+// evaluated.
+type CaseExpr struct {
+	Operand *Expression   `parser:" \"CASE\" @@? "`
+	When    []*WhenClause `parser:" @@+ "`
+	Else    *Expression   `parser:" ( \"ELSE\" @@ )? \"END\" "`
+}
+
+// WhenClause represents a WHEN ... THEN ... branch of a CASE
+// expression.
+type WhenClause struct {
+	Condition *Expression `parser:" \"WHEN\" @@ "`
+	Result    *Expression `parser:" \"THEN\" @@ "`
 }
 
 // FuncExpr represents a function call
@@ -270,15 +312,18 @@ type FuncExpr struct {
 	Trim      *TrimFunc      `parser:"| @@"`
 	DateAdd   *DateAddFunc   `parser:"| @@"`
 	DateDiff  *DateDiffFunc  `parser:"| @@"`
+	Position  *PositionFunc  `parser:"| @@"`
 
 	// Used during evaluation for aggregation funcs
 	aggregate *aggVal
+	// Last compiled pattern of REGEXP_LIKE
+	pattern *regexp.Regexp
 }
 
 // SimpleArgFunc represents functions with simple expression
 // arguments.
 type SimpleArgFunc struct {
-	FunctionName string `parser:" @(\"AVG\" | \"MAX\" | \"MIN\" | \"SUM\" |  \"COALESCE\" | \"NULLIF\" | \"TO_STRING\" | \"TO_TIMESTAMP\" | \"UTCNOW\" | \"CHAR_LENGTH\" | \"CHARACTER_LENGTH\" | \"LOWER\" | \"UPPER\") "`
+	FunctionName string `parser:" @(\"AVG\" | \"MAX\" | \"MIN\" | \"SUM\" |  \"COALESCE\" | \"NULLIF\" | \"TO_STRING\" | \"TO_TIMESTAMP\" | \"UTCNOW\" | \"CHAR_LENGTH\" | \"CHARACTER_LENGTH\" | \"LOWER\" | \"UPPER\" | \"CONCAT\" | \"REPLACE\" | \"REGEXP_LIKE\" | \"ABS\" | \"ROUND\" | \"FLOOR\" | \"CEIL\" | \"CEILING\" | \"MOD\") "`
 
 	ArgsList []*Expression `parser:"\"(\" (@@ (\",\" @@)*)?\")\""`
 }
@@ -317,6 +362,12 @@ type TrimFunc struct {
 	TrimFrom  *PrimaryTerm `parser:"             \"FROM\" )? @@ \")\" "`
 }
 
+// PositionFunc represents POSITION sql function
+type PositionFunc struct {
+	Substr *Operand `parser:" \"POSITION\" \"(\" @@ "`
+	Str    *Operand `parser:" \"IN\" @@ \")\" "`
+}
+
 // DateAddFunc represents the DATE_ADD function
 type DateAddFunc struct {
 	DatePart  string       `parser:" \"DATE_ADD\" \"(\" @( \"YEAR\":Timeword | \"MONTH\":Timeword | \"DAY\":Timeword | \"HOUR\":Timeword | \"MINUTE\":Timeword | \"SECOND\":Timeword ) \",\""`
@@ -345,16 +396,55 @@ type Identifier struct {
 	Quoted   *QuotedIdentifier `parser:"| @QuotIdent"`
 }
 
+// keyPathLexerDefinition lexes keywords following a "." as
+// identifiers, so that key path components can be named like
+// keywords, e.g. s.end or s.year.
+type keyPathLexerDefinition struct {
+	lexer.Definition
+}
+
+func (d keyPathLexerDefinition) Lex(r io.Reader) (lexer.Lexer, error) {
+	l, err := d.Definition.Lex(r)
+	if err != nil {
+		return nil, err
+	}
+	symbols := d.Symbols()
+	return &keyPathLexer{
+		Lexer:    l,
+		ident:    symbols["Ident"],
+		keyword:  symbols["Keyword"],
+		timeword: symbols["Timeword"],
+	}, nil
+}
+
+type keyPathLexer struct {
+	lexer.Lexer
+	ident, keyword, timeword rune
+	afterDot                 bool
+}
+
+func (l *keyPathLexer) Next() (lexer.Token, error) {
+	tok, err := l.Lexer.Next()
+	if err != nil {
+		return tok, err
+	}
+	if l.afterDot && (tok.Type == l.keyword || tok.Type == l.timeword) {
+		tok.Type = l.ident
+	}
+	l.afterDot = tok.Value == "."
+	return tok, nil
+}
+
 var (
-	sqlLexer = lexer.Must(lexer.Regexp(`(\s+)` +
+	sqlLexer = keyPathLexerDefinition{lexer.Must(lexer.Regexp(`(\s+)` +
 		`|(?P<Timeword>(?i)\b(?:YEAR|MONTH|DAY|HOUR|MINUTE|SECOND|TIMEZONE_HOUR|TIMEZONE_MINUTE)\b)` +
-		`|(?P<Keyword>(?i)\b(?:SELECT|FROM|TOP|DISTINCT|ALL|WHERE|GROUP|BY|HAVING|UNION|MINUS|EXCEPT|INTERSECT|ORDER|LIMIT|OFFSET|TRUE|FALSE|NULL|IS|NOT|ANY|SOME|BETWEEN|AND|OR|LIKE|ESCAPE|AS|IN|BOOL|INT|INTEGER|STRING|FLOAT|DECIMAL|NUMERIC|TIMESTAMP|AVG|COUNT|MAX|MIN|SUM|COALESCE|NULLIF|CAST|DATE_ADD|DATE_DIFF|EXTRACT|TO_STRING|TO_TIMESTAMP|UTCNOW|CHAR_LENGTH|CHARACTER_LENGTH|LOWER|SUBSTRING|TRIM|UPPER|LEADING|TRAILING|BOTH|FOR)\b)` +
+		`|(?P<Keyword>(?i)\b(?:SELECT|FROM|TOP|DISTINCT|ALL|WHERE|GROUP|BY|HAVING|UNION|MINUS|EXCEPT|INTERSECT|ORDER|ASC|DESC|LIMIT|OFFSET|TRUE|FALSE|NULL|IS|NOT|ANY|SOME|BETWEEN|AND|OR|LIKE|ESCAPE|AS|IN|BOOL|INT|INTEGER|STRING|FLOAT|DECIMAL|NUMERIC|TIMESTAMP|AVG|COUNT|MAX|MIN|SUM|COALESCE|NULLIF|CAST|DATE_ADD|DATE_DIFF|EXTRACT|TO_STRING|TO_TIMESTAMP|UTCNOW|CHAR_LENGTH|CHARACTER_LENGTH|LOWER|SUBSTRING|TRIM|UPPER|LEADING|TRAILING|BOTH|FOR|CONCAT|REPLACE|REGEXP_LIKE|POSITION|ABS|ROUND|FLOOR|CEIL|CEILING|MOD|CASE|WHEN|THEN|ELSE|END)\b)` +
 		`|(?P<Ident>[a-zA-Z_][a-zA-Z0-9_]*)` +
 		`|(?P<QuotIdent>"([^"]*("")?)*")` +
 		`|(?P<Number>\d*\.?\d+([eE][-+]?\d+)?)` +
 		`|(?P<LitString>'([^']*('')?)*')` +
-		`|(?P<Operators><>|!=|<=|>=|\.\*|\[\*\]|[-+*/%,.()=<>\[\]])`,
-	))
+		`|(?P<Operators>\|\||<>|!=|<=|>=|\.\*|\[\*\]|[-+*/%,.()=<>\[\]])`,
+	))}
 
 	// SQLParser is used to parse SQL statements
 	SQLParser = participle.MustBuild(
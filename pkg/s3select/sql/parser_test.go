@@ -206,6 +206,16 @@ func TestFunctionParsing(t *testing.T) {
 		"trim(leading '12' from '  aab  ')",
 		"trim(trailing '12' from '  aab  ')",
 		"count(23)",
+		"concat(s.a, 'b', 1)",
+		"replace(s.a, 'b', 'c')",
+		"regexp_like(s.a, '^a')",
+		"position('a' in s.b || 'c')",
+
+		"abs(-1)",
+		"round(s.a, 2)",
+		"floor(1.5)",
+		"ceil(1.5)",
+		"mod(s.a, 2)",
 	}
 	for i, tc := range validCases {
 		err := p.ParseString(tc, &fex)
@@ -355,6 +365,13 @@ func TestSelectParsing(t *testing.T) {
 		"select * from s3object where name > 2 or value > 1 or word > 2",
 		"select s.word.id + 2 from s3object s",
 		"select 1-2-3 from s3object s limit 1",
+		"select s.a, count(*) as n from s3object s where s.b > 1 group by s.a having count(*) > 1 order by n desc, s.a limit 1",
+		"select s.a from s3object s order by s.b asc",
+		"select s.a || '-' || s.b from s3object s where s.a || s.b = 'ab'",
+		"select case when s.a > 1 then 'x' when s.a < 0 then 'y' else 'z' end from s3object s",
+		"select case s.a when 1 then 'one' end as n from s3object s",
+		"select s.end, s.position, s.year, s.\"case\" from s3object s where s.mod > 1 order by s.round",
+		"select \"end\", \"mod\" from s3object where \"end\" > 1",
 	}
 	for i, tc := range cases {
 		err := p.ParseString(tc, &s)
@@ -0,0 +1,369 @@
+/*
+ * MinIO Cloud Storage, (C) 2021 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package sql
+
+import (
+	"bufio"
+	"container/heap"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"os"
+	"time"
+)
+
+// spillThreshold is the approximate number of bytes of groups or
+// ordered rows a statement holds in memory, beyond it they are
+// written to temporary files and merged once all input has been
+// processed.
+var spillThreshold int64 = 64 << 20
+
+// Limits of the temporary files written by a statement, beyond them
+// the query fails.
+var (
+	maxSpillSize int64 = 4 << 30
+	maxSpillRuns       = 1024
+)
+
+// SpillDir returns the directory temporary files are written to, the
+// server sets it to the tmp directory of one of its local drives. The
+// system temporary directory is used when it returns "".
+var SpillDir = func() string { return "" }
+
+// Tags of the binary encoding of values.
+const (
+	valueTagNull byte = iota
+	valueTagBool
+	valueTagInt
+	valueTagFloat
+	valueTagString
+	valueTagBytes
+	valueTagTimestamp
+	valueTagArray
+)
+
+var errInvalidEncodedValue = errors.New("invalid encoded value")
+
+// appendValue appends the binary encoding of v to buf. Equal values
+// of the same type have equal encodings, so encodings are usable as
+// grouping keys.
+func appendValue(buf []byte, v *Value) []byte {
+	switch x := v.value.(type) {
+	case bool:
+		buf = append(buf, valueTagBool)
+		if x {
+			return append(buf, 1)
+		}
+		return append(buf, 0)
+	case int64:
+		buf = append(buf, valueTagInt)
+		return appendVarint(buf, x)
+	case float64:
+		buf = append(buf, valueTagFloat)
+		var b [8]byte
+		binary.BigEndian.PutUint64(b[:], math.Float64bits(x))
+		return append(buf, b[:]...)
+	case string:
+		buf = append(buf, valueTagString)
+		return appendBytes(buf, []byte(x))
+	case []byte:
+		buf = append(buf, valueTagBytes)
+		return appendBytes(buf, x)
+	case time.Time:
+		b, err := x.MarshalBinary()
+		if err != nil {
+			// Offsets of fractional minutes cannot be encoded.
+			b, _ = x.UTC().MarshalBinary()
+		}
+		buf = append(buf, valueTagTimestamp)
+		return appendBytes(buf, b)
+	case []Value:
+		buf = append(buf, valueTagArray)
+		buf = appendUvarint(buf, uint64(len(x)))
+		for i := range x {
+			buf = appendValue(buf, &x[i])
+		}
+		return buf
+	}
+	return append(buf, valueTagNull)
+}
+
+// readValue decodes a value encoded by appendValue and returns it
+// with the remainder of buf.
+func readValue(buf []byte) (*Value, []byte, error) {
+	if len(buf) == 0 {
+		return nil, nil, errInvalidEncodedValue
+	}
+	tag, buf := buf[0], buf[1:]
+	switch tag {
+	case valueTagNull:
+		return FromNull(), buf, nil
+	case valueTagBool:
+		if len(buf) == 0 {
+			return nil, nil, errInvalidEncodedValue
+		}
+		return FromBool(buf[0] == 1), buf[1:], nil
+	case valueTagInt:
+		i, n := binary.Varint(buf)
+		if n <= 0 {
+			return nil, nil, errInvalidEncodedValue
+		}
+		return FromInt(i), buf[n:], nil
+	case valueTagFloat:
+		if len(buf) < 8 {
+			return nil, nil, errInvalidEncodedValue
+		}
+		return FromFloat(math.Float64frombits(binary.BigEndian.Uint64(buf))), buf[8:], nil
+	case valueTagString:
+		b, rest, err := readBytes(buf)
+		if err != nil {
+			return nil, nil, err
+		}
+		return FromString(string(b)), rest, nil
+	case valueTagBytes:
+		b, rest, err := readBytes(buf)
+		if err != nil {
+			return nil, nil, err
+		}
+		return FromBytes(append([]byte(nil), b...)), rest, nil
+	case valueTagTimestamp:
+		b, rest, err := readBytes(buf)
+		if err != nil {
+			return nil, nil, err
+		}
+		var t time.Time
+		if err = t.UnmarshalBinary(b); err != nil {
+			return nil, nil, err
+		}
+		return FromTimestamp(t), rest, nil
+	case valueTagArray:
+		n, m := binary.Uvarint(buf)
+		if m <= 0 || n > uint64(len(buf)) {
+			return nil, nil, errInvalidEncodedValue
+		}
+		buf = buf[m:]
+		arr := make([]Value, n)
+		for i := range arr {
+			v, rest, err := readValue(buf)
+			if err != nil {
+				return nil, nil, err
+			}
+			arr[i], buf = *v, rest
+		}
+		return FromArray(arr), buf, nil
+	}
+	return nil, nil, errInvalidEncodedValue
+}
+
+// appendValues appends the encoding of all values to buf.
+func appendValues(buf []byte, values []*Value) []byte {
+	for _, v := range values {
+		buf = appendValue(buf, v)
+	}
+	return buf
+}
+
+// readValues decodes n values encoded by appendValues.
+func readValues(buf []byte, n int) ([]*Value, []byte, error) {
+	values := make([]*Value, n)
+	for i := range values {
+		v, rest, err := readValue(buf)
+		if err != nil {
+			return nil, nil, err
+		}
+		values[i], buf = v, rest
+	}
+	return values, buf, nil
+}
+
+func appendVarint(buf []byte, i int64) []byte {
+	var b [binary.MaxVarintLen64]byte
+	return append(buf, b[:binary.PutVarint(b[:], i)]...)
+}
+
+func appendUvarint(buf []byte, i uint64) []byte {
+	var b [binary.MaxVarintLen64]byte
+	return append(buf, b[:binary.PutUvarint(b[:], i)]...)
+}
+
+func appendBytes(buf, b []byte) []byte {
+	return append(appendUvarint(buf, uint64(len(b))), b...)
+}
+
+func readBytes(buf []byte) ([]byte, []byte, error) {
+	n, m := binary.Uvarint(buf)
+	if m <= 0 || n > uint64(len(buf)-m) {
+		return nil, nil, errInvalidEncodedValue
+	}
+	return buf[m : m+int(n)], buf[m+int(n):], nil
+}
+
+// spillUsage accounts for the temporary files written by a statement.
+type spillUsage struct {
+	size int64
+	runs int
+}
+
+// spillFile is a temporary file holding a sorted run of entries.
+type spillFile struct {
+	f     *os.File
+	w     *bufio.Writer
+	r     *bufio.Reader
+	usage *spillUsage
+}
+
+func newSpillFile(usage *spillUsage) (*spillFile, error) {
+	if usage.runs >= maxSpillRuns {
+		return nil, errQueryTooLarge(fmt.Errorf("more than %d temporary files", maxSpillRuns))
+	}
+	f, err := ioutil.TempFile(SpillDir(), "s3select-spill-")
+	if err != nil {
+		return nil, err
+	}
+	usage.runs++
+	return &spillFile{f: f, w: bufio.NewWriter(f), usage: usage}, nil
+}
+
+// write appends an entry to the run.
+func (s *spillFile) write(entry []byte) error {
+	var b [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(b[:], uint64(len(entry)))
+	s.usage.size += int64(n + len(entry))
+	if s.usage.size > maxSpillSize {
+		return errQueryTooLarge(fmt.Errorf("more than %d bytes of temporary files", maxSpillSize))
+	}
+	if _, err := s.w.Write(b[:n]); err != nil {
+		return err
+	}
+	_, err := s.w.Write(entry)
+	return err
+}
+
+// rewind prepares the run for reading its entries from the start.
+func (s *spillFile) rewind() error {
+	if err := s.w.Flush(); err != nil {
+		return err
+	}
+	if _, err := s.f.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	s.r = bufio.NewReader(s.f)
+	return nil
+}
+
+// next returns the next entry of the run, io.EOF after the last one.
+func (s *spillFile) next() ([]byte, error) {
+	n, err := binary.ReadUvarint(s.r)
+	if err != nil {
+		return nil, err
+	}
+	entry := make([]byte, n)
+	if _, err = io.ReadFull(s.r, entry); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	return entry, nil
+}
+
+// Close closes and removes the file.
+func (s *spillFile) Close() error {
+	err := s.f.Close()
+	if rerr := os.Remove(s.f.Name()); err == nil {
+		err = rerr
+	}
+	return err
+}
+
+type mergeItem struct {
+	run   int
+	entry []byte
+}
+
+// runMerger merges sorted runs, returning their entries in order.
+// Equal entries are returned in the order of their runs.
+type runMerger struct {
+	runs  []*spillFile
+	items []mergeItem
+	less  func(a, b []byte) bool
+}
+
+func newRunMerger(runs []*spillFile, less func(a, b []byte) bool) (*runMerger, error) {
+	m := &runMerger{runs: runs, less: less}
+	for i, run := range runs {
+		if err := run.rewind(); err != nil {
+			return nil, err
+		}
+		entry, err := run.next()
+		if err == io.EOF {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		m.items = append(m.items, mergeItem{run: i, entry: entry})
+	}
+	heap.Init(m)
+	return m, nil
+}
+
+func (m *runMerger) Len() int { return len(m.items) }
+
+func (m *runMerger) Less(i, j int) bool {
+	a, b := m.items[i], m.items[j]
+	if m.less(a.entry, b.entry) {
+		return true
+	}
+	if m.less(b.entry, a.entry) {
+		return false
+	}
+	return a.run < b.run
+}
+
+func (m *runMerger) Swap(i, j int) { m.items[i], m.items[j] = m.items[j], m.items[i] }
+
+func (m *runMerger) Push(x interface{}) { m.items = append(m.items, x.(mergeItem)) }
+
+func (m *runMerger) Pop() interface{} {
+	item := m.items[len(m.items)-1]
+	m.items = m.items[:len(m.items)-1]
+	return item
+}
+
+// next returns the smallest entry of all runs, io.EOF once all runs
+// are exhausted.
+func (m *runMerger) next() ([]byte, error) {
+	if len(m.items) == 0 {
+		return nil, io.EOF
+	}
+	item := m.items[0]
+	entry, err := m.runs[item.run].next()
+	switch err {
+	case nil:
+		m.items[0].entry = entry
+		heap.Fix(m, 0)
+	case io.EOF:
+		heap.Pop(m)
+	default:
+		return nil, err
+	}
+	return item.entry, nil
+}
@@ -27,6 +27,7 @@ import (
 
 var (
 	errBadLimitSpecified = errors.New("Limit value must be a positive integer")
+	errStopResults       = errors.New("Stop producing results")
 )
 
 const (
@@ -46,6 +47,27 @@ type SelectStatement struct {
 
 	// Count of rows that have been output.
 	outputCount int64
+
+	// Names of the output columns, unless all columns are
+	// selected.
+	columns []string
+
+	// For each select expression the index of the equal GROUP BY
+	// expression, or -1.
+	groupExprs []int
+	// For each GROUP BY expression the column name its path
+	// expression is resolved as in grouped rows, or "".
+	groupColumns []string
+	// For each ORDER BY term the index of the output column it
+	// refers to, or -1.
+	orderColumns []int
+
+	// Aggregations per group of grouped queries.
+	groups *groupTable
+	// Output rows of ordered queries.
+	sorter *rowSorter
+	// Temporary files written by the groups and the sorter.
+	spill *spillUsage
 }
 
 // ParseSelectStatement - parses a select query from the given string
@@ -106,10 +128,126 @@ func ParseSelectStatement(s string) (stmt SelectStatement, err error) {
 	err = stmt.selectQProp.err
 	if err != nil {
 		err = errQueryAnalysisFailure(err)
+		return
+	}
+
+	if err = stmt.analyzeClauses(); err != nil {
+		err = errQueryAnalysisFailure(err)
 	}
 	return
 }
 
+// analyzeClauses analyzes the GROUP BY, HAVING and ORDER BY clauses
+// and names the output columns.
+func (e *SelectStatement) analyzeClauses() error {
+	s := e.selectAST
+	e.spill = &spillUsage{}
+	if !s.Expression.All {
+		e.columns = make([]string, len(s.Expression.Expressions))
+		for i, expr := range s.Expression.Expressions {
+			if expr.As != "" {
+				e.columns[i] = expr.As
+			} else if comp, ok := getLastKeypathComponent(expr.Expression); ok {
+				e.columns[i] = comp
+			} else {
+				e.columns[i] = fmt.Sprintf("_%d", i+1)
+			}
+		}
+	}
+
+	if len(s.GroupBy) > 0 {
+		if s.Expression.All {
+			return errors.New("GROUP BY cannot be used with SELECT *")
+		}
+		e.groupColumns = make([]string, len(s.GroupBy))
+		for i, expr := range s.GroupBy {
+			qProp := expr.analyze(s)
+			if qProp.err != nil {
+				return fmt.Errorf("GROUP BY clause error: %w", qProp.err)
+			}
+			if qProp.isAggregation {
+				return errors.New("GROUP BY clause cannot have an aggregation")
+			}
+			if jpath := getJSONPath(expr); jpath != nil {
+				e.groupColumns[i] = jpath.keypath()
+			}
+		}
+		e.groupExprs = make([]int, len(s.Expression.Expressions))
+		for i, expr := range s.Expression.Expressions {
+			e.groupExprs[i] = -1
+			for j, groupExpr := range s.GroupBy {
+				if sameExpression(expr.Expression, groupExpr) {
+					e.groupExprs[i] = j
+					break
+				}
+			}
+		}
+	}
+
+	if s.Having != nil {
+		qProp := s.Having.analyze(s)
+		if qProp.err != nil {
+			return fmt.Errorf("HAVING clause error: %w", qProp.err)
+		}
+		if !e.IsAggregated() {
+			return errors.New("HAVING clause requires GROUP BY or an aggregation")
+		}
+		if qProp.isRowFunc {
+			return errors.New("HAVING clause can only refer to aggregations without GROUP BY")
+		}
+	}
+
+	if len(s.OrderBy) > 0 {
+		if s.Expression.All {
+			return errors.New("ORDER BY cannot be used with SELECT *")
+		}
+		e.orderColumns = make([]int, len(s.OrderBy))
+		for i, term := range s.OrderBy {
+			e.orderColumns[i] = e.outputColumn(term.Expression)
+			if e.orderColumns[i] >= 0 {
+				continue
+			}
+			qProp := term.Expression.analyze(s)
+			if qProp.err != nil {
+				return fmt.Errorf("ORDER BY clause error: %w", qProp.err)
+			}
+			if qProp.isAggregation && !e.IsAggregated() {
+				return errors.New("ORDER BY clause cannot have an aggregation")
+			}
+			if qProp.isRowFunc && e.IsAggregated() {
+				return errors.New("ORDER BY clause can only refer to aggregations without GROUP BY")
+			}
+		}
+		e.sorter = newRowSorter(s.OrderBy, e.spill)
+	}
+
+	// Created last, as analysis of the HAVING and ORDER BY
+	// clauses collects their aggregations.
+	if len(s.GroupBy) > 0 {
+		e.groups = newGroupTable(s.aggregates, e.spill)
+	}
+	return nil
+}
+
+// outputColumn returns the index of the output column expr refers to
+// by its alias or by being the same expression, or -1.
+func (e *SelectStatement) outputColumn(expr *Expression) int {
+	exprs := e.selectAST.Expression.Expressions
+	if jpath := getJSONPath(expr); jpath != nil && len(jpath.PathExpr) == 0 {
+		for i, selectExpr := range exprs {
+			if selectExpr.As != "" && selectExpr.As == jpath.BaseKey.String() {
+				return i
+			}
+		}
+	}
+	for i, selectExpr := range exprs {
+		if sameExpression(expr, selectExpr.Expression) {
+			return i
+		}
+	}
+	return -1
+}
+
 func validateTableName(from *TableExpression) error {
 	if strings.ToLower(from.Table.BaseKey.String()) != baseTableName {
 		return errBadTableName(errors.New("table name must be `s3object`"))
@@ -219,7 +357,7 @@ func (e *SelectStatement) EvalFrom(format string, input Record) ([]*Record, erro
 
 // IsAggregated returns if the statement involves SQL aggregation
 func (e *SelectStatement) IsAggregated() bool {
-	return e.selectQProp.isAggregation
+	return e.selectQProp.isAggregation || len(e.selectAST.GroupBy) > 0
 }
 
 // AggregateResult - returns the aggregated result after all input
@@ -271,15 +409,152 @@ func (e *SelectStatement) AggregateRow(input Record) error {
 		return nil
 	}
 
-	for _, expr := range e.selectAST.Expression.Expressions {
-		err := expr.aggregateRow(input)
-		if err != nil {
+	if e.groups != nil {
+		key := make([]*Value, len(e.selectAST.GroupBy))
+		for i, expr := range e.selectAST.GroupBy {
+			if key[i], err = expr.evalNode(input); err != nil {
+				return err
+			}
+		}
+		return e.groups.aggregateRow(key, input)
+	}
+
+	for _, fn := range e.selectAST.aggregates {
+		if err = fn.evalAggregationNode(input); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
+// Results - produces the output records of aggregation and ordered
+// queries once all input records have been processed. newOutput
+// returns an empty output record, emit is called with every result
+// and returns false to stop producing results.
+func (e *SelectStatement) Results(newOutput func() Record, emit func(Record) bool) error {
+	defer e.Close()
+
+	send := func(values []*Value) (err error) {
+		if e.LimitReached() {
+			return errStopResults
+		}
+		output := newOutput()
+		for i, v := range values {
+			if output, err = output.Set(e.columns[i], v); err != nil {
+				return err
+			}
+		}
+		if e.limitValue > -1 {
+			e.outputCount++
+		}
+		if !emit(output) {
+			return errStopResults
+		}
+		return nil
+	}
+
+	var err error
+	switch {
+	case e.groups != nil:
+		err = e.groups.iterate(func(key []*Value, aggs []*aggVal) error {
+			for i, fn := range e.selectAST.aggregates {
+				fn.aggregate = aggs[i]
+			}
+			return e.groupResult(key, send)
+		})
+	case e.IsAggregated():
+		err = e.groupResult(nil, send)
+	}
+	if err == nil && e.sorter != nil {
+		err = e.sorter.iterate(len(e.columns), send)
+	}
+	if err == errStopResults {
+		err = nil
+	}
+	return err
+}
+
+// groupResult evaluates the HAVING clause and the select expressions
+// for a group and passes the output values to send, or to the sorter
+// of ordered queries.
+func (e *SelectStatement) groupResult(key []*Value, send func([]*Value) error) error {
+	r := newGroupRecord()
+	for i, name := range e.groupColumns {
+		if name != "" {
+			r.columns[name] = key[i]
+		}
+	}
+
+	if e.selectAST.Having != nil {
+		v, err := e.selectAST.Having.evalNode(r)
+		if err != nil {
+			return err
+		}
+		b, ok := v.ToBool()
+		if !ok {
+			return errors.New("HAVING expression did not return bool")
+		}
+		if !b {
+			return nil
+		}
+	}
+
+	exprs := e.selectAST.Expression.Expressions
+	values := make([]*Value, len(exprs))
+	for i, expr := range exprs {
+		if e.groupExprs != nil && e.groupExprs[i] >= 0 {
+			v := *key[e.groupExprs[i]]
+			values[i] = &v
+			continue
+		}
+		v, err := expr.evalNode(r)
+		if err != nil {
+			return err
+		}
+		values[i] = v
+	}
+
+	if e.sorter != nil {
+		return e.addSortedRow(r, values)
+	}
+	return send(values)
+}
+
+// addSortedRow evaluates the ORDER BY terms for the input of an output
+// row and buffers the row.
+func (e *SelectStatement) addSortedRow(input Record, values []*Value) error {
+	keys := make([]*Value, len(e.selectAST.OrderBy))
+	for i, term := range e.selectAST.OrderBy {
+		var v *Value
+		if c := e.orderColumns[i]; c >= 0 {
+			v = values[c]
+		} else {
+			var err error
+			if v, err = term.Expression.evalNode(input); err != nil {
+				return err
+			}
+		}
+		// Sorting may change the type of untyped keys.
+		k := *v
+		keys[i] = &k
+	}
+	return e.sorter.add(keys, values)
+}
+
+// Close - removes the temporary files of grouped and ordered queries.
+func (e *SelectStatement) Close() error {
+	var err error
+	if e.groups != nil {
+		err = e.groups.Close()
+	}
+	if e.sorter != nil {
+		if serr := e.sorter.Close(); err == nil {
+			err = serr
+		}
+	}
+	return err
+}
+
 // Eval - evaluates the Select statement for the given record. It
 // applies only to non-aggregation queries.
 // The function returns whether the statement passed the WHERE clause and should be outputted.
@@ -301,20 +576,24 @@ func (e *SelectStatement) Eval(input, output Record) (Record, error) {
 		return input.Clone(output), nil
 	}
 
+	if e.sorter != nil {
+		// Ordered rows are output by Results.
+		values := make([]*Value, len(e.selectAST.Expression.Expressions))
+		for i, expr := range e.selectAST.Expression.Expressions {
+			if values[i], err = expr.evalNode(input); err != nil {
+				return nil, err
+			}
+		}
+		return nil, e.addSortedRow(input, values)
+	}
+
 	for i, expr := range e.selectAST.Expression.Expressions {
 		v, err := expr.evalNode(input)
 		if err != nil {
 			return nil, err
 		}
 
-		// Pick output column names
-		if expr.As != "" {
-			output, err = output.Set(expr.As, v)
-		} else if comp, ok := getLastKeypathComponent(expr.Expression); ok {
-			output, err = output.Set(comp, v)
-		} else {
-			output, err = output.Set(fmt.Sprintf("_%d", i+1), v)
-		}
+		output, err = output.Set(e.columns[i], v)
 		if err != nil {
 			return nil, err
 		}
@@ -17,6 +17,9 @@
 package sql
 
 import (
+	"errors"
+	"fmt"
+	"strings"
 	"time"
 )
 
@@ -77,6 +80,160 @@ func FormatSQLTimestamp(t time.Time) string {
 	}
 }
 
+var errUnterminatedFormatQuote = errors.New("Unterminated quoted text in timestamp format")
+
+// formatTimestamp formats t using a TO_STRING format pattern. Letters
+// are pattern symbols whose count selects the width or form of the
+// field, text in single quotes is copied as is and two single quotes
+// produce one. Other characters are copied unchanged.
+func formatTimestamp(t time.Time, format string) (string, error) {
+	var sb strings.Builder
+	f := []rune(format)
+	for i := 0; i < len(f); {
+		c := f[i]
+		switch {
+		case c == '\'':
+			i++
+			if i < len(f) && f[i] == '\'' {
+				sb.WriteRune('\'')
+				i++
+				continue
+			}
+			for {
+				if i == len(f) {
+					return "", errUnterminatedFormatQuote
+				}
+				if f[i] == '\'' {
+					if i+1 < len(f) && f[i+1] == '\'' {
+						sb.WriteRune('\'')
+						i += 2
+						continue
+					}
+					i++
+					break
+				}
+				sb.WriteRune(f[i])
+				i++
+			}
+
+		case (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z'):
+			n := 1
+			for i+n < len(f) && f[i+n] == c {
+				n++
+			}
+			if err := formatTimestampField(&sb, t, c, n); err != nil {
+				return "", err
+			}
+			i += n
+
+		default:
+			sb.WriteRune(c)
+			i++
+		}
+	}
+	return sb.String(), nil
+}
+
+// formatTimestampField writes the field of t for n repetitions of
+// the pattern symbol c.
+func formatTimestampField(sb *strings.Builder, t time.Time, c rune, n int) error {
+	invalid := func() error {
+		return fmt.Errorf("Invalid timestamp format pattern %q", strings.Repeat(string(c), n))
+	}
+	number := func(v, maxWidth int) error {
+		if n > maxWidth {
+			return invalid()
+		}
+		fmt.Fprintf(sb, "%0*d", n, v)
+		return nil
+	}
+
+	switch c {
+	case 'y':
+		if n == 2 {
+			fmt.Fprintf(sb, "%02d", t.Year()%100)
+			return nil
+		}
+		return number(t.Year(), 9)
+	case 'M':
+		switch n {
+		case 1, 2:
+			return number(int(t.Month()), 2)
+		case 3:
+			sb.WriteString(t.Month().String()[:3])
+		case 4:
+			sb.WriteString(t.Month().String())
+		case 5:
+			sb.WriteString(t.Month().String()[:1])
+		default:
+			return invalid()
+		}
+	case 'd':
+		return number(t.Day(), 2)
+	case 'a':
+		if n > 1 {
+			return invalid()
+		}
+		sb.WriteString(t.Format("PM"))
+	case 'h':
+		h := t.Hour() % 12
+		if h == 0 {
+			h = 12
+		}
+		return number(h, 2)
+	case 'H':
+		return number(t.Hour(), 2)
+	case 'm':
+		return number(t.Minute(), 2)
+	case 's':
+		return number(t.Second(), 2)
+	case 'S':
+		if n > 9 {
+			return invalid()
+		}
+		frac := t.Nanosecond()
+		for i := n; i < 9; i++ {
+			frac /= 10
+		}
+		fmt.Fprintf(sb, "%0*d", n, frac)
+	case 'n':
+		if n > 1 {
+			return invalid()
+		}
+		fmt.Fprintf(sb, "%d", t.Nanosecond())
+	case 'X', 'x':
+		_, offset := t.Zone()
+		if c == 'X' && offset == 0 {
+			if n > 5 {
+				return invalid()
+			}
+			sb.WriteByte('Z')
+			return nil
+		}
+		sign := '+'
+		if offset < 0 {
+			sign, offset = '-', -offset
+		}
+		hours, minutes := offset/3600, offset%3600/60
+		switch n {
+		case 1:
+			fmt.Fprintf(sb, "%c%02d", sign, hours)
+			if minutes != 0 {
+				fmt.Fprintf(sb, "%02d", minutes)
+			}
+		case 2, 4:
+			fmt.Fprintf(sb, "%c%02d%02d", sign, hours, minutes)
+		case 3, 5:
+			fmt.Fprintf(sb, "%c%02d:%02d", sign, hours, minutes)
+		default:
+			return invalid()
+		}
+	default:
+		return invalid()
+	}
+	return nil
+}
+
 const (
 	timePartYear           = "YEAR"
 	timePartMonth          = "MONTH"
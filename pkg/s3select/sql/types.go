@@ -0,0 +1,212 @@
+/*
+ * MinIO Cloud Storage, (C) 2021 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package sql
+
+import (
+	"math"
+	"strings"
+)
+
+// ValueType is the type of the values of an output column, as far as
+// it follows from the expression of the column.
+type ValueType int
+
+// Output column types, TypeUnknown is the type of expressions whose
+// values are those of the input, such as paths and NULL, and of
+// expressions with branches of different types.
+const (
+	TypeUnknown ValueType = iota
+	TypeInt
+	TypeNumber
+	TypeBool
+	TypeString
+	TypeTimestamp
+)
+
+// OutputColumns returns the names and the types of the output columns
+// of the statement, or nil for SELECT * whose columns are those of the
+// input.
+func (e *SelectStatement) OutputColumns() (names []string, types []ValueType) {
+	if e.selectAST.Expression.All {
+		return nil, nil
+	}
+	types = make([]ValueType, len(e.columns))
+	for i, expr := range e.selectAST.Expression.Expressions {
+		types[i] = expr.Expression.valueType()
+	}
+	return append([]string(nil), e.columns...), types
+}
+
+// unifyTypes returns the type of values that are of type a or b,
+// integers widen to numbers and other mixed types are unknown.
+func unifyTypes(a, b ValueType) ValueType {
+	switch {
+	case a == b:
+		return a
+	case (a == TypeInt || a == TypeNumber) && (b == TypeInt || b == TypeNumber):
+		return TypeNumber
+	}
+	return TypeUnknown
+}
+
+func (e *Expression) valueType() ValueType {
+	if len(e.And) > 1 {
+		return TypeBool
+	}
+	return e.And[0].valueType()
+}
+
+func (e *AndCondition) valueType() ValueType {
+	if len(e.Condition) > 1 {
+		return TypeBool
+	}
+	return e.Condition[0].valueType()
+}
+
+func (e *Condition) valueType() ValueType {
+	if e.Not != nil || e.Operand.ConditionRHS != nil {
+		return TypeBool
+	}
+	return e.Operand.Operand.valueType()
+}
+
+func (e *Operand) valueType() ValueType {
+	if len(e.Concat) > 0 {
+		return TypeString
+	}
+	t := e.Left.valueType()
+	for _, f := range e.Right {
+		t = arithType(t, f.Right.valueType())
+	}
+	return t
+}
+
+// arithType returns the type of an arithmetic operation, integer
+// operands give an integer and other operands are converted to
+// numbers.
+func arithType(a, b ValueType) ValueType {
+	if a == TypeInt && b == TypeInt {
+		return TypeInt
+	}
+	return TypeNumber
+}
+
+func (e *MultOp) valueType() ValueType {
+	t := e.Left.valueType()
+	for _, r := range e.Right {
+		t = arithType(t, r.Right.valueType())
+	}
+	return t
+}
+
+func (e *UnaryTerm) valueType() ValueType {
+	if e.Negated != nil {
+		return arithType(e.Negated.Term.valueType(), TypeInt)
+	}
+	return e.Primary.valueType()
+}
+
+func (e *PrimaryTerm) valueType() ValueType {
+	switch {
+	case e.Value != nil:
+		return e.Value.valueType()
+	case e.ListExpr != nil:
+		return TypeString
+	case e.SubExpression != nil:
+		return e.SubExpression.valueType()
+	case e.FuncCall != nil:
+		return e.FuncCall.valueType()
+	case e.Case != nil:
+		return e.Case.valueType()
+	}
+	return TypeUnknown
+}
+
+func (e *LitValue) valueType() ValueType {
+	switch {
+	case e.Number != nil:
+		if _, frac := math.Modf(*e.Number); frac == 0 {
+			return TypeInt
+		}
+		return TypeNumber
+	case e.String != nil:
+		return TypeString
+	case e.Boolean != nil:
+		return TypeBool
+	}
+	return TypeUnknown
+}
+
+// valueType of a CASE expression is the common type of its results,
+// a missing ELSE gives NULL which any column holds.
+func (e *CaseExpr) valueType() ValueType {
+	t := e.When[0].Result.valueType()
+	for _, w := range e.When[1:] {
+		t = unifyTypes(t, w.Result.valueType())
+	}
+	if e.Else != nil {
+		t = unifyTypes(t, e.Else.valueType())
+	}
+	return t
+}
+
+func (e *FuncExpr) valueType() ValueType {
+	switch e.getFunctionName() {
+	case sqlFnCast:
+		switch strings.ToUpper(e.Cast.CastType) {
+		case "INT", "INTEGER":
+			return TypeInt
+		case "FLOAT", "DECIMAL", "NUMERIC":
+			return TypeNumber
+		case "BOOL":
+			return TypeBool
+		case "STRING":
+			return TypeString
+		case "TIMESTAMP":
+			return TypeTimestamp
+		}
+	case aggFnCount, sqlFnExtract, sqlFnDateDiff, sqlFnPosition, sqlFnCharLength, sqlFnCharacterLength:
+		return TypeInt
+	case aggFnAvg, aggFnSum, sqlFnRound, sqlFnFloor, sqlFnCeil, sqlFnCeiling:
+		return TypeNumber
+	case sqlFnSubstring, sqlFnTrim, sqlFnLower, sqlFnUpper, sqlFnConcat, sqlFnReplace, sqlFnToString:
+		return TypeString
+	case sqlFnDateAdd, sqlFnToTimestamp, sqlFnUTCNow:
+		return TypeTimestamp
+	case sqlFnRegexpLike:
+		return TypeBool
+	case aggFnMin, aggFnMax, sqlFnAbs, sqlFnNullIf:
+		if len(e.SFunc.ArgsList) > 0 {
+			return e.SFunc.ArgsList[0].valueType()
+		}
+	case sqlFnMod:
+		if len(e.SFunc.ArgsList) == 2 {
+			return arithType(e.SFunc.ArgsList[0].valueType(), e.SFunc.ArgsList[1].valueType())
+		}
+	case sqlFnCoalesce:
+		t := TypeUnknown
+		for i, arg := range e.SFunc.ArgsList {
+			if i == 0 {
+				t = arg.valueType()
+			} else {
+				t = unifyTypes(t, arg.valueType())
+			}
+		}
+		return t
+	}
+	return TypeUnknown
+}
@@ -0,0 +1,64 @@
+/*
+ * MinIO Cloud Storage, (C) 2021 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package sql
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestOutputColumns(t *testing.T) {
+	testCases := []struct {
+		query string
+		names []string
+		types []ValueType
+	}{
+		{
+			query: "SELECT * FROM S3Object",
+		},
+		{
+			query: "SELECT s.a, s.b AS x, CAST(s.c AS INT), CAST(s.d AS FLOAT) AS d FROM S3Object s",
+			names: []string{"a", "x", "_3", "d"},
+			types: []ValueType{TypeUnknown, TypeUnknown, TypeInt, TypeNumber},
+		},
+		{
+			query: "SELECT s.a > 1 AS gt, s.a || 'x' AS s, s.a + 1 AS n, 2 * 3 AS i, 1.5 AS f, UPPER(s.a) AS u, DATE_ADD(day, 1, UTCNOW()) AS t FROM S3Object s",
+			names: []string{"gt", "s", "n", "i", "f", "u", "t"},
+			types: []ValueType{TypeBool, TypeString, TypeNumber, TypeInt, TypeNumber, TypeString, TypeTimestamp},
+		},
+		{
+			query: "SELECT COUNT(*) AS c, AVG(s.a) AS a, MAX(CAST(s.b AS INT)) AS m, MIN(s.c) AS n FROM S3Object s",
+			names: []string{"c", "a", "m", "n"},
+			types: []ValueType{TypeInt, TypeNumber, TypeInt, TypeUnknown},
+		},
+		{
+			query: "SELECT CASE WHEN s.a = 1 THEN 1 ELSE 2.5 END AS x, CASE s.a WHEN 1 THEN 'a' ELSE 2 END AS y, COALESCE(CAST(s.a AS INT), 0) AS z FROM S3Object s",
+			names: []string{"x", "y", "z"},
+			types: []ValueType{TypeNumber, TypeUnknown, TypeInt},
+		},
+	}
+	for i, testCase := range testCases {
+		stmt, err := ParseSelectStatement(testCase.query)
+		if err != nil {
+			t.Fatalf("case %d: %v", i, err)
+		}
+		names, types := stmt.OutputColumns()
+		if !reflect.DeepEqual(names, testCase.names) || !reflect.DeepEqual(types, testCase.types) {
+			t.Errorf("case %d: expected %v %v, got %v %v", i, testCase.names, testCase.types, names, types)
+		}
+	}
+}
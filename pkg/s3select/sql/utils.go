@@ -18,6 +18,7 @@ package sql
 
 import (
 	"fmt"
+	"reflect"
 	"strings"
 )
 
@@ -76,23 +77,12 @@ func (o *ObjectKey) keyString() string {
 // expression, and if so extracts the last dot separated component of
 // the path. Otherwise it returns false.
 func getLastKeypathComponent(e *Expression) (string, bool) {
-	if len(e.And) > 1 ||
-		len(e.And[0].Condition) > 1 ||
-		e.And[0].Condition[0].Not != nil ||
-		e.And[0].Condition[0].Operand.ConditionRHS != nil {
-		return "", false
-	}
-
-	operand := e.And[0].Condition[0].Operand.Operand
-	if operand.Right != nil ||
-		operand.Left.Right != nil ||
-		operand.Left.Left.Negated != nil ||
-		operand.Left.Left.Primary.JPathExpr == nil {
+	jpath := getJSONPath(e)
+	if jpath == nil {
 		return "", false
 	}
 
 	// Check if path expression ends in a key
-	jpath := operand.Left.Left.Primary.JPathExpr
 	n := len(jpath.PathExpr)
 	if n > 0 && jpath.PathExpr[n-1].Key == nil {
 		return "", false
@@ -105,6 +95,70 @@ func getLastKeypathComponent(e *Expression) (string, bool) {
 	return ps, true
 }
 
+// getJSONPath returns the path expression if the given expression
+// is only a path expression, otherwise nil.
+func getJSONPath(e *Expression) *JSONPath {
+	if len(e.And) > 1 ||
+		len(e.And[0].Condition) > 1 ||
+		e.And[0].Condition[0].Not != nil ||
+		e.And[0].Condition[0].Operand.ConditionRHS != nil {
+		return nil
+	}
+
+	operand := e.And[0].Condition[0].Operand.Operand
+	if operand.Right != nil ||
+		operand.Concat != nil ||
+		operand.Left.Right != nil ||
+		operand.Left.Left.Negated != nil {
+		return nil
+	}
+	return operand.Left.Left.Primary.JPathExpr
+}
+
+// getLiteral returns the literal value if the given expression is
+// only a literal, possibly negated, otherwise nil.
+func getLiteral(e *Expression) *LitValue {
+	if len(e.And) > 1 ||
+		len(e.And[0].Condition) > 1 ||
+		e.And[0].Condition[0].Not != nil ||
+		e.And[0].Condition[0].Operand.ConditionRHS != nil {
+		return nil
+	}
+	return e.And[0].Condition[0].Operand.Operand.literal()
+}
+
+func (e *Operand) literal() *LitValue {
+	if e.Right != nil || e.Concat != nil || e.Left.Right != nil {
+		return nil
+	}
+	if e.Left.Left.Negated != nil {
+		return e.Left.Left.Negated.Term.Value
+	}
+	return e.Left.Left.Primary.Value
+}
+
+// keypath returns the path without the table name, as it is looked
+// up in records that are not JSON.
+func (e *JSONPath) keypath() string {
+	keypath := e.String()
+	if strings.Contains(keypath, ".") {
+		ps := strings.SplitN(keypath, ".", 2)
+		if len(ps) == 2 {
+			keypath = ps[1]
+		}
+	}
+	return keypath
+}
+
+// sameExpression returns if both expressions are the same.
+func sameExpression(a, b *Expression) bool {
+	pa, pb := getJSONPath(a), getJSONPath(b)
+	if pa != nil || pb != nil {
+		return pa != nil && pb != nil && pa.String() == pb.String()
+	}
+	return reflect.DeepEqual(a, b)
+}
+
 // HasKeypath returns if the from clause has a key path -
 // e.g. S3object[*].id
 func (from *TableExpression) HasKeypath() bool {